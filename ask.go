@@ -0,0 +1,348 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	handoff "github.com/phrazzld/handoff/lib"
+)
+
+// askMaxTokens is the response length limit sent to providers that require one
+const askMaxTokens = 8192
+
+// ErrNoProvider is returned when no provider is selected and no API key is set
+var ErrNoProvider = errors.New("no LLM provider configured; set ANTHROPIC_API_KEY, OPENAI_API_KEY, or GEMINI_API_KEY, or use -provider")
+
+// llmProvider describes how to call a hosted model's API with a single user message
+type llmProvider struct {
+	// keyEnv is the environment variable holding the API key
+	keyEnv string
+
+	// defaultModel is used when no model is specified
+	defaultModel string
+
+	// baseURL is the API endpoint root (overridden in tests)
+	baseURL string
+
+	// newRequest builds the HTTP request for a prompt
+	newRequest func(p *llmProvider, model, key, prompt string) (*http.Request, error)
+
+	// parseResponse extracts the response text from a successful API response
+	parseResponse func(body []byte) (string, error)
+}
+
+// llmProviders lists the supported providers by name
+var llmProviders = map[string]*llmProvider{
+	"anthropic": {
+		keyEnv:        "ANTHROPIC_API_KEY",
+		defaultModel:  "claude-sonnet-4-0",
+		baseURL:       "https://api.anthropic.com",
+		newRequest:    newAnthropicRequest,
+		parseResponse: parseAnthropicResponse,
+	},
+	"openai": {
+		keyEnv:        "OPENAI_API_KEY",
+		defaultModel:  "gpt-4o",
+		baseURL:       "https://api.openai.com",
+		newRequest:    newOpenAIRequest,
+		parseResponse: parseOpenAIResponse,
+	},
+	"gemini": {
+		keyEnv:        "GEMINI_API_KEY",
+		defaultModel:  "gemini-2.5-pro",
+		baseURL:       "https://generativelanguage.googleapis.com",
+		newRequest:    newGeminiRequest,
+		parseResponse: parseGeminiResponse,
+	},
+}
+
+// providerOrder is the order in which providers are tried when none is specified
+var providerOrder = []string{"anthropic", "openai", "gemini"}
+
+// providerNames returns the supported provider names in sorted order
+func providerNames() []string {
+	names := make([]string, 0, len(llmProviders))
+	for name := range llmProviders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// selectProvider returns the named provider and its API key. When name is empty,
+// the first provider whose API key is set in the environment is used.
+func selectProvider(name string) (string, *llmProvider, string, error) {
+	if name == "" {
+		for _, candidate := range providerOrder {
+			if key := os.Getenv(llmProviders[candidate].keyEnv); key != "" {
+				return candidate, llmProviders[candidate], key, nil
+			}
+		}
+		return "", nil, "", ErrNoProvider
+	}
+
+	provider, ok := llmProviders[strings.ToLower(name)]
+	if !ok {
+		return "", nil, "", fmt.Errorf("unknown provider %q (known providers: %s)", name, strings.Join(providerNames(), ", "))
+	}
+	key := os.Getenv(provider.keyEnv)
+	if key == "" {
+		return "", nil, "", fmt.Errorf("provider %s requires %s to be set", name, provider.keyEnv)
+	}
+	return strings.ToLower(name), provider, key, nil
+}
+
+// askModel sends prompt to the provider and returns the model's response text.
+func askModel(provider *llmProvider, model, key, prompt string) (string, error) {
+	if model == "" {
+		model = provider.defaultModel
+	}
+
+	req, err := provider.newRequest(provider, model, key, prompt)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := &http.Client{Timeout: 10 * time.Minute}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("request to %s failed: %w", model, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("%s returned %s: %s", model, resp.Status, strings.TrimSpace(string(body)))
+	}
+	return provider.parseResponse(body)
+}
+
+// newJSONRequest builds a POST request with a JSON-encoded body
+func newJSONRequest(url string, payload any) (*http.Request, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	return http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+}
+
+// newAnthropicRequest builds a Messages API request
+func newAnthropicRequest(p *llmProvider, model, key, prompt string) (*http.Request, error) {
+	req, err := newJSONRequest(p.baseURL+"/v1/messages", map[string]any{
+		"model":      model,
+		"max_tokens": askMaxTokens,
+		"messages":   []map[string]string{{"role": "user", "content": prompt}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("x-api-key", key)
+	req.Header.Set("anthropic-version", "2023-06-01")
+	return req, nil
+}
+
+// parseAnthropicResponse joins the text blocks of a Messages API response
+func parseAnthropicResponse(body []byte) (string, error) {
+	var resp struct {
+		Content []struct {
+			Type string `json:"type"`
+			Text string `json:"text"`
+		} `json:"content"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return "", fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	var text strings.Builder
+	for _, block := range resp.Content {
+		if block.Type == "text" {
+			text.WriteString(block.Text)
+		}
+	}
+	return text.String(), nil
+}
+
+// newOpenAIRequest builds a Chat Completions API request
+func newOpenAIRequest(p *llmProvider, model, key, prompt string) (*http.Request, error) {
+	req, err := newJSONRequest(p.baseURL+"/v1/chat/completions", map[string]any{
+		"model":    model,
+		"messages": []map[string]string{{"role": "user", "content": prompt}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+key)
+	return req, nil
+}
+
+// parseOpenAIResponse returns the first choice of a Chat Completions response
+func parseOpenAIResponse(body []byte) (string, error) {
+	var resp struct {
+		Choices []struct {
+			Message struct {
+				Content string `json:"content"`
+			} `json:"message"`
+		} `json:"choices"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return "", fmt.Errorf("failed to parse response: %w", err)
+	}
+	if len(resp.Choices) == 0 {
+		return "", errors.New("response contained no choices")
+	}
+	return resp.Choices[0].Message.Content, nil
+}
+
+// newGeminiRequest builds a generateContent API request
+func newGeminiRequest(p *llmProvider, model, key, prompt string) (*http.Request, error) {
+	req, err := newJSONRequest(p.baseURL+"/v1beta/models/"+url.PathEscape(model)+":generateContent", map[string]any{
+		"contents": []map[string]any{{"parts": []map[string]string{{"text": prompt}}}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("x-goog-api-key", key)
+	return req, nil
+}
+
+// parseGeminiResponse joins the text parts of the first generateContent candidate
+func parseGeminiResponse(body []byte) (string, error) {
+	var resp struct {
+		Candidates []struct {
+			Content struct {
+				Parts []struct {
+					Text string `json:"text"`
+				} `json:"parts"`
+			} `json:"content"`
+		} `json:"candidates"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return "", fmt.Errorf("failed to parse response: %w", err)
+	}
+	if len(resp.Candidates) == 0 {
+		return "", errors.New("response contained no candidates")
+	}
+
+	var text strings.Builder
+	for _, part := range resp.Candidates[0].Content.Parts {
+		text.WriteString(part.Text)
+	}
+	return text.String(), nil
+}
+
+// providerFlags holds the flags shared by subcommands that call a model
+type providerFlags struct {
+	name  string
+	model string
+}
+
+// registerProviderFlags defines the -provider and -provider-model flags
+func registerProviderFlags() *providerFlags {
+	f := &providerFlags{}
+	flag.StringVar(&f.name, "provider", "", "LLM provider: "+strings.Join(providerNames(), ", ")+" (default: the first with an API key set)")
+	flag.StringVar(&f.model, "provider-model", "", "Provider model ID to use (default: the provider's default model)")
+	return f
+}
+
+// modelTarget is a provider resolved from flags and the environment, ready to receive prompts
+type modelTarget struct {
+	name     string
+	provider *llmProvider
+	model    string
+	key      string
+}
+
+// resolve selects the provider named by the flags, or the first with an API key set
+func (f *providerFlags) resolve() (*modelTarget, error) {
+	name, provider, key, err := selectProvider(f.name)
+	if err != nil {
+		return nil, err
+	}
+	return &modelTarget{name: name, provider: provider, model: f.model, key: key}, nil
+}
+
+// ask sends a prompt to the target and returns the response text
+func (t *modelTarget) ask(prompt string) (string, error) {
+	return askModel(t.provider, t.model, t.key, prompt)
+}
+
+// writeResponse writes a model response to the -output file, or stdout when none is set
+func writeResponse(response string, cli cliOptions, logger *handoff.Logger) {
+	if cli.outputFile == "" {
+		fmt.Println(response)
+		return
+	}
+	if err := handoff.WriteToFile(response, cli.outputFile, cli.force); err != nil {
+		logger.Error("Failed to write to file %s: %v", cli.outputFile, err)
+		os.Exit(1)
+	}
+	logger.Info("Response written to %s", cli.outputFile)
+}
+
+// askPrompt combines the collected context with the user's question
+func askPrompt(context, question string) string {
+	return context + "\n\n" + question
+}
+
+// runAsk implements "handoff ask": it collects context from the given paths,
+// sends it with the prompt to the configured provider, and writes the response
+// to -output or stdout.
+func runAsk(args []string) {
+	var prompt string
+	flag.StringVar(&prompt, "prompt", "", "Question or instruction to send along with the context (required)")
+	providers := registerProviderFlags()
+
+	config, cli := parseConfigArgs(args)
+	logger := handoff.NewLogger(config.Verbose)
+
+	if prompt == "" || flag.NArg() < 1 {
+		logger.Error("usage: %s ask -prompt \"...\" [options] path1 [path2 ...]", os.Args[0])
+		os.Exit(1)
+	}
+
+	// Resolve the provider before collecting context so a missing key fails fast
+	var target *modelTarget
+	if !cli.dryRun {
+		var err error
+		if target, err = providers.resolve(); err != nil {
+			logger.Error("%v", err)
+			os.Exit(1)
+		}
+	}
+
+	content, stats, err := handoff.ProcessProject(flag.Args(), config)
+	if err != nil {
+		logger.Error("Failed to process project: %v", err)
+		os.Exit(1)
+	}
+
+	if cli.dryRun {
+		out, done := startPreview(cli.noPager)
+		fmt.Fprintln(out, "### DRY RUN: Prompt that would be sent ###")
+		fmt.Fprintln(out, askPrompt(content, prompt))
+		done()
+		return
+	}
+	logger.Info("Sending %d files (~%d tokens) to %s", stats.FilesProcessed, stats.Tokens, target.name)
+
+	response, err := target.ask(askPrompt(content, prompt))
+	if err != nil {
+		logger.Error("%v", err)
+		os.Exit(1)
+	}
+	writeResponse(response, cli, logger)
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// clearProviderKeys unsets every provider API key for the duration of a test
+func clearProviderKeys(t *testing.T) {
+	t.Helper()
+	for _, provider := range llmProviders {
+		t.Setenv(provider.keyEnv, "")
+	}
+}
+
+// TestSelectProvider tests choosing a provider explicitly or from API key env vars
+func TestSelectProvider(t *testing.T) {
+	clearProviderKeys(t)
+
+	if _, _, _, err := selectProvider(""); !errors.Is(err, ErrNoProvider) {
+		t.Errorf("selectProvider(\"\") error = %v, want ErrNoProvider", err)
+	}
+
+	t.Setenv("GEMINI_API_KEY", "gem-key")
+	t.Setenv("OPENAI_API_KEY", "oa-key")
+	name, _, key, err := selectProvider("")
+	if err != nil || name != "openai" || key != "oa-key" {
+		t.Errorf("selectProvider(\"\") = %q, %q, %v; want openai with its key", name, key, err)
+	}
+
+	name, _, key, err = selectProvider("Gemini")
+	if err != nil || name != "gemini" || key != "gem-key" {
+		t.Errorf("selectProvider(\"Gemini\") = %q, %q, %v; want gemini with its key", name, key, err)
+	}
+
+	if _, _, _, err := selectProvider("anthropic"); err == nil || !strings.Contains(err.Error(), "ANTHROPIC_API_KEY") {
+		t.Errorf("selectProvider(\"anthropic\") error = %v, want missing key error", err)
+	}
+	if _, _, _, err := selectProvider("mistral"); err == nil {
+		t.Error("selectProvider(\"mistral\") succeeded, want error")
+	}
+}
+
+// TestAskModel tests request construction and response parsing for each provider
+func TestAskModel(t *testing.T) {
+	testCases := []struct {
+		provider   string
+		wantPath   string
+		wantHeader string
+		response   string
+	}{
+		{
+			provider:   "anthropic",
+			wantPath:   "/v1/messages",
+			wantHeader: "x-api-key",
+			response:   `{"content": [{"type": "text", "text": "the "}, {"type": "text", "text": "answer"}]}`,
+		},
+		{
+			provider:   "openai",
+			wantPath:   "/v1/chat/completions",
+			wantHeader: "Authorization",
+			response:   `{"choices": [{"message": {"content": "the answer"}}]}`,
+		},
+		{
+			provider:   "gemini",
+			wantPath:   "/v1beta/models/test-model:generateContent",
+			wantHeader: "x-goog-api-key",
+			response:   `{"candidates": [{"content": {"parts": [{"text": "the answer"}]}}]}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.provider, func(t *testing.T) {
+			var body string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != tc.wantPath {
+					http.Error(w, "unexpected path "+r.URL.Path, http.StatusNotFound)
+					return
+				}
+				if !strings.Contains(r.Header.Get(tc.wantHeader), "secret") {
+					http.Error(w, "missing credentials", http.StatusUnauthorized)
+					return
+				}
+				data, _ := io.ReadAll(r.Body)
+				body = string(data)
+				_, _ = w.Write([]byte(tc.response))
+			}))
+			defer server.Close()
+
+			provider := *llmProviders[tc.provider]
+			provider.baseURL = server.URL
+
+			got, err := askModel(&provider, "test-model", "secret", "<context>x</context>\n\nWhat is it?")
+			if err != nil {
+				t.Fatalf("askModel failed: %v", err)
+			}
+			if got != "the answer" {
+				t.Errorf("askModel() = %q, want %q", got, "the answer")
+			}
+			if !json.Valid([]byte(body)) || !strings.Contains(body, "What is it?") {
+				t.Errorf("request body = %s, want JSON containing the prompt", body)
+			}
+
+			if _, err := askModel(&provider, "test-model", "wrong", "prompt"); err == nil || !strings.Contains(err.Error(), "401") {
+				t.Errorf("askModel with bad key error = %v, want 401 error", err)
+			}
+		})
+	}
+}
@@ -3,6 +3,9 @@ package main
 
 import (
 	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -291,6 +294,12 @@ func TestCLIFiltering(t *testing.T) {
 			included: []string{"file1.txt", filepath.Join("subdir", "subfile1.txt")},
 			excluded: []string{"file2.go", "file3.json", "file4.md", filepath.Join("subdir", "subfile2.go")},
 		},
+		{
+			name:     "Include by content regex",
+			args:     []string{"-include=.go", "-include-content-regex=func Sub\\("},
+			included: []string{filepath.Join("subdir", "subfile2.go")},
+			excluded: []string{"file2.go", "file1.txt"},
+		},
 	}
 
 	for _, tc := range tests {
@@ -418,6 +427,134 @@ func TestCLIDryRun(t *testing.T) {
 	}
 }
 
+// TestCLIResume tests continuing from a checkpoint left by an interrupted run.
+func TestCLIResume(t *testing.T) {
+	binaryPath := buildBinary(t)
+	tempDir, _ := createTestFiles(t)
+	outputFile := filepath.Join(tempDir, "resumed_output.md")
+	inputFile := filepath.Join(tempDir, "file1.txt")
+
+	// Leave a checkpoint as an interrupted run would, recording different
+	// content for the unchanged file so its reuse is visible
+	info, err := os.Stat(inputFile)
+	if err != nil {
+		t.Fatalf("Failed to stat input file: %v", err)
+	}
+	entry, err := json.Marshal(map[string]any{
+		"path":    inputFile,
+		"size":    info.Size(),
+		"modTime": info.ModTime().UnixNano(),
+		"content": []byte("Recorded before the interruption"),
+	})
+	if err != nil {
+		t.Fatalf("Failed to encode checkpoint: %v", err)
+	}
+	if err := os.WriteFile(resumeFileName(outputFile), append(entry, '\n'), 0644); err != nil {
+		t.Fatalf("Failed to write checkpoint: %v", err)
+	}
+
+	_, stderr, err := runCliCommand(t, binaryPath, "-resume", "-output="+outputFile, inputFile)
+	if err != nil {
+		t.Fatalf("Failed to run with -resume: %v\nStderr: %s", err, stderr)
+	}
+	if !strings.Contains(stderr, "Resuming from") {
+		t.Errorf("Expected a resume message, got: %s", stderr)
+	}
+
+	content, err := os.ReadFile(outputFile)
+	if err != nil {
+		t.Fatalf("Failed to read output file: %v", err)
+	}
+	if !strings.Contains(string(content), "Recorded before the interruption") {
+		t.Errorf("Output should reuse the recorded content, got: %s", content)
+	}
+
+	// The checkpoint is removed once the output is written
+	if _, err := os.Stat(resumeFileName(outputFile)); !os.IsNotExist(err) {
+		t.Errorf("Checkpoint should be removed after a successful run, stat error: %v", err)
+	}
+
+	// -resume needs an output file to keep the checkpoint next to
+	_, stderr, err = runCliCommand(t, binaryPath, "-resume", inputFile)
+	if err == nil || !strings.Contains(stderr, "-resume requires -output") {
+		t.Errorf("Expected -resume without -output to fail, got err=%v stderr=%s", err, stderr)
+	}
+}
+
+// TestCLIManifest tests writing a manifest alongside the output
+func TestCLIManifest(t *testing.T) {
+	binaryPath := buildBinary(t)
+	tempDir, _ := createTestFiles(t)
+	outputFile := filepath.Join(tempDir, "output.md")
+	manifestFile := filepath.Join(tempDir, "manifest.json")
+	inputFile := filepath.Join(tempDir, "file1.txt")
+
+	_, stderr, err := runCliCommand(t, binaryPath, "-output="+outputFile, "-manifest="+manifestFile, inputFile)
+	if err != nil {
+		t.Fatalf("Failed to run with -manifest: %v\nStderr: %s", err, stderr)
+	}
+
+	data, err := os.ReadFile(manifestFile)
+	if err != nil {
+		t.Fatalf("Failed to read manifest: %v", err)
+	}
+	var manifest struct {
+		Digest string `json:"digest"`
+		Stats  struct {
+			IncludedFiles []string `json:"includedFiles"`
+		} `json:"stats"`
+	}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		t.Fatalf("Manifest is not valid JSON: %v\n%s", err, data)
+	}
+	if len(manifest.Stats.IncludedFiles) != 1 || manifest.Stats.IncludedFiles[0] != inputFile {
+		t.Errorf("Manifest includedFiles = %v, want [%s]", manifest.Stats.IncludedFiles, inputFile)
+	}
+	content, err := os.ReadFile(outputFile)
+	if err != nil {
+		t.Fatalf("Failed to read output file: %v", err)
+	}
+	sum := sha256.Sum256(content)
+	if want := "sha256:" + hex.EncodeToString(sum[:]); manifest.Digest != want {
+		t.Errorf("Manifest digest = %s, want %s", manifest.Digest, want)
+	}
+
+	// An existing manifest is only replaced with -force
+	_, stderr, err = runCliCommand(t, binaryPath, "-dry-run", "-manifest="+manifestFile, inputFile)
+	if err != nil {
+		t.Errorf("A dry run should not check the manifest: %v\nStderr: %s", err, stderr)
+	}
+	_, stderr, err = runCliCommand(t, binaryPath, "-output="+outputFile, "-force", "-manifest="+manifestFile, inputFile)
+	if err != nil {
+		t.Errorf("Failed to overwrite with -force: %v\nStderr: %s", err, stderr)
+	}
+	_, stderr, err = runCliCommand(t, binaryPath, "-output="+filepath.Join(tempDir, "other.md"), "-manifest="+manifestFile, inputFile)
+	if err == nil || !strings.Contains(stderr, "already exists") {
+		t.Errorf("Expected an existing manifest to fail without -force, got err=%v stderr=%s", err, stderr)
+	}
+}
+
+// TestCLIPreflight tests reporting problems without generating output.
+func TestCLIPreflight(t *testing.T) {
+	binaryPath := buildBinary(t)
+	tempDir, _ := createTestFiles(t)
+	inputFile := filepath.Join(tempDir, "file1.txt")
+	missingFile := filepath.Join(tempDir, "missing.txt")
+
+	stdout, stderr, err := runCliCommand(t, binaryPath, "-preflight", inputFile)
+	if err != nil || stdout != "" {
+		t.Errorf("Preflight of a readable file failed: %v\nStdout: %s\nStderr: %s", err, stdout, stderr)
+	}
+
+	stdout, _, err = runCliCommand(t, binaryPath, "-preflight", inputFile, missingFile)
+	if err == nil {
+		t.Error("Expected preflight to exit with an error when problems are found")
+	}
+	if want := missingFile + ": unreadable (no such file or directory)\n"; stdout != want {
+		t.Errorf("Preflight stdout = %q, want %q", stdout, want)
+	}
+}
+
 // TestCLIVerboseFlag tests the -verbose flag.
 func TestCLIVerboseFlag(t *testing.T) {
 	binaryPath := buildBinary(t)
@@ -537,6 +674,19 @@ func TestCLIErrorHandling(t *testing.T) {
 	if len(stdout) > 0 && !strings.Contains(stdout, "No files processed") {
 		t.Errorf("Expected empty or 'No files processed' output, got: %s", stdout)
 	}
+
+	// Test 3: Unknown model
+	_, stderr, err = runCliCommand(t, binaryPath, "-model", "gpt-9", "-dry-run", ".")
+	if err == nil || !strings.Contains(stderr, "unknown model") {
+		t.Errorf("Expected unknown model error, got err=%v stderr=%s", err, stderr)
+	}
+
+	// Test 4: Output exceeding the model's context window in strict mode
+	testDir, _ := createTestFiles(t)
+	_, stderr, err = runCliCommand(t, binaryPath, "-model", "gpt-4o", "-response-reserve", "127999", "-strict", "-dry-run", testDir)
+	if err == nil || !strings.Contains(stderr, "context window") {
+		t.Errorf("Expected context window error in strict mode, got err=%v stderr=%s", err, stderr)
+	}
 }
 
 // TestCLIOutputToFile tests writing output to a file.
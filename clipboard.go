@@ -0,0 +1,138 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+	"unicode/utf16"
+)
+
+// ErrClipboardFailed is returned when all clipboard commands fail
+var ErrClipboardFailed = errors.New("clipboard commands failed")
+
+// ErrClipboardNotVerified is returned when the clipboard doesn't hold the copied
+// text after a copy command reported success
+var ErrClipboardNotVerified = errors.New("clipboard verification failed")
+
+// clipboardTool is a clipboard utility with the commands that write and read it
+type clipboardTool struct {
+	copy  []string
+	paste []string
+
+	// encode converts the text to the bytes the copy command expects; nil
+	// sends it as UTF-8
+	encode func(text string) []byte
+}
+
+// clipboardTools are tried in order until one succeeds
+var clipboardTools = []clipboardTool{
+	{copy: []string{"pbcopy"}, paste: []string{"pbpaste"}},                                                          // macOS
+	{copy: []string{"xclip", "-selection", "clipboard"}, paste: []string{"xclip", "-selection", "clipboard", "-o"}}, // X11
+	{copy: []string{"wl-copy"}, paste: []string{"wl-paste", "-n"}},                                                  // Wayland
+}
+
+// wslClipboardTool copies to the Windows clipboard from WSL. clip.exe reads
+// its input in the console code page unless it starts with a UTF-16 byte
+// order mark, so text is sent as UTF-16. There is no paste counterpart whose
+// output matches byte for byte, so copies through it are not verified.
+var wslClipboardTool = clipboardTool{copy: []string{"clip.exe"}, encode: encodeUTF16LE}
+
+// clipboardChain returns the clipboard tools to try, adding clip.exe under WSL
+func clipboardChain() []clipboardTool {
+	procVersion, _ := os.ReadFile("/proc/version")
+	if isWSL(os.Getenv("WSL_DISTRO_NAME"), string(procVersion)) {
+		return append(slices.Clone(clipboardTools), wslClipboardTool)
+	}
+	return clipboardTools
+}
+
+// isWSL reports whether the process runs under the Windows Subsystem for Linux,
+// from the WSL_DISTRO_NAME variable or the kernel version string
+func isWSL(distroName, procVersion string) bool {
+	return distroName != "" || strings.Contains(strings.ToLower(procVersion), "microsoft")
+}
+
+// encodeUTF16LE encodes text as UTF-16LE with a byte order mark
+func encodeUTF16LE(text string) []byte {
+	units := utf16.Encode([]rune(text))
+	out := make([]byte, 2, 2+2*len(units))
+	out[0], out[1] = 0xFF, 0xFE
+	for _, unit := range units {
+		out = append(out, byte(unit), byte(unit>>8))
+	}
+	return out
+}
+
+// copyToClipboard copies text to the system clipboard with enhanced error reporting.
+// A non-empty command, split into arguments as a shell would, replaces the
+// built-in tools, and
+// its failure is reported without falling back to them.
+// With verify, the clipboard is read back after a successful copy and compared
+// by checksum, since some utilities exit 0 without storing anything (for example
+// xclip on a headless X server); a mismatch is a hard error. Custom commands
+// and clip.exe have no way to read the clipboard back, so they are not verified.
+func copyToClipboard(text, command string, verify bool) error {
+	args, err := splitFlags(command)
+	if err != nil {
+		return fmt.Errorf("%w: invalid clipboard command %q: %v", ErrClipboardFailed, command, err)
+	}
+	if len(args) > 0 {
+		cmd := exec.Command(args[0], args[1:]...)
+		cmd.Stdin = strings.NewReader(text)
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("%w: %s failed: %v", ErrClipboardFailed, args[0], err)
+		}
+		return nil
+	}
+
+	var failures []string
+
+	for _, tool := range clipboardChain() {
+		name := tool.copy[0]
+		if _, err := exec.LookPath(name); err != nil {
+			failures = append(failures, name+" not found")
+			continue
+		}
+
+		input := []byte(text)
+		if tool.encode != nil {
+			input = tool.encode(text)
+		}
+		cmd := exec.Command(name, tool.copy[1:]...)
+		cmd.Stdin = bytes.NewReader(input)
+		if err := cmd.Run(); err != nil {
+			failures = append(failures, fmt.Sprintf("%s failed: %v", name, err))
+			continue
+		}
+
+		if verify && tool.paste != nil {
+			return verifyClipboard(tool, text)
+		}
+		return nil // Success
+	}
+
+	// If we get here, all clipboard commands failed
+	return fmt.Errorf("%w: %s", ErrClipboardFailed, strings.Join(failures, "; "))
+}
+
+// verifyClipboard reads the clipboard back with the tool's paste command and
+// checks that it holds text
+func verifyClipboard(tool clipboardTool, text string) error {
+	name := tool.paste[0]
+	pasted, err := exec.Command(name, tool.paste[1:]...).Output()
+	if err != nil {
+		return fmt.Errorf("%w: %s failed: %v", ErrClipboardNotVerified, name, err)
+	}
+
+	want := sha256.Sum256([]byte(text))
+	if got := sha256.Sum256(pasted); !bytes.Equal(got[:], want[:]) {
+		return fmt.Errorf("%w: %s returned %d bytes, want the %d bytes copied with %s",
+			ErrClipboardNotVerified, name, len(pasted), len(text), tool.copy[0])
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+// fakeClipboard installs stand-in pbcopy and pbpaste commands as the only
+// commands on PATH. When working is false, pbcopy succeeds without storing
+// anything, like xclip on a headless X server.
+func fakeClipboard(t *testing.T, working bool) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("uses shell scripts as fake clipboard commands")
+	}
+	cat, err := exec.LookPath("cat")
+	if err != nil {
+		t.Skip("cat not available")
+	}
+
+	dir := t.TempDir()
+	stored := filepath.Join(dir, "stored")
+	copyScript := "#!/bin/sh\n" + cat + " > " + stored + "\n"
+	if !working {
+		copyScript = "#!/bin/sh\n" + cat + " > /dev/null\n"
+	}
+	pasteScript := "#!/bin/sh\n[ -f " + stored + " ] && " + cat + " " + stored + "\nexit 0\n"
+	if err := os.WriteFile(filepath.Join(dir, "pbcopy"), []byte(copyScript), 0755); err != nil {
+		t.Fatalf("Failed to write fake pbcopy: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "pbpaste"), []byte(pasteScript), 0755); err != nil {
+		t.Fatalf("Failed to write fake pbpaste: %v", err)
+	}
+	t.Setenv("PATH", dir)
+}
+
+// TestCopyToClipboardVerify tests reading the clipboard back after copying
+func TestCopyToClipboardVerify(t *testing.T) {
+	t.Run("copy took", func(t *testing.T) {
+		fakeClipboard(t, true)
+		if err := copyToClipboard("<context>\nhi\n</context>", "", true); err != nil {
+			t.Errorf("copyToClipboard with verification failed: %v", err)
+		}
+	})
+
+	t.Run("copy silently dropped", func(t *testing.T) {
+		fakeClipboard(t, false)
+		if err := copyToClipboard("<context>\nhi\n</context>", "", false); err != nil {
+			t.Errorf("copyToClipboard without verification failed: %v", err)
+		}
+		err := copyToClipboard("<context>\nhi\n</context>", "", true)
+		if !errors.Is(err, ErrClipboardNotVerified) {
+			t.Errorf("copyToClipboard error = %v, want ErrClipboardNotVerified", err)
+		}
+	})
+}
+
+// TestCopyToClipboardCustomCommand tests routing output through a custom clipboard command
+func TestCopyToClipboardCustomCommand(t *testing.T) {
+	fakeClipboard(t, false)
+	cat, err := exec.LookPath("cat")
+	if err != nil {
+		t.Skip("cat not available")
+	}
+
+	dir := t.TempDir()
+	stored := filepath.Join(dir, "stored")
+	script := filepath.Join(dir, "mytool")
+	if err := os.WriteFile(script, []byte("#!/bin/sh\n[ \"$1\" = --stdin ] || exit 2\n"+cat+" > "+stored+"\n"), 0755); err != nil {
+		t.Fatalf("Failed to write fake tool: %v", err)
+	}
+
+	if err := copyToClipboard("<context>\nhi\n</context>", script+" --stdin", true); err != nil {
+		t.Fatalf("copyToClipboard with custom command failed: %v", err)
+	}
+	data, err := os.ReadFile(stored)
+	if err != nil || string(data) != "<context>\nhi\n</context>" {
+		t.Errorf("custom command received %q (%v), want the output", data, err)
+	}
+
+	// The command is split like a shell would, so quoted paths may hold spaces
+	if err := copyToClipboard("quoted", "'"+script+"' \"--stdin\"", false); err != nil {
+		t.Errorf("copyToClipboard with quoted custom command failed: %v", err)
+	}
+	if err := copyToClipboard("content", "'"+script, false); !errors.Is(err, ErrClipboardFailed) {
+		t.Errorf("copyToClipboard with unterminated quote error = %v, want ErrClipboardFailed", err)
+	}
+
+	// A failing custom command is an error even though pbcopy is available
+	if err := copyToClipboard("content", script+" --wrong", false); !errors.Is(err, ErrClipboardFailed) {
+		t.Errorf("copyToClipboard error = %v, want ErrClipboardFailed", err)
+	}
+}
+
+// TestIsWSL tests detecting the Windows Subsystem for Linux
+func TestIsWSL(t *testing.T) {
+	testCases := []struct {
+		distro      string
+		procVersion string
+		want        bool
+	}{
+		{"Ubuntu", "", true},
+		{"", "Linux version 5.15.153.1-microsoft-standard-WSL2 (root@1ff1e4ea0f52)", true},
+		{"", "Linux version 4.4.0-19041-Microsoft (Microsoft@Microsoft.com)", true},
+		{"", "Linux version 6.8.0-45-generic (buildd@lcy02-amd64-075)", false},
+	}
+
+	for _, tc := range testCases {
+		if got := isWSL(tc.distro, tc.procVersion); got != tc.want {
+			t.Errorf("isWSL(%q, %q) = %v, want %v", tc.distro, tc.procVersion, got, tc.want)
+		}
+	}
+}
+
+// TestCopyToClipboardWSL tests falling back to clip.exe under WSL
+func TestCopyToClipboardWSL(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script as a fake clip.exe")
+	}
+	cat, err := exec.LookPath("cat")
+	if err != nil {
+		t.Skip("cat not available")
+	}
+
+	dir := t.TempDir()
+	stored := filepath.Join(dir, "stored")
+	if err := os.WriteFile(filepath.Join(dir, "clip.exe"), []byte("#!/bin/sh\n"+cat+" > "+stored+"\n"), 0755); err != nil {
+		t.Fatalf("Failed to write fake clip.exe: %v", err)
+	}
+	t.Setenv("PATH", dir)
+	t.Setenv("WSL_DISTRO_NAME", "Ubuntu")
+
+	if err := copyToClipboard("hé", "", true); err != nil {
+		t.Fatalf("copyToClipboard under WSL failed: %v", err)
+	}
+	data, err := os.ReadFile(stored)
+	if err != nil {
+		t.Fatalf("Failed to read stored clipboard: %v", err)
+	}
+	if want := "\xff\xfeh\x00\xe9\x00"; string(data) != want {
+		t.Errorf("clip.exe received %q, want UTF-16LE with a byte order mark %q", data, want)
+	}
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	handoff "github.com/phrazzld/handoff/lib"
+)
+
+// runDiff implements "handoff diff": it collects the files changed in a
+// directory (the current one by default), modified and untracked, optionally
+// followed by their diff, and writes them to -output or copies them to the
+// clipboard.
+func runDiff(args []string) {
+	var base string
+	var staged, patch bool
+	flag.StringVar(&base, "base", "", "Revision to compare against, e.g. main (default: uncommitted changes relative to HEAD)")
+	flag.BoolVar(&staged, "staged", false, "Collect only changes staged in the index")
+	flag.BoolVar(&patch, "patch", false, "Append the unified diff of the changes after the files")
+
+	config, cli := parseConfigArgs(args)
+	logger := handoff.NewLogger(config.Verbose)
+
+	if flag.NArg() > 1 || (staged && base != "") {
+		logger.Error("usage: %s diff [-base rev | -staged] [-patch] [options] [dir]", os.Args[0])
+		os.Exit(1)
+	}
+	dir := "."
+	if flag.NArg() == 1 {
+		dir = flag.Arg(0)
+	}
+	if staged {
+		base = handoff.StagedBase
+	}
+	if patch {
+		handoff.WithIncludeDiff(true)(config)
+	}
+
+	content, stats, err := handoff.ProcessChanges(dir, base, config)
+	if errors.Is(err, handoff.ErrNoChanges) {
+		logger.Info("No changed files in %s", dir)
+		return
+	}
+	if err != nil {
+		logger.Error("Failed to collect changes: %v", err)
+		os.Exit(1)
+	}
+
+	deliverChanges(content, cli, logger)
+	logStatistics(stats, config, logger)
+}
+
+// deliverChanges previews the collected changes or writes them to -output or
+// the clipboard
+func deliverChanges(content string, cli cliOptions, logger *handoff.Logger) {
+	switch {
+	case cli.dryRun:
+		out, done := startPreview(cli.noPager)
+		fmt.Fprintln(out, "### DRY RUN: Content that would be generated ###")
+		fmt.Fprintln(out, content)
+		done()
+	case cli.outputFile != "":
+		if err := handoff.WriteToFile(content, cli.outputFile, cli.force); err != nil {
+			logger.Error("Failed to write to file %s: %v", cli.outputFile, err)
+			os.Exit(1)
+		}
+		logger.Info("Changes written to %s", cli.outputFile)
+	default:
+		if err := copyToClipboard(content, cli.clipboardCmd, cli.verifyClipboard); err != nil {
+			logger.Error("Failed to copy to clipboard: %v", err)
+			os.Exit(1)
+		}
+		logger.Info("Changes copied to clipboard.")
+	}
+}
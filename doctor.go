@@ -0,0 +1,155 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	handoff "github.com/phrazzld/handoff/lib"
+)
+
+// clipboardDisplayVars are the environment variables naming the display
+// server a clipboard tool needs; without them the tool is installed but can't
+// reach a clipboard
+var clipboardDisplayVars = map[string]string{
+	"xclip":   "DISPLAY",
+	"wl-copy": "WAYLAND_DISPLAY",
+}
+
+// runDoctor implements "handoff doctor": it reports the environment handoff
+// depends on, such as git, the clipboard tools, and the config in effect, for
+// diagnosing clipboard or discovery problems.
+func runDoctor(args []string) {
+	config, cli := parseConfigArgs(args)
+	writeDoctorReport(os.Stdout, config, cli)
+}
+
+// writeDoctorReport writes the git, clipboard, config, and filter sections of
+// the doctor report
+func writeDoctorReport(w io.Writer, config *handoff.Config, cli cliOptions) {
+	fmt.Fprintln(w, "Git:")
+	for _, line := range gitStatusLines() {
+		fmt.Fprintf(w, "  %s\n", line)
+	}
+
+	fmt.Fprintln(w, "Clipboard:")
+	if args, err := splitFlags(cli.clipboardCmd); err != nil {
+		fmt.Fprintf(w, "  custom command %q: invalid (%v)\n", cli.clipboardCmd, err)
+	} else if len(args) > 0 {
+		fmt.Fprintf(w, "  custom command %q: %s\n", cli.clipboardCmd, lookPathStatus(args[0]))
+	}
+	for _, tool := range clipboardChain() {
+		fmt.Fprintf(w, "  %s: %s\n", tool.copy[0], clipboardToolStatus(tool))
+	}
+
+	writeConfigStatus(w)
+	writeFilterStatus(w, config)
+}
+
+// writeConfigStatus reports the config file, profile, and HANDOFF_FLAGS in use
+func writeConfigStatus(w io.Writer) {
+	fmt.Fprintln(w, "Config:")
+	root := ""
+	if f := flag.Lookup("root"); f != nil {
+		root = f.Value.String()
+	}
+	configFile, _, _ := handoff.FindConfigFile(root)
+	if f := flag.Lookup("config"); f != nil && f.Value.String() != "" {
+		configFile = f.Value.String()
+	}
+	if exists, err := handoff.FileExists(configFile); err != nil || !exists {
+		fmt.Fprintf(w, "  %s: not found\n", configFile)
+	} else {
+		fmt.Fprintf(w, "  %s: loaded\n", configFile)
+	}
+	if f := flag.Lookup("profile"); f != nil && f.Value.String() != "" {
+		fmt.Fprintf(w, "  profile: %s\n", f.Value.String())
+	}
+	if value := os.Getenv(envFlagsName); value != "" {
+		fmt.Fprintf(w, "  %s: %s\n", envFlagsName, value)
+	} else {
+		fmt.Fprintf(w, "  %s: not set\n", envFlagsName)
+	}
+}
+
+// writeFilterStatus reports the filters the configuration applies
+func writeFilterStatus(w io.Writer, config *handoff.Config) {
+	filters := config.Filters()
+	fmt.Fprintln(w, "Filters:")
+	fmt.Fprintf(w, "  include: %s\n", listOrNone(filters.Include, "all extensions"))
+	fmt.Fprintf(w, "  exclude: %s\n", listOrNone(filters.Exclude, "none"))
+	fmt.Fprintf(w, "  exclude names: %s\n", listOrNone(filters.ExcludeNames, "none"))
+	fmt.Fprintf(w, "  hidden allowlist: %s\n", listOrNone(filters.HiddenAllowlist, "none"))
+	for _, rule := range filters.PathRules {
+		fmt.Fprintf(w, "  path rule %s: include %s, exclude %s\n",
+			rule.Path, listOrNone(rule.Include, "all extensions"), listOrNone(rule.Exclude, "none"))
+	}
+	if config.MaxFileSize > 0 {
+		fmt.Fprintf(w, "  max file size: %d bytes\n", config.MaxFileSize)
+	} else {
+		fmt.Fprintln(w, "  max file size: unlimited")
+	}
+	fmt.Fprintf(w, "  .gitignore: %s\n", bypassText(config.IgnoreGitignore))
+	fmt.Fprintf(w, "  .gitattributes linguist markers: %s\n", bypassText(config.IgnoreGitattributes))
+}
+
+// gitStatusLines describes the git installation and the repository containing
+// the working directory
+func gitStatusLines() []string {
+	path, err := exec.LookPath("git")
+	if err != nil {
+		return []string{"not found; directories are walked without .gitignore support"}
+	}
+	lines := []string{"found at " + path}
+	if out, err := exec.Command("git", "--version").Output(); err == nil {
+		lines[0] = strings.TrimSpace(string(out)) + " at " + path
+	}
+	if out, err := exec.Command("git", "rev-parse", "--show-toplevel").Output(); err == nil {
+		lines = append(lines, "repository: "+strings.TrimSpace(string(out)))
+	} else {
+		lines = append(lines, "working directory is not in a git repository")
+	}
+	return lines
+}
+
+// clipboardToolStatus describes whether a clipboard tool is installed and can
+// reach a clipboard
+func clipboardToolStatus(tool clipboardTool) string {
+	name := tool.copy[0]
+	path, err := exec.LookPath(name)
+	if err != nil {
+		return "not found"
+	}
+	if variable, ok := clipboardDisplayVars[name]; ok && os.Getenv(variable) == "" {
+		return "found at " + path + ", but " + variable + " is not set"
+	}
+	return "found at " + path + ", usable"
+}
+
+// lookPathStatus describes where a command is installed
+func lookPathStatus(name string) string {
+	path, err := exec.LookPath(name)
+	if err != nil {
+		return "not found"
+	}
+	return "found at " + path
+}
+
+// bypassText describes whether a source of ignore rules is bypassed
+func bypassText(bypassed bool) string {
+	if bypassed {
+		return "bypassed"
+	}
+	return "respected"
+}
+
+// listOrNone joins a list for display, or returns empty when it has no items
+func listOrNone(items []string, empty string) string {
+	if len(items) == 0 {
+		return empty
+	}
+	return strings.Join(items, ", ")
+}
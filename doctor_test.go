@@ -0,0 +1,67 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	handoff "github.com/phrazzld/handoff/lib"
+)
+
+// TestClipboardToolStatus tests reporting whether clipboard tools can reach a clipboard
+func TestClipboardToolStatus(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script as a fake clipboard command")
+	}
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "xclip"), []byte("#!/bin/sh\nexit 0\n"), 0755); err != nil {
+		t.Fatalf("Failed to write fake xclip: %v", err)
+	}
+	t.Setenv("PATH", dir)
+	xclip := clipboardTools[1]
+
+	t.Setenv("DISPLAY", "")
+	if got := clipboardToolStatus(xclip); !strings.HasSuffix(got, "but DISPLAY is not set") {
+		t.Errorf("clipboardToolStatus(xclip) without DISPLAY = %q, want a missing DISPLAY warning", got)
+	}
+
+	t.Setenv("DISPLAY", ":0")
+	if got, want := clipboardToolStatus(xclip), "found at "+filepath.Join(dir, "xclip")+", usable"; got != want {
+		t.Errorf("clipboardToolStatus(xclip) = %q, want %q", got, want)
+	}
+
+	if got := clipboardToolStatus(clipboardTools[0]); got != "not found" {
+		t.Errorf("clipboardToolStatus(pbcopy) = %q, want not found", got)
+	}
+}
+
+// TestWriteDoctorReport tests that the report shows the effective filters
+func TestWriteDoctorReport(t *testing.T) {
+	t.Setenv(envFlagsName, "-include .go")
+	config := handoff.NewConfig(
+		handoff.WithInclude(".go"),
+		handoff.WithExcludeNames("go.sum"),
+		handoff.WithIgnoreGitignore(true),
+	)
+
+	var buf bytes.Buffer
+	writeDoctorReport(&buf, config, cliOptions{clipboardCmd: "no-such-clipboard-tool --in"})
+	report := buf.String()
+
+	for _, want := range []string{
+		"Git:\n",
+		"  custom command \"no-such-clipboard-tool --in\": not found\n",
+		"  HANDOFF_FLAGS: -include .go\n",
+		"  include: .go\n",
+		"  exclude: none\n",
+		"  exclude names: go.sum\n",
+		"  .gitignore: bypassed\n",
+	} {
+		if !strings.Contains(report, want) {
+			t.Errorf("doctor report should contain %q, got:\n%s", want, report)
+		}
+	}
+}
@@ -0,0 +1,192 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// shortFlags maps the single-letter aliases of common flags to their long names
+var shortFlags = map[string]string{
+	"i": "include",
+	"e": "exclude",
+	"o": "output",
+	"v": "verbose",
+	"f": "force",
+	"n": "dry-run",
+}
+
+// registerShortFlags defines the single-letter aliases of the flags in fs,
+// sharing the long flags' values (internal helper)
+func registerShortFlags(fs *flag.FlagSet) {
+	for short, long := range shortFlags {
+		if f := fs.Lookup(long); f != nil && fs.Lookup(short) == nil {
+			fs.Var(f.Value, short, "Short for --"+long)
+		}
+	}
+}
+
+// longFlagName returns the long name of a flag given by its alias or long name
+func longFlagName(name string) string {
+	if long, ok := shortFlags[name]; ok {
+		return long
+	}
+	return name
+}
+
+// permuteArgs moves flags ahead of positional paths, as GNU tools allow, so
+// "handoff src -include=.go" works like "handoff -include=.go src". Flags that
+// take a value keep the argument after them; "--" ends the flags, leaving
+// everything after it positional. The paths follow a "--" in the result, so
+// a path starting with a dash isn't read as a flag.
+func permuteArgs(fs *flag.FlagSet, args []string) []string {
+	var flags, paths []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			paths = append(paths, args[i+1:]...)
+			break
+		}
+		if len(arg) < 2 || arg[0] != '-' {
+			paths = append(paths, arg)
+			continue
+		}
+		flags = append(flags, arg)
+		name := strings.TrimLeft(arg, "-")
+		if strings.Contains(name, "=") {
+			continue
+		}
+		if f := fs.Lookup(name); f != nil && !isBoolFlag(f) && i+1 < len(args) {
+			i++
+			flags = append(flags, args[i])
+		}
+	}
+	if len(paths) == 0 {
+		return flags
+	}
+	return append(append(flags, "--"), paths...)
+}
+
+// isBoolFlag reports whether a flag is a switch that takes no separate value
+func isBoolFlag(f *flag.Flag) bool {
+	b, ok := f.Value.(interface{ IsBoolFlag() bool })
+	return ok && b.IsBoolFlag()
+}
+
+// printFlags lists the flags in fs in GNU style, with two dashes and the short
+// alias first where there is one, leaving out the alias entries themselves
+func printFlags(fs *flag.FlagSet) {
+	aliases := make(map[string]string, len(shortFlags))
+	for short, long := range shortFlags {
+		aliases[long] = short
+	}
+
+	out := fs.Output()
+	fs.VisitAll(func(f *flag.Flag) {
+		if _, ok := shortFlags[f.Name]; ok {
+			return
+		}
+		line := "      --" + f.Name
+		if short, ok := aliases[f.Name]; ok {
+			line = "  -" + short + ", --" + f.Name
+		}
+		valueName, usage := flag.UnquoteUsage(f)
+		if valueName != "" {
+			line += " " + valueName
+		}
+		if valueName == "string" && f.DefValue != "" {
+			usage += fmt.Sprintf(" (default %q)", f.DefValue)
+		} else if valueName != "string" && !slices.Contains([]string{"", "false", "0"}, f.DefValue) {
+			usage += fmt.Sprintf(" (default %v)", f.DefValue)
+		}
+		fmt.Fprintf(out, "%s\n    \t%s\n", line, strings.ReplaceAll(usage, "\n", "\n    \t"))
+	})
+}
+
+// printUsage prints the command line synopsis followed by the flags
+func printUsage() {
+	fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [options] path1 [path2 ...]\n\nOptions may come before or after the paths.\n\n", os.Args[0])
+	printFlags(flag.CommandLine)
+}
+
+// stringListFlag is a flag.Value collecting every occurrence of a repeatable flag
+type stringListFlag []string
+
+// String returns the collected values
+func (f *stringListFlag) String() string {
+	return strings.Join(*f, ", ")
+}
+
+// Set appends a value
+func (f *stringListFlag) Set(value string) error {
+	*f = append(*f, value)
+	return nil
+}
+
+// splitFlags splits a command line, such as the HANDOFF_FLAGS value or
+// -clipboard-cmd, into arguments at whitespace, as a shell would: single
+// quotes keep everything literally, and in double quotes or unquoted text a
+// backslash escapes the next character.
+func splitFlags(value string) ([]string, error) {
+	var args []string
+	var current strings.Builder
+	inArg, escaped := false, false
+	var quote rune
+	for _, r := range value {
+		switch {
+		case escaped:
+			current.WriteRune(r)
+			escaped = false
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '\\':
+			inArg, escaped = true, true
+		case quote == '"':
+			if r == '"' {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			inArg, quote = true, r
+		case unicode.IsSpace(r):
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			inArg = true
+			current.WriteRune(r)
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote", quote)
+	}
+	if escaped {
+		return nil, fmt.Errorf("trailing backslash")
+	}
+	if inArg {
+		args = append(args, current.String())
+	}
+	return args, nil
+}
+
+// flagWasSet reports whether a flag was explicitly provided on the command line,
+// by its long name or short alias.
+func flagWasSet(name string) bool {
+	set := false
+	flag.Visit(func(f *flag.Flag) {
+		if longFlagName(f.Name) == name {
+			set = true
+		}
+	})
+	return set
+}
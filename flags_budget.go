@@ -0,0 +1,101 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	handoff "github.com/phrazzld/handoff/lib"
+)
+
+// budgetFlags holds the flags for fitting the output into a token budget
+type budgetFlags struct {
+	maxTokens       int
+	trimPriority    string
+	trimStrategy    string
+	chunkTokens     int
+	chunkOverlap    int
+	model           string
+	responseReserve int
+	strict          bool
+	strictSkips     string
+}
+
+// registerBudgetFlags defines the token budget, chunking, and model flags
+func registerBudgetFlags() *budgetFlags {
+	f := &budgetFlags{}
+	flag.IntVar(&f.maxTokens, "max-tokens", 0, "Drop files until the estimated tokens fit this budget (0 disables the budget)")
+	flag.StringVar(&f.trimPriority, "trim-priority", "", "Comma-separated order for dropping files under -max-tokens: largest, tests, or glob patterns (default: tests,largest)")
+	flag.StringVar(&f.trimStrategy, "trim-strategy", "", "How to cut files down under -max-tokens: drop, tail-truncate, or outline (default: drop)")
+	flag.IntVar(&f.chunkTokens, "chunk-tokens", 0, "Split output into numbered part files of at most this many estimated tokens each, written next to -output or, without -output, copied to the clipboard one at a time (0 disables splitting)")
+	flag.IntVar(&f.chunkOverlap, "chunk-overlap", 0, "With -chunk-tokens, repeat up to the last N lines of each part at the start of the next")
+	flag.StringVar(&f.model, "model", "", "Warn when the output exceeds this model's context window ("+strings.Join(handoff.ModelNames(), ", ")+")")
+	flag.IntVar(&f.responseReserve, "response-reserve", handoff.DefaultResponseReserve, "With -model, tokens of the context window to keep free for the response")
+	flag.BoolVar(&f.strict, "strict", false, "With -model, fail instead of warning when the output exceeds the context window")
+	flag.StringVar(&f.strictSkips, "strict-skips", "", "Comma-separated skip reasons that fail the run, e.g. read-error,binary")
+	return f
+}
+
+// options converts the flags that were set into library options
+func (f *budgetFlags) options() ([]handoff.Option, error) {
+	options, err := f.trimOptions()
+	if err != nil {
+		return nil, err
+	}
+	if f.chunkTokens > 0 {
+		options = append(options, handoff.WithChunkTokens(f.chunkTokens))
+	}
+	if f.chunkOverlap > 0 {
+		options = append(options, handoff.WithChunkOverlap(f.chunkOverlap))
+	}
+	if f.model != "" {
+		profile, err := handoff.LookupModel(f.model)
+		if err != nil {
+			return nil, fmt.Errorf("invalid -model: %v", err)
+		}
+		options = append(options, handoff.WithModel(profile))
+	}
+	if f.responseReserve != handoff.DefaultResponseReserve {
+		options = append(options, handoff.WithResponseReserve(f.responseReserve))
+	}
+	if f.strict {
+		options = append(options, handoff.WithStrict(f.strict))
+	}
+	if f.strictSkips != "" {
+		var reasons []handoff.SkipReason
+		for _, name := range strings.Split(f.strictSkips, ",") {
+			reason, err := handoff.ParseSkipReason(name)
+			if err != nil {
+				return nil, fmt.Errorf("invalid -strict-skips: %v", err)
+			}
+			reasons = append(reasons, reason)
+		}
+		options = append(options, handoff.WithStrictSkips(reasons...))
+	}
+	return options, nil
+}
+
+// trimOptions converts -max-tokens and the flags choosing what it drops
+func (f *budgetFlags) trimOptions() ([]handoff.Option, error) {
+	var options []handoff.Option
+	if f.maxTokens > 0 {
+		options = append(options, handoff.WithMaxTokens(f.maxTokens))
+	}
+	if f.trimPriority != "" {
+		var rules []string
+		for _, rule := range strings.Split(f.trimPriority, ",") {
+			if rule = strings.TrimSpace(rule); rule != "" {
+				rules = append(rules, rule)
+			}
+		}
+		options = append(options, handoff.WithTrimPriority(rules))
+	}
+	if f.trimStrategy != "" {
+		strategy, err := handoff.ParseTrimStrategy(f.trimStrategy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid -trim-strategy: %v", err)
+		}
+		options = append(options, handoff.WithTrimStrategy(strategy))
+	}
+	return options, nil
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	handoff "github.com/phrazzld/handoff/lib"
+)
+
+// contentFlags holds the flags that transform file content before it is output
+type contentFlags struct {
+	collapseBlobs bool
+	expandTabs    int
+	leanComments  int
+	stripTrailing bool
+	normalizeNFC  bool
+	transcode     bool
+	sanitize      string
+}
+
+// registerContentFlags defines the content transformation flags
+func registerContentFlags() *contentFlags {
+	f := &contentFlags{}
+	flag.BoolVar(&f.collapseBlobs, "collapse-blobs", false, "Replace enormous inline data (base64 strings, byte-array literals, multi-thousand-character lines) with \"[... 48KB data elided ...]\" markers")
+	flag.IntVar(&f.expandTabs, "expand-tabs", 0, "Replace tabs with spaces up to the next multiple of this many columns (0 keeps tabs)")
+	flag.IntVar(&f.leanComments, "lean-comments", 0, "Shorten comment blocks longer than this many lines to their first line (0 keeps comments)")
+	flag.BoolVar(&f.stripTrailing, "strip-trailing-whitespace", false, "Remove trailing spaces, tabs, and carriage returns from every line")
+	flag.BoolVar(&f.normalizeNFC, "nfc", false, "Normalize content to Unicode NFC, composing decomposed characters (common in files from macOS tooling)")
+	flag.BoolVar(&f.transcode, "transcode", false, "Convert UTF-16 files (with a byte order mark) and non-UTF-8 text (read as Windows-1252) to UTF-8 instead of skipping or passing them through")
+	flag.StringVar(&f.sanitize, "sanitize-control", "", "Sanitize ANSI escape sequences and control characters in file content: strip (remove them) or escape (show them as \\x1b-style escapes)")
+	return f
+}
+
+// options converts the flags that were set into library options
+func (f *contentFlags) options() ([]handoff.Option, error) {
+	var options []handoff.Option
+	if f.collapseBlobs {
+		options = append(options, handoff.WithCollapseBlobs(f.collapseBlobs))
+	}
+	if f.expandTabs > 0 {
+		options = append(options, handoff.WithExpandTabs(f.expandTabs))
+	}
+	if f.leanComments > 0 {
+		options = append(options, handoff.WithLeanComments(f.leanComments))
+	}
+	if f.stripTrailing {
+		options = append(options, handoff.WithStripTrailingWhitespace(f.stripTrailing))
+	}
+	if f.transcode {
+		options = append(options, handoff.WithTranscode(f.transcode))
+	}
+	if f.normalizeNFC {
+		options = append(options, handoff.WithNormalizeUnicode(f.normalizeNFC))
+	}
+	if f.sanitize != "" {
+		mode, err := handoff.ParseControlMode(f.sanitize)
+		if err != nil {
+			return nil, fmt.Errorf("invalid -sanitize-control: %v", err)
+		}
+		options = append(options, handoff.WithSanitizeControl(mode))
+	}
+	return options, nil
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"regexp"
+
+	handoff "github.com/phrazzld/handoff/lib"
+)
+
+// filterFlags holds the flags that decide which files are included
+type filterFlags struct {
+	include         string
+	exclude         string
+	excludeNames    string
+	hiddenAllowlist string
+	contentRegex    string
+	grep            string
+	grepContext     int
+	ignoreGitignore bool
+	ignoreAttrs     bool
+	maxFileSize     int64
+	skipOverLines   int
+	includeBinary   string
+}
+
+// registerFilterFlags defines the extension, name, content, and size filter flags
+func registerFilterFlags() *filterFlags {
+	f := &filterFlags{}
+	flag.StringVar(&f.include, "include", "", "Comma-separated list of file extensions to include (e.g., .txt,.go)")
+	flag.StringVar(&f.exclude, "exclude", "", "Comma-separated list of file extensions to exclude (e.g., .exe,.bin)")
+	flag.StringVar(&f.excludeNames, "exclude-names", "", "Comma-separated list of file names or glob patterns to exclude (e.g., package-lock.json,*_mock.go)")
+	flag.StringVar(&f.hiddenAllowlist, "hidden-allowlist", "", "Comma-separated list of hidden file or directory names to process (e.g., .github,.golangci.yml)")
+	flag.StringVar(&f.contentRegex, "include-content-regex", "", "Only include files whose content matches this regular expression (e.g., PaymentService)")
+	flag.StringVar(&f.grep, "grep", "", "Only include files containing this text, with matching lines highlighted (e.g., \"TODO(payment)\")")
+	flag.IntVar(&f.grepContext, "grep-context", -1, "With -grep, trim each file to N lines around matches (default keeps whole files)")
+	flag.BoolVar(&f.ignoreGitignore, "ignore-gitignore", false, "Process files even if they are gitignored (bypasses .gitignore rules; default: false)")
+	flag.BoolVar(&f.ignoreAttrs, "ignore-gitattributes", false, "Process files marked linguist-generated or linguist-vendored in .gitattributes (default: false)")
+	flag.Int64Var(&f.maxFileSize, "max-file-size", handoff.DefaultMaxFileSize, "Skip files larger than this many bytes (0 disables the limit)")
+	flag.IntVar(&f.skipOverLines, "skip-over-lines", 0, "Skip files with more than this many lines, such as giant generated schemas, regardless of extension filters (0 disables the limit)")
+	flag.StringVar(&f.includeBinary, "include-binary", "", "Include binary files named as paths or matching -include as a bounded dump instead of skipping them: hex or base64")
+	return f
+}
+
+// options converts the flags that were set into library options
+func (f *filterFlags) options() ([]handoff.Option, error) {
+	options := f.nameOptions()
+	if f.contentRegex != "" {
+		re, err := regexp.Compile(f.contentRegex)
+		if err != nil {
+			return nil, fmt.Errorf("invalid -include-content-regex: %v", err)
+		}
+		options = append(options, handoff.WithIncludeContentRegex(re))
+	}
+	if f.grep != "" {
+		options = append(options, handoff.WithGrep(regexp.MustCompile(regexp.QuoteMeta(f.grep)), f.grepContext))
+	}
+	if f.maxFileSize != handoff.DefaultMaxFileSize {
+		options = append(options, handoff.WithMaxFileSize(f.maxFileSize))
+	}
+	if f.skipOverLines > 0 {
+		options = append(options, handoff.WithMaxFileLines(f.skipOverLines))
+	}
+	if f.includeBinary != "" {
+		encoding, err := handoff.ParseBinaryEncoding(f.includeBinary)
+		if err != nil {
+			return nil, fmt.Errorf("invalid -include-binary: %v", err)
+		}
+		options = append(options, handoff.WithIncludeBinary(encoding))
+	}
+	return options, nil
+}
+
+// nameOptions converts the flags filtering by file name and ignore rules
+func (f *filterFlags) nameOptions() []handoff.Option {
+	var options []handoff.Option
+	if f.include != "" {
+		options = append(options, handoff.WithInclude(f.include))
+	}
+	if f.exclude != "" {
+		options = append(options, handoff.WithExclude(f.exclude))
+	}
+	if f.excludeNames != "" {
+		options = append(options, handoff.WithExcludeNames(f.excludeNames))
+	}
+	if f.hiddenAllowlist != "" {
+		options = append(options, handoff.WithHiddenAllowlist(f.hiddenAllowlist))
+	}
+	if f.ignoreGitignore {
+		options = append(options, handoff.WithIgnoreGitignore(f.ignoreGitignore))
+	}
+	if f.ignoreAttrs {
+		options = append(options, handoff.WithIgnoreGitattributes(f.ignoreAttrs))
+	}
+	return options
+}
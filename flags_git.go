@@ -0,0 +1,97 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"time"
+
+	handoff "github.com/phrazzld/handoff/lib"
+)
+
+// gitFlags holds the flags that select, order, or annotate files using git history
+type gitFlags struct {
+	newerThan      string
+	modifiedWithin string
+	author         string
+	authorMatch    string
+	order          string
+	gitStatus      bool
+	gitLog         int
+	gitLogStat     bool
+}
+
+// registerGitFlags defines the history, author, order, and git section flags
+func registerGitFlags() *gitFlags {
+	f := &gitFlags{}
+	flag.StringVar(&f.newerThan, "newer-than", "", "Only include files last changed after this date (e.g., 2024-01-01), using the last commit date when git has one")
+	flag.StringVar(&f.modifiedWithin, "modified-within", "", "Only include files last changed within this period (e.g., 7d, 2w, 36h), using the last commit date when git has one")
+	flag.StringVar(&f.author, "author", "", "Only include files whose last commit is by this author (matches any part of \"Name <email>\")")
+	flag.StringVar(&f.authorMatch, "author-match", "", "How -author assigns files: last (author of the last commit) or most (author with the most commits) (default: last)")
+	flag.StringVar(&f.order, "order", "", "Order of files in the output: discovery; churn to put the most frequently changed files (by git history) first; or entry to put likely entry points first, then core files, then tests (default: discovery)")
+	flag.BoolVar(&f.gitStatus, "git-status", false, "Mark each file with its git status: modified, staged, untracked, or clean")
+	flag.IntVar(&f.gitLog, "git-log", 0, "Append the last N commit subjects touching the processed paths (0 disables)")
+	flag.BoolVar(&f.gitLogStat, "git-log-stat", false, "Include changed file and line counts in the -git-log section")
+	return f
+}
+
+// options converts the flags that were set into library options
+func (f *gitFlags) options() ([]handoff.Option, error) {
+	var options []handoff.Option
+	if f.newerThan != "" || f.modifiedWithin != "" {
+		cutoff, err := modifiedCutoff(f.newerThan, f.modifiedWithin, time.Now())
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, handoff.WithModifiedAfter(cutoff))
+	}
+	if f.author != "" {
+		match := handoff.AuthorLast
+		if f.authorMatch != "" {
+			var err error
+			if match, err = handoff.ParseAuthorMatch(f.authorMatch); err != nil {
+				return nil, fmt.Errorf("invalid -author-match: %v", err)
+			}
+		}
+		options = append(options, handoff.WithAuthor(f.author, match))
+	}
+	if f.order != "" {
+		fileOrder, err := handoff.ParseFileOrder(f.order)
+		if err != nil {
+			return nil, fmt.Errorf("invalid -order: %v", err)
+		}
+		options = append(options, handoff.WithOrder(fileOrder))
+	}
+	if f.gitStatus {
+		options = append(options, handoff.WithGitStatus(f.gitStatus))
+	}
+	if f.gitLog > 0 {
+		options = append(options, handoff.WithGitLog(f.gitLog))
+	}
+	if f.gitLogStat {
+		options = append(options, handoff.WithGitLogStat(f.gitLogStat))
+	}
+	return options, nil
+}
+
+// modifiedCutoff converts -newer-than and -modified-within values into the
+// earliest modification time to keep. When both are set, the later cutoff wins.
+func modifiedCutoff(newerThan, modifiedWithin string, now time.Time) (time.Time, error) {
+	var cutoff time.Time
+	if newerThan != "" {
+		date, err := handoff.ParseDate(newerThan)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid -newer-than: %v", err)
+		}
+		cutoff = date
+	}
+	if modifiedWithin != "" {
+		period, err := handoff.ParsePeriod(modifiedWithin)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid -modified-within: %v", err)
+		}
+		if since := now.Add(-period); since.After(cutoff) {
+			cutoff = since
+		}
+	}
+	return cutoff, nil
+}
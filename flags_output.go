@@ -0,0 +1,161 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	handoff "github.com/phrazzld/handoff/lib"
+)
+
+// cliOptions holds settings that only affect the CLI's handling of the output
+type cliOptions struct {
+	// outputFile is the -output target: a file path, gist://, or an http(s) webhook URL
+	outputFile string
+
+	// force allows overwriting an existing output file
+	force bool
+
+	// dryRun prints the output instead of writing it
+	dryRun bool
+
+	// noPager prints dry-run previews directly instead of through $PAGER
+	noPager bool
+
+	// verifyClipboard reads the clipboard back after copying to confirm the copy took
+	verifyClipboard bool
+
+	// clipboardCmd is a custom command that receives the output on stdin in
+	// place of the built-in clipboard tools
+	clipboardCmd string
+
+	// outputHeaders are extra "Name: value" headers sent to webhook targets
+	outputHeaders stringListFlag
+
+	// resume continues an interrupted run that wrote to the same output file
+	resume bool
+
+	// manifest is a file to write a JSON manifest of the output to
+	manifest string
+
+	// preflight reports files that can't be read or would be left out
+	// instead of generating output
+	preflight bool
+}
+
+// registerCLIFlags defines the flags for where the output goes and how it gets there
+func registerCLIFlags() *cliOptions {
+	f := &cliOptions{}
+	flag.StringVar(&f.outputFile, "output", "", "Write output to the specified file instead of clipboard (e.g., HANDOFF.md), gist://[name] to upload a secret gist using GITHUB_TOKEN, s3://bucket/key or gs://bucket/key to upload it with the aws or gcloud CLI, or an http(s):// URL to POST it with stats as JSON")
+	flag.BoolVar(&f.force, "force", false, "Allow overwriting existing files when using -output flag")
+	flag.BoolVar(&f.dryRun, "dry-run", false, "Preview what would be copied without actually copying")
+	flag.BoolVar(&f.noPager, "no-pager", false, "Print -dry-run previews directly instead of through $PAGER (less -R) when stdout is a terminal")
+	flag.BoolVar(&f.verifyClipboard, "verify-clipboard", false, "Read the clipboard back after copying and fail if it doesn't hold the output (catches clipboard tools that silently store nothing)")
+	flag.StringVar(&f.clipboardCmd, "clipboard-cmd", "", "Copy by piping the output to this command instead of pbcopy, xclip, or wl-copy (e.g., \"xsel --clipboard --input\"); arguments are split on spaces")
+	flag.Var(&f.outputHeaders, "output-header", "Header to send with webhook -output targets as \"Name: value\"; $VARS are expanded (repeatable)")
+	flag.BoolVar(&f.resume, "resume", false, "Record progress next to the -output file so an interrupted run can continue from the last completed file when run again with -resume")
+	flag.StringVar(&f.manifest, "manifest", "", "Also write a JSON manifest of the output to this file: the files included and skipped, per-file stats, a settings snapshot, and a SHA-256 digest of the output")
+	flag.BoolVar(&f.preflight, "preflight", false, "Report unreadable files, broken symlinks, and oversized files without generating output; exits 1 if any are found")
+	return f
+}
+
+// outputFlags holds the flags that shape how files are rendered and which
+// sections are added to the output
+type outputFlags struct {
+	format          string
+	style           string
+	outputFormat    string
+	escapePaths     bool
+	contextAttrs    bool
+	annotateTokens  bool
+	checksums       bool
+	groupByLang     bool
+	dependencies    bool
+	environmentInfo bool
+	todoIndex       bool
+	skippedAppendix bool
+}
+
+// registerOutputFlags defines the format, style, annotation, and appendix flags
+func registerOutputFlags() *outputFlags {
+	f := &outputFlags{}
+	flag.StringVar(&f.format, "format", handoff.DefaultFormat, "Custom format for output. Use {path}, {content}, {lang}, {fence}, and {root} as placeholders, with modifiers such as {path:base} or {content:trim:indent=2}; write {{ and }} for literal braces")
+	flag.StringVar(&f.style, "style", "", "Output style preset ("+strings.Join(handoff.StyleNames(), ", ")+"); -format overrides its template")
+	flag.StringVar(&f.outputFormat, "output-format", "", "Render output in another format instead of text: html (a page with a file tree and copy buttons) or jsonl (one JSON object per file)")
+	flag.BoolVar(&f.escapePaths, "escape-paths", false, "Percent-encode control characters, bidirectional formatting characters, invalid UTF-8, and % in displayed paths")
+	flag.BoolVar(&f.contextAttrs, "context-attrs", false, "Add files, tokens, and generated attributes to the tag wrapping the output")
+	flag.BoolVar(&f.annotateTokens, "annotate-tokens", false, "Add a <!-- ~N tokens --> comment after each file's block")
+	flag.BoolVar(&f.checksums, "checksums", false, "Add the SHA-256 digest of each file's content to its header and to the -manifest")
+	flag.BoolVar(&f.groupByLang, "group-by-language", false, "List files in a section per language (Go, TypeScript, SQL, Config, ...), each headed by its file count and estimated tokens")
+	flag.BoolVar(&f.dependencies, "deps", false, "Append a summary of the direct dependencies in go.mod, package.json, and requirements.txt")
+	flag.BoolVar(&f.environmentInfo, "env-info", false, "Append the OS, architecture, installed Go version, and tool versions pinned in go.mod, .nvmrc, and similar files")
+	flag.BoolVar(&f.todoIndex, "todo-index", false, "Append an index of the TODO, FIXME, and HACK markers in the included files, as path:line and the comment")
+	flag.BoolVar(&f.skippedAppendix, "list-skipped", false, "Append a section listing the files left out and why, as path (reason)")
+	return f
+}
+
+// options converts the flags that were set into library options
+func (f *outputFlags) options() ([]handoff.Option, error) {
+	options, err := f.formatOptions()
+	if err != nil {
+		return nil, err
+	}
+	if f.escapePaths {
+		options = append(options, handoff.WithEscapePaths(f.escapePaths))
+	}
+	if f.contextAttrs {
+		options = append(options, handoff.WithContextAttributes(f.contextAttrs))
+	}
+	if f.annotateTokens {
+		options = append(options, handoff.WithTokenAnnotations(f.annotateTokens))
+	}
+	if f.checksums {
+		options = append(options, handoff.WithChecksums(f.checksums))
+	}
+	if f.groupByLang {
+		options = append(options, handoff.WithGroupByLanguage(f.groupByLang))
+	}
+	if f.dependencies {
+		options = append(options, handoff.WithDependencies(f.dependencies))
+	}
+	if f.environmentInfo {
+		options = append(options, handoff.WithEnvironmentInfo(f.environmentInfo))
+	}
+	if f.todoIndex {
+		options = append(options, handoff.WithTodoIndex(f.todoIndex))
+	}
+	if f.skippedAppendix {
+		options = append(options, handoff.WithSkippedAppendix(f.skippedAppendix))
+	}
+	return options, nil
+}
+
+// formatOptions converts -format, -style, and -output-format, which all
+// choose how each file is rendered
+func (f *outputFlags) formatOptions() ([]handoff.Option, error) {
+	var options []handoff.Option
+	if f.format != "" {
+		options = append(options, handoff.WithFormat(f.format))
+	}
+	if f.style != "" {
+		preset, err := handoff.LookupStyle(f.style)
+		if err != nil {
+			return nil, fmt.Errorf("invalid -style: %v", err)
+		}
+		if flagWasSet("format") {
+			preset.Format = f.format
+		}
+		options = append(options, handoff.WithStyle(preset))
+	}
+	if f.outputFormat != "" {
+		if f.style != "" {
+			return nil, fmt.Errorf("-output-format cannot be combined with -style")
+		}
+		formatter, err := handoff.LookupFormatter(f.outputFormat)
+		if err != nil {
+			return nil, fmt.Errorf("invalid -output-format: %v", err)
+		}
+		options = append(options, handoff.WithFormatter(formatter))
+	}
+	return options, nil
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	handoff "github.com/phrazzld/handoff/lib"
+)
+
+// selectFlags holds the flags that cap, sample, or rank the discovered files
+type selectFlags struct {
+	dirCap     int
+	dirSample  string
+	sample     int
+	sampleSeed uint64
+	query      string
+	queryTop   int
+}
+
+// registerSelectFlags defines the -dir-cap, -sample, and -query flags
+func registerSelectFlags() *selectFlags {
+	f := &selectFlags{}
+	flag.IntVar(&f.dirCap, "dir-cap", 0, "Process at most this many files from any one directory, noting how many were omitted (0 disables)")
+	flag.StringVar(&f.dirSample, "dir-sample", "", "Which files -dir-cap keeps: first, random, or newest (default: first)")
+	flag.IntVar(&f.sample, "sample", 0, "Process a random selection of this many files across all paths (0 processes every file)")
+	flag.Uint64Var(&f.sampleSeed, "sample-seed", 0, "Seed for -sample and -dir-sample=random, to reproduce a sample (0 picks a seed, reported in the output)")
+	flag.StringVar(&f.query, "query", "", "Rank files by relevance to this query (BM25 over paths and contents) and keep only the best matches, most relevant first")
+	flag.IntVar(&f.queryTop, "query-top", 0, "With -query, keep at most this many files (0 keeps every match that fits -max-tokens)")
+	return f
+}
+
+// options converts the flags that were set into library options
+func (f *selectFlags) options() ([]handoff.Option, error) {
+	var options []handoff.Option
+	if f.dirCap > 0 {
+		sampleMode := handoff.SampleFirst
+		if f.dirSample != "" {
+			var err error
+			if sampleMode, err = handoff.ParseSampleMode(f.dirSample); err != nil {
+				return nil, fmt.Errorf("invalid -dir-sample: %v", err)
+			}
+		}
+		options = append(options, handoff.WithDirectoryCap(f.dirCap, sampleMode))
+	}
+	if f.sample > 0 {
+		options = append(options, handoff.WithSample(f.sample))
+	}
+	if f.sampleSeed != 0 {
+		options = append(options, handoff.WithSampleSeed(f.sampleSeed))
+	}
+	if f.query != "" {
+		options = append(options, handoff.WithQuery(f.query, f.queryTop))
+	}
+	return options, nil
+}
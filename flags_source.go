@@ -0,0 +1,51 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	handoff "github.com/phrazzld/handoff/lib"
+)
+
+// sourceFlags holds the flags for where files are read from
+type sourceFlags struct {
+	root       string
+	rootLabels stringListFlag
+	stdinName  string
+	ioThrottle int64
+}
+
+// registerSourceFlags defines the -root, -root-label, -stdin-name, and
+// -io-throttle flags
+func registerSourceFlags() *sourceFlags {
+	f := &sourceFlags{}
+	flag.StringVar(&f.root, "root", "", "Resolve relative paths and find "+handoff.DefaultConfigFileName+" in this directory instead of the working directory, showing paths relative to it")
+	flag.Var(&f.rootLabels, "root-label", "Label the files under a directory as \"dir=label\", shown by the {root} placeholder, to tell several projects apart (repeatable)")
+	flag.StringVar(&f.stdinName, "stdin-name", "", "Path to show for standard input, given as the path -, which also selects its code fence language (default: "+handoff.DefaultStdinName+")")
+	flag.Int64Var(&f.ioThrottle, "io-throttle", 0, "Limit file reads to this many bytes per second, for network mounts (0 disables the limit)")
+	return f
+}
+
+// options converts the flags that were set into library options
+func (f *sourceFlags) options() ([]handoff.Option, error) {
+	var options []handoff.Option
+	if f.root != "" {
+		options = append(options, handoff.WithRoot(f.root))
+	}
+	if f.stdinName != "" {
+		options = append(options, handoff.WithStdin(os.Stdin, f.stdinName))
+	}
+	for _, rootLabel := range f.rootLabels {
+		dir, label, ok := strings.Cut(rootLabel, "=")
+		if !ok || dir == "" {
+			return nil, fmt.Errorf("invalid -root-label %q: want dir=label", rootLabel)
+		}
+		options = append(options, handoff.WithRootLabel(dir, label))
+	}
+	if f.ioThrottle > 0 {
+		options = append(options, handoff.WithIOThrottle(f.ioThrottle))
+	}
+	return options, nil
+}
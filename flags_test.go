@@ -0,0 +1,61 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestPermuteArgs tests moving flags ahead of paths
+func TestPermuteArgs(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.String("include", "", "")
+	fs.Bool("verbose", false, "")
+	registerShortFlags(fs)
+
+	testCases := []struct {
+		name string
+		args []string
+		want []string
+	}{
+		{"flags first", []string{"-include", ".go", "src"}, []string{"-include", ".go", "--", "src"}},
+		{"flags after paths", []string{"src", "--include", ".go", "-v", "lib"}, []string{"--include", ".go", "-v", "--", "src", "lib"}},
+		{"value after equals", []string{"src", "-i=.go"}, []string{"-i=.go", "--", "src"}},
+		{"standard input", []string{"-", "-v"}, []string{"-v", "--", "-"}},
+		{"double dash ends flags", []string{"-v", "--", "-odd", "src"}, []string{"-v", "--", "-odd", "src"}},
+		{"no paths", []string{"-verbose"}, []string{"-verbose"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := permuteArgs(fs, tc.args); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("permuteArgs(%q) = %q, want %q", tc.args, got, tc.want)
+			}
+		})
+	}
+}
+
+// TestShortFlags tests that short aliases set the long flags
+func TestShortFlags(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	include := fs.String("include", "", "Extensions to include")
+	verbose := fs.Bool("verbose", false, "Enable verbose output")
+	registerShortFlags(fs)
+
+	if err := fs.Parse(permuteArgs(fs, []string{"src", "-i", ".go", "-v"})); err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if *include != ".go" || !*verbose || !reflect.DeepEqual(fs.Args(), []string{"src"}) {
+		t.Errorf("include = %q, verbose = %v, args = %v", *include, *verbose, fs.Args())
+	}
+
+	// The listing shows each long flag once, with its alias
+	var out bytes.Buffer
+	fs.SetOutput(&out)
+	printFlags(fs)
+	if listing := out.String(); !strings.Contains(listing, "  -i, --include string\n") || strings.Contains(listing, "Short for") {
+		t.Errorf("printFlags() =\n%s", listing)
+	}
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+	"time"
+)
+
+// gistScheme is the -output prefix that uploads the content as a GitHub gist
+const gistScheme = "gist://"
+
+// defaultGistFileName is the gist file name used when none follows gist://
+const defaultGistFileName = "handoff.md"
+
+// gistAPIURL is the GitHub endpoint for creating gists (overridden in tests)
+var gistAPIURL = "https://api.github.com/gists"
+
+// ErrGistTokenMissing is returned when no GitHub token is available for gist uploads
+var ErrGistTokenMissing = errors.New("no GitHub token found; set GITHUB_TOKEN or GH_TOKEN to upload gists")
+
+// isGistOutput reports whether an -output value targets a GitHub gist
+func isGistOutput(output string) bool {
+	return strings.HasPrefix(output, gistScheme)
+}
+
+// gistFileName returns the gist file name from an -output value such as
+// gist://context.md, falling back to defaultGistFileName. Gist file names
+// can't hold path separators, so only the last element of a nested path such
+// as gist://docs/context.md is kept.
+func gistFileName(output string) string {
+	name := path.Base(strings.ReplaceAll(strings.TrimPrefix(output, gistScheme), `\`, "/"))
+	if name == "." || name == ".." || name == "/" {
+		return defaultGistFileName
+	}
+	return name
+}
+
+// gistToken returns the GitHub token used for gist uploads from the environment
+func gistToken() (string, error) {
+	for _, name := range []string{"GITHUB_TOKEN", "GH_TOKEN"} {
+		if token := os.Getenv(name); token != "" {
+			return token, nil
+		}
+	}
+	return "", ErrGistTokenMissing
+}
+
+// uploadGist creates a secret gist containing content and returns its URL.
+func uploadGist(content, fileName, token string) (string, error) {
+	payload, err := json.Marshal(map[string]any{
+		"description": "Context generated by handoff",
+		"public":      false,
+		"files": map[string]any{
+			fileName: map[string]string{"content": content},
+		},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode gist: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, gistAPIURL, bytes.NewReader(payload))
+	if err != nil {
+		return "", fmt.Errorf("failed to create gist request: %w", err)
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to upload gist: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return "", fmt.Errorf("failed to read gist response: %w", err)
+	}
+	if resp.StatusCode != http.StatusCreated {
+		return "", fmt.Errorf("failed to upload gist: %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	var created struct {
+		HTMLURL string `json:"html_url"`
+	}
+	if err := json.Unmarshal(body, &created); err != nil || created.HTMLURL == "" {
+		return "", fmt.Errorf("unexpected gist response: %s", strings.TrimSpace(string(body)))
+	}
+	return created.HTMLURL, nil
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestGistFileName tests deriving the gist file name from -output values
+func TestGistFileName(t *testing.T) {
+	testCases := map[string]string{
+		"gist://":            defaultGistFileName,
+		"gist://context.md":  "context.md",
+		"gist:///context.md": "context.md",
+		"gist://docs/ctx.md": "ctx.md",
+		"gist://a\\b\\c.md":  "c.md",
+		"gist://docs/":       "docs",
+		"gist://..":          defaultGistFileName,
+	}
+	for output, want := range testCases {
+		if got := gistFileName(output); got != want {
+			t.Errorf("gistFileName(%q) = %q, want %q", output, got, want)
+		}
+	}
+
+	if isGistOutput("HANDOFF.md") || !isGistOutput("gist://") {
+		t.Error("isGistOutput should only match the gist:// scheme")
+	}
+}
+
+// TestGistToken tests reading the upload token from the environment
+func TestGistToken(t *testing.T) {
+	t.Setenv("GITHUB_TOKEN", "")
+	t.Setenv("GH_TOKEN", "")
+	if _, err := gistToken(); !errors.Is(err, ErrGistTokenMissing) {
+		t.Errorf("gistToken() error = %v, want ErrGistTokenMissing", err)
+	}
+
+	t.Setenv("GH_TOKEN", "gh-secret")
+	if token, err := gistToken(); err != nil || token != "gh-secret" {
+		t.Errorf("gistToken() = %q, %v; want gh-secret", token, err)
+	}
+}
+
+// TestUploadGist tests creating a secret gist against a fake GitHub API
+func TestUploadGist(t *testing.T) {
+	var received struct {
+		Public bool `json:"public"`
+		Files  map[string]struct {
+			Content string `json:"content"`
+		} `json:"files"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			http.Error(w, "bad credentials", http.StatusUnauthorized)
+			return
+		}
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"html_url": "https://gist.github.com/abc123"}`))
+	}))
+	defer server.Close()
+
+	original := gistAPIURL
+	gistAPIURL = server.URL
+	defer func() { gistAPIURL = original }()
+
+	url, err := uploadGist("<context>\nhello\n</context>", "context.md", "secret")
+	if err != nil {
+		t.Fatalf("uploadGist failed: %v", err)
+	}
+	if url != "https://gist.github.com/abc123" {
+		t.Errorf("url = %q, want https://gist.github.com/abc123", url)
+	}
+	if received.Public {
+		t.Error("gist should be secret")
+	}
+	if received.Files["context.md"].Content != "<context>\nhello\n</context>" {
+		t.Errorf("uploaded files = %+v, want context.md with the content", received.Files)
+	}
+
+	_, err = uploadGist("content", "context.md", "wrong")
+	if err == nil || !strings.Contains(err.Error(), "401") {
+		t.Errorf("uploadGist with bad token error = %v, want 401 error", err)
+	}
+}
@@ -1,3 +1,8 @@
 module github.com/phrazzld/handoff
 
 go 1.24.2
+
+require (
+	golang.org/x/text v0.32.0
+	gopkg.in/yaml.v3 v3.0.1
+)
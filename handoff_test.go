@@ -5,7 +5,11 @@ import (
 	"flag"
 	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
+	"time"
+
+	handoff "github.com/phrazzld/handoff/lib"
 )
 
 // The tests in this file focus on CLI-specific functionality.
@@ -105,7 +109,8 @@ func TestParseConfigOutputAndForceFlags(t *testing.T) {
 			os.Args = tc.args
 
 			// Call parseConfig
-			config, outputFile, force, dryRun := parseConfig()
+			config, cli := parseConfig()
+			outputFile, force, dryRun := cli.outputFile, cli.force, cli.dryRun
 
 			// Verify output file path
 			if outputFile != tc.expectedOutput {
@@ -218,7 +223,8 @@ func TestFlagInteractionPrecedence(t *testing.T) {
 			flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ExitOnError)
 
 			// Parse flags
-			_, outputPath, _, dryRun := parseConfig()
+			_, cli := parseConfig()
+			outputPath, dryRun := cli.outputFile, cli.dryRun
 
 			// Directly determine and verify the expected output mode based on the flags
 			var actualMode string
@@ -297,7 +303,7 @@ func TestCopyToClipboardErrorHandling(t *testing.T) {
 		t.Fatalf("Failed to set PATH: %v", err)
 	}
 
-	err := copyToClipboard("Test content")
+	err := copyToClipboard("Test content", "", false)
 
 	// We expect an error since no clipboard commands should be available
 	if err == nil {
@@ -310,10 +316,9 @@ func TestCopyToClipboardErrorHandling(t *testing.T) {
 	}
 }
 
-// TestCheckFileExists tests the checkFileExists function
-func TestCheckFileExists(t *testing.T) {
-	// Create temporary test directory
-	tempDir, err := os.MkdirTemp("", "handoff-test-")
+// TestLoadConfigFileOptions tests loading CLI defaults from an explicit or default config file
+func TestLoadConfigFileOptions(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "handoff-config-test-")
 	if err != nil {
 		t.Fatalf("Failed to create temp directory: %v", err)
 	}
@@ -322,52 +327,165 @@ func TestCheckFileExists(t *testing.T) {
 			t.Logf("Failed to clean up temp directory: %v", cleanErr)
 		}
 	}()
+	t.Chdir(tempDir)
 
-	// Create test file
-	existingFilePath := filepath.Join(tempDir, "existing.txt")
-	err = os.WriteFile(existingFilePath, []byte("Test content"), 0644)
+	// Without a default config file, no options are loaded
+	options, err := loadConfigFileOptions("", "")
+	if err != nil || len(options) != 0 {
+		t.Errorf("loadConfigFileOptions(\"\") = %d options, %v; want none", len(options), err)
+	}
+
+	// The default config file is picked up from the working directory
+	if err := os.WriteFile(handoff.DefaultConfigFileName, []byte(`{"include": ".go", "excludeNames": "go.sum"}`), 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+	options, err = loadConfigFileOptions("", "")
 	if err != nil {
-		t.Fatalf("Failed to create test file: %v", err)
+		t.Fatalf("loadConfigFileOptions(\"\") failed: %v", err)
+	}
+	if len(options) != 2 {
+		t.Errorf("loadConfigFileOptions(\"\") returned %d options, want 2", len(options))
 	}
 
-	// Path to non-existent file
-	nonExistentPath := filepath.Join(tempDir, "nonexistent.txt")
+	// An explicit config file that doesn't exist is an error
+	if _, err := loadConfigFileOptions(filepath.Join(tempDir, "missing.json"), ""); err == nil {
+		t.Error("loadConfigFileOptions() with missing explicit file should return an error")
+	}
+
+	// With a root, the default config file is picked up from the root instead
+	if err := os.Mkdir("project", 0755); err != nil {
+		t.Fatalf("Failed to create project directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join("project", handoff.DefaultConfigFileName), []byte(`{"include": ".go"}`), 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+	options, err = loadConfigFileOptions("", "project")
+	if err != nil {
+		t.Fatalf("loadConfigFileOptions(\"\", \"project\") failed: %v", err)
+	}
+	if len(options) != 1 {
+		t.Errorf("loadConfigFileOptions(\"\", \"project\") returned %d options, want 1", len(options))
+	}
+}
+
+// TestModifiedCutoff tests converting -newer-than and -modified-within into a cutoff time
+func TestModifiedCutoff(t *testing.T) {
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+	date := time.Date(2024, 1, 1, 0, 0, 0, 0, time.Local)
 
-	// Test cases
 	testCases := []struct {
-		name     string
-		path     string
-		expected bool
-		wantErr  bool
+		name           string
+		newerThan      string
+		modifiedWithin string
+		want           time.Time
+		wantErr        bool
 	}{
-		{
-			name:     "Existing file",
-			path:     existingFilePath,
-			expected: true,
-			wantErr:  false,
-		},
-		{
-			name:     "Non-existent file",
-			path:     nonExistentPath,
-			expected: false,
-			wantErr:  false,
-		},
+		{name: "Date", newerThan: "2024-01-01", want: date},
+		{name: "RFC 3339", newerThan: "2024-06-01T08:00:00Z", want: time.Date(2024, 6, 1, 8, 0, 0, 0, time.UTC)},
+		{name: "Days", modifiedWithin: "7d", want: now.AddDate(0, 0, -7)},
+		{name: "Weeks", modifiedWithin: "2w", want: now.AddDate(0, 0, -14)},
+		{name: "Hours", modifiedWithin: "36h", want: now.Add(-36 * time.Hour)},
+		{name: "Later cutoff wins", newerThan: "2024-01-01", modifiedWithin: "7d", want: now.AddDate(0, 0, -7)},
+		{name: "Invalid date", newerThan: "last week", wantErr: true},
+		{name: "Invalid period", modifiedWithin: "7 days", wantErr: true},
+		{name: "Negative period", modifiedWithin: "-3d", wantErr: true},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			exists, err := checkFileExists(tc.path)
-
-			// Check error condition
-			if (err != nil) != tc.wantErr {
-				t.Errorf("checkFileExists(%q) error = %v, wantErr %v", tc.path, err, tc.wantErr)
+			got, err := modifiedCutoff(tc.newerThan, tc.modifiedWithin, now)
+			if tc.wantErr {
+				if err == nil {
+					t.Errorf("modifiedCutoff() = %v, want error", got)
+				}
 				return
 			}
-
-			// Check return value
-			if exists != tc.expected {
-				t.Errorf("checkFileExists(%q) = %v, want %v", tc.path, exists, tc.expected)
+			if err != nil || !got.Equal(tc.want) {
+				t.Errorf("modifiedCutoff() = %v, %v; want %v", got, err, tc.want)
 			}
 		})
 	}
 }
+
+// TestSkippedSummary tests describing skipped file counts by reason
+func TestSkippedSummary(t *testing.T) {
+	skipped := map[handoff.SkipReason]int{
+		handoff.SkipBinary:     1,
+		handoff.SkipFiltered:   4,
+		handoff.SkipGitIgnored: 1,
+	}
+	if got, want := skippedSummary(skipped), "6 (4 filtered, 1 binary, 1 gitignored)"; got != want {
+		t.Errorf("skippedSummary() = %q, want %q", got, want)
+	}
+}
+
+// TestLoadConfigFileClipboardCmd tests reading the CLI-only clipboard command from a config file
+func TestLoadConfigFileClipboardCmd(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "handoff.json")
+	if err := os.WriteFile(path, []byte(`{"include": ".go", "clipboardCmd": "xsel --clipboard --input"}`), 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+
+	fileConfig, err := handoff.LoadProjectConfig(path, "")
+	if err != nil {
+		t.Fatalf("LoadProjectConfig() failed: %v", err)
+	}
+	if fileConfig.ClipboardCmd != "xsel --clipboard --input" {
+		t.Errorf("ClipboardCmd = %q, want the configured command", fileConfig.ClipboardCmd)
+	}
+	if len(fileConfig.Options()) != 1 {
+		t.Errorf("Options() = %d options, want only include", len(fileConfig.Options()))
+	}
+}
+
+// TestSplitFlags tests splitting HANDOFF_FLAGS into arguments
+func TestSplitFlags(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    []string
+		wantErr bool
+	}{
+		{value: "", want: nil},
+		{value: "  -no-tests\t-exclude-names go.sum ", want: []string{"-no-tests", "-exclude-names", "go.sum"}},
+		{value: `-format '## {path}\n{content}'`, want: []string{"-format", `## {path}\n{content}`}},
+		{value: `-exclude-names "a b,c\"d"`, want: []string{"-exclude-names", `a b,c"d`}},
+		{value: `-exclude-names a\ b -x=""`, want: []string{"-exclude-names", "a b", "-x="}},
+		{value: `-format 'unterminated`, wantErr: true},
+		{value: `-no-tests \`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := splitFlags(tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("splitFlags(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("splitFlags(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+// TestParseConfigEnvFlags tests that HANDOFF_FLAGS supplies defaults that
+// command-line flags override
+func TestParseConfigEnvFlags(t *testing.T) {
+	oldFlagCommandLine := flag.CommandLine
+	defer func() { flag.CommandLine = oldFlagCommandLine }()
+
+	t.Setenv(envFlagsName, "-verbose -output=default.md -nfc")
+	flag.CommandLine = flag.NewFlagSet("handoff", flag.ExitOnError)
+	config, cli := parseConfigArgs([]string{"-output=custom.md", "file1.go"})
+
+	if !config.Verbose {
+		t.Error("Expected verbose from HANDOFF_FLAGS")
+	}
+	if !config.NormalizeUnicode {
+		t.Error("Expected NFC normalization from HANDOFF_FLAGS")
+	}
+	if cli.outputFile != "custom.md" {
+		t.Errorf("Expected command-line output custom.md to override HANDOFF_FLAGS, got %q", cli.outputFile)
+	}
+	if args := flag.Args(); !reflect.DeepEqual(args, []string{"file1.go"}) {
+		t.Errorf("Expected paths [file1.go], got %q", args)
+	}
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	handoff "github.com/phrazzld/handoff/lib"
+)
+
+// initHeader introduces the config file written by handoff init
+const initHeader = `# handoff settings; command-line flags take precedence.
+# Apply a profile over the settings above with -profile <name>.
+`
+
+// runInit implements "handoff init": it inspects a project directory (the
+// current one by default) and writes a starter config file with suggested
+// filters for the languages and files it finds.
+func runInit(args []string) {
+	_, cli := parseConfigArgs(args)
+	logger := handoff.NewLogger(false)
+
+	dir := "."
+	switch flag.NArg() {
+	case 0:
+	case 1:
+		dir = flag.Arg(0)
+	default:
+		logger.Error("usage: %s init [-force] [-dry-run] [dir]", os.Args[0])
+		os.Exit(1)
+	}
+
+	suggested, survey, err := handoff.SuggestFileConfig(dir, nil)
+	if err != nil {
+		logger.Error("Failed to inspect %s: %v", dir, err)
+		os.Exit(1)
+	}
+	encoded, err := suggested.YAML()
+	if err != nil {
+		logger.Error("Failed to encode settings: %v", err)
+		os.Exit(1)
+	}
+	content := initHeader + encoded
+
+	logger.Info("%s", surveySummary(survey))
+	path := filepath.Join(dir, handoff.DefaultConfigFileName)
+	if cli.dryRun {
+		fmt.Printf("### DRY RUN: %s ###\n%s", path, content)
+		return
+	}
+	if err := handoff.WriteToFile(content, path, cli.force); err != nil {
+		logger.Error("Failed to write to file %s: %v (use -force to overwrite)", path, err)
+		os.Exit(1)
+	}
+	logger.Info("Wrote %s; review the suggested filters and profiles, then run handoff %s", path, dir)
+}
+
+// surveySummary describes what init found in a project
+func surveySummary(survey handoff.ProjectSurvey) string {
+	var languages []string
+	for _, language := range survey.Languages {
+		languages = append(languages, fmt.Sprintf("%s (%d)", language.Name, language.Files))
+	}
+	summary := fmt.Sprintf("Found %d files", survey.Files)
+	if len(languages) > 0 {
+		summary += ": " + strings.Join(languages, ", ")
+	}
+	if len(survey.IgnoreFiles) > 0 {
+		summary += "\nRespecting " + strings.Join(survey.IgnoreFiles, ", ")
+	}
+	return summary
+}
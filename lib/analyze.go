@@ -0,0 +1,83 @@
+package handoff
+
+import (
+	"strings"
+	"unicode"
+)
+
+// ContentStats holds detailed statistics about a piece of content, as returned
+// by Analyze.
+type ContentStats struct {
+	// Bytes is the length of the content in bytes
+	Bytes int `json:"bytes"`
+
+	// Runes is the number of characters; each invalid UTF-8 byte counts as one
+	Runes int `json:"runes"`
+
+	// Lines is the number of lines. A final line counts whether or not it ends
+	// with a newline, and empty content has no lines.
+	Lines int `json:"lines"`
+
+	// NonEmptyLines is the number of lines holding anything besides whitespace
+	NonEmptyLines int `json:"nonEmptyLines"`
+
+	// LineBreaks is the number of line breaks; LF and CRLF each count as one
+	LineBreaks int `json:"lineBreaks"`
+
+	// Words is the number of runs of letters, digits, and underscores
+	Words int `json:"words"`
+
+	// Tokens is an estimated count of tokens, as reported by CalculateStatistics
+	Tokens int `json:"tokens"`
+}
+
+// Analyze returns detailed statistics about content in a single pass.
+// Unlike CalculateStatistics, it distinguishes bytes from characters and
+// counts lines the way editors do: "a\n" and "a" are both one line, and a
+// carriage return before a newline is part of the line break rather than the line.
+func Analyze(content string) ContentStats {
+	s := ContentStats{Bytes: len(content)}
+	var tokens TokenCounter
+	inWord := false
+	blank := true
+	for _, r := range content {
+		s.Runes++
+		tokens.addRune(r)
+
+		if isWordRune(r) {
+			if !inWord {
+				s.Words++
+			}
+			inWord = true
+		} else {
+			inWord = false
+		}
+
+		if r == '\n' {
+			s.LineBreaks++
+			s.Lines++
+			if !blank {
+				s.NonEmptyLines++
+			}
+			blank = true
+		} else if !unicode.IsSpace(r) {
+			blank = false
+		}
+	}
+
+	// Count a final line that has no trailing newline
+	if content != "" && !strings.HasSuffix(content, "\n") {
+		s.Lines++
+		if !blank {
+			s.NonEmptyLines++
+		}
+	}
+	s.Tokens = tokens.Tokens()
+	return s
+}
+
+// isWordRune reports whether r belongs to a word counted by Analyze. Combining
+// marks are included so decomposed accented letters don't split words.
+func isWordRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r)
+}
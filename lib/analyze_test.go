@@ -0,0 +1,81 @@
+package handoff
+
+import "testing"
+
+// TestAnalyze tests the detailed content statistics
+func TestAnalyze(t *testing.T) {
+	testCases := []struct {
+		name    string
+		content string
+		want    ContentStats
+	}{
+		{
+			name: "Empty",
+		},
+		{
+			name:    "No trailing newline",
+			content: "one two",
+			want:    ContentStats{Bytes: 7, Runes: 7, Lines: 1, NonEmptyLines: 1, Words: 2, Tokens: 2},
+		},
+		{
+			name:    "Trailing newline",
+			content: "one two\n",
+			want:    ContentStats{Bytes: 8, Runes: 8, Lines: 1, NonEmptyLines: 1, LineBreaks: 1, Words: 2, Tokens: 2},
+		},
+		{
+			name:    "CRLF with blank line",
+			content: "a\r\n\r\nb\r\n",
+			want:    ContentStats{Bytes: 8, Runes: 8, Lines: 3, NonEmptyLines: 2, LineBreaks: 3, Words: 2, Tokens: 2},
+		},
+		{
+			name:    "Whitespace-only lines",
+			content: "x\n  \t\n",
+			want:    ContentStats{Bytes: 6, Runes: 6, Lines: 2, NonEmptyLines: 1, LineBreaks: 2, Words: 1, Tokens: 1},
+		},
+		{
+			name:    "Multi-byte characters",
+			content: "caf\u00e9 na\u0131ve",
+			want:    ContentStats{Bytes: 12, Runes: 10, Lines: 1, NonEmptyLines: 1, Words: 2, Tokens: 2},
+		},
+		{
+			name:    "Combining marks stay in the word",
+			content: "cafe\u0301",
+			want:    ContentStats{Bytes: 6, Runes: 5, Lines: 1, NonEmptyLines: 1, Words: 1, Tokens: 1},
+		},
+		{
+			name:    "Punctuation splits words but not tokens",
+			content: "fmt.Println(x_y)",
+			want:    ContentStats{Bytes: 16, Runes: 16, Lines: 1, NonEmptyLines: 1, Words: 3, Tokens: 1},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := Analyze(tc.content); got != tc.want {
+				t.Errorf("Analyze(%q) = %+v, want %+v", tc.content, got, tc.want)
+			}
+		})
+	}
+}
+
+// TestCalculateStatisticsWrapsAnalyze tests that the original statistics are unchanged
+func TestCalculateStatisticsWrapsAnalyze(t *testing.T) {
+	testCases := []struct {
+		content              string
+		chars, lines, tokens int
+	}{
+		{"", 0, 1, 0},
+		{"one two", 7, 1, 2},
+		{"one two\n", 8, 2, 2},
+		{"a\r\nb", 4, 2, 2},
+		{"caf\u00e9", 5, 1, 1},
+	}
+
+	for _, tc := range testCases {
+		chars, lines, tokens := CalculateStatistics(tc.content)
+		if chars != tc.chars || lines != tc.lines || tokens != tc.tokens {
+			t.Errorf("CalculateStatistics(%q) = %d, %d, %d, want %d, %d, %d",
+				tc.content, chars, lines, tokens, tc.chars, tc.lines, tc.tokens)
+		}
+	}
+}
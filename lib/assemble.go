@@ -0,0 +1,431 @@
+package handoff
+
+import (
+	"slices"
+	"strings"
+)
+
+// processPaths processes multiple file or directory paths according to the configuration.
+// It creates a customized processor function that tracks progress and formats output
+// using the config's Format template. The function first discovers all files to process
+// upfront, then processes them, avoiding redundant directory scans.
+//
+// Parameters:
+//   - paths: List of file or directory paths to process
+//   - config: Configuration for file filtering and processing
+//   - logger: Logger for status and error messages
+//
+// Returns:
+//   - A string containing the combined formatted content
+//   - Stats struct with information about processed files and content
+//   - An error if the processing fails, including ErrNoFilesProcessed if paths were provided,
+//     files were found (stats.FilesTotal > 0), but no files were processed due to filtering
+func processPaths(paths []string, config *Config, logger *Logger) (string, Stats, error) {
+	result, err := assemblePaths(paths, config, logger)
+	return result.content(), result.stats, err
+}
+
+// assembly holds the formatted files and sections collected from the processed
+// paths before they are joined, for callers that need per-file output (internal helper)
+type assembly struct {
+	files    []formattedFile
+	sections []string
+	stats    Stats
+}
+
+// content joins the formatted files and sections into the combined output
+func (a *assembly) content() string {
+	var b strings.Builder
+	for _, file := range a.files {
+		b.WriteString(file.heading)
+		b.WriteString(file.output)
+	}
+	for _, section := range a.sections {
+		b.WriteString(section)
+	}
+	return b.String()
+}
+
+// assembler carries the state of one assemblePaths run through its stages
+// (internal helper)
+type assembler struct {
+	config    *Config
+	logger    *Logger
+	formatter Formatter
+
+	// diskPaths are the resolved paths without standard input, which the
+	// supplementary sections describe
+	diskPaths []string
+
+	totalFiles     int
+	processedFiles int
+	trimmedFiles   int
+
+	// oversized counts the files skipped while processing because they alone
+	// exceed the token budget
+	oversized int
+
+	files        []formattedFile
+	skipped      map[SkipReason]int
+	skippedFiles []SkippedFile
+
+	duplicateNotes []string
+	sampleNotes    []string
+
+	sections     []string
+	sectionStats contentStats
+	headingStats contentStats
+
+	// todoIndex is the position of the TODO index in sections, or -1
+	todoIndex int
+
+	// cp records progress so an interrupted run can be resumed, or is nil
+	cp *checkpoint
+}
+
+// assemblePaths discovers, filters, formats, and trims files for processPaths,
+// returning the per-file results along with statistics (internal helper).
+// Like processPaths, it returns ErrNoFilesProcessed along with the (empty) result
+// when files were found but none were processed.
+func assemblePaths(paths []string, config *Config, logger *Logger) (*assembly, error) {
+	a := &assembler{config: config, logger: logger, formatter: config.formatter(), todoIndex: -1}
+
+	// Resolve relative paths against the root, if one is set; supplementary
+	// sections describe the paths on disk, leaving out standard input
+	paths = config.resolvePaths(paths)
+	a.diskPaths = slices.DeleteFunc(slices.Clone(paths), func(path string) bool { return path == StdinPath })
+
+	files, err := a.selectFiles(paths)
+	if err != nil {
+		return &assembly{}, err
+	}
+	if err := a.processFiles(files); err != nil {
+		return &assembly{}, err
+	}
+
+	switch config.Order {
+	case OrderChurn:
+		sortByChurn(a.files, a.diskPaths, config, logger)
+	case OrderEntryPoints:
+		sortByEntryPoints(a.files)
+	}
+
+	if a.processedFiles > 0 {
+		a.buildSections()
+	}
+
+	// Group files by language, leaving room in the budget for the group headings
+	if config.GroupByLanguage {
+		sortByLanguage(a.files)
+		a.headingStats = addLanguageHeadings(a.files, a.formatter)
+	}
+
+	// Cut files down until the output fits the token budget, leaving room for sections
+	if config.MaxTokens > 0 {
+		a.trim()
+	}
+
+	// Subtotal the groups as they are after trimming
+	if config.GroupByLanguage {
+		a.headingStats = addLanguageHeadings(a.files, a.formatter)
+	}
+
+	return a.result(paths)
+}
+
+// selectFiles discovers the files under paths and filters them, then leaves
+// out duplicates, files beyond the directory cap or sample size, and files
+// irrelevant to the query, which are reported as skipped
+func (a *assembler) selectFiles(paths []string) ([]discoveredFile, error) {
+	config, logger := a.config, a.logger
+
+	// Discover all files upfront to avoid redundant directory scans
+	files, err := discoverFiles(paths, config, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	// Store total file count for stats and progress tracking
+	a.totalFiles = len(files)
+	logger.Verbose("Found %d total files across all paths", a.totalFiles)
+
+	// Filter each file once, since later stages and processing all need the result
+	applyFilters(files, config, logger)
+
+	// Include files reachable by several names once, referring to them from the others
+	files, duplicates, duplicateNotes := collapseDuplicates(files, config)
+	for _, note := range duplicateNotes {
+		logger.Verbose("Skipped %s", note)
+	}
+	a.duplicateNotes = duplicateNotes
+	a.skipUnread(duplicates, SkipDuplicate)
+
+	// Leave out files beyond the directory cap or sample size
+	files, sampledOut, sampleNotes := sampleFiles(files, config)
+	for _, file := range sampledOut {
+		logger.Verbose("Sampled out %s", file.path)
+	}
+	a.sampleNotes = sampleNotes
+	a.skipUnread(sampledOut, SkipSampled)
+
+	// Keep only the files most relevant to a query, most relevant first
+	if config.Query != "" {
+		var irrelevant []discoveredFile
+		files, irrelevant = rankFiles(files, config, logger)
+		a.skipUnread(irrelevant, SkipIrrelevant)
+	}
+	return files, nil
+}
+
+// skipUnread reports files left out before they are read like skipped files
+func (a *assembler) skipUnread(files []discoveredFile, reason SkipReason) {
+	for _, file := range files {
+		path := a.config.displayPath(file.path)
+		a.config.Hooks.fileSkipped(path, reason)
+		a.recordSkip(path, reason)
+	}
+}
+
+// recordSkip counts a skipped file for Stats
+func (a *assembler) recordSkip(path string, reason SkipReason) {
+	if a.skipped == nil {
+		a.skipped = make(map[SkipReason]int)
+	}
+	a.skipped[reason]++
+	a.skippedFiles = append(a.skippedFiles, SkippedFile{Path: path, Reason: reason})
+}
+
+// processFiles reads and formats the selected files in order, stopping when
+// the error handler aborts the run
+func (a *assembler) processFiles(files []discoveredFile) error {
+	config, logger := a.config, a.logger
+
+	// Record progress so an interrupted run can be resumed
+	if config.ResumeFile != "" {
+		var err error
+		if a.cp, err = openCheckpoint(config.ResumeFile); err != nil {
+			logger.Warn("%v; continuing without resume support", err)
+		} else {
+			defer func(cp *checkpoint) { _ = cp.Close() }(a.cp)
+			if len(a.cp.entries) > 0 {
+				logger.Info("Resuming from %s (%d files already processed)", config.ResumeFile, len(a.cp.entries))
+			}
+		}
+	}
+
+	// Read the git status of the files to mark them
+	var statuses *gitStatusTracker
+	if config.GitStatus {
+		statuses = readGitStatus(a.diskPaths, config, logger)
+	}
+
+	for _, file := range files {
+		if err := a.addFile(file, statuses); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addFile processes one file and adds its formatted output, or records why it
+// was skipped
+func (a *assembler) addFile(file discoveredFile, statuses *gitStatusTracker) error {
+	config, logger := a.config, a.logger
+	path := config.displayPath(file.path)
+	root := config.rootLabelFor(file.path)
+	status := statuses.status(file.path)
+	config.Hooks.fileStarted(path)
+
+	// Create a processor function that tracks progress and keeps the content
+	// in case the file must be cut down to fit the token budget
+	var content []byte
+	var checksum string
+	var oversized bool
+	processor := func(filepath string, fileContent []byte) string {
+		if oversized = a.exceedsBudget(fileContent); oversized {
+			return ""
+		}
+		a.processedFiles++
+		content = fileContent
+		if config.Checksums {
+			checksum = contentDigest(string(fileContent))
+		}
+		logger.Verbose("Processing file (%d/%d): %s", a.processedFiles, a.totalFiles, filepath)
+
+		// Format the output using the configured formatter
+		return formatFile(a.formatter, FileInfo{Path: config.displayPath(filepath), Root: root, Status: status, Checksum: checksum, Size: int64(len(fileContent))}, fileContent)
+	}
+
+	output, meta := a.readFile(file, processor)
+	switch {
+	case meta.err != nil:
+		return meta.err
+	case oversized:
+		logger.Verbose("Dropped %s (over %d tokens) to fit the token budget", file.path, config.MaxTokens)
+		a.oversized++
+		config.Hooks.fileSkipped(path, SkipOverBudget)
+		a.recordSkip(path, SkipOverBudget)
+	case output != "":
+		// Accumulate statistics while the output is in memory rather than
+		// re-scanning the combined content afterwards
+		formatted := formattedFile{path: path, root: root, status: status, checksum: checksum, content: content, output: output, meta: meta}
+		formatted.stats.add(output)
+		if config.TokenAnnotations {
+			formatted.annotate(a.formatter)
+		}
+		a.files = append(a.files, formatted)
+		config.Hooks.fileDone(path, formatted.fileStat())
+	case meta.skipped != "":
+		a.recordSkip(path, meta.skipped)
+	}
+	return nil
+}
+
+// readFile passes a file's content to the processor, reusing the content
+// recorded by an interrupted run and recording the content read otherwise
+func (a *assembler) readFile(file discoveredFile, processor ProcessorFunc) (string, fileMeta) {
+	if file.path == StdinPath {
+		// Standard input is read afresh, since a checkpoint can't tell if it changed
+		return processStdin(a.logger, a.config, processor)
+	}
+	if recorded, ok := a.cp.lookupFile(file); ok {
+		// Reuse the content read by an earlier, interrupted run, filtering and
+		// transforming it again so the current settings apply
+		if reason := unreadableReason(file, a.logger, a.config); reason != "" {
+			return skipFile(file.path, reason, a.config)
+		}
+		return processContent(file.path, recorded.Content, a.logger, a.config, processor)
+	}
+
+	// Process the file directly without rediscovering it
+	output, meta := processFileMeta(file, a.logger, a.config, processor)
+	if output != "" && a.cp != nil {
+		if err := a.cp.record(file.path, file.info, meta); err != nil {
+			a.logger.Warn("%v; continuing without resume support", err)
+			a.cp = nil
+		}
+	}
+	return output, meta
+}
+
+// exceedsBudget reports whether a file's content alone exceeds the token
+// budget, so it is dropped before it is formatted rather than held until the
+// budget is applied. Only the drop strategy can never keep such a file. Every
+// token takes a character and a separator, so content no longer than twice
+// the budget can't exceed it and isn't scanned.
+func (a *assembler) exceedsBudget(content []byte) bool {
+	limit := a.config.MaxTokens
+	if limit <= 0 || (a.config.TrimStrategy != "" && a.config.TrimStrategy != TrimDrop) {
+		return false
+	}
+	return len(content) > 2*limit && exceedsTokens(string(content), limit)
+}
+
+// buildSections builds supplementary sections such as recent commit history;
+// the TODO index comes last, so it can be rebuilt once files have been trimmed
+func (a *assembler) buildSections() {
+	config, formatter := a.config, a.formatter
+	a.sections = buildSections(a.diskPaths, config, formatter, a.logger)
+	if len(a.duplicateNotes) > 0 {
+		a.sections = append(a.sections, formatSection(formatter, "duplicate-files", strings.Join(a.duplicateNotes, "\n")))
+	}
+	if len(a.sampleNotes) > 0 {
+		a.sections = append(a.sections, formatSection(formatter, "omitted-files", strings.Join(a.sampleNotes, "\n")))
+	}
+	if appendix := skippedAppendixSection(a.skippedFiles, config, formatter); appendix != "" {
+		a.sections = append(a.sections, appendix)
+	}
+	if index := todoIndexSection(a.files, config, formatter); index != "" {
+		a.todoIndex = len(a.sections)
+		a.sections = append(a.sections, index)
+	}
+	a.countSections()
+}
+
+// countSections totals the statistics of the sections
+func (a *assembler) countSections() {
+	a.sectionStats = contentStats{}
+	for _, section := range a.sections {
+		a.sectionStats.add(section)
+	}
+}
+
+// trim cuts files down until the output fits the token budget, leaving room
+// for the sections and language headings
+func (a *assembler) trim() {
+	config, logger := a.config, a.logger
+	var dropped []formattedFile
+	a.files, dropped = trimToBudget(a.files, config.MaxTokens-a.sectionStats.tokens-a.headingStats.tokens, config.trimPriority, config.TrimStrategy, a.formatter)
+	for _, file := range a.files {
+		if file.trimmed {
+			a.trimmedFiles++
+			logger.Verbose("Shortened %s to %d tokens to fit the token budget", file.path, file.stats.tokens)
+		}
+	}
+	for _, file := range dropped {
+		logger.Verbose("Dropped %s (%d tokens) to fit the token budget", file.path, file.stats.tokens)
+	}
+	droppedFiles := len(dropped)
+	a.processedFiles -= droppedFiles
+	a.trimmedFiles += droppedFiles
+	if a.trimmedFiles > 0 {
+		logger.Warn("trimmed %d files (%d dropped) to fit the %d-token budget", a.trimmedFiles, droppedFiles, config.MaxTokens)
+	}
+	if a.oversized > 0 {
+		logger.Warn("skipped %d files larger than the whole %d-token budget", a.oversized, config.MaxTokens)
+	}
+
+	// List only the markers left in the files kept; the index can only
+	// shrink, so it still fits the room left for it
+	if a.todoIndex >= 0 && a.trimmedFiles > 0 {
+		a.sections = a.sections[:a.todoIndex]
+		if index := todoIndexSection(a.files, config, a.formatter); index != "" {
+			a.sections = append(a.sections, index)
+		}
+		a.countSections()
+	}
+}
+
+// result totals the statistics of the kept files and sections into the
+// assembly, with ErrNoFilesProcessed when paths were given and files were
+// found but none were processed, or the error for skips made strict
+func (a *assembler) result(paths []string) (*assembly, error) {
+	var totals contentStats
+	totals.merge(a.headingStats)
+	fileStats := make([]FileStat, 0, len(a.files))
+	includedFiles := make([]string, 0, len(a.files))
+	for _, file := range a.files {
+		totals.merge(file.stats)
+		fileStats = append(fileStats, file.fileStat())
+		includedFiles = append(includedFiles, file.path)
+	}
+	warnLineEndings(fileStats, a.logger)
+	sections := a.sections
+	if a.processedFiles > 0 {
+		totals.merge(a.sectionStats)
+	} else {
+		sections = nil
+	}
+
+	stats := Stats{
+		FilesProcessed: a.processedFiles,
+		FilesTotal:     a.totalFiles,
+		FilesTrimmed:   a.trimmedFiles,
+		Lines:          totals.lines(),
+		Chars:          totals.chars,
+		Tokens:         totals.tokens,
+		IncludedFiles:  includedFiles,
+		Files:          fileStats,
+		Skipped:        a.skipped,
+		SkippedFiles:   a.skippedFiles,
+	}
+
+	result := &assembly{files: a.files, sections: sections, stats: stats}
+	if len(paths) > 0 && stats.FilesProcessed == 0 && stats.FilesTotal > 0 {
+		return result, ErrNoFilesProcessed
+	}
+	if err := checkStrictSkips(a.skippedFiles, a.config); err != nil {
+		return result, err
+	}
+	return result, nil
+}
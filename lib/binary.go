@@ -0,0 +1,163 @@
+package handoff
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// BinaryEncoding selects how binary files are shown when they are included
+type BinaryEncoding string
+
+const (
+	// BinaryHex shows binary files as a hexdump -C style listing of offsets,
+	// hex bytes, and printable characters
+	BinaryHex BinaryEncoding = "hex"
+
+	// BinaryBase64 shows binary files as standard base64 in 76-character lines
+	BinaryBase64 BinaryEncoding = "base64"
+)
+
+// binaryDumpLimit is the number of bytes of a binary file that are dumped;
+// the rest is replaced with a marker
+const binaryDumpLimit = 16 * 1024
+
+// base64LineLength is the line length of base64 dumps, as in MIME
+const base64LineLength = 76
+
+// ParseBinaryEncoding converts a name such as "hex" into a BinaryEncoding.
+func ParseBinaryEncoding(name string) (BinaryEncoding, error) {
+	switch encoding := BinaryEncoding(strings.ToLower(name)); encoding {
+	case BinaryHex, BinaryBase64:
+		return encoding, nil
+	}
+	return "", fmt.Errorf("unknown binary encoding %q (want hex or base64)", name)
+}
+
+// WithIncludeBinary sets how binary files are included instead of skipped.
+// Only binary files named directly as paths, or whose extension is in the
+// include list, are dumped; binary files found by walking directories are
+// still skipped. The first 16KB of each file is shown. An empty encoding skips
+// every binary file.
+func WithIncludeBinary(encoding BinaryEncoding) Option {
+	return func(c *Config) {
+		c.IncludeBinary = encoding
+	}
+}
+
+// binaryAllowed reports whether a binary file was asked for closely enough to
+// be dumped: named directly as a path, or matching an included extension
+// (internal helper)
+func binaryAllowed(filePath string, explicit bool, config *Config) bool {
+	if explicit {
+		return true
+	}
+	includeExts := config.includeExts
+	if rule := matchPathRule(filePath, config.pathRules); rule != nil {
+		includeExts = rule.Include
+	}
+	return slices.Contains(includeExts, strings.ToLower(filepath.Ext(filePath)))
+}
+
+// readBinaryDump reads up to binaryDumpLimit bytes of a file and returns them
+// dumped in the given encoding (internal helper)
+func readBinaryDump(filePath string, size int64, encoding BinaryEncoding, throttle *ioThrottle) ([]byte, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	data, err := io.ReadAll(io.LimitReader(throttle.reader(f), binaryDumpLimit))
+	if err != nil {
+		return nil, err
+	}
+	return dumpBinary(data, max(size, int64(len(data))), encoding), nil
+}
+
+// dumpBinary renders the leading bytes of a binary file of the given total
+// size, preceded by a line describing the file and followed by a marker for
+// any bytes left out (internal helper)
+func dumpBinary(data []byte, size int64, encoding BinaryEncoding) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "[binary file, %d bytes, shown as %s]\n", size, encoding)
+
+	switch encoding {
+	case BinaryBase64:
+		encoded := base64.StdEncoding.EncodeToString(data)
+		for len(encoded) > base64LineLength {
+			b.WriteString(encoded[:base64LineLength])
+			b.WriteByte('\n')
+			encoded = encoded[base64LineLength:]
+		}
+		if encoded != "" {
+			b.WriteString(encoded)
+			b.WriteByte('\n')
+		}
+	default:
+		b.WriteString(hex.Dump(data))
+	}
+
+	if rest := size - int64(len(data)); rest > 0 {
+		fmt.Fprintf(&b, "[... %d more bytes not shown ...]\n", rest)
+	}
+	return []byte(b.String())
+}
+
+// Constants for binary file detection
+const (
+	binarySampleSize            = 512 // Number of bytes to sample for binary detection
+	binaryNonPrintableThreshold = 0.3 // Threshold ratio of non-printable chars to consider a file binary
+)
+
+// isBinaryFile uses heuristics to determine if content is likely binary.
+// This is an internal helper that employs two main detection strategies:
+//  1. Presence of null bytes (ASCII 0): Any null byte indicates binary content
+//  2. High ratio of non-printable characters: If more than 30% of the first 512 bytes
+//     are non-printable, non-whitespace characters, the content is considered binary
+//
+// Note that this heuristic approach:
+//   - Only examines up to the first 512 bytes (configurable via binarySampleSize)
+//   - May produce false positives for some text files with unusual encoding
+//   - May produce false negatives for some binary files that appear text-like
+//   - Only considers ASCII control characters and DEL (127) as non-printable
+//   - Treats common whitespace characters (\n, \r, \t, space) as printable
+func isBinaryFile(content []byte) bool {
+	// Check for null bytes, which are common in binary files
+	if len(content) > 0 && bytes.IndexByte(content, 0) != -1 {
+		return true
+	}
+
+	// Check for a high percentage of non-printable, non-whitespace characters
+	// which suggest binary content
+	nonPrintable := 0
+	sampleSize := minInt(len(content), binarySampleSize) // Sample the first bytes
+	for i := 0; i < sampleSize; i++ {
+		// Check for non-printable characters (ASCII 0-31 except whitespace, and DEL which is 127)
+		if (content[i] < 32 && !isWhitespace(content[i])) || content[i] == 127 {
+			nonPrintable++
+		}
+	}
+
+	// If more than the threshold percentage of sampled bytes are non-printable, consider it binary
+	return float64(nonPrintable) > float64(sampleSize)*binaryNonPrintableThreshold
+}
+
+// isWhitespace checks if a byte is a whitespace character (unexported, internal helper)
+func isWhitespace(b byte) bool {
+	return b == '\n' || b == '\r' || b == '\t' || b == ' '
+}
+
+// minInt returns the minimum of two integers (unexported, internal helper)
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
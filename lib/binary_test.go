@@ -0,0 +1,102 @@
+package handoff
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestDumpBinary tests rendering binary data as hex and base64
+func TestDumpBinary(t *testing.T) {
+	data := []byte{0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00}
+
+	hexDump := string(dumpBinary(data, 8, BinaryHex))
+	wantHex := "[binary file, 8 bytes, shown as hex]\n" +
+		"00000000  00 61 73 6d 01 00 00 00                           |.asm....|\n"
+	if hexDump != wantHex {
+		t.Errorf("hex dump = %q, want %q", hexDump, wantHex)
+	}
+
+	base64Dump := string(dumpBinary(data, 20, BinaryBase64))
+	wantBase64 := "[binary file, 20 bytes, shown as base64]\n" +
+		"AGFzbQEAAAA=\n" +
+		"[... 12 more bytes not shown ...]\n"
+	if base64Dump != wantBase64 {
+		t.Errorf("base64 dump = %q, want %q", base64Dump, wantBase64)
+	}
+
+	long := string(dumpBinary(make([]byte, 100), 100, BinaryBase64))
+	for _, line := range strings.Split(strings.TrimSuffix(long, "\n"), "\n")[1:] {
+		if len(line) > base64LineLength {
+			t.Errorf("base64 line is %d characters, want at most %d", len(line), base64LineLength)
+		}
+	}
+}
+
+// TestParseBinaryEncoding tests parsing binary encoding names
+func TestParseBinaryEncoding(t *testing.T) {
+	if encoding, err := ParseBinaryEncoding("Base64"); err != nil || encoding != BinaryBase64 {
+		t.Errorf("ParseBinaryEncoding(Base64) = %q, %v; want base64", encoding, err)
+	}
+	if _, err := ParseBinaryEncoding("octal"); err == nil {
+		t.Errorf("expected error for unknown binary encoding")
+	}
+}
+
+// TestIncludeBinaryProcessing tests that only binary files named as paths or
+// matching an included extension are dumped, and only up to the limit
+func TestIncludeBinaryProcessing(t *testing.T) {
+	dir := t.TempDir()
+	fixture := filepath.Join(dir, "fixture.bin")
+	if err := os.WriteFile(fixture, append([]byte{0x00, 0xff}, make([]byte, binaryDumpLimit)...), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	blob := filepath.Join(dir, "module.wasm")
+	if err := os.WriteFile(blob, []byte{0x00, 0x61, 0x73, 0x6d}, 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	// Binary files found by walking the directory are still skipped
+	config := NewConfig(WithGitClient(NewMockGitClient(false)), WithIncludeBinary(BinaryHex))
+	content, _, err := ProcessProject([]string{dir}, config)
+	if err != nil {
+		t.Fatalf("ProcessProject failed: %v", err)
+	}
+	if strings.Contains(content, "binary file") {
+		t.Errorf("expected directory binaries to be skipped, got:\n%s", content)
+	}
+
+	// A binary file named as a path is dumped up to the limit
+	content, _, err = ProcessProject([]string{dir, fixture}, config)
+	if err != nil {
+		t.Fatalf("ProcessProject failed: %v", err)
+	}
+	if !strings.Contains(content, "[binary file, 16386 bytes, shown as hex]") ||
+		!strings.Contains(content, "00000000  00 ff 00") ||
+		!strings.Contains(content, "[... 2 more bytes not shown ...]") {
+		t.Errorf("expected bounded hex dump of the named file, got:\n%s", content)
+	}
+	if strings.Contains(content, "module.wasm") {
+		t.Errorf("expected unnamed binary to be skipped, got:\n%s", content)
+	}
+
+	// A binary file matching an included extension is dumped
+	config = NewConfig(WithGitClient(NewMockGitClient(false)), WithInclude(".wasm"), WithIncludeBinary(BinaryBase64))
+	content, _, err = ProcessProject([]string{dir}, config)
+	if err != nil {
+		t.Fatalf("ProcessProject failed: %v", err)
+	}
+	if !strings.Contains(content, "[binary file, 4 bytes, shown as base64]\nAGFzbQ==\n") {
+		t.Errorf("expected base64 dump of the included extension, got:\n%s", content)
+	}
+
+	// Without an encoding, named binary files are skipped as before
+	config = NewConfig(WithGitClient(NewMockGitClient(false)))
+	if content, _, _ = ProcessProject([]string{fixture, filepath.Join(dir, "main.go")}, config); strings.Contains(content, "fixture.bin") {
+		t.Errorf("expected binary file to be skipped without an encoding, got:\n%s", content)
+	}
+}
@@ -0,0 +1,115 @@
+package handoff
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"unicode/utf8"
+)
+
+const (
+	// blobLineLength is the line length beyond which a line is treated as embedded data
+	blobLineLength = 1000
+
+	// blobLinePrefix is how much of a long line without a recognizable blob is kept
+	blobLinePrefix = 120
+
+	// blobMinRunLines is the number of consecutive data-only lines that are collapsed
+	blobMinRunLines = 20
+)
+
+var (
+	// blobTokenPattern matches long base64, base64url, or hex strings
+	blobTokenPattern = regexp.MustCompile(`[A-Za-z0-9+/_-]{200,}={0,2}`)
+
+	// blobArrayPattern matches long comma-separated runs of numeric literals,
+	// such as byte-array initializers
+	blobArrayPattern = regexp.MustCompile(`(?:(?:0[xX][0-9a-fA-F]+|\d+)\s*,\s*){100,}(?:0[xX][0-9a-fA-F]+|\d+)?`)
+
+	// blobDataLinePattern matches a line holding only numeric literals and separators
+	blobDataLinePattern = regexp.MustCompile(`^\s*(?:(?:0[xX][0-9a-fA-F]+|\d+)\s*,\s*)+(?:0[xX][0-9a-fA-F]+|\d+)?\s*$`)
+)
+
+// WithCollapseBlobs sets whether enormous inline data inside source files, such
+// as base64 strings, byte-array literals, and multi-thousand-character lines,
+// is replaced with a short "[... 48KB data elided ...]" marker to keep the
+// surrounding code readable and cheap.
+func WithCollapseBlobs(collapse bool) Option {
+	return func(c *Config) {
+		c.CollapseBlobs = collapse
+	}
+}
+
+// blobMarker describes elided data of the given size in bytes (internal helper)
+func blobMarker(size int) string {
+	if size < 1024 {
+		return fmt.Sprintf("[... %dB data elided ...]", size)
+	}
+	return fmt.Sprintf("[... %dKB data elided ...]", (size+512)/1024)
+}
+
+// collapseBlobs replaces embedded data in content with size markers: long
+// base64/hex strings and numeric array runs within long lines, the remainder
+// of long lines with no recognizable blob, and runs of lines holding only
+// numeric literals. Content without blobs is returned unchanged (internal helper).
+func collapseBlobs(content []byte) []byte {
+	lines := bytes.SplitAfter(content, []byte("\n"))
+	var out bytes.Buffer
+	changed := false
+
+	for i := 0; i < len(lines); i++ {
+		// Collapse runs of data-only lines into one marker, keeping the indentation
+		run := 0
+		size := 0
+		for i+run < len(lines) && blobDataLinePattern.Match(bytes.TrimRight(lines[i+run], "\r\n")) {
+			size += len(lines[i+run])
+			run++
+		}
+		if run >= blobMinRunLines {
+			line := lines[i]
+			indent := line[:len(line)-len(bytes.TrimLeft(line, " \t"))]
+			out.Write(indent)
+			out.WriteString(blobMarker(size))
+			out.WriteString("\n")
+			i += run - 1
+			changed = true
+			continue
+		}
+
+		line := lines[i]
+		if len(line) > blobLineLength {
+			line = collapseLongLine(line)
+			changed = true
+		}
+		out.Write(line)
+	}
+
+	if !changed {
+		return content
+	}
+	return out.Bytes()
+}
+
+// collapseLongLine elides blobs within a single long line, or its tail when no
+// blob is recognized, preserving the line ending (internal helper)
+func collapseLongLine(line []byte) []byte {
+	body := bytes.TrimRight(line, "\r\n")
+	ending := line[len(body):]
+
+	collapse := func(blob []byte) []byte {
+		return []byte(blobMarker(len(blob)))
+	}
+	collapsed := blobArrayPattern.ReplaceAllFunc(body, collapse)
+	collapsed = blobTokenPattern.ReplaceAllFunc(collapsed, collapse)
+
+	if len(collapsed) > blobLineLength {
+		// Nothing recognizable: keep the start of the line for context, cut at a
+		// character boundary
+		keep := blobLinePrefix
+		for keep > 0 && !utf8.RuneStart(collapsed[keep]) {
+			keep--
+		}
+		collapsed = append(collapsed[:keep:keep], " "+blobMarker(len(collapsed)-keep)...)
+	}
+	return append(collapsed, ending...)
+}
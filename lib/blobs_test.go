@@ -0,0 +1,72 @@
+package handoff
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestCollapseBlobs tests eliding embedded data while keeping surrounding code
+func TestCollapseBlobs(t *testing.T) {
+	base64Blob := strings.Repeat("QUJDRA", 2000)
+	byteArray := strings.Repeat("0x1f, ", 400) + "0x00"
+	dataLines := strings.Repeat("\t0x01, 0x02, 0x03, 0x04,\n", 30)
+	longCode := "var x = " + strings.Repeat("a + ", 400) + "a;"
+
+	testCases := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{
+			name:    "No blobs",
+			content: "package main\n\nfunc main() {}\n",
+			want:    "package main\n\nfunc main() {}\n",
+		},
+		{
+			name:    "Base64 string",
+			content: "const logo = \"" + base64Blob + "\"\nfunc f() {}\n",
+			want:    "const logo = \"[... 12KB data elided ...]\"\nfunc f() {}\n",
+		},
+		{
+			name:    "Inline byte array",
+			content: "var data = []byte{" + byteArray + "}\n",
+			want:    "var data = []byte{[... 2KB data elided ...]}\n",
+		},
+		{
+			name:    "Multi-line byte array",
+			content: "var data = []byte{\n" + dataLines + "}\n",
+			want:    "var data = []byte{\n\t[... 750B data elided ...]\n}\n",
+		},
+		{
+			name:    "Short data runs are kept",
+			content: "x := []int{\n1, 2,\n3, 4,\n}\n",
+			want:    "x := []int{\n1, 2,\n3, 4,\n}\n",
+		},
+		{
+			name:    "Long line without a recognizable blob",
+			content: longCode + "\r\nnext\n",
+			want:    longCode[:120] + " [... " + "1KB data elided ...]\r\nnext\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := string(collapseBlobs([]byte(tc.content))); got != tc.want {
+				t.Errorf("collapseBlobs() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+// TestBlobMarker tests size formatting in elision markers
+func TestBlobMarker(t *testing.T) {
+	for size, want := range map[int]string{
+		512:       "[... 512B data elided ...]",
+		48 * 1024: "[... 48KB data elided ...]",
+		1500:      "[... 1KB data elided ...]",
+	} {
+		if got := blobMarker(size); got != want {
+			t.Errorf("blobMarker(%d) = %q, want %q", size, got, want)
+		}
+	}
+}
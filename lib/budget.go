@@ -0,0 +1,343 @@
+package handoff
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Trim priority rules understood by WithTrimPriority. Any other rule is treated
+// as a glob pattern naming files to drop first.
+const (
+	// TrimLargest drops files with the most estimated tokens first
+	TrimLargest = "largest"
+
+	// TrimTests drops test files (e.g., foo_test.go, foo.spec.ts, tests/...) first
+	TrimTests = "tests"
+)
+
+// defaultTrimPriority is used when no trim priority is configured
+var defaultTrimPriority = []string{TrimTests, TrimLargest}
+
+// WithMaxTokens sets a budget on the estimated number of tokens in the output.
+// When the collected content exceeds the budget, files are cut down in the order
+// given by the trim priority (see WithTrimPriority) until it fits, either by
+// dropping them whole or as selected with WithTrimStrategy.
+// A value of zero or less disables the budget.
+func WithMaxTokens(n int) Option {
+	return func(c *Config) {
+		c.MaxTokens = n
+	}
+}
+
+// WithTrimPriority sets the order in which files are dropped when the output
+// exceeds the token budget. Each rule is TrimLargest, TrimTests, or a glob pattern
+// matched against a file's base name or slash-separated path (e.g., "*.md" or
+// "docs/*"). Rules are applied in order: the first rule that distinguishes two
+// files decides which is dropped first, and files that no rule distinguishes are
+// dropped in reverse discovery order.
+//
+// When no rules are set, test files are dropped first, then the largest files.
+func WithTrimPriority(rules []string) Option {
+	return func(c *Config) {
+		c.trimPriority = slices.Clone(rules)
+	}
+}
+
+// TrimStrategy selects how files chosen by the trim priority are cut down when
+// the output exceeds the token budget.
+type TrimStrategy string
+
+const (
+	// TrimDrop omits whole files
+	TrimDrop TrimStrategy = "drop"
+
+	// TrimTailTruncate keeps the head of a file, cutting just enough lines from its end
+	TrimTailTruncate TrimStrategy = "tail-truncate"
+
+	// TrimOutline replaces a file with an outline of its declarations and headings
+	TrimOutline TrimStrategy = "outline"
+)
+
+// WithTrimStrategy sets how files are cut down to fit the token budget.
+// Files are visited in trim priority order (see WithTrimPriority) until the output
+// fits; files that still don't fit after truncating or outlining are dropped.
+// The default strategy is TrimDrop.
+func WithTrimStrategy(strategy TrimStrategy) Option {
+	return func(c *Config) {
+		c.TrimStrategy = strategy
+	}
+}
+
+// ParseTrimStrategy converts a strategy name such as "tail-truncate" into a TrimStrategy
+func ParseTrimStrategy(name string) (TrimStrategy, error) {
+	switch strategy := TrimStrategy(name); strategy {
+	case TrimDrop, TrimTailTruncate, TrimOutline:
+		return strategy, nil
+	}
+	return "", fmt.Errorf("unknown trim strategy %q (want %s, %s, or %s)", name, TrimDrop, TrimTailTruncate, TrimOutline)
+}
+
+// formattedFile is a file's formatted output held until the token budget is applied (internal helper)
+type formattedFile struct {
+	path     string
+	root     string
+	status   FileStatus
+	checksum string
+	content  []byte
+	output   string
+	stats    contentStats
+	meta     fileMeta
+
+	// heading is written before the output to introduce a group of files;
+	// it is not counted in stats
+	heading string
+
+	// trimmed is set when the output was shortened to fit the token budget
+	trimmed bool
+
+	// annotated is set when the output ends with a token annotation, which
+	// is added again if the file is shortened
+	annotated bool
+}
+
+// annotate adds the formatter's token annotation after the file's block, if the
+// formatter has one, and updates the statistics to include it
+func (f *formattedFile) annotate(formatter Formatter) {
+	annotator, ok := formatter.(tokenAnnotator)
+	if !ok {
+		return
+	}
+	f.output = appendAnnotation(f.output, annotator.tokenAnnotation(f.stats.tokens))
+	f.stats = contentStats{}
+	f.stats.add(f.output)
+	f.annotated = true
+}
+
+// fileStat returns the statistics reported for the file in Stats.Files
+func (f formattedFile) fileStat() FileStat {
+	return FileStat{
+		Path:        f.path,
+		Lines:       f.stats.lines(),
+		Chars:       f.stats.chars,
+		Tokens:      f.stats.tokens,
+		LineEndings: f.meta.lineEndings,
+		Encoding:    f.meta.encoding,
+		Transcoded:  f.meta.transcoded,
+		GitStatus:   f.status,
+		Checksum:    f.checksum,
+	}
+}
+
+// trimToBudget cuts files down until their combined token estimate fits within budget (internal helper).
+// Files are visited in trim priority order and shortened according to the strategy,
+// then dropped in the same order if the output still doesn't fit. The kept files
+// retain their original order. It returns the kept files and the dropped files.
+func trimToBudget(files []formattedFile, budget int, rules []string, strategy TrimStrategy, formatter Formatter) (kept, dropped []formattedFile) {
+	total := 0
+	for _, file := range files {
+		total += file.stats.tokens
+	}
+	if total <= budget {
+		return files, nil
+	}
+	if len(rules) == 0 {
+		rules = defaultTrimPriority
+	}
+
+	// Start from reverse discovery order so the stable sort breaks ties by
+	// dropping files found last
+	order := make([]int, len(files))
+	for i := range order {
+		order[i] = len(files) - 1 - i
+	}
+	slices.SortStableFunc(order, func(a, b int) int {
+		return compareTrimPriority(files[a], files[b], rules)
+	})
+
+	files = slices.Clone(files)
+	if strategy == TrimTailTruncate || strategy == TrimOutline {
+		for _, i := range order {
+			if total <= budget {
+				break
+			}
+			var content []byte
+			if strategy == TrimTailTruncate {
+				content = truncateTail(files[i].content, estimateTokenCount(string(files[i].content))-(total-budget))
+			} else {
+				content = outlineContent(files[i].content)
+			}
+			if content == nil {
+				continue
+			}
+
+			var checksum string
+			if files[i].checksum != "" {
+				checksum = contentDigest(string(content))
+			}
+			output := formatFile(formatter, FileInfo{Path: files[i].path, Root: files[i].root, Status: files[i].status, Checksum: checksum, Size: int64(len(content))}, content)
+			var stats contentStats
+			stats.add(output)
+			shortened := formattedFile{path: files[i].path, root: files[i].root, status: files[i].status, checksum: checksum, content: content, output: output, stats: stats, meta: files[i].meta, trimmed: true}
+			if files[i].annotated {
+				shortened.annotate(formatter)
+			}
+			if shortened.stats.tokens >= files[i].stats.tokens {
+				continue
+			}
+			total -= files[i].stats.tokens - shortened.stats.tokens
+			files[i] = shortened
+		}
+	}
+
+	drop := make([]bool, len(files))
+	for _, i := range order {
+		if total <= budget {
+			break
+		}
+		drop[i] = true
+		total -= files[i].stats.tokens
+	}
+
+	for i, file := range files {
+		if drop[i] {
+			dropped = append(dropped, file)
+		} else {
+			kept = append(kept, file)
+		}
+	}
+	return kept, dropped
+}
+
+// truncateTail keeps the leading lines of content that fit within roughly
+// maxTokens tokens, followed by a marker noting how many lines were cut.
+// It returns nil when not even one line fits. (internal helper)
+func truncateTail(content []byte, maxTokens int) []byte {
+	lines := strings.SplitAfter(string(content), "\n")
+	// Reserve room for the truncation marker
+	maxTokens -= 5
+
+	tokens := 0
+	keep := 0
+	for keep < len(lines) && tokens+estimateTokenCount(lines[keep]) <= maxTokens {
+		tokens += estimateTokenCount(lines[keep])
+		keep++
+	}
+	if keep == 0 || keep == len(lines) {
+		return nil
+	}
+
+	head := strings.Join(lines[:keep], "")
+	if !strings.HasSuffix(head, "\n") {
+		head += "\n"
+	}
+	return []byte(fmt.Sprintf("%s... (truncated %d more lines)", head, len(lines)-keep))
+}
+
+// outlinePattern matches lines that declare functions, types, and classes in
+// common languages, plus Markdown headings
+var outlinePattern = regexp.MustCompile(`^\s*(?:(?:export|default|public|private|protected|internal|static|abstract|final|async|pub(?:\([a-z]+\))?)\s+)*(?:func|function|type|class|interface|struct|enum|trait|impl|fn|def|module|package)\b|^#{1,6}\s`)
+
+// outlineContent reduces content to its declaration and heading lines, followed
+// by a marker noting how many lines are shown. It returns nil when content has
+// no such lines. (internal helper)
+func outlineContent(content []byte) []byte {
+	lines := strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+
+	var outline []string
+	for _, line := range lines {
+		if outlinePattern.MatchString(line) {
+			outline = append(outline, strings.TrimRight(line, " \t\r{"))
+		}
+	}
+	if len(outline) == 0 || len(outline) == len(lines) {
+		return nil
+	}
+
+	return []byte(fmt.Sprintf("%s\n... (outline: %d of %d lines shown)", strings.Join(outline, "\n"), len(outline), len(lines)))
+}
+
+// compareTrimPriority orders two files for dropping: negative if a should be
+// dropped before b, positive if after, zero if no rule distinguishes them (internal helper)
+func compareTrimPriority(a, b formattedFile, rules []string) int {
+	for _, rule := range rules {
+		var cmp int
+		switch rule {
+		case TrimLargest:
+			cmp = b.stats.tokens - a.stats.tokens
+		case TrimTests:
+			cmp = compareMatches(isTestFile(a.path), isTestFile(b.path))
+		default:
+			cmp = compareMatches(matchesTrimPattern(a.path, rule), matchesTrimPattern(b.path, rule))
+		}
+		if cmp != 0 {
+			return cmp
+		}
+	}
+	return 0
+}
+
+// compareMatches orders a matching file before a non-matching one (internal helper)
+func compareMatches(a, b bool) int {
+	switch {
+	case a && !b:
+		return -1
+	case b && !a:
+		return 1
+	}
+	return 0
+}
+
+// matchesTrimPattern reports whether a glob pattern matches a file's base name
+// or slash-separated path (internal helper)
+func matchesTrimPattern(path, pattern string) bool {
+	slashPath := filepath.ToSlash(filepath.Clean(path))
+	if matched, err := filepath.Match(pattern, filepath.Base(path)); err == nil && matched {
+		return true
+	}
+	if matched, err := filepath.Match(pattern, slashPath); err == nil && matched {
+		return true
+	}
+	// Allow relative patterns like "docs/*" to match paths below the working directory
+	return strings.Contains(pattern, "/") && matchesPathSuffix(slashPath, pattern)
+}
+
+// matchesPathSuffix reports whether a glob pattern matches any trailing run of
+// path components (internal helper)
+func matchesPathSuffix(slashPath, pattern string) bool {
+	for i := strings.IndexByte(slashPath, '/'); i >= 0; {
+		slashPath = slashPath[i+1:]
+		if matched, err := filepath.Match(pattern, slashPath); err == nil && matched {
+			return true
+		}
+		i = strings.IndexByte(slashPath, '/')
+	}
+	return false
+}
+
+// testDirNames are directory names whose contents are treated as tests
+var testDirNames = []string{"test", "tests", "__tests__", "spec", "specs", "testdata"}
+
+// isTestFile reports whether a path follows common test file conventions (internal helper)
+func isTestFile(path string) bool {
+	slashPath := filepath.ToSlash(filepath.Clean(path))
+	base := strings.ToLower(filepath.Base(slashPath))
+	stem := strings.TrimSuffix(base, filepath.Ext(base))
+
+	switch {
+	case strings.HasSuffix(stem, "_test"), strings.HasPrefix(stem, "test_"),
+		strings.HasSuffix(stem, ".test"), strings.HasSuffix(stem, ".spec"),
+		strings.HasSuffix(stem, "_spec"):
+		return true
+	}
+
+	dirs := strings.Split(filepath.ToSlash(filepath.Dir(slashPath)), "/")
+	for _, dir := range dirs {
+		if slices.Contains(testDirNames, strings.ToLower(dir)) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,244 @@
+package handoff
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// newFormattedFile builds a formattedFile with the given number of tokens
+func newFormattedFile(path string, tokens int) formattedFile {
+	f := formattedFile{path: path, output: strings.Repeat("tok ", tokens)}
+	f.stats.add(f.output)
+	return f
+}
+
+// filePaths returns the paths of formatted files in order
+func filePaths(files []formattedFile) []string {
+	var paths []string
+	for _, f := range files {
+		paths = append(paths, f.path)
+	}
+	return paths
+}
+
+// TestTrimToBudget tests the order in which files are dropped to fit a token budget
+func TestTrimToBudget(t *testing.T) {
+	files := []formattedFile{
+		newFormattedFile("main.go", 30),
+		newFormattedFile("main_test.go", 20),
+		newFormattedFile("README.md", 40),
+		newFormattedFile("util.go", 10),
+	}
+
+	testCases := []struct {
+		name        string
+		budget      int
+		rules       []string
+		wantKept    []string
+		wantDropped []string
+	}{
+		{
+			name:     "Within budget",
+			budget:   100,
+			wantKept: []string{"main.go", "main_test.go", "README.md", "util.go"},
+		},
+		{
+			name:        "Default drops tests then largest",
+			budget:      50,
+			wantKept:    []string{"main.go", "util.go"},
+			wantDropped: []string{"main_test.go", "README.md"},
+		},
+		{
+			name:        "Largest first",
+			budget:      50,
+			rules:       []string{TrimLargest},
+			wantKept:    []string{"main_test.go", "util.go"},
+			wantDropped: []string{"main.go", "README.md"},
+		},
+		{
+			name:        "Glob first",
+			budget:      70,
+			rules:       []string{"*.md"},
+			wantKept:    []string{"main.go", "main_test.go", "util.go"},
+			wantDropped: []string{"README.md"},
+		},
+		{
+			name:        "Ties drop files found last",
+			budget:      60,
+			rules:       []string{"*.txt"},
+			wantKept:    []string{"main.go", "main_test.go"},
+			wantDropped: []string{"README.md", "util.go"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			kept, dropped := trimToBudget(files, tc.budget, tc.rules, TrimDrop, nil)
+			if !equalSlices(filePaths(kept), tc.wantKept) {
+				t.Errorf("kept = %v, want %v", filePaths(kept), tc.wantKept)
+			}
+			if !equalSlices(filePaths(dropped), tc.wantDropped) {
+				t.Errorf("dropped = %v, want %v", filePaths(dropped), tc.wantDropped)
+			}
+		})
+	}
+}
+
+// TestIsTestFile tests recognition of common test file conventions
+func TestIsTestFile(t *testing.T) {
+	testCases := map[string]bool{
+		"lib/handoff_test.go":        true,
+		"src/app.spec.ts":            true,
+		"src/app.test.js":            true,
+		"tests/test_parser.py":       true,
+		"spec/models/user_spec.rb":   true,
+		"src/__tests__/App.jsx":      true,
+		"lib/handoff.go":             false,
+		"docs/latest.md":             false,
+		"internal/contest/winner.go": false,
+	}
+
+	for path, want := range testCases {
+		if got := isTestFile(path); got != want {
+			t.Errorf("isTestFile(%q) = %v, want %v", path, got, want)
+		}
+	}
+}
+
+// TestMatchesTrimPattern tests glob matching for trim priority rules
+func TestMatchesTrimPattern(t *testing.T) {
+	testCases := []struct {
+		path    string
+		pattern string
+		want    bool
+	}{
+		{"docs/guide.md", "*.md", true},
+		{"/tmp/project/docs/guide.md", "docs/*", true},
+		{"docs/guide.md", "docs/*", true},
+		{"src/docs.go", "docs/*", false},
+		{"src/main.go", "*.md", false},
+	}
+
+	for _, tc := range testCases {
+		if got := matchesTrimPattern(tc.path, tc.pattern); got != tc.want {
+			t.Errorf("matchesTrimPattern(%q, %q) = %v, want %v", tc.path, tc.pattern, got, tc.want)
+		}
+	}
+}
+
+// TestProcessPathsTokenBudget tests that processPaths drops files to fit MaxTokens
+func TestProcessPathsTokenBudget(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"main.go":      strings.Repeat("code ", 20),
+		"main_test.go": strings.Repeat("test ", 20),
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	config := NewConfig(WithGitClient(NewMockGitClient(false)), WithMaxTokens(40))
+	content, stats, err := processPaths([]string{dir}, config, NewLogger(false))
+	if err != nil {
+		t.Fatalf("processPaths failed: %v", err)
+	}
+
+	if strings.Contains(content, "main_test.go") || !strings.Contains(content, "main.go") {
+		t.Errorf("expected only main.go to be kept, got:\n%s", content)
+	}
+	if stats.FilesProcessed != 1 || stats.FilesTrimmed != 1 {
+		t.Errorf("FilesProcessed = %d, FilesTrimmed = %d, want 1 and 1", stats.FilesProcessed, stats.FilesTrimmed)
+	}
+	if want := []string{filepath.Join(dir, "main.go")}; !reflect.DeepEqual(stats.IncludedFiles, want) {
+		t.Errorf("IncludedFiles = %v, want %v", stats.IncludedFiles, want)
+	}
+	if stats.Tokens > 40 {
+		t.Errorf("stats.Tokens = %d, want at most 40", stats.Tokens)
+	}
+	if _, _, tokens := CalculateStatistics(content); tokens != stats.Tokens {
+		t.Errorf("stats.Tokens = %d, want %d", stats.Tokens, tokens)
+	}
+}
+
+// TestTrimStrategies tests shortening files instead of dropping them
+func TestTrimStrategies(t *testing.T) {
+	var body strings.Builder
+	body.WriteString("package demo\n\n")
+	for i := 0; i < 10; i++ {
+		body.WriteString("func F() {\n\treturn one two three four five six seven eight\n}\n")
+	}
+	formatter := NewTemplateFormatter("{content}\n")
+
+	newFile := func(path, content string) formattedFile {
+		f := formattedFile{path: path, content: []byte(content)}
+		f.output = formatter.FormatFile(FileInfo{Path: path}, f.content)
+		f.stats.add(f.output)
+		return f
+	}
+	files := []formattedFile{
+		newFile("keep.go", "package keep\n"),
+		newFile("big.go", body.String()),
+	}
+
+	t.Run("Tail truncate keeps head", func(t *testing.T) {
+		kept, dropped := trimToBudget(files, 50, []string{TrimLargest}, TrimTailTruncate, formatter)
+		if len(dropped) != 0 || len(kept) != 2 {
+			t.Fatalf("kept %v, dropped %v; want both kept", filePaths(kept), filePaths(dropped))
+		}
+		big := kept[1]
+		if !big.trimmed || !strings.HasPrefix(big.output, "package demo\n") || !strings.Contains(big.output, "... (truncated") {
+			t.Errorf("expected truncated head of big.go, got:\n%s", big.output)
+		}
+		if total := kept[0].stats.tokens + big.stats.tokens; total > 50 {
+			t.Errorf("total tokens = %d, want at most 50", total)
+		}
+	})
+
+	t.Run("Outline keeps declarations", func(t *testing.T) {
+		kept, dropped := trimToBudget(files, 50, []string{TrimLargest}, TrimOutline, formatter)
+		if len(dropped) != 0 || len(kept) != 2 {
+			t.Fatalf("kept %v, dropped %v; want both kept", filePaths(kept), filePaths(dropped))
+		}
+		big := kept[1]
+		if !big.trimmed || strings.Contains(big.output, "return") || !strings.Contains(big.output, "func F()") {
+			t.Errorf("expected outline of big.go, got:\n%s", big.output)
+		}
+	})
+
+	t.Run("Outline falls back to dropping", func(t *testing.T) {
+		kept, dropped := trimToBudget(files, 5, []string{TrimLargest}, TrimOutline, formatter)
+		if !equalSlices(filePaths(kept), []string{"keep.go"}) || !equalSlices(filePaths(dropped), []string{"big.go"}) {
+			t.Errorf("kept %v, dropped %v; want keep.go kept and big.go dropped", filePaths(kept), filePaths(dropped))
+		}
+	})
+}
+
+// TestOutlineContent tests extracting declarations and headings
+func TestOutlineContent(t *testing.T) {
+	content := "# Title\n\nSome prose.\n\n## Usage\n\nexport async function run() {\n  return 1\n}\nclass Widget:\n    def draw(self):\n        pass\n"
+	want := "# Title\n## Usage\nexport async function run()\nclass Widget:\n    def draw(self):\n... (outline: 5 of 12 lines shown)"
+	if got := string(outlineContent([]byte(content))); got != want {
+		t.Errorf("outlineContent() = %q, want %q", got, want)
+	}
+
+	if got := outlineContent([]byte("just prose\n")); got != nil {
+		t.Errorf("outlineContent() = %q, want nil for content without declarations", got)
+	}
+}
+
+// TestParseTrimStrategy tests parsing trim strategy names
+func TestParseTrimStrategy(t *testing.T) {
+	for _, name := range []string{"drop", "tail-truncate", "outline"} {
+		if got, err := ParseTrimStrategy(name); err != nil || string(got) != name {
+			t.Errorf("ParseTrimStrategy(%q) = %q, %v", name, got, err)
+		}
+	}
+	if _, err := ParseTrimStrategy("shrink"); err == nil {
+		t.Error("ParseTrimStrategy(\"shrink\") succeeded, want error")
+	}
+}
@@ -0,0 +1,100 @@
+package handoff
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ErrNoChanges is returned by ProcessChanges when no files changed
+var ErrNoChanges = errors.New("no changed files")
+
+// changeSet is a directory and base revision whose changes are processed
+type changeSet struct {
+	dir  string
+	base string
+}
+
+// WithIncludeDiff sets whether ProcessChanges appends the unified diff of the
+// changes in a git-diff section, after the changed files' full content.
+func WithIncludeDiff(include bool) Option {
+	return func(c *Config) {
+		c.IncludeDiff = include
+	}
+}
+
+// ChangedPaths returns the files in dir that changed relative to base, which
+// is a revision or StagedBase as for GitClient.ChangedFiles: tracked files
+// that differ, except deleted ones, plus untracked files that aren't ignored
+// unless base is StagedBase or the client doesn't implement UntrackedLister.
+// Paths are joined with dir and sorted.
+func ChangedPaths(dir, base string, config *Config) ([]string, error) {
+	if config == nil {
+		config = NewConfig()
+	}
+	if !config.GitClient.IsAvailable() {
+		return nil, fmt.Errorf("git not available")
+	}
+
+	paths, err := config.GitClient.ChangedFiles(dir, base)
+	if err != nil {
+		return nil, err
+	}
+	if lister, ok := config.GitClient.(UntrackedLister); ok && base != StagedBase {
+		untracked, err := lister.UntrackedFiles(dir)
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, untracked...)
+	}
+	slices.Sort(paths)
+	return slices.Compact(paths), nil
+}
+
+// ProcessChanges collects and formats the files in dir that changed relative
+// to base, as ProcessProject does for paths, for handing off work in progress.
+// An empty base selects uncommitted changes relative to HEAD; see ChangedPaths
+// for the files included. With WithIncludeDiff, the unified diff follows the
+// files in a git-diff section. It returns ErrNoChanges when nothing changed.
+// A relative dir is resolved against the root set by WithRoot.
+func ProcessChanges(dir, base string, config *Config) (string, Stats, error) {
+	if config == nil {
+		config = NewConfig()
+	}
+	config = config.Clone()
+	config.ProcessConfig()
+	logger := NewLogger(config.Verbose)
+
+	dir = config.resolvePath(dir)
+	paths, err := ChangedPaths(dir, base, config)
+	if err != nil {
+		return "", Stats{}, err
+	}
+	if len(paths) == 0 {
+		return "", Stats{}, ErrNoChanges
+	}
+	logger.Verbose("Found %d changed files in %s", len(paths), dir)
+
+	// The paths are resolved against the root again when they are processed
+	for i, path := range paths {
+		paths[i] = config.rootRelative(path)
+	}
+
+	config.changes = &changeSet{dir: dir, base: base}
+	return processProject(paths, config, logger)
+}
+
+// gitDiffSection returns the diff of the changes being processed, or an empty
+// string when the section is disabled or unavailable (internal helper)
+func gitDiffSection(config *Config, logger *Logger) string {
+	if !config.IncludeDiff || config.changes == nil {
+		return ""
+	}
+	diff, err := config.GitClient.Diff(config.changes.dir, config.changes.base)
+	if err != nil {
+		logger.Warn("cannot read git diff: %v", err)
+		return ""
+	}
+	return strings.TrimSpace(diff)
+}
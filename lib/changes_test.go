@@ -0,0 +1,92 @@
+package handoff
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestChangedPaths tests combining changed and untracked files
+func TestChangedPaths(t *testing.T) {
+	gitClient := NewMockGitClient(true)
+	gitClient.SetChangedFiles("repo", "", []string{"repo/main.go", "repo/lib/util.go"})
+	gitClient.SetChangedFiles("repo", StagedBase, []string{"repo/main.go"})
+	gitClient.SetUntrackedFiles("repo", []string{"repo/new.go", "repo/main.go"})
+	config := NewConfig(WithGitClient(gitClient))
+
+	paths, err := ChangedPaths("repo", "", config)
+	if err != nil {
+		t.Fatalf("ChangedPaths failed: %v", err)
+	}
+	if want := []string{"repo/lib/util.go", "repo/main.go", "repo/new.go"}; !reflect.DeepEqual(paths, want) {
+		t.Errorf("ChangedPaths() = %v, want %v", paths, want)
+	}
+
+	paths, err = ChangedPaths("repo", StagedBase, config)
+	if err != nil {
+		t.Fatalf("ChangedPaths failed: %v", err)
+	}
+	if want := []string{"repo/main.go"}; !reflect.DeepEqual(paths, want) {
+		t.Errorf("ChangedPaths(staged) = %v, want %v without untracked files", paths, want)
+	}
+
+	// A client without UntrackedLister reports only tracked changes
+	paths, err = ChangedPaths("repo", "", NewConfig(WithGitClient(basicGitClient{gitClient})))
+	if err != nil {
+		t.Fatalf("ChangedPaths failed: %v", err)
+	}
+	if want := []string{"repo/lib/util.go", "repo/main.go"}; !reflect.DeepEqual(paths, want) {
+		t.Errorf("ChangedPaths() without UntrackedLister = %v, want %v", paths, want)
+	}
+
+	if _, err := ChangedPaths("repo", "", NewConfig(WithGitClient(NewMockGitClient(false)))); err == nil {
+		t.Error("expected error without git")
+	}
+}
+
+// TestProcessChanges tests collecting changed files with and without their diff
+func TestProcessChanges(t *testing.T) {
+	dir := t.TempDir()
+	changed := filepath.Join(dir, "main.go")
+	untracked := filepath.Join(dir, "notes.md")
+	if err := os.WriteFile(changed, []byte("package main\n"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	if err := os.WriteFile(untracked, []byte("# Notes\n"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	gitClient := NewMockGitClient(true)
+	gitClient.SetChangedFiles(dir, "main", []string{changed})
+	gitClient.SetUntrackedFiles(dir, []string{untracked})
+	gitClient.SetDiff(dir, "main", "diff --git a/main.go b/main.go\n+package main\n")
+
+	content, stats, err := ProcessChanges(dir, "main", NewConfig(WithGitClient(gitClient)))
+	if err != nil {
+		t.Fatalf("ProcessChanges failed: %v", err)
+	}
+	if stats.FilesProcessed != 2 {
+		t.Errorf("FilesProcessed = %d, want 2", stats.FilesProcessed)
+	}
+	if !strings.Contains(content, "package main") || !strings.Contains(content, "# Notes") {
+		t.Errorf("expected changed and untracked files, got:\n%s", content)
+	}
+	if strings.Contains(content, "<git-diff>") {
+		t.Errorf("expected no diff section without WithIncludeDiff, got:\n%s", content)
+	}
+
+	content, _, err = ProcessChanges(dir, "main", NewConfig(WithGitClient(gitClient), WithIncludeDiff(true)))
+	if err != nil {
+		t.Fatalf("ProcessChanges failed: %v", err)
+	}
+	if !strings.Contains(content, "<git-diff>\ndiff --git a/main.go b/main.go\n+package main\n</git-diff>") {
+		t.Errorf("expected diff section, got:\n%s", content)
+	}
+
+	if _, _, err := ProcessChanges(dir, "HEAD~1", NewConfig(WithGitClient(NewMockGitClient(true)))); !errors.Is(err, ErrNoChanges) {
+		t.Errorf("ProcessChanges() without changes error = %v, want ErrNoChanges", err)
+	}
+}
@@ -0,0 +1,118 @@
+package handoff
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// WithResume records each processed file in a checkpoint file at path and,
+// on later runs, reuses the recorded content of files that are unchanged since
+// they were recorded instead of reading them again. This lets an interrupted
+// run over a very large repository continue from the last completed file.
+// Recorded content is filtered and transformed again, so a resumed run applies
+// its own settings; the caller removes the checkpoint once the output has been
+// saved. An empty path disables checkpointing.
+func WithResume(path string) Option {
+	return func(c *Config) {
+		c.ResumeFile = path
+	}
+}
+
+// checkpointEntry records a processed file in the checkpoint (internal helper)
+type checkpointEntry struct {
+	Path    string `json:"path"`
+	Size    int64  `json:"size"`
+	ModTime int64  `json:"modTime"`
+
+	// Content is the file content as read, before filtering and transformations
+	Content []byte `json:"content"`
+}
+
+// matches reports whether the entry still describes the file (internal helper)
+func (e checkpointEntry) matches(info os.FileInfo) bool {
+	return info != nil && e.Size == info.Size() && e.ModTime == info.ModTime().UnixNano()
+}
+
+// checkpoint reuses and records processed files for an interruptible run (internal helper)
+type checkpoint struct {
+	entries map[string]checkpointEntry
+	file    *os.File
+	encoder *json.Encoder
+}
+
+// openCheckpoint loads the entries already recorded at path and opens it for
+// appending new ones. A line cut short by an interrupted write is ignored, so
+// the file it describes is processed again (internal helper).
+func openCheckpoint(path string) (*checkpoint, error) {
+	cp := &checkpoint{entries: make(map[string]checkpointEntry)}
+
+	if existing, err := os.Open(path); err == nil {
+		scanner := bufio.NewScanner(existing)
+		scanner.Buffer(make([]byte, 0, 64*1024), 1<<30)
+		for scanner.Scan() {
+			var entry checkpointEntry
+			if json.Unmarshal(scanner.Bytes(), &entry) == nil && entry.Path != "" {
+				cp.entries[entry.Path] = entry
+			}
+		}
+		scanErr := scanner.Err()
+		_ = existing.Close()
+		if scanErr != nil {
+			return nil, fmt.Errorf("failed to read checkpoint %q: %w", path, scanErr)
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to open checkpoint %q: %w", path, err)
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open checkpoint %q: %w", path, err)
+	}
+	// Start on a fresh line in case the last write was interrupted
+	if _, err := file.WriteString("\n"); err != nil {
+		_ = file.Close()
+		return nil, fmt.Errorf("failed to write checkpoint %q: %w", path, err)
+	}
+	cp.file = file
+	cp.encoder = json.NewEncoder(file)
+	return cp, nil
+}
+
+// lookupFile returns the entry recorded for a discovered file that is unchanged
+// since it was recorded. A nil checkpoint has no entries.
+func (cp *checkpoint) lookupFile(file discoveredFile) (checkpointEntry, bool) {
+	if cp == nil {
+		return checkpointEntry{}, false
+	}
+	entry, ok := cp.entries[file.path]
+	if !ok || !entry.matches(file.info) {
+		return checkpointEntry{}, false
+	}
+	return entry, true
+}
+
+// record appends a processed file and the content read from it to the
+// checkpoint. Binary dumps aren't recorded, since their content isn't text.
+func (cp *checkpoint) record(path string, info os.FileInfo, meta fileMeta) error {
+	if info == nil || meta.read == nil {
+		return nil
+	}
+	entry := checkpointEntry{
+		Path:    path,
+		Size:    info.Size(),
+		ModTime: info.ModTime().UnixNano(),
+		Content: meta.read,
+	}
+	cp.entries[path] = entry
+	if err := cp.encoder.Encode(entry); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	return nil
+}
+
+// Close closes the checkpoint file
+func (cp *checkpoint) Close() error {
+	return cp.file.Close()
+}
@@ -0,0 +1,83 @@
+package handoff
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestResume tests reusing unchanged files recorded by an interrupted run
+func TestResume(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{"a.txt": "alpha\n", "b.txt": "beta\n"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+	checkpointPath := filepath.Join(t.TempDir(), "out.md.resume")
+	config := NewConfig(WithGitClient(NewMockGitClient(false)), WithResume(checkpointPath))
+
+	first, _, err := ProcessProject([]string{dir}, config)
+	if err != nil {
+		t.Fatalf("ProcessProject failed: %v", err)
+	}
+
+	// Simulate an interrupted write at the end of the checkpoint
+	f, err := os.OpenFile(checkpointPath, os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		t.Fatalf("Failed to open checkpoint: %v", err)
+	}
+	_, _ = f.WriteString(`{"path":"` + filepath.Join(dir, "c.txt") + `","si`)
+	_ = f.Close()
+
+	cp, err := openCheckpoint(checkpointPath)
+	if err != nil {
+		t.Fatalf("openCheckpoint failed: %v", err)
+	}
+	_ = cp.Close()
+	if len(cp.entries) != 2 {
+		t.Fatalf("checkpoint has %d entries, want 2", len(cp.entries))
+	}
+
+	// Rewrite a.txt with the same size and modification time: a resumed run
+	// trusts the checkpoint and uses the recorded content
+	aPath := filepath.Join(dir, "a.txt")
+	info, _ := os.Stat(aPath)
+	if err := os.WriteFile(aPath, []byte("ALPHA\n"), 0644); err != nil {
+		t.Fatalf("Failed to write: %v", err)
+	}
+	if err := os.Chtimes(aPath, info.ModTime(), info.ModTime()); err != nil {
+		t.Fatalf("Failed to set times: %v", err)
+	}
+
+	resumed, _, err := ProcessProject([]string{dir}, config)
+	if err != nil {
+		t.Fatalf("resumed ProcessProject failed: %v", err)
+	}
+	if resumed != first {
+		t.Errorf("resumed output differs from the original:\n%s\nwant:\n%s", resumed, first)
+	}
+
+	// Recorded content is filtered again under the resumed run's settings
+	grepConfig := NewConfig(WithGitClient(NewMockGitClient(false)), WithResume(checkpointPath), WithGrep(regexp.MustCompile("beta"), 0))
+	grepped, _, err := ProcessProject([]string{dir}, grepConfig)
+	if err != nil {
+		t.Fatalf("resumed ProcessProject with grep failed: %v", err)
+	}
+	if strings.Contains(grepped, "alpha") || !strings.Contains(grepped, "beta") {
+		t.Errorf("resumed run didn't apply its grep filter:\n%s", grepped)
+	}
+
+	// Changed files are read again
+	if err := os.WriteFile(aPath, []byte("alpha changed\n"), 0644); err != nil {
+		t.Fatalf("Failed to write: %v", err)
+	}
+	_ = os.Chtimes(aPath, time.Now(), info.ModTime().Add(time.Second))
+	changed, _, err := ProcessProject([]string{dir}, config)
+	if err != nil || !strings.Contains(changed, "alpha changed") {
+		t.Errorf("changed file was not re-read: %v\n%s", err, changed)
+	}
+}
@@ -0,0 +1,264 @@
+package handoff
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// WithChunkTokens sets the estimated token limit for each part produced by
+// ProcessProjectChunks. Zero or less keeps the output in a single part.
+func WithChunkTokens(tokens int) Option {
+	return func(c *Config) {
+		c.ChunkTokens = tokens
+	}
+}
+
+// WithChunkOverlap repeats up to the last n lines of each part at the start of
+// the next, so parts keep some local context when embedded or read
+// independently. Zero or less disables the overlap.
+func WithChunkOverlap(lines int) Option {
+	return func(c *Config) {
+		c.ChunkOverlap = lines
+	}
+}
+
+// ProcessProjectChunks is like ProcessProject but splits the output into parts
+// of at most Config.ChunkTokens estimated tokens, each wrapped by the formatter.
+// Files are packed first-fit-decreasing to minimize the number of parts, keeping
+// files from the same directory in the same part where they fit. A file larger
+// than the limit gets a part of its own. Within a part, files keep their
+// discovery order and supplementary sections come last. With Config.ChunkOverlap,
+// each part after the first starts with a "previous-part" section repeating the
+// end of the part before it; fewer lines are repeated when a part has no room.
+// When the output is split, every part begins with a "part" section giving its
+// number, the overall token count, and the files it contains.
+func ProcessProjectChunks(paths []string, config *Config) ([]string, Stats, error) {
+	if config == nil {
+		config = NewConfig()
+	}
+	config = config.Clone()
+	config.ProcessConfig()
+
+	logger := NewLogger(config.Verbose)
+
+	if len(paths) == 0 {
+		return nil, Stats{}, fmt.Errorf("no paths provided")
+	}
+
+	result, err := assemblePaths(paths, config, logger)
+	if err != nil {
+		return nil, Stats{}, err
+	}
+
+	formatter := config.formatter()
+	if config.ChunkTokens <= 0 {
+		if err := checkContextWindow(result.stats.Tokens, config, logger); err != nil {
+			return nil, result.stats, err
+		}
+		return []string{wrapOutput(result.content(), result.stats, config)}, result.stats, nil
+	}
+
+	// Leave room in each part for the formatter's wrapper, and for the overlap
+	// based on the average tokens per line
+	limit := config.ChunkTokens - estimateTokenCount(formatter.Wrap(""))
+	packLimit := limit
+	if config.ChunkOverlap > 0 && result.stats.Lines > 0 {
+		reserve := result.stats.Tokens * config.ChunkOverlap / result.stats.Lines
+		packLimit -= min(reserve, limit/4)
+	}
+
+	// Each part's header has a fixed portion, sized for a part that is not the
+	// last, plus a line per file, which is counted with the file's tokens
+	maxParts := len(result.files) + len(result.sections)
+	packLimit -= estimateTokenCount(formatSection(formatter, "part", partHeader(1, maxParts, result.stats.Tokens, config.ChunkTokens, nil)))
+
+	items := make([]chunkItem, 0, len(result.files)+len(result.sections))
+	for _, file := range result.files {
+		tokens := file.stats.tokens + estimateTokenCount(file.heading) + estimateTokenCount(partHeaderLine(file.path))
+		items = append(items, chunkItem{dir: filepath.Dir(file.path), path: file.path, output: file.heading + file.output, tokens: tokens})
+	}
+	for _, section := range result.sections {
+		// Sections have no directory, so each forms a group of its own
+		items = append(items, chunkItem{output: section, tokens: estimateTokenCount(section)})
+	}
+	for i := range items {
+		items[i].order = i
+	}
+
+	chunks := packChunks(items, packLimit)
+
+	var parts []string
+	var previous string
+	largest := 0
+	for i, chunk := range chunks {
+		var b strings.Builder
+		var files []string
+		for _, item := range chunk {
+			b.WriteString(item.output)
+			if item.path != "" {
+				files = append(files, item.path)
+			}
+		}
+		content := b.String()
+
+		body := content
+		if len(chunks) > 1 {
+			header := formatSection(formatter, "part", partHeader(i+1, len(chunks), result.stats.Tokens, config.ChunkTokens, files))
+			if previous != "" && config.ChunkOverlap > 0 {
+				room := limit - estimateTokenCount(header) - estimateTokenCount(content)
+				body = chunkOverlap(previous, config.ChunkOverlap, room, formatter) + body
+			}
+			body = header + body
+		}
+		previous = content
+
+		part := formatter.Wrap(body)
+		largest = max(largest, estimateTokenCount(part))
+		parts = append(parts, part)
+	}
+	logger.Verbose("Packed %d files into %d parts of up to %d tokens", len(result.files), len(parts), config.ChunkTokens)
+
+	// Each part is sent on its own, so only the largest must fit the model
+	if err := checkContextWindow(largest, config, logger); err != nil {
+		return nil, result.stats, err
+	}
+
+	return parts, result.stats, nil
+}
+
+// partHeader describes part n of total for the reader: where it fits in the
+// whole, whether more parts follow, and the files it contains (internal helper)
+func partHeader(n, total, totalTokens, chunkTokens int, files []string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Part %d of %d. The full context is ~%d tokens, split into parts of at most %d tokens.\n", n, total, totalTokens, chunkTokens)
+	if n < total {
+		b.WriteString("More parts follow; wait until you have received all of them before responding.\n")
+	} else {
+		b.WriteString("This is the last part.\n")
+	}
+	if len(files) > 0 {
+		b.WriteString("Files in this part:\n")
+		for _, file := range files {
+			b.WriteString(partHeaderLine(file))
+		}
+	}
+	return b.String()
+}
+
+// partHeaderLine is a part header's entry for one file (internal helper)
+func partHeaderLine(path string) string {
+	return "- " + path + "\n"
+}
+
+// chunkOverlap renders up to the last n lines of a part's body as a
+// "previous-part" section of at most room tokens, dropping the earliest lines
+// until it fits. It returns an empty string when not even one line fits
+// (internal helper).
+func chunkOverlap(body string, n, room int, formatter Formatter) string {
+	lines := strings.Split(strings.TrimRight(body, "\n"), "\n")
+	for keep := min(n, len(lines)); keep > 0; keep-- {
+		section := formatSection(formatter, "previous-part", strings.Join(lines[len(lines)-keep:], "\n"))
+		if !exceedsTokens(section, room) {
+			return section
+		}
+	}
+	return ""
+}
+
+// chunkItem is a formatted file or section to be packed into a part (internal helper)
+type chunkItem struct {
+	// dir groups items that should stay together; empty for sections
+	dir string
+
+	// path is the file's path, listed in the part header; empty for sections
+	path string
+
+	output string
+	tokens int
+
+	// order is the item's position in the unsplit output
+	order int
+}
+
+// chunkGroup is a set of items from the same directory (internal helper)
+type chunkGroup struct {
+	items  []chunkItem
+	tokens int
+}
+
+// chunkBin is a part being filled by packChunks (internal helper)
+type chunkBin struct {
+	items  []chunkItem
+	tokens int
+}
+
+// packChunks packs items into as few parts of at most limit tokens as it can
+// using first-fit decreasing. Items from the same directory are placed as a
+// group while the group fits in a part; larger groups are split and their items
+// placed individually. Each returned part is in the items' original order
+// (internal helper).
+func packChunks(items []chunkItem, limit int) [][]chunkItem {
+	// Group items by directory, keeping the order in which directories appear
+	var groups []*chunkGroup
+	byDir := make(map[string]*chunkGroup)
+	for _, item := range items {
+		group, ok := byDir[item.dir]
+		if !ok || item.dir == "" {
+			group = &chunkGroup{}
+			groups = append(groups, group)
+			if item.dir != "" {
+				byDir[item.dir] = group
+			}
+		}
+		group.items = append(group.items, item)
+		group.tokens += item.tokens
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		return groups[i].tokens > groups[j].tokens
+	})
+
+	var bins []*chunkBin
+	place := func(items []chunkItem, tokens int) {
+		for _, bin := range bins {
+			if bin.tokens+tokens <= limit {
+				bin.items = append(bin.items, items...)
+				bin.tokens += tokens
+				return
+			}
+		}
+		bins = append(bins, &chunkBin{items: append([]chunkItem(nil), items...), tokens: tokens})
+	}
+
+	for _, group := range groups {
+		if group.tokens <= limit {
+			place(group.items, group.tokens)
+			continue
+		}
+
+		// The directory cannot fit in one part, so place its files individually
+		sort.SliceStable(group.items, func(i, j int) bool {
+			return group.items[i].tokens > group.items[j].tokens
+		})
+		for _, item := range group.items {
+			place([]chunkItem{item}, item.tokens)
+		}
+	}
+
+	chunks := make([][]chunkItem, 0, len(bins))
+	for _, bin := range bins {
+		sort.Slice(bin.items, func(i, j int) bool {
+			return bin.items[i].order < bin.items[j].order
+		})
+		chunks = append(chunks, bin.items)
+	}
+
+	// Order the parts by their first item so reading them in sequence follows the
+	// original output as closely as possible
+	sort.SliceStable(chunks, func(i, j int) bool {
+		return chunks[i][0].order < chunks[j][0].order
+	})
+	return chunks
+}
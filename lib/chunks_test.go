@@ -0,0 +1,227 @@
+package handoff
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// chunkOutputs returns the outputs of packed items for comparison
+func chunkOutputs(chunks [][]chunkItem) [][]string {
+	var result [][]string
+	for _, chunk := range chunks {
+		var outputs []string
+		for _, item := range chunk {
+			outputs = append(outputs, item.output)
+		}
+		result = append(result, outputs)
+	}
+	return result
+}
+
+// newChunkItems builds chunk items in order from "dir/name:tokens" specs
+func newChunkItems(specs ...string) []chunkItem {
+	var items []chunkItem
+	for i, spec := range specs {
+		name, tokens, _ := strings.Cut(spec, ":")
+		n := 0
+		for _, r := range tokens {
+			n = n*10 + int(r-'0')
+		}
+		dir := filepath.Dir(name)
+		if !strings.Contains(name, "/") {
+			dir = ""
+		}
+		items = append(items, chunkItem{dir: dir, output: name, tokens: n, order: i})
+	}
+	return items
+}
+
+// TestPackChunks tests first-fit-decreasing packing with directory grouping
+func TestPackChunks(t *testing.T) {
+	testCases := []struct {
+		name  string
+		items []chunkItem
+		limit int
+		want  [][]string
+	}{
+		{
+			name:  "Fewer parts than sequential filling",
+			items: newChunkItems("a/1:6", "b/1:5", "c/1:4", "d/1:5"),
+			limit: 10,
+			// Sequential filling would need three parts: [6] [5 4] [5]
+			want: [][]string{{"a/1", "c/1"}, {"b/1", "d/1"}},
+		},
+		{
+			name:  "Same-directory files stay together",
+			items: newChunkItems("a/1:3", "b/1:3", "a/2:3", "b/2:3"),
+			limit: 6,
+			want:  [][]string{{"a/1", "a/2"}, {"b/1", "b/2"}},
+		},
+		{
+			name:  "Directories larger than a part are split",
+			items: newChunkItems("a/1:4", "a/2:4", "a/3:4"),
+			limit: 8,
+			want:  [][]string{{"a/1", "a/2"}, {"a/3"}},
+		},
+		{
+			name:  "Oversized items get their own part",
+			items: newChunkItems("a/1:2", "a/big:20", "b/1:2"),
+			limit: 10,
+			want:  [][]string{{"a/1", "b/1"}, {"a/big"}},
+		},
+		{
+			name:  "Sections are packed individually",
+			items: newChunkItems("a/1:5", "git-log:3", "notes:3"),
+			limit: 8,
+			want:  [][]string{{"a/1", "git-log"}, {"notes"}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := chunkOutputs(packChunks(tc.items, tc.limit))
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("packChunks() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+// TestProcessProjectChunks tests splitting output into wrapped parts
+func TestProcessProjectChunks(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"a/one.txt", "a/two.txt", "b/three.txt"} {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(strings.Repeat("word ", 400)), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	config := NewConfig(WithGitClient(NewMockGitClient(false)))
+	single, _, err := ProcessProject([]string{dir}, config)
+	if err != nil {
+		t.Fatalf("ProcessProject failed: %v", err)
+	}
+
+	parts, stats, err := ProcessProjectChunks([]string{dir}, config)
+	if err != nil || len(parts) != 1 || parts[0] != single {
+		t.Fatalf("without ChunkTokens, got %d parts, %v; want the ProcessProject output", len(parts), err)
+	}
+
+	config = NewConfig(WithGitClient(NewMockGitClient(false)), WithChunkTokens(stats.Tokens*3/4))
+	parts, _, err = ProcessProjectChunks([]string{dir}, config)
+	if err != nil {
+		t.Fatalf("ProcessProjectChunks failed: %v", err)
+	}
+	if len(parts) != 2 {
+		t.Fatalf("got %d parts, want 2", len(parts))
+	}
+	for i, part := range parts {
+		if !strings.HasPrefix(part, "<context>") {
+			t.Errorf("part %d is not wrapped: %q", i+1, part)
+		}
+		if tokens := estimateTokenCount(part); tokens > config.ChunkTokens {
+			t.Errorf("part %d has %d tokens, over the %d limit", i+1, tokens, config.ChunkTokens)
+		}
+	}
+	if !strings.Contains(parts[0], "one.txt") || !strings.Contains(parts[0], "two.txt") || !strings.Contains(parts[1], "three.txt") {
+		t.Errorf("files from the same directory should share a part:\n%v", parts)
+	}
+
+	// Each part starts with a header numbering it and listing its files
+	wantHeader := "<context>\n<part>\nPart 2 of 2. The full context is ~" + fmt.Sprint(stats.Tokens) + " tokens"
+	if !strings.HasPrefix(parts[1], wantHeader) {
+		t.Errorf("second part should start with %q:\n%s", wantHeader, parts[1])
+	}
+	header, _, _ := strings.Cut(parts[0], "</part>")
+	if !strings.Contains(header, "More parts follow") || !strings.Contains(header, "- "+filepath.Join(dir, "a", "one.txt")+"\n") {
+		t.Errorf("first part header = %q, want a notice and its files", header)
+	}
+	if strings.Contains(header, "three.txt") {
+		t.Errorf("first part header lists a file from another part: %q", header)
+	}
+}
+
+// TestPartHeader tests the header text for a part
+func TestPartHeader(t *testing.T) {
+	got := partHeader(3, 3, 5000, 2000, []string{"a.go", "b/c.go"})
+	want := "Part 3 of 3. The full context is ~5000 tokens, split into parts of at most 2000 tokens.\n" +
+		"This is the last part.\n" +
+		"Files in this part:\n- a.go\n- b/c.go\n"
+	if got != want {
+		t.Errorf("partHeader() = %q, want %q", got, want)
+	}
+}
+
+// TestChunkOverlap tests repeating the end of a part within the available room
+func TestChunkOverlap(t *testing.T) {
+	formatter := NewTemplateFormatter(DefaultFormat)
+	body := "line one\nline two\nline three\n"
+
+	got := chunkOverlap(body, 2, 1000, formatter)
+	if got != "<previous-part>\nline two\nline three\n</previous-part>\n\n" {
+		t.Errorf("chunkOverlap() = %q", got)
+	}
+
+	if got := chunkOverlap(body, 10, 1000, formatter); !strings.Contains(got, "line one") {
+		t.Errorf("chunkOverlap() with more lines than the body = %q, want every line", got)
+	}
+
+	// With little room, earlier lines are dropped first
+	room := estimateTokenCount(formatSection(formatter, "previous-part", "line three"))
+	if got := chunkOverlap(body, 3, room, formatter); strings.Contains(got, "line two") || !strings.Contains(got, "line three") {
+		t.Errorf("chunkOverlap() with room for one line = %q", got)
+	}
+
+	if got := chunkOverlap(body, 3, 1, formatter); got != "" {
+		t.Errorf("chunkOverlap() without room = %q, want empty", got)
+	}
+}
+
+// TestProcessProjectChunksOverlap tests that parts repeat the end of the previous part
+func TestProcessProjectChunksOverlap(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"a/one.txt", "b/two.txt"} {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		content := strings.Repeat("filler words here\n", 100) + "last line of " + filepath.Base(name) + "\n"
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	_, stats, err := ProcessProjectChunks([]string{dir}, NewConfig(WithGitClient(NewMockGitClient(false))))
+	if err != nil {
+		t.Fatalf("ProcessProjectChunks failed: %v", err)
+	}
+
+	config := NewConfig(WithGitClient(NewMockGitClient(false)), WithChunkTokens(stats.Tokens*3/4), WithChunkOverlap(5))
+	parts, _, err := ProcessProjectChunks([]string{dir}, config)
+	if err != nil {
+		t.Fatalf("ProcessProjectChunks failed: %v", err)
+	}
+	if len(parts) != 2 {
+		t.Fatalf("got %d parts, want 2", len(parts))
+	}
+	if strings.Contains(parts[0], "<previous-part>") {
+		t.Error("the first part should not have an overlap")
+	}
+	overlap, _, _ := strings.Cut(parts[1], "</previous-part>")
+	if !strings.Contains(overlap, "<previous-part>") || !strings.Contains(overlap, "last line of one.txt") {
+		t.Errorf("second part should start with the end of the first:\n%s", parts[1])
+	}
+	for i, part := range parts {
+		if tokens := estimateTokenCount(part); tokens > config.ChunkTokens {
+			t.Errorf("part %d has %d tokens, over the %d limit", i+1, tokens, config.ChunkTokens)
+		}
+	}
+}
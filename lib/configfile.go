@@ -0,0 +1,178 @@
+package handoff
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultConfigFileName is the name of the project configuration file written
+// by handoff init, and the first of ConfigFileNames the CLI looks for.
+const DefaultConfigFileName = ".handoff.yaml"
+
+// ConfigFileNames are the names FindConfigFile looks for, in order. The JSON
+// name predates YAML support and is still loaded.
+var ConfigFileNames = []string{DefaultConfigFileName, ".handoff.yml", ".handoff.json"}
+
+// FileConfig is the on-disk representation of handoff settings, typically stored
+// as YAML in a .handoff.yaml file at the project root, or as JSON in a
+// .handoff.json file. Its fields mirror the corresponding functional options;
+// empty fields leave defaults unchanged.
+type FileConfig struct {
+	// Include is a comma-separated list of extensions to include (see WithInclude)
+	Include string `json:"include,omitempty" yaml:"include,omitempty"`
+
+	// Exclude is a comma-separated list of extensions to exclude (see WithExclude)
+	Exclude string `json:"exclude,omitempty" yaml:"exclude,omitempty"`
+
+	// ExcludeNames is a comma-separated list of file names to exclude (see WithExcludeNames)
+	ExcludeNames string `json:"excludeNames,omitempty" yaml:"excludeNames,omitempty"`
+
+	// HiddenAllowlist is a comma-separated list of hidden names to process (see WithHiddenAllowlist)
+	HiddenAllowlist string `json:"hiddenAllowlist,omitempty" yaml:"hiddenAllowlist,omitempty"`
+
+	// PathRules are path-scoped filter overrides (see WithPathRules)
+	PathRules []PathRule `json:"pathRules,omitempty" yaml:"pathRules,omitempty"`
+
+	// ClipboardCmd is a command the CLI pipes output to instead of the built-in
+	// clipboard tools, such as "xsel --clipboard --input". It has no functional
+	// option and is ignored by Options.
+	ClipboardCmd string `json:"clipboardCmd,omitempty" yaml:"clipboardCmd,omitempty"`
+
+	// Profiles are named sets of settings that Profile applies over the others,
+	// such as a "docs" profile including only documentation. Profiles can't
+	// contain profiles.
+	Profiles map[string]*FileConfig `json:"profiles,omitempty" yaml:"profiles,omitempty"`
+}
+
+// LoadProjectConfig loads the config file at path, or when path is empty the
+// one FindConfigFile finds in root. A missing default config file is not an
+// error and yields an empty FileConfig.
+func LoadProjectConfig(path, root string) (*FileConfig, error) {
+	if path == "" {
+		defaultPath, exists, err := FindConfigFile(root)
+		if err != nil || !exists {
+			return &FileConfig{}, err
+		}
+		path = defaultPath
+	}
+	return LoadConfigFile(path)
+}
+
+// LoadConfigFile reads a FileConfig from a YAML file, or a JSON file when
+// the name ends in .json. YAML files may also hold JSON, which is valid YAML.
+// Unknown fields are rejected so that typos in setting names are reported
+// rather than silently ignored.
+func LoadConfigFile(path string) (*FileConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file %q: %w", path, err)
+	}
+
+	var fc FileConfig
+	if strings.ToLower(filepath.Ext(path)) == ".json" {
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(&fc)
+	} else {
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		if err = decoder.Decode(&fc); errors.Is(err, io.EOF) {
+			err = nil // an empty document holds no settings
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse config file %q: %w", path, err)
+	}
+	for name, profile := range fc.Profiles {
+		if profile != nil && len(profile.Profiles) > 0 {
+			return nil, fmt.Errorf("failed to parse config file %q: profile %q can't contain profiles", path, name)
+		}
+	}
+	return &fc, nil
+}
+
+// YAML encodes the settings as a YAML document that LoadConfigFile reads back.
+func (fc *FileConfig) YAML() (string, error) {
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(fc); err != nil {
+		return "", fmt.Errorf("failed to encode config: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// Profile returns the settings with the named profile's non-empty fields
+// applied over them, and without profiles. It returns an error naming the
+// available profiles when there is no such profile.
+func (fc *FileConfig) Profile(name string) (*FileConfig, error) {
+	profile, ok := fc.Profiles[name]
+	if !ok {
+		names := make([]string, 0, len(fc.Profiles))
+		for name := range fc.Profiles {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		if len(names) == 0 {
+			return nil, fmt.Errorf("unknown profile %q: the config file has no profiles", name)
+		}
+		return nil, fmt.Errorf("unknown profile %q (want %s)", name, strings.Join(names, ", "))
+	}
+
+	merged := *fc
+	merged.Profiles = nil
+	if profile == nil {
+		return &merged, nil
+	}
+	if profile.Include != "" {
+		merged.Include = profile.Include
+	}
+	if profile.Exclude != "" {
+		merged.Exclude = profile.Exclude
+	}
+	if profile.ExcludeNames != "" {
+		merged.ExcludeNames = profile.ExcludeNames
+	}
+	if profile.HiddenAllowlist != "" {
+		merged.HiddenAllowlist = profile.HiddenAllowlist
+	}
+	if len(profile.PathRules) > 0 {
+		merged.PathRules = profile.PathRules
+	}
+	if profile.ClipboardCmd != "" {
+		merged.ClipboardCmd = profile.ClipboardCmd
+	}
+	return &merged, nil
+}
+
+// Options converts the file settings into functional options.
+// Options for empty fields are omitted, so they can be combined with other
+// options (e.g., from command-line flags) applied afterwards.
+func (fc *FileConfig) Options() []Option {
+	var options []Option
+	if fc.Include != "" {
+		options = append(options, WithInclude(fc.Include))
+	}
+	if fc.Exclude != "" {
+		options = append(options, WithExclude(fc.Exclude))
+	}
+	if fc.ExcludeNames != "" {
+		options = append(options, WithExcludeNames(fc.ExcludeNames))
+	}
+	if fc.HiddenAllowlist != "" {
+		options = append(options, WithHiddenAllowlist(fc.HiddenAllowlist))
+	}
+	if len(fc.PathRules) > 0 {
+		options = append(options, WithPathRules(fc.PathRules))
+	}
+	return options
+}
@@ -0,0 +1,199 @@
+package handoff
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestLoadConfigFile tests loading settings from a JSON config file
+func TestLoadConfigFile(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "handoff-configfile-test-")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer func() {
+		if cleanErr := os.RemoveAll(tmpDir); cleanErr != nil {
+			t.Logf("Failed to clean up temp directory: %v", cleanErr)
+		}
+	}()
+
+	testCases := []struct {
+		name    string
+		content string
+		wantErr bool
+		check   func(t *testing.T, config *Config)
+	}{
+		{
+			name: "Valid config",
+			content: `{
+				"include": ".go,.md",
+				"excludeNames": "go.sum",
+				"pathRules": [{"path": "docs"}, {"path": "src", "include": [".go"]}]
+			}`,
+			check: func(t *testing.T, config *Config) {
+				if !equalSlices(config.includeExts, []string{".go", ".md"}) {
+					t.Errorf("includeExts = %v, want [.go .md]", config.includeExts)
+				}
+				if !equalSlices(config.excludeNames, []string{"go.sum"}) {
+					t.Errorf("excludeNames = %v, want [go.sum]", config.excludeNames)
+				}
+				if len(config.pathRules) != 2 || config.pathRules[1].Path != "src" {
+					t.Errorf("pathRules = %v, want docs and src rules", config.pathRules)
+				}
+			},
+		},
+		{
+			name:    "Empty object",
+			content: `{}`,
+			check: func(t *testing.T, config *Config) {
+				if config.includeExts != nil || config.pathRules != nil {
+					t.Errorf("empty config file should not set filters, got %v %v", config.includeExts, config.pathRules)
+				}
+			},
+		},
+		{
+			name:    "Unknown field",
+			content: `{"includes": ".go"}`,
+			wantErr: true,
+		},
+		{
+			name:    "Invalid JSON",
+			content: `{"include": `,
+			wantErr: true,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(tmpDir, "config"+string(rune('a'+i))+".json")
+			if err := os.WriteFile(path, []byte(tc.content), 0644); err != nil {
+				t.Fatalf("Failed to write config file: %v", err)
+			}
+
+			fc, err := LoadConfigFile(path)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("LoadConfigFile() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if tc.wantErr {
+				return
+			}
+			tc.check(t, NewConfig(fc.Options()...))
+		})
+	}
+
+	if _, err := LoadConfigFile(filepath.Join(tmpDir, "missing.json")); err == nil {
+		t.Error("LoadConfigFile() for missing file should return an error")
+	}
+}
+
+// TestLoadConfigFileYAML tests loading settings and profiles from a YAML config file
+func TestLoadConfigFileYAML(t *testing.T) {
+	path := filepath.Join(t.TempDir(), DefaultConfigFileName)
+	content := `# project settings
+include: .go,.md
+excludeNames: "*.pb.go,go.sum"
+pathRules:
+  - path: docs
+  - path: src
+    include: [.go]
+profiles:
+  docs:
+    include: .md   # documentation only
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+
+	fc, err := LoadConfigFile(path)
+	if err != nil {
+		t.Fatalf("LoadConfigFile() failed: %v", err)
+	}
+	want := &FileConfig{
+		Include:      ".go,.md",
+		ExcludeNames: "*.pb.go,go.sum",
+		PathRules:    []PathRule{{Path: "docs"}, {Path: "src", Include: []string{".go"}}},
+		Profiles:     map[string]*FileConfig{"docs": {Include: ".md"}},
+	}
+	if !reflect.DeepEqual(fc, want) {
+		t.Errorf("LoadConfigFile() = %+v, want %+v", fc, want)
+	}
+
+	// The encoded settings read back the same
+	encoded, err := want.YAML()
+	if err != nil {
+		t.Fatalf("YAML() failed: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(encoded), 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+	if fc, err := LoadConfigFile(path); err != nil || !reflect.DeepEqual(fc, want) {
+		t.Errorf("LoadConfigFile() of YAML() = %+v, %v; want %+v", fc, err, want)
+	}
+
+	for name, content := range map[string]string{
+		"Unknown field":   "includes: .go\n",
+		"Nested profiles": "profiles:\n  a:\n    profiles:\n      b: {}\n",
+		"Bad indentation": "include: .go\n  exclude: .md\n",
+	} {
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write config file: %v", err)
+		}
+		if _, err := LoadConfigFile(path); err == nil {
+			t.Errorf("%s: LoadConfigFile() succeeded, want error", name)
+		}
+	}
+}
+
+// TestFileConfigProfile tests applying a profile over the other settings
+func TestFileConfigProfile(t *testing.T) {
+	fc := &FileConfig{
+		Exclude:      ".svg",
+		ExcludeNames: "go.sum",
+		Profiles: map[string]*FileConfig{
+			"docs": {Include: ".md"},
+			"code": {ExcludeNames: "*_test.go,go.sum"},
+		},
+	}
+
+	docs, err := fc.Profile("docs")
+	if err != nil {
+		t.Fatalf("Profile(docs) failed: %v", err)
+	}
+	if want := (&FileConfig{Include: ".md", Exclude: ".svg", ExcludeNames: "go.sum"}); !reflect.DeepEqual(docs, want) {
+		t.Errorf("Profile(docs) = %+v, want %+v", docs, want)
+	}
+	code, err := fc.Profile("code")
+	if err != nil || code.ExcludeNames != "*_test.go,go.sum" || code.Exclude != ".svg" {
+		t.Errorf("Profile(code) = %+v, %v; want the test exclusions over the base", code, err)
+	}
+	if fc.ExcludeNames != "go.sum" {
+		t.Errorf("Profile changed the base settings: %+v", fc)
+	}
+
+	if _, err := fc.Profile("review"); err == nil || !strings.Contains(err.Error(), "code, docs") {
+		t.Errorf("Profile(review) error = %v, want one naming the profiles", err)
+	}
+}
+
+// TestLoadProjectConfig tests loading the default config file from a root
+func TestLoadProjectConfig(t *testing.T) {
+	root := t.TempDir()
+	fileConfig, err := LoadProjectConfig("", root)
+	if err != nil || !reflect.DeepEqual(fileConfig, &FileConfig{}) {
+		t.Errorf("LoadProjectConfig without a config file = %+v, %v; want an empty config", fileConfig, err)
+	}
+
+	if err := os.WriteFile(filepath.Join(root, DefaultConfigFileName), []byte("include: .go\n"), 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+	if fileConfig, err = LoadProjectConfig("", root); err != nil || fileConfig.Include != ".go" {
+		t.Errorf("LoadProjectConfig found %+v, %v; want include .go", fileConfig, err)
+	}
+
+	if _, err := LoadProjectConfig(filepath.Join(root, "missing.yaml"), root); err == nil {
+		t.Error("LoadProjectConfig with a missing explicit path succeeded, want error")
+	}
+}
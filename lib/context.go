@@ -0,0 +1,78 @@
+package handoff
+
+import (
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ContextAttr is an attribute of the <context> tag written by WrapInContext
+type ContextAttr struct {
+	Name  string
+	Value string
+}
+
+// WrapInContext wraps the content in top-level context tags.
+// This provides consistent formatting for the final output, making it easier
+// to identify the boundaries of the collected content. Any attributes are
+// written on the opening tag in the order given, with their values escaped.
+//
+// Parameters:
+//   - content: The raw content to wrap
+//   - attrs: Optional attributes for the opening tag
+//
+// Returns:
+//   - The content wrapped with <context> tags
+func WrapInContext(content string, attrs ...ContextAttr) string {
+	return wrapInTag("context", content, attrs)
+}
+
+// wrapInTag wraps content in an opening tag carrying attrs and a closing tag (internal helper)
+func wrapInTag(tag, content string, attrs []ContextAttr) string {
+	var b strings.Builder
+	b.WriteString("<" + tag)
+	for _, attr := range attrs {
+		fmt.Fprintf(&b, " %s=\"%s\"", attr.Name, html.EscapeString(attr.Value))
+	}
+	b.WriteString(">\n")
+	b.WriteString(content)
+	b.WriteString("</" + tag + ">")
+	return b.String()
+}
+
+// summaryAttrs returns the context attributes describing processed output (internal helper)
+func summaryAttrs(stats Stats, generated time.Time) []ContextAttr {
+	return []ContextAttr{
+		{Name: "files", Value: strconv.Itoa(stats.FilesProcessed)},
+		{Name: "tokens", Value: strconv.Itoa(stats.Tokens)},
+		{Name: "generated", Value: generated.UTC().Format(time.RFC3339)},
+	}
+}
+
+// attrWrapper is implemented by formatters whose wrapper tag can carry context attributes
+type attrWrapper interface {
+	wrapWithAttrs(body string, attrs []ContextAttr) string
+}
+
+// wrapOutput wraps content with the configured formatter, adding summary
+// attributes to its wrapper tag when enabled (internal helper)
+func wrapOutput(content string, stats Stats, config *Config) string {
+	formatter := config.formatter()
+	if wrapper, ok := formatter.(attrWrapper); ok && config.ContextAttributes {
+		return wrapper.wrapWithAttrs(content, summaryAttrs(stats, time.Now()))
+	}
+	return formatter.Wrap(content)
+}
+
+// WithContextAttributes adds summary attributes to the <context> tag that wraps
+// the output, e.g. <context files="42" tokens="18231" generated="2025-01-02T10:00:00Z">,
+// so prompt builders can read them without parsing the content. It applies to
+// the default template and style wrapper tags; other formatters render their
+// own envelope and ignore it.
+func WithContextAttributes(enabled bool) Option {
+	return func(c *Config) {
+		c.ContextAttributes = enabled
+	}
+}
@@ -0,0 +1,184 @@
+package handoff
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// dependencyManifests are the manifests summarized in the dependencies section,
+// in the order they are listed
+var dependencyManifests = []struct {
+	name      string
+	summarize func(content []byte) (string, error)
+}{
+	{"go.mod", summarizeGoMod},
+	{"package.json", summarizePackageJSON},
+	{"requirements.txt", summarizeRequirements},
+}
+
+// WithDependencies sets whether a section summarizing the direct dependencies
+// declared in go.mod, package.json, and requirements.txt is appended, giving
+// the reader the project's ecosystem at a fraction of the cost of the raw
+// manifests or lockfiles.
+func WithDependencies(summarize bool) Option {
+	return func(c *Config) {
+		c.Dependencies = summarize
+	}
+}
+
+// dependenciesSection summarizes the dependency manifests at the top of each
+// directory argument, or returns an empty string when the section is disabled
+// or there are no manifests (internal helper)
+func dependenciesSection(paths []string, config *Config, logger *Logger) string {
+	if !config.Dependencies {
+		return ""
+	}
+
+	var summaries []string
+	seen := make(map[string]bool)
+	for _, path := range paths {
+		dir := filepath.Clean(path)
+		if info, err := os.Stat(dir); err != nil || !info.IsDir() || seen[dir] {
+			continue
+		}
+		seen[dir] = true
+
+		for _, manifest := range dependencyManifests {
+			manifestPath := filepath.Join(dir, manifest.name)
+			content, err := os.ReadFile(manifestPath)
+			if err != nil {
+				continue
+			}
+			summary, err := manifest.summarize(content)
+			if err != nil {
+				logger.Warn("cannot summarize %s: %v", manifestPath, err)
+				continue
+			}
+			summaries = append(summaries, manifestPath+"\n"+summary)
+		}
+	}
+	return strings.Join(summaries, "\n\n")
+}
+
+// summarizeGoMod lists a go.mod file's module, Go version, and direct
+// requirements, leaving out indirect ones (internal helper)
+func summarizeGoMod(content []byte) (string, error) {
+	var header, requires []string
+	inRequire := false
+	scanner := bufio.NewScanner(strings.NewReader(string(content)))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case inRequire && line == ")":
+			inRequire = false
+		case inRequire:
+			requires = appendGoRequire(requires, line)
+		case line == "require (":
+			inRequire = true
+		case strings.HasPrefix(line, "require "):
+			requires = appendGoRequire(requires, strings.TrimPrefix(line, "require "))
+		case strings.HasPrefix(line, "module "), strings.HasPrefix(line, "go "):
+			header = append(header, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+	return dependencySummary(header, "require", requires), nil
+}
+
+// appendGoRequire adds a "path version" requirement unless it is marked
+// indirect (internal helper)
+func appendGoRequire(requires []string, line string) []string {
+	if line == "" || strings.HasPrefix(line, "//") || strings.Contains(line, "// indirect") {
+		return requires
+	}
+	if i := strings.Index(line, "//"); i >= 0 {
+		line = strings.TrimSpace(line[:i])
+	}
+	return append(requires, strings.Join(strings.Fields(line), " "))
+}
+
+// summarizePackageJSON lists a package.json file's name, version, and
+// dependencies by kind (internal helper)
+func summarizePackageJSON(content []byte) (string, error) {
+	var manifest struct {
+		Name                 string            `json:"name"`
+		Version              string            `json:"version"`
+		Dependencies         map[string]string `json:"dependencies"`
+		DevDependencies      map[string]string `json:"devDependencies"`
+		PeerDependencies     map[string]string `json:"peerDependencies"`
+		OptionalDependencies map[string]string `json:"optionalDependencies"`
+	}
+	if err := json.Unmarshal(content, &manifest); err != nil {
+		return "", err
+	}
+
+	var header []string
+	if manifest.Name != "" {
+		header = append(header, strings.TrimSpace("name "+manifest.Name+" "+manifest.Version))
+	}
+	var lines []string
+	for _, kind := range []struct {
+		name string
+		deps map[string]string
+	}{
+		{"dependencies", manifest.Dependencies},
+		{"devDependencies", manifest.DevDependencies},
+		{"peerDependencies", manifest.PeerDependencies},
+		{"optionalDependencies", manifest.OptionalDependencies},
+	} {
+		if len(kind.deps) == 0 {
+			continue
+		}
+		names := make([]string, 0, len(kind.deps))
+		for name := range kind.deps {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for i, name := range names {
+			names[i] = name + " " + kind.deps[name]
+		}
+		lines = append(lines, fmt.Sprintf("%s: %s", kind.name, strings.Join(names, ", ")))
+	}
+	return strings.Join(append(header, lines...), "\n"), nil
+}
+
+// summarizeRequirements lists the requirements in a requirements.txt file,
+// without comments, options, or blank lines (internal helper)
+func summarizeRequirements(content []byte) (string, error) {
+	var requires []string
+	scanner := bufio.NewScanner(strings.NewReader(string(content)))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "-") {
+			continue
+		}
+		requires = append(requires, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+	return dependencySummary(nil, "require", requires), nil
+}
+
+// dependencySummary joins header lines with a labeled, comma-separated list
+// of dependencies (internal helper)
+func dependencySummary(header []string, label string, deps []string) string {
+	lines := header
+	if len(deps) > 0 {
+		lines = append(lines, label+": "+strings.Join(deps, ", "))
+	} else {
+		lines = append(lines, label+": none")
+	}
+	return strings.Join(lines, "\n")
+}
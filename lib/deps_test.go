@@ -0,0 +1,92 @@
+package handoff
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestSummarizeManifests tests summarizing each supported dependency manifest
+func TestSummarizeManifests(t *testing.T) {
+	testCases := []struct {
+		name      string
+		summarize func([]byte) (string, error)
+		content   string
+		want      string
+	}{
+		{
+			"go.mod",
+			summarizeGoMod,
+			"module example.com/app\n\ngo 1.24\n\nrequire example.com/single v1.0.0\n\nrequire (\n\texample.com/a v1.2.3\n\texample.com/b v0.1.0 // indirect\n\texample.com/c v2.0.0+incompatible // pinned\n)\n",
+			"module example.com/app\ngo 1.24\nrequire: example.com/single v1.0.0, example.com/a v1.2.3, example.com/c v2.0.0+incompatible",
+		},
+		{
+			"go.mod without requirements",
+			summarizeGoMod,
+			"module example.com/app\n\ngo 1.24\n",
+			"module example.com/app\ngo 1.24\nrequire: none",
+		},
+		{
+			"package.json",
+			summarizePackageJSON,
+			`{"name": "app", "version": "1.0.0", "dependencies": {"react": "^18.2.0", "axios": "^1.6.0"}, "devDependencies": {"vitest": "^1.0.0"}}`,
+			"name app 1.0.0\ndependencies: axios ^1.6.0, react ^18.2.0\ndevDependencies: vitest ^1.0.0",
+		},
+		{
+			"requirements.txt",
+			summarizeRequirements,
+			"# web\nflask==3.0.0\n-r dev.txt\n\nrequests>=2.31  # http\n",
+			"require: flask==3.0.0, requests>=2.31",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := tc.summarize([]byte(tc.content))
+			if err != nil {
+				t.Fatalf("summarize failed: %v", err)
+			}
+			if got != tc.want {
+				t.Errorf("summary = %q, want %q", got, tc.want)
+			}
+		})
+	}
+
+	if _, err := summarizePackageJSON([]byte("{")); err == nil {
+		t.Error("summarizePackageJSON with invalid JSON succeeded, want error")
+	}
+}
+
+// TestDependenciesSection tests appending the dependency summary to the output
+func TestDependenciesSection(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod":  "module example.com/app\n\ngo 1.24\n\nrequire example.com/lib v1.0.0\n",
+		"main.go": "package main\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	config := NewConfig(WithGitClient(NewMockGitClient(false)), WithInclude(".go"), WithDependencies(true))
+	content, _, err := ProcessProject([]string{dir}, config)
+	if err != nil {
+		t.Fatalf("ProcessProject failed: %v", err)
+	}
+	want := "<dependencies>\n" + filepath.Join(dir, "go.mod") + "\nmodule example.com/app\ngo 1.24\nrequire: example.com/lib v1.0.0\n</dependencies>"
+	if !strings.Contains(content, want) {
+		t.Errorf("content is missing the dependencies section %q:\n%s", want, content)
+	}
+
+	// Disabled by default
+	content, _, err = ProcessProject([]string{dir}, NewConfig(WithGitClient(NewMockGitClient(false)), WithInclude(".go")))
+	if err != nil {
+		t.Fatalf("ProcessProject failed: %v", err)
+	}
+	if strings.Contains(content, "<dependencies>") {
+		t.Errorf("content has a dependencies section by default:\n%s", content)
+	}
+}
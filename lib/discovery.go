@@ -0,0 +1,489 @@
+package handoff
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// DiscoverFiles returns the files that would be processed for the given paths
+// without reading their content. It performs the same Git-aware discovery and
+// extension/name filtering as ProcessProject, which makes it useful for building
+// file pickers, previews, or custom processing pipelines.
+//
+// Because no content is read, binary detection is not applied; files that
+// ProcessProject would later reject as binary are still included in the result.
+// With WithRoot, files under the root are returned relative to it.
+//
+// Parameters:
+//   - paths: List of file or directory paths to search
+//   - config: Configuration for file filtering (can be nil for defaults)
+//
+// Returns:
+//   - The list of files that pass all filters, in discovery order
+//   - An error if no paths are provided, or the error handler aborted
+func DiscoverFiles(paths []string, config *Config) ([]string, error) {
+	if config == nil {
+		config = NewConfig()
+	}
+	config = config.Clone()
+	config.ProcessConfig()
+
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no paths provided")
+	}
+
+	logger := NewLogger(config.Verbose)
+
+	discovered, err := discoverFiles(config.resolvePaths(paths), config, logger)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, file := range discovered {
+		if file.path == StdinPath || filterReason(file.path, file.info, config, logger) == "" {
+			files = append(files, config.displayPath(file.path))
+		}
+	}
+	return files, nil
+}
+
+// discoveredFile pairs a discovered path with the file info obtained while
+// discovering it, so later pipeline stages don't need to stat the file again.
+type discoveredFile struct {
+	path string
+	info os.FileInfo
+
+	// explicit marks a file named directly as a path rather than found in a directory
+	explicit bool
+
+	// filtered marks that reason holds the result of the filters, so later
+	// stages don't run them, and the git lookups they make, again
+	filtered bool
+
+	// reason is why the filters skip the file, or empty if it passes
+	reason SkipReason
+}
+
+// applyFilters runs the filters once for each discovered file other than
+// directories and files without info, recording the result for the later
+// pipeline stages (internal helper)
+func applyFilters(files []discoveredFile, config *Config, logger *Logger) {
+	for i := range files {
+		if files[i].info == nil || files[i].info.IsDir() {
+			continue
+		}
+		files[i].reason = filterReason(files[i].path, files[i].info, config, logger)
+		files[i].filtered = true
+	}
+}
+
+// filterReason returns the reason the filters skip the file, running them only
+// when applyFilters hasn't already
+func (f discoveredFile) filterReason(config *Config, logger *Logger) SkipReason {
+	if f.filtered {
+		return f.reason
+	}
+	return filterReason(f.path, f.info, config, logger)
+}
+
+// discoverFiles expands the given paths into a flat list of candidate files.
+// Directories are expanded via getFilesFromDir, while regular paths are kept as-is.
+// Paths that don't exist but contain glob metacharacters are expanded via
+// expandGlob. Paths that cannot be accessed are logged as warnings and skipped,
+// unless the error handler aborts. (internal helper)
+func discoverFiles(paths []string, config *Config, logger *Logger) ([]discoveredFile, error) {
+	var allFiles []discoveredFile
+	stdin := false
+	for _, path := range paths {
+		logger.Verbose("Processing path: %s", path)
+
+		// Standard input is a single document that can only be read once
+		if path == StdinPath {
+			if stdin {
+				logger.Warn("%s given more than once; standard input is read once", StdinPath)
+			} else {
+				allFiles = append(allFiles, discoveredFile{path: path, explicit: true})
+				stdin = true
+			}
+			continue
+		}
+
+		var info os.FileInfo
+		abort, err := config.attempt(path, func() (err error) {
+			info, err = os.Stat(path)
+			if err != nil && isGlob(path) {
+				// A missing path with metacharacters is a pattern, not a failure
+				return nil
+			}
+			return err
+		})
+		if abort {
+			return nil, abortRun(err)
+		}
+		if info == nil && isGlob(path) {
+			files, globErr := expandGlob(path, config)
+			if globErr != nil {
+				logger.Warn("Error expanding pattern %s: %v", path, globErr)
+			} else if len(files) == 0 {
+				logger.Warn("no files match %s", path)
+			}
+			allFiles = append(allFiles, files...)
+			continue
+		}
+		if err != nil {
+			logger.Warn("%v", err)
+			continue
+		}
+
+		if info.IsDir() {
+			var files []discoveredFile
+			abort, err := config.attempt(path, func() (err error) {
+				files, err = getFilesFromDir(path, config)
+				return err
+			})
+			if abort {
+				return nil, abortRun(err)
+			}
+			if err != nil {
+				logger.Warn("Error getting files from directory %s: %v", path, err)
+				continue
+			}
+			allFiles = append(allFiles, files...)
+		} else {
+			// It's a single file
+			allFiles = append(allFiles, discoveredFile{path: path, info: info, explicit: true})
+		}
+	}
+	prefetchAttributes(allFiles, config)
+	return allFiles, nil
+}
+
+// isGlob reports whether a path contains glob metacharacters (internal helper)
+func isGlob(path string) bool {
+	return strings.ContainsAny(path, "*?[")
+}
+
+// expandGlob returns the files matching a glob pattern such as "src/**/*.go",
+// without relying on shell globbing (internal helper). The files are found in
+// the pattern's leading directory as a directory path would be, so ignored and
+// hidden files are left out the same way, then matched with gitignore-style
+// syntax: "*" matches within a path element and "**" across elements.
+func expandGlob(pattern string, config *Config) ([]discoveredFile, error) {
+	base, rest := splitGlob(pattern)
+	re, err := regexp.Compile(ignorePatternToRegexp("/" + rest))
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := os.Stat(base); os.IsNotExist(err) {
+		return nil, nil
+	}
+	files, err := getFilesFromDir(base, config)
+	if err != nil {
+		return nil, err
+	}
+	var matches []discoveredFile
+	for _, file := range files {
+		rel, err := filepath.Rel(base, file.path)
+		if err == nil && re.MatchString(filepath.ToSlash(rel)) {
+			matches = append(matches, file)
+		}
+	}
+	return matches, nil
+}
+
+// splitGlob splits a glob pattern into the directory before its first element
+// with metacharacters and the slash-separated remainder (internal helper)
+func splitGlob(pattern string) (base, rest string) {
+	elems := strings.Split(filepath.ToSlash(pattern), "/")
+	i := 0
+	for i < len(elems) && !isGlob(elems[i]) {
+		i++
+	}
+	base = strings.Join(elems[:i], "/")
+	if base == "" {
+		base = "."
+		if strings.HasPrefix(pattern, "/") {
+			base = "/"
+		}
+	}
+	return filepath.Clean(filepath.FromSlash(base)), strings.Join(elems[i:], "/")
+}
+
+// passesFilters reports whether a file passes the gitignore and extension/name
+// filters from the configuration, logging the reason when a file is skipped.
+// It does not inspect file content. (internal helper)
+func passesFilters(filePath string, config *Config, logger *Logger) bool {
+	return filterReason(filePath, nil, config, logger) == ""
+}
+
+// filterReason applies the same filters as passesFilters, followed by any
+// custom filters, and returns the reason a file is skipped, or an empty string
+// if it passes. Custom filters receive info, which is looked up when nil
+// (internal helper).
+func filterReason(filePath string, info os.FileInfo, config *Config, logger *Logger) SkipReason {
+	// Respect gitignore rules unless explicitly bypassed.
+	// The IgnoreGitignore flag allows processing files that would normally be excluded
+	// by .gitignore rules - useful for documentation files, context gathering, or
+	// when users need to process specific files regardless of Git's ignore patterns.
+	if isGitIgnored(filePath, config) && !isHiddenAllowed(filePath, config) {
+		if config.IgnoreGitignore {
+			logger.Verbose("processing gitignored file (bypass enabled): %s", filePath)
+		} else {
+			logger.Verbose("skipping gitignored file: %s", filePath)
+			// Hidden files count as ignored only when git can't evaluate the
+			// ignore rules; a hidden file git itself ignores is gitignored
+			if strings.HasPrefix(filepath.Base(filePath), ".") && !gitChecksIgnores(filePath, config) {
+				return SkipHidden
+			}
+			return SkipGitIgnored
+		}
+	}
+
+	// Check if file should be processed based on filters
+	if !shouldProcess(filePath, config) {
+		if matchesExcludeName(filepath.Base(filePath), config.excludeNames) {
+			logger.Verbose("skipping file (in exclude-names list): %s", filePath)
+		}
+		return SkipFiltered
+	}
+
+	// Skip generated and vendored code marked via .gitattributes, matching how
+	// GitHub's linguist decides which files are worth reading
+	if !config.IgnoreGitattributes {
+		if attr := linguistExclusion(filePath, config); attr != "" {
+			logger.Verbose("skipping file (marked %s in .gitattributes): %s", attr, filePath)
+			return SkipFiltered
+		}
+	}
+
+	// Keep only recently touched files when a modification cutoff is set
+	if !config.ModifiedAfter.IsZero() {
+		if modified := lastModified(filePath, config); modified.Before(config.ModifiedAfter) {
+			logger.Verbose("skipping file (last modified %s, before %s): %s",
+				modified.Format(time.DateOnly), config.ModifiedAfter.Format(time.DateOnly), filePath)
+			return SkipFiltered
+		}
+	}
+
+	// Keep only files belonging to the requested author
+	if config.Author != "" {
+		if author := fileAuthor(filePath, config.AuthorMatch, config.GitClient); !matchesAuthor(author, config.Author) {
+			logger.Verbose("skipping file (not authored by %s): %s", config.Author, filePath)
+			return SkipFiltered
+		}
+	}
+
+	// Apply policies registered in code
+	if reason := customFilterReason(filePath, info, config.fileFilters); reason != "" {
+		logger.Verbose("skipping file (%s): %s", reason, filePath)
+		return reason
+	}
+
+	return ""
+}
+
+// linguistAttrs are the gitattributes that mark files as not worth reading
+var linguistAttrs = []string{"linguist-generated", "linguist-vendored"}
+
+// linguistExclusion returns the name of the linguist attribute that excludes the
+// file, or an empty string if none applies or attributes cannot be read. Files
+// from the discovery pass use the attributes prefetched for them. (internal helper)
+func linguistExclusion(filePath string, config *Config) string {
+	values, ok := config.attributes[filePath]
+	if !ok {
+		checker, isChecker := config.GitClient.(AttributeChecker)
+		if !isChecker || !config.GitClient.IsAvailable() {
+			return ""
+		}
+		batch, err := checker.CheckAttrs([]string{filePath}, linguistAttrs...)
+		if err != nil {
+			return ""
+		}
+		values = batch[filePath]
+	}
+	for _, attr := range linguistAttrs {
+		if value := values[attr]; value == "set" || value == "true" {
+			return attr
+		}
+	}
+	return ""
+}
+
+// prefetchAttributes looks up the linguist attributes of the discovered files
+// in one git call rather than one per file. When the files span several
+// repositories, or lie outside one, they are looked up a directory at a time,
+// and files whose attributes can't be read get none. (internal helper)
+func prefetchAttributes(files []discoveredFile, config *Config) {
+	checker, ok := config.GitClient.(AttributeChecker)
+	if !ok || config.IgnoreGitattributes || !config.GitClient.IsAvailable() {
+		return
+	}
+
+	var paths []string
+	for _, file := range files {
+		if file.path != StdinPath && (file.info == nil || !file.info.IsDir()) {
+			paths = append(paths, file.path)
+		}
+	}
+	if len(paths) == 0 {
+		return
+	}
+
+	config.attributes = make(map[string]map[string]string, len(paths))
+	lookup := func(batch []string) bool {
+		values, err := checker.CheckAttrs(batch, linguistAttrs...)
+		if err != nil {
+			return false
+		}
+		for _, path := range batch {
+			config.attributes[path] = values[path]
+		}
+		return true
+	}
+	if lookup(paths) {
+		return
+	}
+
+	byDir := make(map[string][]string)
+	var dirs []string
+	for _, path := range paths {
+		dir := filepath.Dir(path)
+		if byDir[dir] == nil {
+			dirs = append(dirs, dir)
+		}
+		byDir[dir] = append(byDir[dir], path)
+	}
+	for _, dir := range dirs {
+		if !lookup(byDir[dir]) {
+			for _, path := range byDir[dir] {
+				config.attributes[path] = nil
+			}
+		}
+	}
+}
+
+// isHiddenSkipped reports whether a file or directory name is hidden and not
+// covered by the hidden allowlist (internal helper)
+func isHiddenSkipped(name string, config *Config) bool {
+	return strings.HasPrefix(name, ".") && !slices.Contains(config.hiddenAllowlist, name)
+}
+
+// isHiddenAllowed reports whether a file's ignored status should be overridden
+// because it is an allowlisted hidden file (internal helper). GitClient treats
+// hidden files as ignored whenever git can't evaluate ignore rules, so the
+// override only applies outside git repositories; inside a repository the
+// result of git's own ignore check is respected.
+func isHiddenAllowed(filePath string, config *Config) bool {
+	name := filepath.Base(filePath)
+	if !strings.HasPrefix(name, ".") || !slices.Contains(config.hiddenAllowlist, name) {
+		return false
+	}
+	return !gitChecksIgnores(filePath, config)
+}
+
+// gitChecksIgnores reports whether git evaluates the ignore rules for a file:
+// git is available and the file is inside a repository (internal helper)
+func gitChecksIgnores(filePath string, config *Config) bool {
+	if !config.GitClient.IsAvailable() {
+		return false
+	}
+	absPath, err := filepath.Abs(filePath)
+	if err != nil {
+		return true
+	}
+	return findRepoRoot(filepath.Dir(absPath)) != ""
+}
+
+// isGitIgnored checks if a file is gitignored or hidden (internal helper).
+// It delegates the check to the GitClient implementation in the config.
+func isGitIgnored(file string, config *Config) bool {
+	return config.GitClient.IsGitIgnored(file)
+}
+
+// getGitFiles retrieves files from a directory using Git's ls-files command (internal helper)
+// It delegates the operation to the GitClient implementation in the config.
+// Each returned file is stat'ed exactly once here, and the resulting info is carried
+// through the rest of the pipeline.
+func getGitFiles(dir string, config *Config) ([]discoveredFile, error) {
+	files, err := config.GitClient.GetGitFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check if files still exist before returning them
+	var existingFiles []discoveredFile
+	for _, filePath := range files {
+		if info, err := os.Stat(filePath); err == nil {
+			existingFiles = append(existingFiles, discoveredFile{path: filePath, info: info})
+		}
+	}
+	return existingFiles, nil
+}
+
+// getFilesWithFilepathWalk retrieves files from a directory by walking the filesystem (internal helper)
+// The file info reported by the walk is reused, so regular files are not stat'ed again;
+// only symlinks are resolved to describe their targets.
+//
+// To stay consistent with git-based discovery, the walk honors the user's global
+// ignore file, including one set by core.excludesFile, and the repository's
+// info/exclude file when present, in worktrees and submodules too. Hidden
+// files and directories are skipped unless they appear in the hidden allowlist.
+func getFilesWithFilepathWalk(dir string, config *Config) ([]discoveredFile, error) {
+	ignores := newFallbackIgnoreMatcher(dir)
+
+	var files []discoveredFile
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if path == dir {
+				return nil
+			}
+			if isHiddenSkipped(info.Name(), config) || ignores.matches(path, true) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if isHiddenSkipped(info.Name(), config) || ignores.matches(path, false) {
+			return nil
+		}
+		if info.Mode()&os.ModeSymlink != 0 {
+			target, statErr := os.Stat(path)
+			if statErr != nil || target.IsDir() {
+				// Skip broken links and links to directories
+				return nil
+			}
+			info = target
+		}
+		files = append(files, discoveredFile{path: path, info: info})
+		return nil
+	})
+	return files, err
+}
+
+// getFilesFromDir retrieves all files to process from a directory.
+// It tries to use Git first and falls back to filepath.Walk if Git is not available
+// or the directory is not a Git repository. (internal helper)
+func getFilesFromDir(dir string, config *Config) ([]discoveredFile, error) {
+	if config.GitClient.IsAvailable() {
+		files, err := getGitFiles(dir, config)
+		if err == nil {
+			return files, nil
+		}
+		// If there's an error running git ls-files and it's not "not a git repository", return the error
+		if !strings.Contains(err.Error(), "not a git repository") {
+			return nil, err
+		}
+		// Otherwise fall back to filepath.Walk
+	}
+
+	// Fallback to walking the directory, excluding hidden files and dirs
+	return getFilesWithFilepathWalk(dir, config)
+}
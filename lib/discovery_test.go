@@ -0,0 +1,390 @@
+package handoff
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// TestDiscoverFiles tests that DiscoverFiles applies discovery and filtering without reading content
+func TestDiscoverFiles(t *testing.T) {
+	tmpDir, _ := createTestDir(t)
+	defer func() {
+		if cleanErr := os.RemoveAll(tmpDir); cleanErr != nil {
+			t.Logf("Failed to clean up test directory: %v", cleanErr)
+		}
+	}()
+
+	testCases := []struct {
+		name    string
+		paths   []string
+		config  *Config
+		want    []string
+		reject  []string
+		wantErr bool
+	}{
+		{
+			name:   "Default config includes binaries",
+			paths:  []string{tmpDir},
+			config: NewConfig(WithGitClient(NewMockGitClient(false))),
+			want:   []string{"main.go", "file1.txt", "binary.bin", filepath.Join("subdir", "subfile1.txt")},
+		},
+		{
+			name:   "Include filter",
+			paths:  []string{tmpDir},
+			config: NewConfig(WithGitClient(NewMockGitClient(false)), WithInclude(".go")),
+			want:   []string{"main.go", "util.go", filepath.Join("subdir", "subfile2.go")},
+			reject: []string{"file1.txt", "binary.bin"},
+		},
+		{
+			name:   "Exclude names filter",
+			paths:  []string{tmpDir},
+			config: NewConfig(WithGitClient(NewMockGitClient(false)), WithExcludeNames("main.go")),
+			want:   []string{"util.go"},
+			reject: []string{"main.go"},
+		},
+		{
+			name:   "Glob pattern",
+			paths:  []string{filepath.Join(tmpDir, "*.go")},
+			config: NewConfig(WithGitClient(NewMockGitClient(false))),
+			want:   []string{"main.go", "util.go"},
+			reject: []string{"file1.txt", filepath.Join("subdir", "subfile2.go")},
+		},
+		{
+			name:   "Recursive glob pattern is filtered",
+			paths:  []string{filepath.Join(tmpDir, "**", "*.go")},
+			config: NewConfig(WithGitClient(NewMockGitClient(false)), WithExcludeNames("util.go")),
+			want:   []string{"main.go", filepath.Join("subdir", "subfile2.go")},
+			reject: []string{"util.go", filepath.Join("subdir", "subfile1.txt")},
+		},
+		{
+			name:   "Glob pattern without matches",
+			paths:  []string{filepath.Join(tmpDir, "missing", "*.go")},
+			config: NewConfig(WithGitClient(NewMockGitClient(false))),
+			reject: []string{"main.go"},
+		},
+		{
+			name:   "Nil config uses defaults",
+			paths:  []string{filepath.Join(tmpDir, "main.go")},
+			config: nil,
+			want:   []string{"main.go"},
+		},
+		{
+			name:    "Empty paths",
+			paths:   []string{},
+			config:  NewConfig(),
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			files, err := DiscoverFiles(tc.paths, tc.config)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("DiscoverFiles() error = %v, wantErr %v", err, tc.wantErr)
+			}
+
+			found := make(map[string]bool)
+			for _, file := range files {
+				rel, err := filepath.Rel(tmpDir, file)
+				if err != nil {
+					t.Fatalf("Failed to get relative path for %s: %v", file, err)
+				}
+				found[rel] = true
+			}
+
+			for _, want := range tc.want {
+				if !found[want] {
+					t.Errorf("DiscoverFiles() result should contain %q, got %v", want, files)
+				}
+			}
+			for _, reject := range tc.reject {
+				if found[reject] {
+					t.Errorf("DiscoverFiles() result should not contain %q, got %v", reject, files)
+				}
+			}
+		})
+	}
+}
+
+// TestSplitGlob tests splitting glob patterns into a base directory and remainder
+func TestSplitGlob(t *testing.T) {
+	testCases := []struct {
+		pattern  string
+		wantBase string
+		wantRest string
+	}{
+		{"*.go", ".", "*.go"},
+		{"./src/**/*.go", "src", "**/*.go"},
+		{"docs/*.md", "docs", "*.md"},
+		{"/tmp/a/b?/c.txt", filepath.FromSlash("/tmp/a"), "b?/c.txt"},
+		{"/*.go", filepath.FromSlash("/"), "*.go"},
+	}
+
+	for _, tc := range testCases {
+		base, rest := splitGlob(tc.pattern)
+		if base != tc.wantBase || rest != tc.wantRest {
+			t.Errorf("splitGlob(%q) = %q, %q; want %q, %q", tc.pattern, base, rest, tc.wantBase, tc.wantRest)
+		}
+	}
+}
+
+// TestDiscoverFilesCarriesInfo tests that discovery attaches file info to every discovered file
+func TestDiscoverFilesCarriesInfo(t *testing.T) {
+	tmpDir, _ := createTestDir(t)
+	defer func() {
+		if cleanErr := os.RemoveAll(tmpDir); cleanErr != nil {
+			t.Logf("Failed to clean up test directory: %v", cleanErr)
+		}
+	}()
+
+	config := NewConfig(WithGitClient(NewMockGitClient(false)))
+	files, err := discoverFiles([]string{tmpDir, filepath.Join(tmpDir, "main.go")}, config, NewLogger(false))
+	if err != nil || len(files) == 0 {
+		t.Fatal("discoverFiles() returned no files")
+	}
+
+	for _, file := range files {
+		if file.info == nil {
+			t.Errorf("discoverFiles() returned %s without file info", file.path)
+			continue
+		}
+		if file.info.IsDir() {
+			t.Errorf("discoverFiles() returned directory %s", file.path)
+		}
+		if file.info.Name() != filepath.Base(file.path) {
+			t.Errorf("file info name = %q, want %q", file.info.Name(), filepath.Base(file.path))
+		}
+	}
+}
+
+// TestPassesFiltersLinguistAttributes tests that files marked generated or vendored are skipped
+func TestPassesFiltersLinguistAttributes(t *testing.T) {
+	mockGit := NewMockGitClient(true)
+	mockGit.SetAttributes("/repo/gen.pb.go", map[string]string{"linguist-generated": "true"})
+	mockGit.SetAttributes("/repo/vendor/lib.js", map[string]string{"linguist-vendored": "set"})
+	mockGit.SetAttributes("/repo/docs.md", map[string]string{"linguist-generated": "false"})
+
+	testCases := []struct {
+		name   string
+		file   string
+		ignore bool
+		want   bool
+	}{
+		{name: "Generated file", file: "/repo/gen.pb.go", want: false},
+		{name: "Vendored file", file: "/repo/vendor/lib.js", want: false},
+		{name: "Explicitly not generated", file: "/repo/docs.md", want: true},
+		{name: "No attributes", file: "/repo/main.go", want: true},
+		{name: "Override includes generated", file: "/repo/gen.pb.go", ignore: true, want: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := NewConfig(WithGitClient(mockGit), WithIgnoreGitattributes(tc.ignore))
+			if got := passesFilters(tc.file, config, NewLogger(false)); got != tc.want {
+				t.Errorf("passesFilters(%q) = %v, want %v", tc.file, got, tc.want)
+			}
+		})
+	}
+}
+
+// attrCountingGitClient counts the attribute lookups made through it
+type attrCountingGitClient struct {
+	*MockGitClient
+	lookups int
+}
+
+// CheckAttrs counts the lookup and delegates it to the mock
+func (c *attrCountingGitClient) CheckAttrs(files []string, attrs ...string) (map[string]map[string]string, error) {
+	c.lookups++
+	return c.MockGitClient.CheckAttrs(files, attrs...)
+}
+
+// TestLinguistAttributesBatched tests that the attributes of a run's files are
+// looked up together rather than once per file
+func TestLinguistAttributesBatched(t *testing.T) {
+	dir := t.TempDir()
+	var files []string
+	for _, name := range []string{"main.go", "util.go", "gen.pb.go"} {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte("package main\n"), 0644); err != nil {
+			t.Fatalf("Failed to write file: %v", err)
+		}
+		files = append(files, path)
+	}
+	mockGit := NewMockGitClient(true)
+	mockGit.SetFilesInDir(dir, files)
+	mockGit.SetAttributes(filepath.Join(dir, "gen.pb.go"), map[string]string{"linguist-generated": "true"})
+
+	client := &attrCountingGitClient{MockGitClient: mockGit}
+	_, stats, err := ProcessProject([]string{dir}, NewConfig(WithGitClient(client)))
+	if err != nil {
+		t.Fatalf("ProcessProject failed: %v", err)
+	}
+	if client.lookups != 1 {
+		t.Errorf("attribute lookups = %d, want 1 for the whole run", client.lookups)
+	}
+	if stats.FilesProcessed != 2 {
+		t.Errorf("FilesProcessed = %d, want 2 with the generated file skipped", stats.FilesProcessed)
+	}
+
+	// A client without AttributeChecker skips nothing for its attributes
+	_, stats, err = ProcessProject([]string{dir}, NewConfig(WithGitClient(basicGitClient{mockGit})))
+	if err != nil {
+		t.Fatalf("ProcessProject failed: %v", err)
+	}
+	if stats.FilesProcessed != 3 {
+		t.Errorf("FilesProcessed without AttributeChecker = %d, want 3", stats.FilesProcessed)
+	}
+}
+
+// TestHiddenAllowlist tests that allowlisted hidden paths are discovered while other hidden paths are skipped
+func TestHiddenAllowlist(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "handoff-hidden-test-")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer func() {
+		if cleanErr := os.RemoveAll(tmpDir); cleanErr != nil {
+			t.Logf("Failed to clean up temp directory: %v", cleanErr)
+		}
+	}()
+
+	for _, file := range []string{
+		"main.go",
+		".golangci.yml",
+		".env",
+		".DS_Store",
+		filepath.Join(".github", "workflows", "ci.yml"),
+		filepath.Join(".cache", "data.txt"),
+	} {
+		path := filepath.Join(tmpDir, file)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory for %s: %v", file, err)
+		}
+		if err := os.WriteFile(path, []byte("content"), 0644); err != nil {
+			t.Fatalf("Failed to create file %s: %v", file, err)
+		}
+	}
+
+	testCases := []struct {
+		name      string
+		allowlist string
+		want      []string
+		reject    []string
+	}{
+		{
+			name:   "No allowlist skips hidden paths",
+			want:   []string{"main.go"},
+			reject: []string{".golangci.yml", ".env", filepath.Join(".github", "workflows", "ci.yml")},
+		},
+		{
+			name:      "Allowlisted names are included",
+			allowlist: ".github,.golangci.yml",
+			want:      []string{"main.go", ".golangci.yml", filepath.Join(".github", "workflows", "ci.yml")},
+			reject:    []string{".env", ".DS_Store", filepath.Join(".cache", "data.txt")},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := NewConfig(WithGitClient(NewMockGitClient(false)), WithHiddenAllowlist(tc.allowlist))
+			files, err := DiscoverFiles([]string{tmpDir}, config)
+			if err != nil {
+				t.Fatalf("DiscoverFiles failed: %v", err)
+			}
+
+			found := make(map[string]bool)
+			for _, file := range files {
+				rel, _ := filepath.Rel(tmpDir, file)
+				found[rel] = true
+			}
+			for _, want := range tc.want {
+				if !found[want] {
+					t.Errorf("DiscoverFiles() should include %s, got %v", want, files)
+				}
+			}
+			for _, reject := range tc.reject {
+				if found[reject] {
+					t.Errorf("DiscoverFiles() should not include %s, got %v", reject, files)
+				}
+			}
+		})
+	}
+}
+
+// TestFilterReasonGitIgnoredHidden tests that a hidden file git ignores is
+// reported as gitignored, and as hidden only when git can't check ignores
+func TestFilterReasonGitIgnoredHidden(t *testing.T) {
+	repo := t.TempDir()
+	if err := os.Mkdir(filepath.Join(repo, ".git"), 0755); err != nil {
+		t.Fatalf("Failed to create .git: %v", err)
+	}
+	env := filepath.Join(repo, ".env")
+	outside := filepath.Join(t.TempDir(), ".env")
+	for _, path := range []string{env, outside} {
+		if err := os.WriteFile(path, []byte("KEY=value\n"), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", path, err)
+		}
+	}
+
+	git := NewMockGitClient(true)
+	git.SetIgnoredFiles(map[string]bool{env: true})
+	config := NewConfig(WithGitClient(git))
+	logger := NewLogger(false)
+	if got := filterReason(env, nil, config, logger); got != SkipGitIgnored {
+		t.Errorf("filterReason(gitignored .env) = %q, want %q", got, SkipGitIgnored)
+	}
+	if got := filterReason(outside, nil, config, logger); got != SkipHidden {
+		t.Errorf("filterReason(.env outside a repository) = %q, want %q", got, SkipHidden)
+	}
+
+	config = NewConfig(WithGitClient(NewMockGitClient(false)))
+	if got := filterReason(env, nil, config, logger); got != SkipHidden {
+		t.Errorf("filterReason(.env without git) = %q, want %q", got, SkipHidden)
+	}
+}
+
+// TestWatchFiles tests finding the files to watch without walking ignored trees
+func TestWatchFiles(t *testing.T) {
+	client := NewRealGitClient()
+	if !client.IsAvailable() {
+		t.Skip("git not available")
+	}
+
+	dir := t.TempDir()
+	runGit(t, dir, "init", "-q")
+	files := map[string]string{
+		".gitignore":                "node_modules/\n",
+		"main.go":                   "package main\n",
+		"notes.txt":                 "notes\n",
+		"node_modules/pkg/index.go": "package pkg\n",
+		"src/util.go":               "package src\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	config := NewConfig(WithGitClient(client), WithInclude(".go"))
+	got, err := config.WatchFiles([]string{dir, filepath.Join(dir, "missing.go"), StdinPath})
+	if err != nil {
+		t.Fatalf("WatchFiles failed: %v", err)
+	}
+	var rel []string
+	for _, file := range got {
+		r, _ := filepath.Rel(dir, file)
+		rel = append(rel, filepath.ToSlash(r))
+	}
+	sort.Strings(rel)
+	if want := []string{"main.go", "missing.go", "src/util.go"}; !reflect.DeepEqual(rel, want) {
+		t.Errorf("WatchFiles() = %v, want %v", rel, want)
+	}
+}
@@ -72,10 +72,17 @@
 //	chars, lines, tokens := handoff.CalculateStatistics("Some content to analyze")
 //	fmt.Printf("Analysis: %d chars, %d lines, ~%d tokens\n", chars, lines, tokens)
 //
+//	// Or get bytes, runes, words, and editor-style line counts
+//	s := handoff.Analyze("Some content to analyze\n")
+//	fmt.Printf("%d bytes, %d runes, %d words, %d lines\n", s.Bytes, s.Runes, s.Words, s.Lines)
+//
 //	// Wrap content in context tags for better AI assistant compatibility
 //	wrappedContent := handoff.WrapInContext("Content to wrap in context tags")
 //	fmt.Println(wrappedContent) // Outputs: <context>Content to wrap in context tags</context>
 //
+//	// List the files that would be processed without reading them
+//	files, err := handoff.DiscoverFiles([]string{"./src"}, config)
+//
 // The package is designed to be simple to use with a focused API. ProcessProject
 // is the main entry point for all file processing functionality.
 package handoff
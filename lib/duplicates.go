@@ -0,0 +1,61 @@
+package handoff
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// collapseDuplicates finds files reachable by more than one name, through
+// hard links, symlinks, or a path given twice, among the files that pass the
+// name and extension filters. The first name found is kept; the others are
+// returned with a note referring to it, so a large file isn't silently
+// included twice. (internal helper)
+func collapseDuplicates(files []discoveredFile, config *Config) ([]discoveredFile, []discoveredFile, []string) {
+	quiet := NewLogger(false)
+	bySize := make(map[int64][]int)
+	var kept, duplicates []discoveredFile
+	var notes []string
+	for _, file := range files {
+		if file.info == nil || file.info.IsDir() || file.filterReason(config, quiet) != "" {
+			kept = append(kept, file)
+			continue
+		}
+
+		// Only files of the same size can be the same file
+		original := -1
+		for _, i := range bySize[file.info.Size()] {
+			if os.SameFile(kept[i].info, file.info) {
+				original = i
+				break
+			}
+		}
+		if original < 0 {
+			bySize[file.info.Size()] = append(bySize[file.info.Size()], len(kept))
+			kept = append(kept, file)
+			continue
+		}
+
+		// A file named directly as a path keeps that status under its first name
+		kept[original].explicit = kept[original].explicit || file.explicit
+		duplicates = append(duplicates, file)
+		notes = append(notes, fmt.Sprintf("%s: same file as %s (%s)",
+			config.displayPath(file.path), config.displayPath(kept[original].path), duplicateKind(kept[original].path, file.path)))
+	}
+	return kept, duplicates, notes
+}
+
+// duplicateKind describes how two names reach the same file (internal helper)
+func duplicateKind(original, duplicate string) string {
+	absOriginal, errOriginal := filepath.Abs(original)
+	absDuplicate, errDuplicate := filepath.Abs(duplicate)
+	if errOriginal == nil && errDuplicate == nil && absOriginal == absDuplicate {
+		return "named twice"
+	}
+	for _, path := range []string{original, duplicate} {
+		if info, err := os.Lstat(path); err == nil && info.Mode()&os.ModeSymlink != 0 {
+			return "symlink"
+		}
+	}
+	return "hard link"
+}
@@ -0,0 +1,52 @@
+package handoff
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestCollapseDuplicates tests including a file reachable by several names once
+func TestCollapseDuplicates(t *testing.T) {
+	dir := t.TempDir()
+	original := filepath.Join(dir, "big.go")
+	if err := os.WriteFile(original, []byte("package big\n"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "other.go"), []byte("package big\n"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	hardLink := filepath.Join(dir, "hard.go")
+	if err := os.Link(original, hardLink); err != nil {
+		t.Skipf("hard links not supported: %v", err)
+	}
+	symlink := filepath.Join(dir, "sym.go")
+	if err := os.Symlink(original, symlink); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+
+	config := NewConfig(WithGitClient(NewMockGitClient(false)))
+	content, stats, err := ProcessProject([]string{dir, original}, config)
+	if err != nil {
+		t.Fatalf("ProcessProject failed: %v", err)
+	}
+
+	// Files with identical content but different inodes are both kept
+	want := []string{original, filepath.Join(dir, "other.go")}
+	if strings.Join(stats.IncludedFiles, " ") != strings.Join(want, " ") {
+		t.Errorf("IncludedFiles = %v, want %v", stats.IncludedFiles, want)
+	}
+	if stats.Skipped[SkipDuplicate] != 3 {
+		t.Errorf("Skipped = %v, want 3 duplicates", stats.Skipped)
+	}
+	for _, note := range []string{
+		hardLink + ": same file as " + original + " (hard link)",
+		symlink + ": same file as " + original + " (symlink)",
+		original + ": same file as " + original + " (named twice)",
+	} {
+		if !strings.Contains(content, note) {
+			t.Errorf("content is missing %q:\n%s", note, content)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package handoff
+
+import (
+	"bytes"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// Encoding identifies the character encoding detected in a file
+type Encoding string
+
+const (
+	// EncodingUTF8 marks UTF-8 content, including plain ASCII
+	EncodingUTF8 Encoding = "utf-8"
+
+	// EncodingUTF8BOM marks UTF-8 content starting with a byte order mark
+	EncodingUTF8BOM Encoding = "utf-8-bom"
+
+	// EncodingUTF16LE marks little-endian UTF-16 content with a byte order mark
+	EncodingUTF16LE Encoding = "utf-16le"
+
+	// EncodingUTF16BE marks big-endian UTF-16 content with a byte order mark
+	EncodingUTF16BE Encoding = "utf-16be"
+
+	// EncodingWindows1252 marks text that is not valid UTF-8, read as
+	// Windows-1252, the superset of Latin-1 used by legacy Windows tools
+	EncodingWindows1252 Encoding = "windows-1252"
+)
+
+// Byte order marks recognized by detectEncoding
+var (
+	bomUTF8    = []byte{0xEF, 0xBB, 0xBF}
+	bomUTF16LE = []byte{0xFF, 0xFE}
+	bomUTF16BE = []byte{0xFE, 0xFF}
+)
+
+// windows1252High maps the bytes 0x80-0x9F, where Windows-1252 differs from
+// Latin-1, to their characters; unassigned bytes keep their Latin-1 meaning
+var windows1252High = [32]rune{
+	0x20AC, 0x0081, 0x201A, 0x0192, 0x201E, 0x2026, 0x2020, 0x2021,
+	0x02C6, 0x2030, 0x0160, 0x2039, 0x0152, 0x008D, 0x017D, 0x008F,
+	0x0090, 0x2018, 0x2019, 0x201C, 0x201D, 0x2022, 0x2013, 0x2014,
+	0x02DC, 0x2122, 0x0161, 0x203A, 0x0153, 0x009D, 0x017E, 0x0178,
+}
+
+// WithTranscode sets whether files in other encodings are converted to UTF-8:
+// UTF-16 files with a byte order mark, which are otherwise skipped as binary,
+// and text that is not valid UTF-8, which is read as Windows-1252. UTF-8 byte
+// order marks are dropped. The encoding detected for each file is reported in
+// Stats.Files either way, so converted files can be audited.
+func WithTranscode(transcode bool) Option {
+	return func(c *Config) {
+		c.Transcode = transcode
+	}
+}
+
+// hasUTF16BOM reports whether content starts with a UTF-16 byte order mark (internal helper)
+func hasUTF16BOM(content []byte) bool {
+	return bytes.HasPrefix(content, bomUTF16LE) || bytes.HasPrefix(content, bomUTF16BE)
+}
+
+// detectEncoding identifies the encoding of text content from its byte order
+// mark, falling back to Windows-1252 for content that is not valid UTF-8
+// (internal helper)
+func detectEncoding(content []byte) Encoding {
+	switch {
+	case bytes.HasPrefix(content, bomUTF8):
+		return EncodingUTF8BOM
+	case bytes.HasPrefix(content, bomUTF16LE):
+		return EncodingUTF16LE
+	case bytes.HasPrefix(content, bomUTF16BE):
+		return EncodingUTF16BE
+	case utf8.Valid(content):
+		return EncodingUTF8
+	}
+	return EncodingWindows1252
+}
+
+// transcode converts content in the given encoding to UTF-8 without a byte
+// order mark (internal helper)
+func transcode(content []byte, encoding Encoding) []byte {
+	switch encoding {
+	case EncodingUTF8BOM:
+		return content[len(bomUTF8):]
+	case EncodingUTF16LE, EncodingUTF16BE:
+		return decodeUTF16(content[2:], encoding == EncodingUTF16BE)
+	case EncodingWindows1252:
+		return decodeWindows1252(content)
+	}
+	return content
+}
+
+// decodeUTF16 converts UTF-16 content to UTF-8; a trailing odd byte and
+// unpaired surrogates become U+FFFD (internal helper)
+func decodeUTF16(content []byte, bigEndian bool) []byte {
+	units := make([]uint16, 0, len(content)/2)
+	for i := 0; i+1 < len(content); i += 2 {
+		if bigEndian {
+			units = append(units, uint16(content[i])<<8|uint16(content[i+1]))
+		} else {
+			units = append(units, uint16(content[i+1])<<8|uint16(content[i]))
+		}
+	}
+
+	out := make([]byte, 0, len(content))
+	for _, r := range utf16.Decode(units) {
+		out = utf8.AppendRune(out, r)
+	}
+	if len(content)%2 != 0 {
+		out = utf8.AppendRune(out, utf8.RuneError)
+	}
+	return out
+}
+
+// decodeWindows1252 converts Windows-1252 content to UTF-8 (internal helper)
+func decodeWindows1252(content []byte) []byte {
+	out := make([]byte, 0, len(content)+len(content)/4)
+	for _, b := range content {
+		switch {
+		case b < 0x80:
+			out = append(out, b)
+		case b < 0xA0:
+			out = utf8.AppendRune(out, windows1252High[b-0x80])
+		default:
+			out = utf8.AppendRune(out, rune(b))
+		}
+	}
+	return out
+}
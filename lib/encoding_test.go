@@ -0,0 +1,108 @@
+package handoff
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestDetectEncoding tests identifying encodings from byte order marks and validity
+func TestDetectEncoding(t *testing.T) {
+	testCases := []struct {
+		content string
+		want    Encoding
+	}{
+		{"plain", EncodingUTF8},
+		{"café", EncodingUTF8},
+		{"\xef\xbb\xbfbom", EncodingUTF8BOM},
+		{"\xff\xfeh\x00i\x00", EncodingUTF16LE},
+		{"\xfe\xff\x00h\x00i", EncodingUTF16BE},
+		{"caf\xe9", EncodingWindows1252},
+	}
+
+	for _, tc := range testCases {
+		if got := detectEncoding([]byte(tc.content)); got != tc.want {
+			t.Errorf("detectEncoding(%q) = %q, want %q", tc.content, got, tc.want)
+		}
+	}
+}
+
+// TestTranscode tests converting detected encodings to UTF-8
+func TestTranscode(t *testing.T) {
+	testCases := []struct {
+		content  string
+		encoding Encoding
+		want     string
+	}{
+		{"plain", EncodingUTF8, "plain"},
+		{"\xef\xbb\xbfbom", EncodingUTF8BOM, "bom"},
+		{"\xff\xfeh\x00i\x00\r\x00\n\x00", EncodingUTF16LE, "hi\r\n"},
+		{"\xfe\xff\x00h\xd8\x3d\xde\x00", EncodingUTF16BE, "h\U0001F600"},
+		{"\xff\xfeh\x00i", EncodingUTF16LE, "h�"},
+		{"caf\xe9 \x93quoted\x94 \x80", EncodingWindows1252, "café “quoted” €"},
+	}
+
+	for _, tc := range testCases {
+		if got := string(transcode([]byte(tc.content), tc.encoding)); got != tc.want {
+			t.Errorf("transcode(%q, %s) = %q, want %q", tc.content, tc.encoding, got, tc.want)
+		}
+	}
+}
+
+// TestTranscodeProcessing tests that encodings are reported per file and converted on request
+func TestTranscodeProcessing(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"plain.txt":  "plain text\n",
+		"legacy.txt": "caf\xe9\n",
+		"wide.txt":   "\xff\xfew\x00i\x00d\x00e\x00\r\x00\n\x00",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	// Without transcoding, UTF-16 is skipped and other text passes through
+	_, stats, err := ProcessProject([]string{dir}, NewConfig(WithGitClient(NewMockGitClient(false))))
+	if err != nil {
+		t.Fatalf("ProcessProject failed: %v", err)
+	}
+	if len(stats.Files) != 2 {
+		t.Fatalf("got %d files without transcoding, want 2", len(stats.Files))
+	}
+	for _, file := range stats.Files {
+		if file.Transcoded {
+			t.Errorf("%s transcoded without WithTranscode", file.Path)
+		}
+	}
+
+	content, stats, err := ProcessProject([]string{dir}, NewConfig(WithGitClient(NewMockGitClient(false)), WithTranscode(true)))
+	if err != nil {
+		t.Fatalf("ProcessProject failed: %v", err)
+	}
+	want := map[string]struct {
+		encoding   Encoding
+		transcoded bool
+	}{
+		"plain.txt":  {EncodingUTF8, false},
+		"legacy.txt": {EncodingWindows1252, true},
+		"wide.txt":   {EncodingUTF16LE, true},
+	}
+	if len(stats.Files) != len(want) {
+		t.Fatalf("got %d files with transcoding, want %d", len(stats.Files), len(want))
+	}
+	for _, file := range stats.Files {
+		w := want[filepath.Base(file.Path)]
+		if file.Encoding != w.encoding || file.Transcoded != w.transcoded {
+			t.Errorf("%s: encoding %q transcoded %v, want %q %v", file.Path, file.Encoding, file.Transcoded, w.encoding, w.transcoded)
+		}
+		if filepath.Base(file.Path) == "wide.txt" && file.LineEndings != LineEndingCRLF {
+			t.Errorf("wide.txt line endings = %q, want crlf", file.LineEndings)
+		}
+	}
+	if !strings.Contains(content, "café\n") || !strings.Contains(content, "wide\r\n") {
+		t.Errorf("expected transcoded content, got:\n%q", content)
+	}
+}
@@ -0,0 +1,120 @@
+package handoff
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+// versionFiles are files that pin a tool version, holding just the version
+var versionFiles = []string{
+	".nvmrc", ".node-version", ".python-version", ".ruby-version", ".java-version",
+	".terraform-version", ".bun-version", "rust-toolchain",
+}
+
+// WithEnvironmentInfo sets whether a section describing the environment is
+// appended: the operating system and architecture, the installed Go version,
+// and the tool versions the project pins in go.mod, .nvmrc, .tool-versions,
+// and similar files, since the version in use is the first thing a reader
+// needs to know when helping with a problem.
+func WithEnvironmentInfo(include bool) Option {
+	return func(c *Config) {
+		c.EnvironmentInfo = include
+	}
+}
+
+// environmentSection describes the environment and the tool versions pinned
+// at the top of each directory argument, or returns an empty string when the
+// section is disabled (internal helper)
+func environmentSection(paths []string, config *Config) string {
+	if !config.EnvironmentInfo {
+		return ""
+	}
+
+	lines := []string{"os/arch: " + runtime.GOOS + "/" + runtime.GOARCH}
+	if out, err := exec.Command("go", "env", "GOVERSION").Output(); err == nil {
+		lines = append(lines, "go: "+strings.TrimSpace(string(out)))
+	}
+
+	seen := make(map[string]bool)
+	for _, path := range paths {
+		dir := filepath.Clean(path)
+		if info, err := os.Stat(dir); err != nil || !info.IsDir() || seen[dir] {
+			continue
+		}
+		seen[dir] = true
+		lines = append(lines, pinnedVersions(dir)...)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// pinnedVersions lists the tool versions a project pins, one "file: versions"
+// line per file that pins any (internal helper)
+func pinnedVersions(dir string) []string {
+	var lines []string
+	add := func(name string, versions []string) {
+		if len(versions) > 0 {
+			lines = append(lines, filepath.Join(dir, name)+": "+strings.Join(versions, ", "))
+		}
+	}
+
+	// go.mod pins the language version and, optionally, a toolchain
+	add("go.mod", matchingLines(filepath.Join(dir, "go.mod"), func(line string) bool {
+		return strings.HasPrefix(line, "go ") || strings.HasPrefix(line, "toolchain ")
+	}))
+
+	for _, name := range versionFiles {
+		add(name, matchingLines(filepath.Join(dir, name), func(line string) bool {
+			return !strings.HasPrefix(line, "#")
+		}))
+	}
+
+	// .tool-versions (asdf, mise) lists a "tool version" pair per line
+	add(".tool-versions", matchingLines(filepath.Join(dir, ".tool-versions"), func(line string) bool {
+		return !strings.HasPrefix(line, "#")
+	}))
+
+	// package.json may declare the engines it supports
+	if content, err := os.ReadFile(filepath.Join(dir, "package.json")); err == nil {
+		var manifest struct {
+			Engines        map[string]string `json:"engines"`
+			PackageManager string            `json:"packageManager"`
+		}
+		if json.Unmarshal(content, &manifest) == nil {
+			var engines []string
+			for engine, version := range manifest.Engines {
+				engines = append(engines, engine+" "+version)
+			}
+			sort.Strings(engines)
+			if manifest.PackageManager != "" {
+				engines = append(engines, manifest.PackageManager)
+			}
+			add("package.json", engines)
+		}
+	}
+	return lines
+}
+
+// matchingLines returns the trimmed, non-empty lines of a file that match,
+// or nil if the file can't be read (internal helper)
+func matchingLines(path string, match func(line string) bool) []string {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer func() { _ = f.Close() }()
+
+	var lines []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" && match(line) {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
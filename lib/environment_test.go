@@ -0,0 +1,68 @@
+package handoff
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// TestPinnedVersions tests reading the tool versions a project pins
+func TestPinnedVersions(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod":         "module example.com/app\n\ngo 1.24.2\n\ntoolchain go1.24.3\n",
+		".nvmrc":         "20.11.0\n",
+		".tool-versions": "# asdf\nnodejs 20.11.0\npython 3.12.1\n",
+		"package.json":   `{"engines": {"node": ">=20", "npm": ">=10"}, "packageManager": "pnpm@9.0.0"}`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	want := []string{
+		filepath.Join(dir, "go.mod") + ": go 1.24.2, toolchain go1.24.3",
+		filepath.Join(dir, ".nvmrc") + ": 20.11.0",
+		filepath.Join(dir, ".tool-versions") + ": nodejs 20.11.0, python 3.12.1",
+		filepath.Join(dir, "package.json") + ": node >=20, npm >=10, pnpm@9.0.0",
+	}
+	got := pinnedVersions(dir)
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("pinnedVersions = %q, want %q", got, want)
+	}
+
+	if got := pinnedVersions(t.TempDir()); len(got) != 0 {
+		t.Errorf("pinnedVersions of an empty directory = %q, want none", got)
+	}
+}
+
+// TestEnvironmentSection tests appending the environment section to the output
+func TestEnvironmentSection(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		".python-version": "3.12\n",
+		"app.py":          "print('hi')\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	config := NewConfig(WithGitClient(NewMockGitClient(false)), WithHiddenAllowlist(".python-version"), WithEnvironmentInfo(true))
+	content, _, err := ProcessProject([]string{dir}, config)
+	if err != nil {
+		t.Fatalf("ProcessProject failed: %v", err)
+	}
+	for _, want := range []string{
+		"<environment>\nos/arch: " + runtime.GOOS + "/" + runtime.GOARCH + "\n",
+		filepath.Join(dir, ".python-version") + ": 3.12\n</environment>",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("content is missing %q:\n%s", want, content)
+		}
+	}
+}
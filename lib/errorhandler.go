@@ -0,0 +1,65 @@
+package handoff
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrAborted is returned, wrapping the failure, when an ErrorHandler aborts a run
+var ErrAborted = errors.New("aborted by error handler")
+
+// ErrorAction tells a run what to do about a failure to stat or read a path
+type ErrorAction int
+
+const (
+	// ErrorSkip leaves the path out with a warning, as runs do without a handler
+	ErrorSkip ErrorAction = iota
+
+	// ErrorRetry tries the failed operation again
+	ErrorRetry
+
+	// ErrorAbort stops the run, which returns an error wrapping ErrAborted and
+	// the failure
+	ErrorAbort
+)
+
+// ErrorHandler decides what to do about a failure to stat or read path, such
+// as a permission error or a flaky network mount. The path is shown as in
+// Stats and hooks.
+type ErrorHandler func(path string, err error) ErrorAction
+
+// WithErrorHandler sets a handler that decides, for each failure to stat or
+// read a path, whether to skip the path, retry, or abort the whole run. An
+// embedder can retry transient errors on a network mount a few times, or
+// abort rather than produce output silently missing a file. The handler is
+// called again each time a retry fails, so it should stop retrying after some
+// attempts. Standard input can't be read again, so retrying it skips it.
+// Without a handler, every failure is skipped with a warning.
+func WithErrorHandler(handler ErrorHandler) Option {
+	return func(c *Config) {
+		c.ErrorHandler = handler
+	}
+}
+
+// attempt runs op, asking the error handler what to do when it fails, until it
+// succeeds or the handler skips or aborts. It returns the last failure, if
+// any, and whether to abort. (internal helper)
+func (c *Config) attempt(path string, op func() error) (abort bool, err error) {
+	for {
+		if err = op(); err == nil || c.ErrorHandler == nil {
+			return false, err
+		}
+		switch c.ErrorHandler(c.displayPath(path), err) {
+		case ErrorRetry:
+			continue
+		case ErrorAbort:
+			return true, err
+		}
+		return false, err
+	}
+}
+
+// abortRun returns the error a run stops with when the error handler aborts it (internal helper)
+func abortRun(err error) error {
+	return fmt.Errorf("%w: %w", ErrAborted, err)
+}
@@ -0,0 +1,90 @@
+package handoff
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestWithErrorHandler tests skipping, retrying, and aborting on failures
+func TestWithErrorHandler(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	late := filepath.Join(dir, "late.go")
+	paths := []string{filepath.Join(dir, "main.go"), late}
+
+	// Skipping leaves the path out, as without a handler
+	var failed []string
+	config := NewConfig(WithGitClient(NewMockGitClient(false)), WithErrorHandler(func(path string, err error) ErrorAction {
+		failed = append(failed, path)
+		return ErrorSkip
+	}))
+	_, stats, err := ProcessProject(paths, config)
+	if err != nil {
+		t.Fatalf("ProcessProject failed: %v", err)
+	}
+	if stats.FilesProcessed != 1 || len(failed) != 1 || failed[0] != late {
+		t.Errorf("FilesProcessed = %d, failures = %v; want 1 and [%s]", stats.FilesProcessed, failed, late)
+	}
+
+	// Aborting stops the run with the failure
+	config = NewConfig(WithGitClient(NewMockGitClient(false)), WithErrorHandler(func(path string, err error) ErrorAction {
+		return ErrorAbort
+	}))
+	if _, _, err := ProcessProject(paths, config); !errors.Is(err, ErrAborted) || !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("ProcessProject() error = %v, want ErrAborted wrapping the failure", err)
+	}
+
+	// Retrying succeeds once the file appears
+	attempts := 0
+	config = NewConfig(WithGitClient(NewMockGitClient(false)), WithErrorHandler(func(path string, err error) ErrorAction {
+		attempts++
+		if attempts == 2 {
+			if err := os.WriteFile(late, []byte("package late\n"), 0644); err != nil {
+				t.Fatalf("Failed to write file: %v", err)
+			}
+		}
+		return ErrorRetry
+	}))
+	content, stats, err := ProcessProject(paths, config)
+	if err != nil {
+		t.Fatalf("ProcessProject failed: %v", err)
+	}
+	if attempts != 2 || stats.FilesProcessed != 2 || !strings.Contains(content, "package late") {
+		t.Errorf("attempts = %d, FilesProcessed = %d; want 2 and 2", attempts, stats.FilesProcessed)
+	}
+}
+
+// TestErrorHandlerStdin tests that a failed read of standard input is not retried
+func TestErrorHandlerStdin(t *testing.T) {
+	calls := 0
+	config := NewConfig(
+		WithGitClient(NewMockGitClient(false)),
+		WithStdin(failingReader{}, ""),
+		WithErrorHandler(func(path string, err error) ErrorAction {
+			calls++
+			return ErrorRetry
+		}),
+	)
+	_, _, _ = ProcessProject([]string{StdinPath}, config)
+	if calls != 1 {
+		t.Errorf("handler called %d times, want once", calls)
+	}
+
+	config.ErrorHandler = func(path string, err error) ErrorAction { return ErrorAbort }
+	if _, _, err := ProcessProject([]string{StdinPath}, config); !errors.Is(err, ErrAborted) {
+		t.Errorf("ProcessProject() error = %v, want ErrAborted", err)
+	}
+}
+
+// failingReader is an io.Reader that always fails
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error) {
+	return 0, errors.New("read failed")
+}
@@ -0,0 +1,251 @@
+package handoff
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// FileFilter decides whether a file is processed, for policies beyond the
+// built-in extension and name filters, such as "no files under secrets/" or
+// "no .sql files". ShouldProcess returns false with a short reason to skip the
+// file; the reason is reported as a SkipReason. Filters are consulted only for files that pass the built-in filters,
+// before their content is read, and must be safe for concurrent use.
+type FileFilter interface {
+	ShouldProcess(path string, info fs.FileInfo) (bool, string)
+}
+
+// FileFilterFunc adapts an ordinary function to the FileFilter interface.
+type FileFilterFunc func(path string, info fs.FileInfo) (bool, string)
+
+// ShouldProcess calls f(path, info).
+func (f FileFilterFunc) ShouldProcess(path string, info fs.FileInfo) (bool, string) {
+	return f(path, info)
+}
+
+// WithFileFilter adds a custom filter composed with the built-in ones: a file
+// is processed only if it passes the built-in filters and every custom filter,
+// which run in the order they are added.
+func WithFileFilter(filter FileFilter) Option {
+	return func(c *Config) {
+		if filter != nil {
+			c.fileFilters = append(c.fileFilters, filter)
+		}
+	}
+}
+
+// customFilterReason runs the custom filters over a file and returns the reason
+// the first rejecting filter gave, or SkipFiltered if it gave none. It returns
+// an empty reason if every filter accepts the file. The file is stat'ed only when
+// info is nil and a filter is configured (internal helper).
+func customFilterReason(filePath string, info fs.FileInfo, filters []FileFilter) SkipReason {
+	if len(filters) == 0 {
+		return ""
+	}
+	if info == nil {
+		info, _ = os.Stat(filePath)
+	}
+	for _, filter := range filters {
+		if ok, reason := filter.ShouldProcess(filePath, info); !ok {
+			if reason == "" {
+				return SkipFiltered
+			}
+			return SkipReason(reason)
+		}
+	}
+	return ""
+}
+
+// matchesExcludeName reports whether a file's base name matches any exclude-names
+// entry (internal helper). Entries may contain glob wildcards understood by
+// filepath.Match (e.g., "*.generated.ts" or "*_mock.go"); malformed patterns are
+// compared literally.
+func matchesExcludeName(base string, names []string) bool {
+	for _, name := range names {
+		if name == base {
+			return true
+		}
+		if matched, err := filepath.Match(name, base); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
+
+// shouldProcess decides if a file should be processed based on all filters (internal helper)
+func shouldProcess(file string, config *Config) bool {
+	base := filepath.Base(file)
+	ext := strings.ToLower(filepath.Ext(file))
+
+	// Check exclude names filter
+	if matchesExcludeName(base, config.excludeNames) {
+		return false
+	}
+
+	// Path-scoped rules replace the global extension filters for files under them
+	includeExts, excludeExts := config.includeExts, config.excludeExts
+	if rule := matchPathRule(file, config.pathRules); rule != nil {
+		includeExts, excludeExts = rule.Include, rule.Exclude
+	}
+
+	// Extensionless scripts are filtered by the language named in their shebang line
+	if ext == "" && (len(includeExts) > 0 || len(excludeExts) > 0) {
+		ext = detectShebangExt(file)
+	}
+
+	// Check include extensions filter
+	if len(includeExts) > 0 {
+		included := false
+		for _, includeExt := range includeExts {
+			if ext == includeExt {
+				included = true
+				break
+			}
+		}
+		if !included {
+			return false
+		}
+	}
+
+	// Check exclude extensions filter
+	if len(excludeExts) > 0 {
+		for _, excludeExt := range excludeExts {
+			if ext == excludeExt {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// WithInclude specifies file extensions to include.
+// Extensions can be provided with or without dots (e.g., ".go,.md" or "go,md").
+// Files without an extension are matched by the interpreter in their shebang line,
+// so ".sh" also includes a script like bin/deploy starting with "#!/usr/bin/env bash".
+func WithInclude(include string) Option {
+	return func(c *Config) {
+		c.include = include
+		c.includeExts = processExtensions(include)
+	}
+}
+
+// WithExclude specifies file extensions to exclude.
+// Extensions can be provided with or without dots (e.g., ".exe,.bin" or "exe,bin").
+func WithExclude(exclude string) Option {
+	return func(c *Config) {
+		c.exclude = exclude
+		c.excludeExts = processExtensions(exclude)
+	}
+}
+
+// WithExcludeNames specifies file names to exclude.
+// Names may contain glob wildcards matched against the file's base name (e.g., "*_mock.go").
+func WithExcludeNames(excludeNames string) Option {
+	return func(c *Config) {
+		c.excludeNamesStr = excludeNames
+		c.excludeNames = processNames(excludeNames)
+	}
+}
+
+// WithHiddenAllowlist specifies hidden file and directory names (starting with a dot)
+// that should be processed even though hidden paths are skipped by default,
+// e.g., ".github,.golangci.yml". Allowlisted paths remain subject to gitignore rules.
+func WithHiddenAllowlist(names string) Option {
+	return func(c *Config) {
+		c.hiddenAllowlist = processNames(names)
+	}
+}
+
+// WithIncludeContentRegex restricts processing to files whose content matches the
+// regular expression, e.g., to collect every file that references a given symbol.
+// The filter is applied after a file is read and before it is formatted.
+// A nil regexp disables the filter.
+func WithIncludeContentRegex(re *regexp.Regexp) Option {
+	return func(c *Config) {
+		c.includeContent = re
+	}
+}
+
+// WithIgnoreGitignore sets whether to ignore gitignore rules.
+func WithIgnoreGitignore(ignoreGitignore bool) Option {
+	return func(c *Config) {
+		c.IgnoreGitignore = ignoreGitignore
+	}
+}
+
+// WithIgnoreGitattributes sets whether to process files that .gitattributes marks
+// as linguist-generated or linguist-vendored.
+func WithIgnoreGitattributes(ignoreGitattributes bool) Option {
+	return func(c *Config) {
+		c.IgnoreGitattributes = ignoreGitattributes
+	}
+}
+
+// Helper function to process comma-separated extensions
+func processExtensions(exts string) []string {
+	if exts == "" {
+		return nil
+	}
+
+	result := []string{}
+	for _, ext := range strings.Split(exts, ",") {
+		ext = strings.TrimSpace(ext)
+		if !strings.HasPrefix(ext, ".") {
+			ext = "." + ext
+		}
+		result = append(result, ext)
+	}
+	return result
+}
+
+// Helper function to process comma-separated names
+func processNames(names string) []string {
+	if names == "" {
+		return nil
+	}
+
+	result := []string{}
+	for _, name := range strings.Split(names, ",") {
+		result = append(result, strings.TrimSpace(name))
+	}
+	return result
+}
+
+// Filters describes the extension, name, and path filters a Config applies to
+// discovered files
+type Filters struct {
+	// Include lists the extensions to include; empty includes all extensions
+	Include []string
+
+	// Exclude lists the extensions to exclude
+	Exclude []string
+
+	// ExcludeNames lists the file names and glob patterns to exclude
+	ExcludeNames []string
+
+	// HiddenAllowlist lists the hidden file and directory names to process
+	HiddenAllowlist []string
+
+	// PathRules are the path-scoped overrides of Include and Exclude
+	PathRules []PathRule
+}
+
+// Filters returns copies of the filters the Config applies, such as to show
+// the effective settings after options, config files, and flags are combined.
+func (c *Config) Filters() Filters {
+	c.ProcessConfig()
+
+	configMu.Lock()
+	defer configMu.Unlock()
+	return Filters{
+		Include:         slices.Clone(c.includeExts),
+		Exclude:         slices.Clone(c.excludeExts),
+		ExcludeNames:    slices.Clone(c.excludeNames),
+		HiddenAllowlist: slices.Clone(c.hiddenAllowlist),
+		PathRules:       slices.Clone(c.pathRules),
+	}
+}
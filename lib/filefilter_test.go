@@ -0,0 +1,96 @@
+package handoff
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestFileFilter tests that custom filters compose with the built-in filters
+func TestFileFilter(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "secrets"), 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	files := []string{"main.go", "schema.sql", "notes.txt", filepath.Join("secrets", "key.go")}
+	for _, name := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("content of "+name+"\n"), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	var seenInfo bool
+	noSecrets := FileFilterFunc(func(path string, info fs.FileInfo) (bool, string) {
+		seenInfo = seenInfo || info != nil
+		if strings.Contains(filepath.ToSlash(path), "/secrets/") {
+			return false, "secret"
+		}
+		return true, ""
+	})
+	noSQL := FileFilterFunc(func(path string, _ fs.FileInfo) (bool, string) {
+		return filepath.Ext(path) != ".sql", ""
+	})
+
+	skipped := make(map[string]SkipReason)
+	config := NewConfig(
+		WithGitClient(NewMockGitClient(false)),
+		WithExclude(".txt"),
+		WithFileFilter(noSecrets),
+		WithFileFilter(noSQL),
+		WithHooks(Hooks{OnFileSkipped: func(path string, reason SkipReason) { skipped[filepath.Base(path)] = reason }}),
+	)
+
+	content, stats, err := ProcessProject([]string{dir}, config)
+	if err != nil {
+		t.Fatalf("ProcessProject failed: %v", err)
+	}
+	if stats.FilesProcessed != 1 || !strings.Contains(content, "content of main.go") {
+		t.Errorf("expected only main.go, got %d files:\n%s", stats.FilesProcessed, content)
+	}
+	want := map[string]SkipReason{"key.go": "secret", "schema.sql": SkipFiltered, "notes.txt": SkipFiltered}
+	for name, reason := range want {
+		if skipped[name] != reason {
+			t.Errorf("%s skipped with reason %q, want %q", name, skipped[name], reason)
+		}
+	}
+	if !seenInfo {
+		t.Errorf("expected filters to receive file info")
+	}
+
+	discovered, err := DiscoverFiles([]string{dir}, config)
+	if err != nil {
+		t.Fatalf("DiscoverFiles failed: %v", err)
+	}
+	if len(discovered) != 1 || filepath.Base(discovered[0]) != "main.go" {
+		t.Errorf("DiscoverFiles = %v, want only main.go", discovered)
+	}
+}
+
+// TestFileFilterRunsOnce tests that each file is filtered once per run, even
+// when duplicate detection, sampling, and query ranking all need the result
+func TestFileFilterRunsOnce(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"cache.go", "server.go", "skip.go"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("// cache "+name+"\n"), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	calls := make(map[string]int)
+	counting := FileFilterFunc(func(path string, info fs.FileInfo) (bool, string) {
+		calls[filepath.Base(path)]++
+		return filepath.Base(path) != "skip.go", "skipped"
+	})
+	config := NewConfig(WithGitClient(NewMockGitClient(false)), WithFileFilter(counting),
+		WithDirectoryCap(5, SampleFirst), WithQuery("cache", 5))
+	if _, _, err := ProcessProject([]string{dir}, config); err != nil {
+		t.Fatalf("ProcessProject failed: %v", err)
+	}
+	for _, name := range []string{"cache.go", "server.go", "skip.go"} {
+		if calls[name] != 1 {
+			t.Errorf("filter calls for %s = %d, want 1", name, calls[name])
+		}
+	}
+}
@@ -0,0 +1,205 @@
+package handoff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultFormat is the default template used by TemplateFormatter.
+// It wraps each file's content in a code fence surrounded by path tags.
+const DefaultFormat = "<{path}>\n```\n{content}\n```\n</{path}>\n\n"
+
+// FileInfo describes a file whose content is being formatted.
+type FileInfo struct {
+	// Path is the path of the file as it was discovered
+	Path string
+
+	// Root is the label of the directory the file belongs to, set with
+	// WithRootLabel; empty when no labeled directory holds it
+	Root string
+
+	// Status is the file's git status, set with WithGitStatus; empty when
+	// disabled or the file is outside a repository
+	Status FileStatus
+
+	// Checksum is the SHA-256 digest of the content as "sha256:" and hex
+	// digits, set with WithChecksums; empty when disabled
+	Checksum string
+
+	// Size is the size of the file content in bytes
+	Size int64
+}
+
+// Formatter controls how processed files are rendered into the final output.
+// Implementations can produce alternative output formats such as JSON, Markdown,
+// or XML and are installed with the WithFormatter option.
+type Formatter interface {
+	// FormatFile renders a single file's content
+	FormatFile(info FileInfo, content []byte) string
+
+	// Wrap renders the final output from the concatenated formatted files
+	Wrap(body string) string
+}
+
+// TemplateFormatter is the default Formatter implementation.
+// It renders each file by substituting placeholders in its Format template and
+// wraps the combined output in context tags. Supported placeholders are:
+//   - {path}: the file's path
+//   - {content}: the file's content
+//   - {lang}: the file's language for a code fence info string (e.g., "go"), if known
+//   - {fence}: a backtick fence longer than any backtick run in the content
+//   - {root}: the label of the project the file belongs to (see WithRootLabel), if any
+//   - {status}: the file's git status (see WithGitStatus), if known
+//   - {checksum}: the SHA-256 digest of the content (see WithChecksums), if enabled
+//
+// Placeholders take modifiers after colons, such as {path:base},
+// {content:trim}, or {content:trim:indent=2}: trim, indent=N, base, dir, ext,
+// upper, and lower. Doubled braces are literal, so "{{path}}" renders as
+// "{path}"; other braced text, including unknown modifiers, is kept as written.
+type TemplateFormatter struct {
+	// Format is the template string applied to each file
+	Format string
+}
+
+// NewTemplateFormatter creates a TemplateFormatter using the given template.
+// An empty template falls back to DefaultFormat.
+func NewTemplateFormatter(format string) *TemplateFormatter {
+	if format == "" {
+		format = DefaultFormat
+	}
+	return &TemplateFormatter{Format: format}
+}
+
+// FormatFile substitutes the file's path and content into the template.
+func (f *TemplateFormatter) FormatFile(info FileInfo, content []byte) string {
+	return expandPlaceholders(f.Format, func(name string) (string, bool) {
+		switch name {
+		case "path":
+			return info.Path, true
+		case "content":
+			return string(content), true
+		case "lang":
+			return fenceLanguage(info.Path, content), true
+		case "fence":
+			return codeFence(content), true
+		case "root":
+			return info.Root, true
+		case "status":
+			return string(info.Status), true
+		case "checksum":
+			return info.Checksum, true
+		}
+		return "", false
+	})
+}
+
+// Wrap wraps the body in top-level context tags using WrapInContext.
+func (f *TemplateFormatter) Wrap(body string) string {
+	return WrapInContext(body)
+}
+
+// wrapWithAttrs wraps the body in context tags carrying attrs.
+func (f *TemplateFormatter) wrapWithAttrs(body string, attrs []ContextAttr) string {
+	return WrapInContext(body, attrs...)
+}
+
+// tokenAnnotator is implemented by formatters that can note a file's estimated
+// tokens after its block (see WithTokenAnnotations)
+type tokenAnnotator interface {
+	tokenAnnotation(tokens int) string
+}
+
+// tokenAnnotation returns an HTML comment, which Markdown and XML readers
+// both accept, giving the file's estimated tokens.
+func (f *TemplateFormatter) tokenAnnotation(tokens int) string {
+	return fmt.Sprintf("<!-- ~%d tokens -->", tokens)
+}
+
+// headerAnnotator is implemented by formatters that can note a file's git
+// status and checksum before its block (see WithGitStatus and WithChecksums)
+type headerAnnotator interface {
+	headerAnnotations(info FileInfo) []string
+}
+
+// headerAnnotations returns HTML comments giving the file's git status and
+// checksum, leaving out those the template places itself with {status} or
+// {checksum}.
+func (f *TemplateFormatter) headerAnnotations(info FileInfo) []string {
+	var annotations []string
+	if info.Status != "" && !strings.Contains(f.Format, "{status") {
+		annotations = append(annotations, fmt.Sprintf("<!-- git: %s -->", info.Status))
+	}
+	if info.Checksum != "" && !strings.Contains(f.Format, "{checksum") {
+		annotations = append(annotations, fmt.Sprintf("<!-- %s -->", info.Checksum))
+	}
+	return annotations
+}
+
+// formatFile renders a file with the formatter, preceded by the formatter's
+// header annotations, if it has any (internal helper)
+func formatFile(formatter Formatter, info FileInfo, content []byte) string {
+	output := formatter.FormatFile(info, content)
+	if annotator, ok := formatter.(headerAnnotator); ok {
+		if annotations := annotator.headerAnnotations(info); len(annotations) > 0 {
+			output = strings.Join(annotations, "\n") + "\n" + output
+		}
+	}
+	return output
+}
+
+// appendAnnotation inserts an annotation line after a formatted file's block,
+// ahead of the blank lines separating it from the next file (internal helper)
+func appendAnnotation(output, annotation string) string {
+	block := strings.TrimRight(output, "\n")
+	separator := output[len(block):]
+	if separator == "" {
+		separator = "\n"
+	}
+	return block + "\n" + annotation + separator
+}
+
+// WithFormatter sets a custom Formatter implementation.
+// When no formatter is set, a TemplateFormatter built from Config.Format is used.
+func WithFormatter(formatter Formatter) Option {
+	return func(c *Config) {
+		c.Formatter = formatter
+	}
+}
+
+// builtinFormatters are the formatters LookupFormatter can create by name
+var builtinFormatters = []struct {
+	name string
+	new  func() Formatter
+}{
+	{"html", func() Formatter { return NewHTMLFormatter() }},
+	{"jsonl", func() Formatter { return NewJSONLFormatter() }},
+}
+
+// LookupFormatter returns a new built-in formatter with the given name,
+// ignoring case: "html" for NewHTMLFormatter or "jsonl" for NewJSONLFormatter.
+func LookupFormatter(name string) (Formatter, error) {
+	for _, f := range builtinFormatters {
+		if strings.EqualFold(f.name, name) {
+			return f.new(), nil
+		}
+	}
+	return nil, fmt.Errorf("unknown output format %q (known formats: %s)", name, strings.Join(FormatterNames(), ", "))
+}
+
+// FormatterNames returns the names accepted by LookupFormatter.
+func FormatterNames() []string {
+	names := make([]string, len(builtinFormatters))
+	for i, f := range builtinFormatters {
+		names[i] = f.name
+	}
+	return names
+}
+
+// formatter returns the configured Formatter, falling back to a
+// TemplateFormatter using the config's Format template. (internal helper)
+func (c *Config) formatter() Formatter {
+	if c.Formatter != nil {
+		return c.Formatter
+	}
+	return NewTemplateFormatter(c.Format)
+}
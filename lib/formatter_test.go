@@ -0,0 +1,276 @@
+package handoff
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// upperFormatter is a test Formatter that renders paths and wraps output in custom markers
+type upperFormatter struct{}
+
+func (upperFormatter) FormatFile(info FileInfo, content []byte) string {
+	return fmt.Sprintf("FILE %s (%d bytes)\n%s\n", strings.ToUpper(filepath.Base(info.Path)), info.Size, content)
+}
+
+func (upperFormatter) Wrap(body string) string {
+	return "BEGIN\n" + body + "END"
+}
+
+// TestTemplateFormatter tests the default template-based formatter
+func TestTemplateFormatter(t *testing.T) {
+	testCases := []struct {
+		name    string
+		format  string
+		info    FileInfo
+		content string
+		want    string
+	}{
+		{
+			name:    "Default format",
+			format:  DefaultFormat,
+			info:    FileInfo{Path: "main.go"},
+			content: "package main",
+			want:    "<main.go>\n```\npackage main\n```\n</main.go>\n\n",
+		},
+		{
+			name:    "Empty format falls back to default",
+			format:  "",
+			info:    FileInfo{Path: "a.txt"},
+			content: "x",
+			want:    "<a.txt>\n```\nx\n```\n</a.txt>\n\n",
+		},
+		{
+			name:    "Custom format",
+			format:  "## {path}\n{content}\n",
+			info:    FileInfo{Path: "b.md"},
+			content: "hello",
+			want:    "## b.md\nhello\n",
+		},
+		{
+			name:    "Language and fence placeholders",
+			format:  "{fence}{lang}\n{content}\n{fence}\n",
+			info:    FileInfo{Path: "main.go"},
+			content: "// see ```example```",
+			want:    "````go\n// see ```example```\n````\n",
+		},
+		{
+			name:    "Language from shebang",
+			format:  "{lang}",
+			info:    FileInfo{Path: "bin/deploy"},
+			content: "#!/usr/bin/env python3\n",
+			want:    "python",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			formatter := NewTemplateFormatter(tc.format)
+			if got := formatter.FormatFile(tc.info, []byte(tc.content)); got != tc.want {
+				t.Errorf("FormatFile() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+
+	if got := NewTemplateFormatter("").Wrap("body\n"); got != WrapInContext("body\n") {
+		t.Errorf("Wrap() = %q, want %q", got, WrapInContext("body\n"))
+	}
+}
+
+// TestWithFormatter tests that a custom Formatter is used by ProcessProject
+func TestWithFormatter(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "handoff-formatter-test-")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer func() {
+		if cleanErr := os.RemoveAll(tmpDir); cleanErr != nil {
+			t.Logf("Failed to clean up temp directory: %v", cleanErr)
+		}
+	}()
+
+	filePath := filepath.Join(tmpDir, "notes.txt")
+	if err := os.WriteFile(filePath, []byte("some notes"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	config := NewConfig(WithFormatter(upperFormatter{}), WithGitClient(NewMockGitClient(false)))
+	content, _, err := ProcessProject([]string{filePath}, config)
+	if err != nil {
+		t.Fatalf("ProcessProject failed: %v", err)
+	}
+
+	want := "BEGIN\nFILE NOTES.TXT (10 bytes)\nsome notes\nEND"
+	if content != want {
+		t.Errorf("ProcessProject() content = %q, want %q", content, want)
+	}
+}
+
+// TestWrapInContextAttrs tests writing escaped attributes on the context tag
+func TestWrapInContextAttrs(t *testing.T) {
+	got := WrapInContext("body\n", ContextAttr{Name: "files", Value: "2"}, ContextAttr{Name: "title", Value: `a "b" & <c>`})
+	want := "<context files=\"2\" title=\"a &#34;b&#34; &amp; &lt;c&gt;\">\nbody\n</context>"
+	if got != want {
+		t.Errorf("WrapInContext() = %q, want %q", got, want)
+	}
+}
+
+// TestWithContextAttributes tests that ProcessProject adds summary attributes
+// to the wrapper tag of template and style formatters only
+func TestWithContextAttributes(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	claude, err := LookupStyle("claude")
+	if err != nil {
+		t.Fatalf("LookupStyle failed: %v", err)
+	}
+
+	testCases := []struct {
+		name    string
+		option  Option
+		wantTag string
+	}{
+		{name: "Template", option: WithFormat(""), wantTag: "<context "},
+		{name: "Style", option: WithStyle(claude), wantTag: "<documents "},
+		{name: "Custom formatter", option: WithFormatter(upperFormatter{})},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := NewConfig(WithGitClient(NewMockGitClient(false)), tc.option, WithContextAttributes(true))
+			content, stats, err := ProcessProject([]string{dir}, config)
+			if err != nil {
+				t.Fatalf("ProcessProject failed: %v", err)
+			}
+
+			if tc.wantTag == "" {
+				if strings.Contains(content, "generated=") {
+					t.Errorf("custom formatter output has attributes: %q", content)
+				}
+				return
+			}
+			prefix := fmt.Sprintf("%sfiles=\"1\" tokens=\"%d\" generated=\"", tc.wantTag, stats.Tokens)
+			if !strings.HasPrefix(content, prefix) {
+				t.Fatalf("content = %q, want prefix %q", content, prefix)
+			}
+			generated, _, _ := strings.Cut(content[len(prefix):], "\"")
+			if _, err := time.Parse(time.RFC3339, generated); err != nil || !strings.HasSuffix(generated, "Z") {
+				t.Errorf("generated = %q, want an RFC 3339 UTC time", generated)
+			}
+		})
+	}
+}
+
+// TestAppendAnnotation tests placing annotations ahead of the separator between files
+func TestAppendAnnotation(t *testing.T) {
+	testCases := []struct {
+		output string
+		want   string
+	}{
+		{"</a.go>\n\n", "</a.go>\n<!-- x -->\n\n"},
+		{"body\n", "body\n<!-- x -->\n"},
+		{"body", "body\n<!-- x -->\n"},
+	}
+
+	for _, tc := range testCases {
+		if got := appendAnnotation(tc.output, "<!-- x -->"); got != tc.want {
+			t.Errorf("appendAnnotation(%q) = %q, want %q", tc.output, got, tc.want)
+		}
+	}
+}
+
+// TestWithTokenAnnotations tests that each file's block is followed by its
+// token estimate and that the statistics include the annotations
+func TestWithTokenAnnotations(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"a.go": "package a\n",
+		"b.go": strings.Repeat("word ", 50) + "\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	config := NewConfig(WithGitClient(NewMockGitClient(false)), WithTokenAnnotations(true))
+	content, stats, err := ProcessProject([]string{dir}, config)
+	if err != nil {
+		t.Fatalf("ProcessProject failed: %v", err)
+	}
+
+	for _, name := range []string{"a.go", "b.go"} {
+		path := filepath.Join(dir, name)
+		block := NewTemplateFormatter("").FormatFile(FileInfo{Path: path}, []byte(files[name]))
+		want := strings.TrimRight(block, "\n") + fmt.Sprintf("\n<!-- ~%d tokens -->\n\n", estimateTokenCount(block))
+		if !strings.Contains(content, want) {
+			t.Errorf("content missing annotated block %q:\n%s", want, content)
+		}
+	}
+	if _, _, tokens := CalculateStatistics(strings.TrimSuffix(strings.TrimPrefix(content, "<context>\n"), "</context>")); tokens != stats.Tokens {
+		t.Errorf("stats.Tokens = %d, want %d", stats.Tokens, tokens)
+	}
+
+	// Custom formatters are left alone
+	config = NewConfig(WithGitClient(NewMockGitClient(false)), WithTokenAnnotations(true), WithFormatter(upperFormatter{}))
+	content, _, err = ProcessProject([]string{dir}, config)
+	if err != nil {
+		t.Fatalf("ProcessProject failed: %v", err)
+	}
+	if strings.Contains(content, "tokens -->") {
+		t.Errorf("custom formatter output was annotated:\n%s", content)
+	}
+}
+
+// TestTokenAnnotationsAfterTrim tests that shortened files are annotated with their new size
+func TestTokenAnnotationsAfterTrim(t *testing.T) {
+	dir := t.TempDir()
+	content := strings.Repeat("some words on a line\n", 40)
+	if err := os.WriteFile(filepath.Join(dir, "long.txt"), []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	config := NewConfig(WithGitClient(NewMockGitClient(false)), WithTokenAnnotations(true),
+		WithMaxTokens(60), WithTrimStrategy(TrimTailTruncate))
+	output, stats, err := ProcessProject([]string{dir}, config)
+	if err != nil {
+		t.Fatalf("ProcessProject failed: %v", err)
+	}
+	if stats.FilesTrimmed != 1 || strings.Count(output, "tokens -->") != 1 {
+		t.Fatalf("expected one shortened, annotated file, got %d trimmed:\n%s", stats.FilesTrimmed, output)
+	}
+	if stats.Tokens > 60 {
+		t.Errorf("stats.Tokens = %d, want at most 60", stats.Tokens)
+	}
+	annotation := fmt.Sprintf("<!-- ~%d tokens -->", stats.Tokens-4)
+	if !strings.Contains(output, annotation) {
+		t.Errorf("output missing %q:\n%s", annotation, output)
+	}
+}
+
+// TestLookupFormatter tests looking up built-in formatters by name
+func TestLookupFormatter(t *testing.T) {
+	formatter, err := LookupFormatter("HTML")
+	if err != nil {
+		t.Fatalf("LookupFormatter(HTML) failed: %v", err)
+	}
+	if _, ok := formatter.(*HTMLFormatter); !ok {
+		t.Errorf("LookupFormatter(HTML) = %T, want *HTMLFormatter", formatter)
+	}
+	if formatter, err = LookupFormatter("jsonl"); err != nil {
+		t.Fatalf("LookupFormatter(jsonl) failed: %v", err)
+	}
+	if _, ok := formatter.(*JSONLFormatter); !ok {
+		t.Errorf("LookupFormatter(jsonl) = %T, want *JSONLFormatter", formatter)
+	}
+
+	_, err = LookupFormatter("xml")
+	if err == nil || !strings.Contains(err.Error(), "html, jsonl") {
+		t.Errorf("LookupFormatter(xml) error = %v, want one listing the known formats", err)
+	}
+}
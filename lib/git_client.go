@@ -4,7 +4,9 @@ import (
 	"fmt"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 )
 
 // GitClient is an interface that abstracts git operations needed by the handoff package.
@@ -19,6 +21,92 @@ type GitClient interface {
 
 	// GetGitFiles retrieves files from a directory using git ls-files
 	GetGitFiles(dir string) ([]string, error)
+
+	// ChangedFiles retrieves files in a directory that differ from the base revision.
+	// See StagedBase for the meaning of special base values.
+	ChangedFiles(dir, base string) ([]string, error)
+
+	// Diff returns a unified diff of a directory against the base revision.
+	// See StagedBase for the meaning of special base values.
+	Diff(dir, base string) (string, error)
+}
+
+// UntrackedLister is an optional interface a GitClient can implement to list
+// new files for ChangedPaths and WithGitStatus. With a client that doesn't
+// implement it, ChangedPaths leaves out untracked files and WithGitStatus
+// marks no files, since untracked files can't be told apart from clean ones.
+type UntrackedLister interface {
+	// UntrackedFiles retrieves files in a directory that git doesn't track and
+	// doesn't ignore
+	UntrackedFiles(dir string) ([]string, error)
+}
+
+// CommitLister is an optional interface a GitClient can implement to list the
+// commits for the history section requested with WithGitLog. With a client
+// that doesn't implement it, the section is left out.
+type CommitLister interface {
+	// RecentCommits returns a summary of the last n commits touching the given paths,
+	// one commit per line, optionally followed by change statistics
+	RecentCommits(dir string, paths []string, n int, stat bool) (string, error)
+}
+
+// CommitDater is an optional interface a GitClient can implement to date files
+// by their last commit for WithModifiedAfter and SampleNewest, since checkouts
+// reset modification times. With a client that doesn't implement it, files are
+// dated by their modification time.
+type CommitDater interface {
+	// LastCommitTime returns the committer date of the last commit that changed a file.
+	// Files without commits, such as untracked files, return the zero time.
+	LastCommitTime(file string) (time.Time, error)
+}
+
+// AuthorLister is an optional interface a GitClient can implement to read who
+// wrote a file for WithAuthor. With a client that doesn't implement it, no
+// file matches an author.
+type AuthorLister interface {
+	// CommitAuthors returns the author of each commit that changed a file, newest
+	// first, as "Name <email>". Files without commits return no authors.
+	CommitAuthors(file string) ([]string, error)
+}
+
+// ChurnCounter is an optional interface a GitClient can implement to measure
+// how often files change for OrderChurn. With a client that doesn't implement
+// it, files keep discovery order.
+type ChurnCounter interface {
+	// FileChurn returns the lines added plus lines deleted across all commits for
+	// each file under a directory, keyed by the file's path joined to dir
+	FileChurn(dir string) (map[string]int, error)
+}
+
+// AttributeChecker is an optional interface a GitClient can implement to read
+// the gitattributes that mark files as generated or vendored, which are
+// skipped unless WithIgnoreGitattributes is set. With a client that doesn't
+// implement it, no files are skipped for their attributes.
+type AttributeChecker interface {
+	// CheckAttrs returns the values of the named gitattributes for each of the
+	// files, keyed by file as given, in one lookup. Values follow git
+	// check-attr: "set", "unset", "unspecified", or the assigned value.
+	CheckAttrs(files []string, attrs ...string) (map[string]map[string]string, error)
+}
+
+// StagedBase is a special base value for ChangedFiles and Diff that selects
+// changes staged in the index rather than changes relative to a revision.
+// An empty base selects all uncommitted changes to tracked files (staged and unstaged)
+// relative to HEAD; any other value is passed to git as a revision (e.g., "main" or "HEAD~3").
+const StagedBase = ":staged"
+
+// gitDiffArgs converts a base value into the git diff arguments selecting it.
+// A revision follows --end-of-options, so a base such as "--output=file" is
+// rejected as an unknown revision rather than run as an option. (internal helper)
+func gitDiffArgs(base string) []string {
+	switch base {
+	case "":
+		return []string{"HEAD"}
+	case StagedBase:
+		return []string{"--cached"}
+	default:
+		return []string{"--end-of-options", base}
+	}
 }
 
 // RealGitClient is the default implementation of GitClient that uses
@@ -97,6 +185,248 @@ func (c *RealGitClient) GetGitFiles(dir string) ([]string, error) {
 	return files, nil
 }
 
+// ChangedFiles retrieves files in a directory that differ from the base revision
+// using git diff. Deleted files are omitted since they cannot be processed.
+// Returned paths are joined with dir, matching GetGitFiles.
+func (c *RealGitClient) ChangedFiles(dir, base string) ([]string, error) {
+	if !c.gitAvailable {
+		return nil, fmt.Errorf("git not available")
+	}
+
+	args := append([]string{"-C", dir, "diff", "--name-only", "--relative", "--diff-filter=d"}, gitDiffArgs(base)...)
+	cmd := exec.Command("git", args...)
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, gitDiffError(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	var files []string
+	for _, line := range lines {
+		if line != "" {
+			files = append(files, filepath.Join(dir, line))
+		}
+	}
+	return files, nil
+}
+
+// UntrackedFiles retrieves files in a directory that are neither tracked nor
+// ignored using git ls-files. Returned paths are joined with dir, matching GetGitFiles.
+func (c *RealGitClient) UntrackedFiles(dir string) ([]string, error) {
+	if !c.gitAvailable {
+		return nil, fmt.Errorf("git not available")
+	}
+
+	cmd := exec.Command("git", "-C", dir, "ls-files", "--others", "--exclude-standard")
+	output, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 128 {
+			return nil, fmt.Errorf("not a git repository")
+		}
+		return nil, fmt.Errorf("error running git ls-files: %v", err)
+	}
+
+	var files []string
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if line != "" {
+			files = append(files, filepath.Join(dir, line))
+		}
+	}
+	return files, nil
+}
+
+// Diff returns a unified diff of a directory against the base revision using git diff.
+// Paths in the diff are relative to dir.
+func (c *RealGitClient) Diff(dir, base string) (string, error) {
+	if !c.gitAvailable {
+		return "", fmt.Errorf("git not available")
+	}
+
+	args := append([]string{"-C", dir, "diff", "--relative"}, gitDiffArgs(base)...)
+	cmd := exec.Command("git", args...)
+	output, err := cmd.Output()
+	if err != nil {
+		return "", gitDiffError(err)
+	}
+	return string(output), nil
+}
+
+// RecentCommits returns the last n commits touching the given paths using git log.
+// Each commit is rendered as "<short hash> <date> <subject>"; when stat is true,
+// a short summary of changed files and lines follows each commit.
+func (c *RealGitClient) RecentCommits(dir string, paths []string, n int, stat bool) (string, error) {
+	if !c.gitAvailable {
+		return "", fmt.Errorf("git not available")
+	}
+
+	args := []string{"-C", dir, "log", fmt.Sprintf("-n%d", n), "--date=short", "--format=%h %ad %s"}
+	if stat {
+		args = append(args, "--shortstat")
+	}
+	args = append(args, "--")
+	args = append(args, paths...)
+
+	cmd := exec.Command("git", args...)
+	output, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 128 {
+			return "", fmt.Errorf("error running git log: %s", strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return "", fmt.Errorf("error running git log: %v", err)
+	}
+	return string(output), nil
+}
+
+// CheckAttrs returns the values of the named gitattributes for files using a
+// single git check-attr call, run in the first file's directory. Every file
+// must belong to that repository.
+func (c *RealGitClient) CheckAttrs(files []string, attrs ...string) (map[string]map[string]string, error) {
+	if !c.gitAvailable {
+		return nil, fmt.Errorf("git not available")
+	}
+	if len(files) == 0 {
+		return map[string]map[string]string{}, nil
+	}
+
+	// Pass absolute paths, which git echoes back, so each line maps to its file
+	given := make(map[string]string, len(files))
+	var input strings.Builder
+	for _, file := range files {
+		absPath, err := filepath.Abs(file)
+		if err != nil {
+			return nil, err
+		}
+		given[absPath] = file
+		input.WriteString(absPath)
+		input.WriteByte(0)
+	}
+
+	absFirst, _ := filepath.Abs(files[0])
+	args := append([]string{"-C", filepath.Dir(absFirst), "check-attr", "--stdin", "-z"}, attrs...)
+	cmd := exec.Command("git", args...)
+	cmd.Stdin = strings.NewReader(input.String())
+	output, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 128 {
+			return nil, fmt.Errorf("error running git check-attr: %s", strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return nil, fmt.Errorf("error running git check-attr: %v", err)
+	}
+
+	// Output is a sequence of NUL-terminated <path> <attribute> <value> triples
+	values := make(map[string]map[string]string, len(files))
+	fields := strings.Split(strings.TrimSuffix(string(output), "\x00"), "\x00")
+	for i := 0; i+2 < len(fields); i += 3 {
+		file, ok := given[fields[i]]
+		if !ok {
+			continue
+		}
+		if values[file] == nil {
+			values[file] = make(map[string]string, len(attrs))
+		}
+		values[file][fields[i+1]] = fields[i+2]
+	}
+	return values, nil
+}
+
+// LastCommitTime returns the committer date of the last commit touching a file using git log.
+func (c *RealGitClient) LastCommitTime(file string) (time.Time, error) {
+	if !c.gitAvailable {
+		return time.Time{}, fmt.Errorf("git not available")
+	}
+
+	cmd := exec.Command("git", "-C", filepath.Dir(file), "log", "-1", "--format=%ct", "--", filepath.Base(file))
+	output, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 128 {
+			return time.Time{}, fmt.Errorf("not a git repository")
+		}
+		return time.Time{}, fmt.Errorf("error running git log: %v", err)
+	}
+
+	value := strings.TrimSpace(string(output))
+	if value == "" {
+		return time.Time{}, nil
+	}
+	seconds, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("unexpected git log output %q", value)
+	}
+	return time.Unix(seconds, 0), nil
+}
+
+// CommitAuthors returns the authors of the commits touching a file using git log.
+func (c *RealGitClient) CommitAuthors(file string) ([]string, error) {
+	if !c.gitAvailable {
+		return nil, fmt.Errorf("git not available")
+	}
+
+	cmd := exec.Command("git", "-C", filepath.Dir(file), "log", "--format=%an <%ae>", "--", filepath.Base(file))
+	output, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 128 {
+			return nil, fmt.Errorf("not a git repository")
+		}
+		return nil, fmt.Errorf("error running git log: %v", err)
+	}
+
+	var authors []string
+	for _, line := range strings.Split(string(output), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			authors = append(authors, line)
+		}
+	}
+	return authors, nil
+}
+
+// FileChurn sums the lines changed per file under a directory using git log --numstat.
+// Binary files, which git reports without line counts, are omitted.
+func (c *RealGitClient) FileChurn(dir string) (map[string]int, error) {
+	if !c.gitAvailable {
+		return nil, fmt.Errorf("git not available")
+	}
+
+	cmd := exec.Command("git", "-C", dir, "log", "--numstat", "--format=", "--no-renames", "--relative", "--", ".")
+	output, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 128 {
+			return nil, fmt.Errorf("not a git repository")
+		}
+		return nil, fmt.Errorf("error running git log: %v", err)
+	}
+
+	// Each line has the form "<added>\t<deleted>\t<path>"
+	churn := make(map[string]int)
+	for _, line := range strings.Split(string(output), "\n") {
+		fields := strings.SplitN(line, "\t", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		added, addErr := strconv.Atoi(fields[0])
+		deleted, delErr := strconv.Atoi(fields[1])
+		if addErr != nil || delErr != nil {
+			continue
+		}
+		churn[filepath.Join(dir, fields[2])] += added + deleted
+	}
+	return churn, nil
+}
+
+// gitDiffError converts a git diff failure into a descriptive error (internal helper)
+func gitDiffError(err error) error {
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		// Outside a repository git diff exits with 129 and "Not a git repository"
+		stderr := strings.TrimSpace(string(exitErr.Stderr))
+		if strings.Contains(strings.ToLower(stderr), "not a git repository") {
+			return fmt.Errorf("not a git repository")
+		}
+		if exitErr.ExitCode() == 128 {
+			return fmt.Errorf("error running git diff: %s", stderr)
+		}
+	}
+	return fmt.Errorf("error running git diff: %v", err)
+}
+
 // MockGitClient is a mock implementation of GitClient used for testing.
 // It allows controlling git availability and behavior without requiring
 // an actual git executable or repository.
@@ -104,6 +434,20 @@ type MockGitClient struct {
 	available    bool
 	ignoredFiles map[string]bool
 	filesInDir   map[string][]string
+	changedFiles map[mockDiffKey][]string
+	untracked    map[string][]string
+	diffs        map[mockDiffKey]string
+	commits      map[string]string
+	attributes   map[string]map[string]string
+	commitTimes  map[string]time.Time
+	authors      map[string][]string
+	churn        map[string]map[string]int
+}
+
+// mockDiffKey identifies a directory and base revision pair in MockGitClient
+type mockDiffKey struct {
+	dir  string
+	base string
 }
 
 // NewMockGitClient creates a new MockGitClient with the specified availability.
@@ -112,6 +456,14 @@ func NewMockGitClient(available bool) *MockGitClient {
 		available:    available,
 		ignoredFiles: make(map[string]bool),
 		filesInDir:   make(map[string][]string),
+		changedFiles: make(map[mockDiffKey][]string),
+		untracked:    make(map[string][]string),
+		diffs:        make(map[mockDiffKey]string),
+		commits:      make(map[string]string),
+		attributes:   make(map[string]map[string]string),
+		commitTimes:  make(map[string]time.Time),
+		authors:      make(map[string][]string),
+		churn:        make(map[string]map[string]int),
 	}
 }
 
@@ -155,3 +507,123 @@ func (m *MockGitClient) SetIgnoredFiles(files map[string]bool) {
 func (m *MockGitClient) SetFilesInDir(dir string, files []string) {
 	m.filesInDir[dir] = files
 }
+
+// ChangedFiles returns the changed files configured for the directory and base.
+// If the pair isn't configured, it returns no files.
+func (m *MockGitClient) ChangedFiles(dir, base string) ([]string, error) {
+	if !m.available {
+		return nil, fmt.Errorf("git not available")
+	}
+	return m.changedFiles[mockDiffKey{dir: dir, base: base}], nil
+}
+
+// UntrackedFiles returns the untracked files configured for the directory.
+func (m *MockGitClient) UntrackedFiles(dir string) ([]string, error) {
+	if !m.available {
+		return nil, fmt.Errorf("git not available")
+	}
+	return m.untracked[dir], nil
+}
+
+// Diff returns the diff configured for the directory and base.
+// If the pair isn't configured, it returns an empty diff.
+func (m *MockGitClient) Diff(dir, base string) (string, error) {
+	if !m.available {
+		return "", fmt.Errorf("git not available")
+	}
+	return m.diffs[mockDiffKey{dir: dir, base: base}], nil
+}
+
+// SetChangedFiles configures which files should be reported as changed for a directory and base.
+func (m *MockGitClient) SetChangedFiles(dir, base string, files []string) {
+	m.changedFiles[mockDiffKey{dir: dir, base: base}] = files
+}
+
+// SetUntrackedFiles configures which files should be reported as untracked for a directory.
+func (m *MockGitClient) SetUntrackedFiles(dir string, files []string) {
+	m.untracked[dir] = files
+}
+
+// SetDiff configures the diff returned for a directory and base.
+func (m *MockGitClient) SetDiff(dir, base, diff string) {
+	m.diffs[mockDiffKey{dir: dir, base: base}] = diff
+}
+
+// RecentCommits returns the commit log configured for the directory.
+// The paths, n, and stat arguments are ignored.
+func (m *MockGitClient) RecentCommits(dir string, paths []string, n int, stat bool) (string, error) {
+	if !m.available {
+		return "", fmt.Errorf("git not available")
+	}
+	return m.commits[dir], nil
+}
+
+// SetRecentCommits configures the commit log returned for a directory.
+func (m *MockGitClient) SetRecentCommits(dir, log string) {
+	m.commits[dir] = log
+}
+
+// CheckAttrs returns the attributes configured for each file. Attributes that
+// aren't configured are reported as "unspecified", like git check-attr.
+func (m *MockGitClient) CheckAttrs(files []string, attrs ...string) (map[string]map[string]string, error) {
+	if !m.available {
+		return nil, fmt.Errorf("git not available")
+	}
+	values := make(map[string]map[string]string, len(files))
+	for _, file := range files {
+		values[file] = make(map[string]string, len(attrs))
+		for _, attr := range attrs {
+			if value, ok := m.attributes[file][attr]; ok {
+				values[file][attr] = value
+			} else {
+				values[file][attr] = "unspecified"
+			}
+		}
+	}
+	return values, nil
+}
+
+// SetAttributes configures the gitattributes reported for a file.
+func (m *MockGitClient) SetAttributes(file string, attrs map[string]string) {
+	m.attributes[file] = attrs
+}
+
+// LastCommitTime returns the commit time configured for the file, or the zero
+// time if none is configured.
+func (m *MockGitClient) LastCommitTime(file string) (time.Time, error) {
+	if !m.available {
+		return time.Time{}, fmt.Errorf("git not available")
+	}
+	return m.commitTimes[file], nil
+}
+
+// SetLastCommitTime configures the last commit time reported for a file.
+func (m *MockGitClient) SetLastCommitTime(file string, t time.Time) {
+	m.commitTimes[file] = t
+}
+
+// CommitAuthors returns the commit authors configured for the file.
+func (m *MockGitClient) CommitAuthors(file string) ([]string, error) {
+	if !m.available {
+		return nil, fmt.Errorf("git not available")
+	}
+	return m.authors[file], nil
+}
+
+// SetCommitAuthors configures the commit authors reported for a file, newest first.
+func (m *MockGitClient) SetCommitAuthors(file string, authors []string) {
+	m.authors[file] = authors
+}
+
+// FileChurn returns the churn configured for the directory.
+func (m *MockGitClient) FileChurn(dir string) (map[string]int, error) {
+	if !m.available {
+		return nil, fmt.Errorf("git not available")
+	}
+	return m.churn[dir], nil
+}
+
+// SetFileChurn configures the per-file churn reported for a directory.
+func (m *MockGitClient) SetFileChurn(dir string, churn map[string]int) {
+	m.churn[dir] = churn
+}
@@ -3,11 +3,19 @@ package handoff
 import (
 	"errors"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 )
 
+// basicGitClient implements only the GitClient interface, hiding the optional
+// interfaces MockGitClient implements, like a client written before they existed
+type basicGitClient struct {
+	GitClient
+}
+
 // TestMockGitClient tests the basic functionality of the MockGitClient
 func TestMockGitClient(t *testing.T) {
 	// Test availability settings
@@ -90,6 +98,174 @@ func TestMockGitClient(t *testing.T) {
 	})
 }
 
+// TestMockGitClientDiff tests the diff capabilities of the MockGitClient
+func TestMockGitClientDiff(t *testing.T) {
+	client := NewMockGitClient(true)
+	client.SetChangedFiles("/repo", "main", []string{"/repo/a.go"})
+	client.SetDiff("/repo", StagedBase, "diff --git a/b.go b/b.go\n")
+
+	files, err := client.ChangedFiles("/repo", "main")
+	if err != nil {
+		t.Errorf("ChangedFiles should not return error: %v", err)
+	}
+	if !stringSlicesEqual(files, []string{"/repo/a.go"}) {
+		t.Errorf("ChangedFiles returned %v, expected [/repo/a.go]", files)
+	}
+
+	files, err = client.ChangedFiles("/repo", "other")
+	if err != nil || len(files) != 0 {
+		t.Errorf("ChangedFiles for unconfigured base returned %v, %v; expected no files", files, err)
+	}
+
+	diff, err := client.Diff("/repo", StagedBase)
+	if err != nil {
+		t.Errorf("Diff should not return error: %v", err)
+	}
+	if diff != "diff --git a/b.go b/b.go\n" {
+		t.Errorf("Diff returned %q, expected configured diff", diff)
+	}
+
+	unavailableClient := NewMockGitClient(false)
+	if _, err := unavailableClient.ChangedFiles("/repo", "main"); err == nil {
+		t.Error("ChangedFiles with git unavailable should return error")
+	}
+	if _, err := unavailableClient.Diff("/repo", "main"); err == nil {
+		t.Error("Diff with git unavailable should return error")
+	}
+}
+
+// runGit runs a git command in dir for test setup, failing the test on error
+func runGit(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", append([]string{"-C", dir, "-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %v failed: %v\n%s", args, err, output)
+	}
+}
+
+// TestRealGitClientDiff tests ChangedFiles and Diff against a real repository
+func TestRealGitClientDiff(t *testing.T) {
+	client := NewRealGitClient()
+	if !client.IsAvailable() {
+		t.Skip("git not available")
+	}
+
+	tmpDir, err := os.MkdirTemp("", "handoff-git-diff-test-")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer func() {
+		if cleanErr := os.RemoveAll(tmpDir); cleanErr != nil {
+			t.Logf("Failed to clean up test directory: %v", cleanErr)
+		}
+	}()
+
+	runGit(t, tmpDir, "init", "-q")
+	if err := os.WriteFile(filepath.Join(tmpDir, ".gitattributes"), []byte("kept.go linguist-generated=true\n"), 0644); err != nil {
+		t.Fatalf("Failed to create .gitattributes: %v", err)
+	}
+	for _, file := range []string{"kept.go", "changed.go", "staged.go", "deleted.go"} {
+		if err := os.WriteFile(filepath.Join(tmpDir, file), []byte("package main\n"), 0644); err != nil {
+			t.Fatalf("Failed to create test file %s: %v", file, err)
+		}
+	}
+	runGit(t, tmpDir, "add", ".")
+	runGit(t, tmpDir, "commit", "-q", "-m", "initial")
+
+	if err := os.WriteFile(filepath.Join(tmpDir, "changed.go"), []byte("package main\n\nfunc changed() {}\n"), 0644); err != nil {
+		t.Fatalf("Failed to modify file: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "staged.go"), []byte("package main\n\nfunc staged() {}\n"), 0644); err != nil {
+		t.Fatalf("Failed to modify file: %v", err)
+	}
+	runGit(t, tmpDir, "add", "staged.go")
+	if err := os.Remove(filepath.Join(tmpDir, "deleted.go")); err != nil {
+		t.Fatalf("Failed to delete file: %v", err)
+	}
+
+	files, err := client.ChangedFiles(tmpDir, "")
+	if err != nil {
+		t.Fatalf("ChangedFiles failed: %v", err)
+	}
+	want := []string{filepath.Join(tmpDir, "changed.go"), filepath.Join(tmpDir, "staged.go")}
+	if !stringSlicesEqual(files, want) {
+		t.Errorf("ChangedFiles(\"\") = %v, want %v", files, want)
+	}
+
+	files, err = client.ChangedFiles(tmpDir, StagedBase)
+	if err != nil {
+		t.Fatalf("ChangedFiles staged failed: %v", err)
+	}
+	if !stringSlicesEqual(files, []string{filepath.Join(tmpDir, "staged.go")}) {
+		t.Errorf("ChangedFiles(StagedBase) = %v, want only staged.go", files)
+	}
+
+	diff, err := client.Diff(tmpDir, StagedBase)
+	if err != nil {
+		t.Fatalf("Diff failed: %v", err)
+	}
+	if !strings.Contains(diff, "func staged()") || strings.Contains(diff, "func changed()") {
+		t.Errorf("Diff(StagedBase) should contain only staged changes, got:\n%s", diff)
+	}
+
+	if _, err := client.ChangedFiles(tmpDir, "no-such-revision"); err == nil {
+		t.Error("ChangedFiles with unknown revision should return error")
+	}
+
+	// A base that looks like an option is taken as a revision, never as an option
+	injected := filepath.Join(tmpDir, "injected.txt")
+	if _, err := client.ChangedFiles(tmpDir, "--output="+injected); err == nil {
+		t.Error("ChangedFiles with an option as base should return error")
+	}
+	if _, err := client.Diff(tmpDir, "--output="+injected); err == nil {
+		t.Error("Diff with an option as base should return error")
+	}
+	if _, err := os.Stat(injected); err == nil {
+		t.Error("a base of --output=file should not write the file")
+	}
+
+	kept, changed := filepath.Join(tmpDir, "kept.go"), filepath.Join(tmpDir, "changed.go")
+	attrs, err := client.CheckAttrs([]string{kept, changed}, "linguist-generated", "linguist-vendored")
+	if err != nil {
+		t.Fatalf("CheckAttrs failed: %v", err)
+	}
+	if attrs[kept]["linguist-generated"] != "true" || attrs[kept]["linguist-vendored"] != "unspecified" {
+		t.Errorf("CheckAttrs returned %v for kept.go, want linguist-generated=true and linguist-vendored unspecified", attrs[kept])
+	}
+	if attrs[changed]["linguist-generated"] != "unspecified" {
+		t.Errorf("CheckAttrs returned %v for changed.go, want linguist-generated unspecified", attrs[changed])
+	}
+	if _, err := client.CheckAttrs([]string{kept, filepath.Join(os.TempDir(), "outside.go")}, "linguist-generated"); err == nil {
+		t.Error("CheckAttrs with a file outside the repository should return error")
+	}
+
+	committed, err := client.LastCommitTime(filepath.Join(tmpDir, "kept.go"))
+	if err != nil || committed.IsZero() || time.Since(committed) > time.Hour {
+		t.Errorf("LastCommitTime(kept.go) = %v, %v; want the initial commit time", committed, err)
+	}
+	if authors, err := client.CommitAuthors(filepath.Join(tmpDir, "kept.go")); err != nil || len(authors) != 1 || authors[0] != "test <test@example.com>" {
+		t.Errorf("CommitAuthors(kept.go) = %v, %v; want the initial commit's author", authors, err)
+	}
+	churn, err := client.FileChurn(tmpDir)
+	if err != nil || churn[filepath.Join(tmpDir, "kept.go")] != 1 {
+		t.Errorf("FileChurn() = %v, %v; want 1 line for kept.go", churn, err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "untracked.go"), []byte("package main\n"), 0644); err != nil {
+		t.Fatalf("Failed to create untracked file: %v", err)
+	}
+	if committed, err := client.LastCommitTime(filepath.Join(tmpDir, "untracked.go")); err != nil || !committed.IsZero() {
+		t.Errorf("LastCommitTime(untracked.go) = %v, %v; want the zero time", committed, err)
+	}
+
+	log, err := client.RecentCommits(tmpDir, []string{filepath.Join(tmpDir, "kept.go")}, 5, true)
+	if err != nil {
+		t.Fatalf("RecentCommits failed: %v", err)
+	}
+	if !strings.Contains(log, "initial") || !strings.Contains(log, "file") {
+		t.Errorf("RecentCommits should list the initial commit with stats, got:\n%s", log)
+	}
+}
+
 // TestRealGitClientWithMocks tests the RealGitClient using MockGitClient for comparison
 func TestGitClientIntegration(t *testing.T) {
 	// Create a temporary directory for testing
@@ -0,0 +1,115 @@
+package handoff
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// FileStatus is a file's state in git relative to the last commit
+type FileStatus string
+
+const (
+	// StatusModified marks a tracked file with uncommitted changes that are not staged
+	StatusModified FileStatus = "modified"
+
+	// StatusStaged marks a file with changes staged for the next commit
+	StatusStaged FileStatus = "staged"
+
+	// StatusUntracked marks a file git doesn't track and doesn't ignore
+	StatusUntracked FileStatus = "untracked"
+
+	// StatusClean marks a tracked file without uncommitted changes
+	StatusClean FileStatus = "clean"
+)
+
+// WithGitStatus marks each file with its git status (modified, staged,
+// untracked, or clean), so reviewers and models see which parts of the
+// context are in flux. The default output and style presets show it in a
+// comment such as <!-- git: modified --> before the file's block, templates
+// can place it with the {status} placeholder, and JSON Lines output and
+// Stats.Files carry it as a field. Files outside a git repository are not
+// marked.
+func WithGitStatus(mark bool) Option {
+	return func(c *Config) {
+		c.GitStatus = mark
+	}
+}
+
+// gitStatusTracker looks up the git status of files under the directories it
+// was built from (internal helper)
+type gitStatusTracker struct {
+	// repos are the directories whose status could be read
+	repos []string
+
+	// changed maps the cleaned paths of files that aren't clean to their status
+	changed map[string]FileStatus
+}
+
+// readGitStatus reads the status of the files under each directory argument,
+// or the parent directory of file arguments. Directories whose status can't
+// be read, such as those outside a repository, are left out with a verbose
+// note. (internal helper)
+func readGitStatus(paths []string, config *Config, logger *Logger) *gitStatusTracker {
+	tracker := &gitStatusTracker{changed: make(map[string]FileStatus)}
+	lister, ok := config.GitClient.(UntrackedLister)
+	if !ok || !config.GitClient.IsAvailable() {
+		logger.Warn("git status markers require git; files are not marked")
+		return tracker
+	}
+
+	seen := make(map[string]bool)
+	for _, path := range paths {
+		dir := path
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			dir = filepath.Dir(path)
+		}
+		if seen[dir] {
+			continue
+		}
+		seen[dir] = true
+
+		// Later sets take precedence: staged over modified, untracked over both
+		modified, err := config.GitClient.ChangedFiles(dir, "")
+		if err != nil {
+			logger.Verbose("cannot read git status for %s: %v", dir, err)
+			continue
+		}
+		staged, err := config.GitClient.ChangedFiles(dir, StagedBase)
+		if err != nil {
+			logger.Verbose("cannot read git status for %s: %v", dir, err)
+			continue
+		}
+		untracked, err := lister.UntrackedFiles(dir)
+		if err != nil {
+			logger.Verbose("cannot read git status for %s: %v", dir, err)
+			continue
+		}
+		for _, set := range []struct {
+			files  []string
+			status FileStatus
+		}{{modified, StatusModified}, {staged, StatusStaged}, {untracked, StatusUntracked}} {
+			for _, file := range set.files {
+				tracker.changed[filepath.Clean(file)] = set.status
+			}
+		}
+		tracker.repos = append(tracker.repos, dir)
+	}
+	return tracker
+}
+
+// status returns a file's git status, or an empty status for a file outside
+// the directories whose status was read
+func (t *gitStatusTracker) status(path string) FileStatus {
+	if t == nil {
+		return ""
+	}
+	if status, ok := t.changed[filepath.Clean(path)]; ok {
+		return status
+	}
+	for _, repo := range t.repos {
+		if withinDir(repo, path) {
+			return StatusClean
+		}
+	}
+	return ""
+}
@@ -0,0 +1,93 @@
+package handoff
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestWithGitStatus tests marking files with their git status
+func TestWithGitStatus(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"clean.go", "edited.go", "staged.go", "new.go"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("package main\n"), 0644); err != nil {
+			t.Fatalf("Failed to write file: %v", err)
+		}
+	}
+	gitClient := NewMockGitClient(true)
+	gitClient.SetChangedFiles(dir, "", []string{filepath.Join(dir, "edited.go"), filepath.Join(dir, "staged.go")})
+	gitClient.SetChangedFiles(dir, StagedBase, []string{filepath.Join(dir, "staged.go")})
+	gitClient.SetUntrackedFiles(dir, []string{filepath.Join(dir, "new.go")})
+
+	config := NewConfig(WithGitClient(gitClient), WithGitStatus(true))
+	content, stats, err := ProcessProject([]string{dir}, config)
+	if err != nil {
+		t.Fatalf("ProcessProject failed: %v", err)
+	}
+
+	want := map[string]FileStatus{
+		"clean.go":  StatusClean,
+		"edited.go": StatusModified,
+		"staged.go": StatusStaged,
+		"new.go":    StatusUntracked,
+	}
+	for _, file := range stats.Files {
+		name := filepath.Base(file.Path)
+		if file.GitStatus != want[name] {
+			t.Errorf("GitStatus of %s = %q, want %q", name, file.GitStatus, want[name])
+		}
+		annotation := "<!-- git: " + string(want[name]) + " -->\n<" + file.Path + ">"
+		if !strings.Contains(content, annotation) {
+			t.Errorf("content is missing %q:\n%s", annotation, content)
+		}
+	}
+
+	// A template placing {status} itself gets no comment
+	config = NewConfig(WithGitClient(gitClient), WithGitStatus(true), WithFormat("{status} {path}\n"))
+	content, _, err = ProcessProject([]string{filepath.Join(dir, "staged.go")}, config)
+	if err != nil {
+		t.Fatalf("ProcessProject failed: %v", err)
+	}
+	if want := "staged " + filepath.Join(dir, "staged.go") + "\n"; !strings.Contains(content, want) {
+		t.Errorf("content = %q, want it to contain %q", content, want)
+	}
+
+	// JSON Lines output carries the status as a field
+	config = NewConfig(WithGitClient(gitClient), WithGitStatus(true), WithFormatter(NewJSONLFormatter()))
+	content, _, err = ProcessProject([]string{filepath.Join(dir, "new.go")}, config)
+	if err != nil {
+		t.Fatalf("ProcessProject failed: %v", err)
+	}
+	if !strings.Contains(content, `"status":"untracked"`) {
+		t.Errorf("JSON Lines output is missing the status:\n%s", content)
+	}
+}
+
+// TestWithGitStatusUnavailable tests that files are not marked without git
+// or with a client that can't list untracked files
+func TestWithGitStatusUnavailable(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	for name, client := range map[string]GitClient{
+		"Without git":             NewMockGitClient(false),
+		"Without UntrackedLister": basicGitClient{NewMockGitClient(true)},
+	} {
+		t.Run(name, func(t *testing.T) {
+			config := NewConfig(WithGitClient(client), WithGitStatus(true))
+			content, stats, err := ProcessProject([]string{dir}, config)
+			if err != nil {
+				t.Fatalf("ProcessProject failed: %v", err)
+			}
+			if strings.Contains(content, "<!-- git:") {
+				t.Errorf("content has a git status:\n%s", content)
+			}
+			if len(stats.Files) != 1 || stats.Files[0].GitStatus != "" {
+				t.Errorf("Files = %+v, want one unmarked file", stats.Files)
+			}
+		})
+	}
+}
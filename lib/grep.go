@@ -0,0 +1,80 @@
+package handoff
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// grepMatchMarker and grepContextMarker prefix matching and surrounding lines in grep mode
+const (
+	grepMatchMarker   = "> "
+	grepContextMarker = "  "
+	grepGapMarker     = "..."
+)
+
+// WithGrep enables grep mode: only files with at least one line matching the
+// regular expression are processed, and their content is rendered with line
+// numbers and matching lines highlighted with a leading "> " marker.
+//
+// When contextLines is zero or more, each file is trimmed to the matching lines
+// plus that many lines of context around each match, with "..." marking the gaps.
+// A negative contextLines keeps the whole file. A nil regexp disables grep mode.
+func WithGrep(re *regexp.Regexp, contextLines int) Option {
+	return func(c *Config) {
+		c.grep = re
+		c.grepContext = contextLines
+	}
+}
+
+// grepContent renders content for grep mode (internal helper).
+// It returns the rendered content and whether any line matched; when nothing
+// matches, the rendered content is empty.
+func grepContent(content []byte, re *regexp.Regexp, contextLines int) (string, bool) {
+	lines := strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+
+	matches := make([]bool, len(lines))
+	matched := false
+	for i, line := range lines {
+		if re.MatchString(line) {
+			matches[i] = true
+			matched = true
+		}
+	}
+	if !matched {
+		return "", false
+	}
+
+	// Mark the lines to keep: everything, or matches plus surrounding context
+	keep := make([]bool, len(lines))
+	for i := range lines {
+		if contextLines < 0 {
+			keep[i] = true
+			continue
+		}
+		if !matches[i] {
+			continue
+		}
+		for j := max(0, i-contextLines); j <= min(len(lines)-1, i+contextLines); j++ {
+			keep[j] = true
+		}
+	}
+
+	width := len(fmt.Sprint(len(lines)))
+	var b strings.Builder
+	for i, line := range lines {
+		if !keep[i] {
+			// Emit a single gap marker for each run of skipped lines
+			if i == 0 || keep[i-1] {
+				b.WriteString(grepGapMarker + "\n")
+			}
+			continue
+		}
+		marker := grepContextMarker
+		if matches[i] {
+			marker = grepMatchMarker
+		}
+		fmt.Fprintf(&b, "%s%*d| %s\n", marker, width, i+1, line)
+	}
+	return strings.TrimSuffix(b.String(), "\n"), true
+}
@@ -0,0 +1,100 @@
+package handoff
+
+import (
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// TestGrepContent tests grep-mode rendering with and without context trimming
+func TestGrepContent(t *testing.T) {
+	content := "line one\nTODO(payment) fix\nline three\nline four\nline five\nline six\nTODO(payment) again\n"
+	re := regexp.MustCompile(regexp.QuoteMeta("TODO(payment)"))
+
+	testCases := []struct {
+		name        string
+		content     string
+		context     int
+		want        string
+		wantMatched bool
+	}{
+		{
+			name:    "Whole file with highlights",
+			content: content,
+			context: -1,
+			want: "  1| line one\n" +
+				"> 2| TODO(payment) fix\n" +
+				"  3| line three\n" +
+				"  4| line four\n" +
+				"  5| line five\n" +
+				"  6| line six\n" +
+				"> 7| TODO(payment) again",
+			wantMatched: true,
+		},
+		{
+			name:    "One line of context",
+			content: content,
+			context: 1,
+			want: "  1| line one\n" +
+				"> 2| TODO(payment) fix\n" +
+				"  3| line three\n" +
+				"...\n" +
+				"  6| line six\n" +
+				"> 7| TODO(payment) again",
+			wantMatched: true,
+		},
+		{
+			name:        "Matches only",
+			content:     "a\nb\nTODO(payment)\nc\n",
+			context:     0,
+			want:        "...\n> 3| TODO(payment)\n...",
+			wantMatched: true,
+		},
+		{
+			name:        "No match",
+			content:     "nothing to see\n",
+			context:     2,
+			want:        "",
+			wantMatched: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, matched := grepContent([]byte(tc.content), re, tc.context)
+			if matched != tc.wantMatched {
+				t.Errorf("grepContent() matched = %v, want %v", matched, tc.wantMatched)
+			}
+			if got != tc.want {
+				t.Errorf("grepContent() =\n%s\nwant\n%s", got, tc.want)
+			}
+		})
+	}
+}
+
+// TestWithGrep tests that grep mode skips non-matching files during processing
+func TestWithGrep(t *testing.T) {
+	tmpDir, _ := createTestDir(t)
+	defer func() {
+		if cleanErr := os.RemoveAll(tmpDir); cleanErr != nil {
+			t.Logf("Failed to clean up test directory: %v", cleanErr)
+		}
+	}()
+
+	config := NewConfig(
+		WithGitClient(NewMockGitClient(false)),
+		WithGrep(regexp.MustCompile("content of main.go"), 0),
+	)
+	content, stats, err := ProcessProject([]string{tmpDir}, config)
+	if err != nil {
+		t.Fatalf("ProcessProject failed: %v", err)
+	}
+
+	if stats.FilesProcessed != 1 {
+		t.Errorf("stats.FilesProcessed = %d, want 1", stats.FilesProcessed)
+	}
+	if !strings.Contains(content, "> 1| This is the content of main.go") {
+		t.Errorf("content should contain the highlighted match, got:\n%s", content)
+	}
+}
@@ -0,0 +1,127 @@
+package handoff
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// languageGroupOther holds the files that belong to no other language group
+const languageGroupOther = "Other"
+
+// languageGroups maps code fence languages to the names of the groups their
+// files are listed under, merging dialects such as JSX with their language
+var languageGroups = map[string]string{
+	"go":         "Go",
+	"python":     "Python",
+	"javascript": "JavaScript",
+	"jsx":        "JavaScript",
+	"typescript": "TypeScript",
+	"tsx":        "TypeScript",
+	"ruby":       "Ruby",
+	"rust":       "Rust",
+	"java":       "Java",
+	"kotlin":     "Kotlin",
+	"swift":      "Swift",
+	"c":          "C",
+	"cpp":        "C++",
+	"csharp":     "C#",
+	"php":        "PHP",
+	"perl":       "Perl",
+	"lua":        "Lua",
+	"r":          "R",
+	"bash":       "Shell",
+	"fish":       "Shell",
+	"powershell": "Shell",
+	"sql":        "SQL",
+	"html":       "HTML",
+	"css":        "CSS",
+	"scss":       "CSS",
+	"markdown":   "Markdown",
+	"protobuf":   "Protobuf",
+	"json":       "Config",
+	"yaml":       "Config",
+	"toml":       "Config",
+	"xml":        "Config",
+}
+
+// configExtensions and configNames identify configuration files that have no
+// code fence language of their own
+var (
+	configExtensions = []string{".ini", ".cfg", ".conf", ".env", ".properties", ".mod", ".sum", ".lock"}
+	configNames      = []string{"dockerfile", "makefile", ".gitignore", ".gitattributes", ".editorconfig", ".dockerignore", ".env"}
+)
+
+// WithGroupByLanguage lists files in a section per language, such as Go,
+// TypeScript, SQL, and Config, each introduced by a "language" heading giving
+// the number of files and estimated tokens in it. This helps when asking
+// language-specific questions about a polyglot repository. Groups appear in
+// the order their first file would otherwise appear, with unrecognized files
+// last, and files keep their order within a group.
+func WithGroupByLanguage(group bool) Option {
+	return func(c *Config) {
+		c.GroupByLanguage = group
+	}
+}
+
+// languageGroup returns the name of the language group a file belongs to (internal helper)
+func languageGroup(path string, content []byte) string {
+	if group, ok := languageGroups[fenceLanguage(path, content)]; ok {
+		return group
+	}
+	name := strings.ToLower(filepath.Base(path))
+	if slices.Contains(configNames, name) || slices.Contains(configExtensions, filepath.Ext(name)) {
+		return "Config"
+	}
+	return languageGroupOther
+}
+
+// sortByLanguage orders files by language group, keeping their order within
+// a group (internal helper)
+func sortByLanguage(files []formattedFile) {
+	rank := make(map[string]int)
+	groups := make(map[string]string, len(files))
+	for _, file := range files {
+		group := languageGroup(file.path, file.content)
+		groups[file.path] = group
+		if _, ok := rank[group]; !ok && group != languageGroupOther {
+			rank[group] = len(rank)
+		}
+	}
+	rank[languageGroupOther] = len(rank)
+
+	sort.SliceStable(files, func(i, j int) bool {
+		return rank[groups[files[i].path]] < rank[groups[files[j].path]]
+	})
+}
+
+// addLanguageHeadings gives the first file of each language group in files,
+// which are sorted by language, a heading with the group's subtotals, and
+// returns the statistics of the headings (internal helper)
+func addLanguageHeadings(files []formattedFile, formatter Formatter) contentStats {
+	var stats contentStats
+	for start := 0; start < len(files); {
+		group := languageGroup(files[start].path, files[start].content)
+		end, tokens := start, 0
+		for end < len(files) && languageGroup(files[end].path, files[end].content) == group {
+			tokens += files[end].stats.tokens
+			files[end].heading = ""
+			end++
+		}
+		files[start].heading = formatSection(formatter, "language", languageSummary(group, end-start, tokens))
+		stats.add(files[start].heading)
+		start = end
+	}
+	return stats
+}
+
+// languageSummary describes a language group's subtotals (internal helper)
+func languageSummary(group string, files, tokens int) string {
+	noun := "files"
+	if files == 1 {
+		noun = "file"
+	}
+	return fmt.Sprintf("%s: %d %s, ~%d tokens", group, files, noun, tokens)
+}
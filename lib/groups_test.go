@@ -0,0 +1,68 @@
+package handoff
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// TestGroupByLanguage tests listing files in a section per language
+func TestGroupByLanguage(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"a.go":       "package a\n",
+		"b.sql":      "select 1;\n",
+		"c.go":       "package c\n",
+		"d.yaml":     "key: value\n",
+		"Dockerfile": "FROM scratch\n",
+		"e.txt":      "notes\n",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write file: %v", err)
+		}
+	}
+
+	config := NewConfig(
+		WithGitClient(NewMockGitClient(false)),
+		WithRoot(dir),
+		WithGroupByLanguage(true),
+	)
+	content, stats, err := processPaths([]string{"."}, config, NewLogger(false))
+	if err != nil {
+		t.Fatalf("processPaths failed: %v", err)
+	}
+
+	// Groups follow their first file in discovery order, with other files last
+	want := []string{"Dockerfile", "d.yaml", "a.go", "c.go", "b.sql", "e.txt"}
+	if !reflect.DeepEqual(stats.IncludedFiles, want) {
+		t.Errorf("IncludedFiles = %v, want %v", stats.IncludedFiles, want)
+	}
+
+	goTokens := 0
+	for _, file := range stats.Files {
+		if strings.HasSuffix(file.Path, ".go") {
+			goTokens += file.Tokens
+		}
+	}
+	for _, heading := range []string{
+		"<language>\nConfig: 2 files,",
+		"<language>\nGo: 2 files, ~" + strconv.Itoa(goTokens) + " tokens\n</language>\n\n<a.go>",
+		"<language>\nSQL: 1 file,",
+		"<language>\nOther: 1 file,",
+	} {
+		if !strings.Contains(content, heading) {
+			t.Errorf("content is missing %q:\n%s", heading, content)
+		}
+	}
+	if got := strings.Count(content, "<language>"); got != 4 {
+		t.Errorf("content has %d language headings, want 4", got)
+	}
+
+	// Headings are counted in the totals
+	if _, _, tokens := CalculateStatistics(content); stats.Tokens != tokens {
+		t.Errorf("stats.Tokens = %d, want %d", stats.Tokens, tokens)
+	}
+}
@@ -20,29 +20,30 @@
 package handoff
 
 import (
-	"bytes"
 	"errors"
 	"fmt"
-	"os"
-	"path/filepath"
+	"io"
+	"regexp"
 	"slices"
-	"strings"
-	"unicode"
+	"sync"
+	"time"
 )
 
 // ErrNoFilesProcessed is returned when paths were provided, files were found,
 // but no files were processed due to filtering
 var ErrNoFilesProcessed = errors.New("no files were processed from the provided paths")
 
-// ErrFileExists is returned when WriteToFile is called with overwrite=false and the file already exists
-var ErrFileExists = errors.New("file already exists and overwrite is not allowed")
-
 // Option is a function that configures a Config instance.
 // It implements the functional options pattern for configuration.
 type Option func(*Config)
 
 // Config holds all configuration options for file processing and output formatting.
 // Users should create a Config with NewConfig() and provide the desired options as arguments.
+//
+// A Config is safe for concurrent use by multiple ProcessProject and DiscoverFiles calls:
+// those functions work on a private copy and never modify the caller's Config. Callers
+// must not change a Config's fields while it is in use; use Clone to derive variants.
+// The GitClient and Formatter implementations must themselves be safe for concurrent use.
 type Config struct {
 	// Verbose enables detailed logging output
 	Verbose bool
@@ -50,13 +51,206 @@ type Config struct {
 	// Format is a template string for formatting output, using {path} and {content} placeholders
 	Format string
 
+	// ContextAttributes adds the file count, token estimate, and generation time
+	// as attributes of the <context> tag wrapping the default output
+	ContextAttributes bool
+
+	// TokenAnnotations adds a comment with each file's estimated token count
+	// after its block, for formatters that support it
+	TokenAnnotations bool
+
 	// IgnoreGitignore bypasses gitignore filtering when true
 	IgnoreGitignore bool
 
+	// IgnoreGitattributes processes files marked linguist-generated or linguist-vendored
+	// in .gitattributes, which are skipped by default
+	IgnoreGitattributes bool
+
+	// MaxFileSize is the maximum size in bytes of files to process; zero or less disables the limit
+	MaxFileSize int64
+
+	// IOThrottle is the limit on file reads in bytes per second, set with
+	// WithIOThrottle; zero or less reads at full speed
+	IOThrottle int64
+
+	// CollapseBlobs replaces enormous inline data, such as base64 strings and
+	// byte-array literals, with size markers
+	CollapseBlobs bool
+
+	// IncludeBinary dumps binary files named directly as paths, or matching an
+	// included extension, in this encoding instead of skipping them; empty
+	// skips every binary file
+	IncludeBinary BinaryEncoding
+
+	// Transcode converts UTF-16 files with a byte order mark and text that is not
+	// valid UTF-8 (read as Windows-1252) to UTF-8, dropping byte order marks
+	Transcode bool
+
+	// NormalizeUnicode composes content to Unicode NFC before content filters
+	// and grep run, so decomposed text matches and counts like composed text
+	NormalizeUnicode bool
+
+	// EscapePaths percent-encodes control characters, bidirectional formatting
+	// characters, invalid UTF-8, and percent signs in displayed paths
+	EscapePaths bool
+
+	// SanitizeControl strips or escapes ANSI escape sequences and control
+	// characters before content filters run; empty leaves content as is
+	SanitizeControl ControlMode
+
+	// MaxFileLines is the maximum number of lines in files to process; zero or less disables the limit
+	MaxFileLines int
+
+	// GitLog is the number of recent commits to list in a history section; zero disables it
+	GitLog int
+
+	// GitLogStat adds changed file and line counts to each commit in the history section
+	GitLogStat bool
+
+	// Dependencies appends a section summarizing the direct dependencies declared
+	// in the dependency manifests of directory arguments
+	Dependencies bool
+
+	// EnvironmentInfo appends a section with the OS, architecture, installed Go
+	// version, and tool versions pinned by the project
+	EnvironmentInfo bool
+
+	// TodoIndex appends a section listing the TODO, FIXME, and HACK markers in
+	// the included files
+	TodoIndex bool
+
+	// SkippedAppendix appends a section listing the files left out and why
+	SkippedAppendix bool
+
+	// DirectoryCap is the most files processed from any one directory; zero or
+	// less disables the cap
+	DirectoryCap int
+
+	// DirectorySample selects which files a directory cap keeps; empty keeps the first
+	DirectorySample SampleMode
+
+	// Query ranks files by relevance and keeps only the best matches; empty
+	// disables ranking
+	Query string
+
+	// QueryTop is the most files a query keeps; zero or less keeps every match
+	// that fits the token budget
+	QueryTop int
+
+	// Sample is the number of files chosen at random across all paths; zero or
+	// less processes every file
+	Sample int
+
+	// SampleSeed seeds random sampling; zero picks a seed at random
+	SampleSeed uint64
+
+	// Root is the directory relative paths are resolved against and displayed
+	// relative to; empty uses the working directory
+	Root string
+
+	// Stdin is read as one document for the path "-"; nil reads os.Stdin
+	Stdin io.Reader
+
+	// StdinName is the path shown for standard input; empty uses DefaultStdinName
+	StdinName string
+
+	// IncludeDiff makes ProcessChanges append the unified diff of the changes in
+	// a git-diff section
+	IncludeDiff bool
+
+	// MaxTokens is the budget for the estimated tokens in the output; zero or less disables it
+	MaxTokens int
+
+	// TrimStrategy selects how files are cut down to fit MaxTokens; empty drops whole files
+	TrimStrategy TrimStrategy
+
+	// Model is the target model whose context window the output is checked against;
+	// a zero ContextWindow disables the check
+	Model ModelProfile
+
+	// ResponseReserve is the number of context window tokens kept free for the model's response
+	ResponseReserve int
+
+	// Strict makes exceeding the model's context window an error instead of a warning
+	Strict bool
+
+	// StrictSkips lists the skip reasons that make a run fail with ErrFilesSkipped
+	StrictSkips []SkipReason
+
+	// ChunkTokens is the estimated token limit for each part produced by
+	// ProcessProjectChunks; zero or less keeps the output in a single part
+	ChunkTokens int
+
+	// ModifiedAfter keeps only files last changed after this time, using the last
+	// commit date when git has one and the file's modification time otherwise;
+	// the zero time disables the filter
+	ModifiedAfter time.Time
+
+	// Author keeps only files belonging to this author in git history, matched
+	// case-insensitively against "Name <email>"; empty disables the filter
+	Author string
+
+	// AuthorMatch decides which commits assign a file to an author; empty uses AuthorLast
+	AuthorMatch AuthorMatch
+
+	// Order is the order of files in the output; empty keeps discovery order
+	Order FileOrder
+
+	// GitStatus marks each file with its git status relative to the last commit
+	GitStatus bool
+
+	// Checksums adds the SHA-256 digest of each file's content to its header
+	// and statistics
+	Checksums bool
+
+	// GroupByLanguage lists files in a section per language, after ordering
+	// them by Order, each introduced by a heading with the group's subtotals
+	GroupByLanguage bool
+
+	// ResumeFile is a checkpoint recording processed files so an interrupted run
+	// can continue where it stopped; empty disables checkpointing
+	ResumeFile string
+
+	// ChunkOverlap is the number of lines from the end of each part repeated at
+	// the start of the next; zero or less disables the overlap
+	ChunkOverlap int
+
+	// Hooks are callbacks invoked as files are processed
+	Hooks Hooks
+
+	// ErrorHandler decides what to do when a path can't be stat'ed or read;
+	// without one, the path is skipped with a warning
+	ErrorHandler ErrorHandler
+
 	// Internal representation of include/exclude patterns
-	includeExts  []string
-	excludeExts  []string
-	excludeNames []string
+	includeExts     []string
+	excludeExts     []string
+	excludeNames    []string
+	hiddenAllowlist []string
+	pathRules       []PathRule
+	includeContent  *regexp.Regexp
+	grep            *regexp.Regexp
+	grepContext     int
+	trimPriority    []string
+	transformers    []Transformer
+	fileFilters     []FileFilter
+	rootLabels      []rootLabel
+
+	// changes is the change set being processed by ProcessChanges, if any
+	changes *changeSet
+
+	// attributes caches the linguist attributes of the files found by the
+	// current run's discovery pass, looked up together; a nil entry means they
+	// couldn't be read
+	attributes map[string]map[string]string
+
+	// uncommitted caches, per directory, the files with uncommitted changes,
+	// filled as lastModified dates files in it; a nil entry means they
+	// couldn't be read
+	uncommitted map[string]map[string]bool
+
+	// throttle paces file reads when WithIOThrottle is set; clones share it
+	throttle *ioThrottle
 
 	// Original string forms (retained for backward compatibility)
 	include         string
@@ -65,6 +259,10 @@ type Config struct {
 
 	// GitClient is used for git-related operations
 	GitClient GitClient
+
+	// Formatter renders files and the final output; when nil, a TemplateFormatter
+	// using Format is applied
+	Formatter Formatter
 }
 
 // NewConfig creates a new Config with default values and applies the given options.
@@ -72,9 +270,11 @@ type Config struct {
 // with file path headers and code fences.
 func NewConfig(opts ...Option) *Config {
 	c := &Config{
-		Verbose:   false,
-		Format:    "<{path}>\n```\n{content}\n```\n</{path}>\n\n",
-		GitClient: NewRealGitClient(),
+		Verbose:         false,
+		Format:          DefaultFormat,
+		MaxFileSize:     DefaultMaxFileSize,
+		ResponseReserve: DefaultResponseReserve,
+		GitClient:       NewRealGitClient(),
 	}
 
 	// Apply all options
@@ -99,29 +299,14 @@ func WithFormat(format string) Option {
 	}
 }
 
-// WithInclude specifies file extensions to include.
-// Extensions can be provided with or without dots (e.g., ".go,.md" or "go,md").
-func WithInclude(include string) Option {
+// WithTokenAnnotations adds a comment such as <!-- ~812 tokens --> after each
+// file's block, giving the estimated tokens of the block, so readers can see
+// which files to trim when the output is too large. It applies to the default
+// template and style formatters; the JSON Lines formatter already reports
+// tokens per file, and the HTML and custom formatters ignore it.
+func WithTokenAnnotations(enabled bool) Option {
 	return func(c *Config) {
-		c.include = include
-		c.includeExts = processExtensions(include)
-	}
-}
-
-// WithExclude specifies file extensions to exclude.
-// Extensions can be provided with or without dots (e.g., ".exe,.bin" or "exe,bin").
-func WithExclude(exclude string) Option {
-	return func(c *Config) {
-		c.exclude = exclude
-		c.excludeExts = processExtensions(exclude)
-	}
-}
-
-// WithExcludeNames specifies file names to exclude.
-func WithExcludeNames(excludeNames string) Option {
-	return func(c *Config) {
-		c.excludeNamesStr = excludeNames
-		c.excludeNames = processNames(excludeNames)
+		c.TokenAnnotations = enabled
 	}
 }
 
@@ -134,120 +319,18 @@ func WithGitClient(gitClient GitClient) Option {
 	}
 }
 
-// WithIgnoreGitignore sets whether to ignore gitignore rules.
-func WithIgnoreGitignore(ignoreGitignore bool) Option {
-	return func(c *Config) {
-		c.IgnoreGitignore = ignoreGitignore
-	}
-}
-
-// Helper function to process comma-separated extensions
-func processExtensions(exts string) []string {
-	if exts == "" {
-		return nil
-	}
-
-	result := []string{}
-	for _, ext := range strings.Split(exts, ",") {
-		ext = strings.TrimSpace(ext)
-		if !strings.HasPrefix(ext, ".") {
-			ext = "." + ext
-		}
-		result = append(result, ext)
-	}
-	return result
-}
-
-// Helper function to process comma-separated names
-func processNames(names string) []string {
-	if names == "" {
-		return nil
-	}
-
-	result := []string{}
-	for _, name := range strings.Split(names, ",") {
-		result = append(result, strings.TrimSpace(name))
-	}
-	return result
-}
-
-// ProcessorFunc is a function type that processes a file's content and returns formatted output.
-// It receives the file path and raw content and should return the processed content as a string.
-// This type is used for custom file processing in internal functions like processFile and processPathWithProcessor.
-type ProcessorFunc func(filePath string, content []byte) string
-
-// Logger provides a simple logging interface with different log levels.
-// All messages are sent to stderr with appropriate prefixes for their level.
-type Logger struct {
-	// verbose determines whether Verbose-level messages are displayed
-	verbose bool
-}
-
-// NewLogger creates a new Logger instance with the specified verbosity setting.
-// When verbose is false, calls to the Verbose method will be suppressed.
-func NewLogger(verbose bool) *Logger {
-	return &Logger{
-		verbose: verbose,
-	}
-}
-
-// Info logs an informational message to stderr.
-// These messages are always displayed regardless of the verbose setting.
-func (l *Logger) Info(format string, args ...interface{}) {
-	fmt.Fprintf(os.Stderr, format+"\n", args...)
-}
-
-// Warn logs a warning message to stderr.
-// Warning messages are prefixed with "warning: " and are always displayed.
-func (l *Logger) Warn(format string, args ...interface{}) {
-	fmt.Fprintf(os.Stderr, "warning: "+format+"\n", args...)
-}
-
-// Error logs an error message to stderr.
-// Error messages are prefixed with "error: " and are always displayed.
-func (l *Logger) Error(format string, args ...interface{}) {
-	fmt.Fprintf(os.Stderr, "error: "+format+"\n", args...)
-}
-
-// Verbose logs a message to stderr only if verbose mode is enabled.
-// These messages are useful for detailed progress information.
-func (l *Logger) Verbose(format string, args ...interface{}) {
-	if l.verbose {
-		fmt.Fprintf(os.Stderr, format+"\n", args...)
-	}
-}
-
-// Stats holds statistics about processed files and content.
-// It's returned by file processing functions to provide information
-// about the operation results without relying on logging.
-type Stats struct {
-	// FilesProcessed is the number of files successfully processed
-	FilesProcessed int
-
-	// FilesTotal is the total number of candidate files found before filtering
-	FilesTotal int
-
-	// Lines is the number of lines in the processed content
-	Lines int
-
-	// Chars is the number of characters in the processed content
-	Chars int
-
-	// Tokens is an estimated count of tokens in the processed content
-	Tokens int
-}
-
-// Note: The global gitAvailable variable and its initialization have been replaced
-// with a GitClient interface. This allows for better dependency injection and testing.
-// See git_client.go for the implementation details.
-
 // ProcessConfig is maintained for backward compatibility.
 // It processes the string-based fields in the Config struct and populates
 // the corresponding slice fields using the new helper functions.
 //
 // Note: This method is deprecated. New code should use the functional options pattern
 // with NewConfig() and option functions like WithInclude(), WithExclude(), etc.
+//
+// ProcessConfig is idempotent and may be called concurrently on the same Config.
 func (c *Config) ProcessConfig() {
+	configMu.Lock()
+	defer configMu.Unlock()
+
 	// Only re-process if the internal slices are nil or empty
 	// This ensures we don't overwrite slices already set by option functions
 	if c.includeExts == nil && c.include != "" {
@@ -261,378 +344,27 @@ func (c *Config) ProcessConfig() {
 	}
 }
 
-// isGitIgnored checks if a file is gitignored or hidden (internal helper).
-// It delegates the check to the GitClient implementation in the config.
-func isGitIgnored(file string, config *Config) bool {
-	return config.GitClient.IsGitIgnored(file)
-}
+// configMu serializes ProcessConfig and Clone so that lazily processing the
+// legacy string fields never races with copying a Config.
+var configMu sync.Mutex
 
-// getGitFiles retrieves files from a directory using Git's ls-files command (internal helper)
-// It delegates the operation to the GitClient implementation in the config.
-func getGitFiles(dir string, config *Config) ([]string, error) {
-	files, err := config.GitClient.GetGitFiles(dir)
-	if err != nil {
-		return nil, err
-	}
-
-	// Check if files still exist before returning them
-	var existingFiles []string
-	for _, filePath := range files {
-		if _, err := os.Stat(filePath); err == nil {
-			existingFiles = append(existingFiles, filePath)
-		}
-	}
-	return existingFiles, nil
-}
-
-// getFilesWithFilepathWalk retrieves files from a directory by walking the filesystem (internal helper)
-func getFilesWithFilepathWalk(dir string) ([]string, error) {
-	var files []string
-	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
-		}
-		if info.IsDir() {
-			if strings.HasPrefix(info.Name(), ".") && path != dir {
-				return filepath.SkipDir
-			}
-			return nil
-		}
-		if !strings.HasPrefix(info.Name(), ".") {
-			files = append(files, path)
-		}
-		return nil
-	})
-	return files, err
-}
-
-// getFilesFromDir retrieves all files to process from a directory.
-// It tries to use Git first and falls back to filepath.Walk if Git is not available
-// or the directory is not a Git repository. (internal helper)
-func getFilesFromDir(dir string, config *Config) ([]string, error) {
-	if config.GitClient.IsAvailable() {
-		files, err := getGitFiles(dir, config)
-		if err == nil {
-			return files, nil
-		}
-		// If there's an error running git ls-files and it's not "not a git repository", return the error
-		if !strings.Contains(err.Error(), "not a git repository") {
-			return nil, err
-		}
-		// Otherwise fall back to filepath.Walk
-	}
-
-	// Fallback to walking the directory, excluding hidden files and dirs
-	return getFilesWithFilepathWalk(dir)
-}
-
-// Constants for binary file detection
-const (
-	binarySampleSize            = 512 // Number of bytes to sample for binary detection
-	binaryNonPrintableThreshold = 0.3 // Threshold ratio of non-printable chars to consider a file binary
-)
-
-// isBinaryFile uses heuristics to determine if content is likely binary.
-// This is an internal helper that employs two main detection strategies:
-//  1. Presence of null bytes (ASCII 0): Any null byte indicates binary content
-//  2. High ratio of non-printable characters: If more than 30% of the first 512 bytes
-//     are non-printable, non-whitespace characters, the content is considered binary
-//
-// Note that this heuristic approach:
-//   - Only examines up to the first 512 bytes (configurable via binarySampleSize)
-//   - May produce false positives for some text files with unusual encoding
-//   - May produce false negatives for some binary files that appear text-like
-//   - Only considers ASCII control characters and DEL (127) as non-printable
-//   - Treats common whitespace characters (\n, \r, \t, space) as printable
-func isBinaryFile(content []byte) bool {
-	// Check for null bytes, which are common in binary files
-	if len(content) > 0 && bytes.IndexByte(content, 0) != -1 {
-		return true
-	}
-
-	// Check for a high percentage of non-printable, non-whitespace characters
-	// which suggest binary content
-	nonPrintable := 0
-	sampleSize := minInt(len(content), binarySampleSize) // Sample the first bytes
-	for i := 0; i < sampleSize; i++ {
-		// Check for non-printable characters (ASCII 0-31 except whitespace, and DEL which is 127)
-		if (content[i] < 32 && !isWhitespace(content[i])) || content[i] == 127 {
-			nonPrintable++
-		}
-	}
-
-	// If more than the threshold percentage of sampled bytes are non-printable, consider it binary
-	return float64(nonPrintable) > float64(sampleSize)*binaryNonPrintableThreshold
-}
-
-// isWhitespace checks if a byte is a whitespace character (unexported, internal helper)
-func isWhitespace(b byte) bool {
-	return b == '\n' || b == '\r' || b == '\t' || b == ' '
-}
-
-// minInt returns the minimum of two integers (unexported, internal helper)
-func minInt(a, b int) int {
-	if a < b {
-		return a
-	}
-	return b
-}
-
-// shouldProcess decides if a file should be processed based on all filters (internal helper)
-func shouldProcess(file string, config *Config) bool {
-	base := filepath.Base(file)
-	ext := strings.ToLower(filepath.Ext(file))
-
-	// Check exclude names filter
-	if len(config.excludeNames) > 0 && slices.Contains(config.excludeNames, base) {
-		return false
-	}
-
-	// Check include extensions filter
-	if len(config.includeExts) > 0 {
-		included := false
-		for _, includeExt := range config.includeExts {
-			if ext == includeExt {
-				included = true
-				break
-			}
-		}
-		if !included {
-			return false
-		}
-	}
-
-	// Check exclude extensions filter
-	if len(config.excludeExts) > 0 {
-		for _, excludeExt := range config.excludeExts {
-			if ext == excludeExt {
-				return false
-			}
-		}
-	}
-
-	return true
-}
-
-// processFile processes a single file with the given processor function and configuration.
-// It applies various filters (gitignore, extension/name filters, binary detection) and
-// passes the valid file's content to the processor function to generate formatted output.
-//
-// If the file should be skipped (doesn't exist, is gitignored, doesn't match filters,
-// or is binary), an empty string is returned and appropriate messages are logged.
-//
-// Parameters:
-//   - filePath: The path to the file to process
-//   - logger: Logger for status and error messages
-//   - config: Configuration options controlling filtering
-//   - processor: Function to process the file content
-//
-// Returns a formatted string for valid files or an empty string for skipped files.
-func processFile(filePath string, logger *Logger, config *Config, processor ProcessorFunc) string {
-	// First check if file exists
-	if _, statErr := os.Stat(filePath); statErr != nil {
-		if os.IsNotExist(statErr) {
-			// Skip without warning if the file simply doesn't exist
-			return ""
-		}
-		// Log warning for other errors
-		logger.Warn("stat %s: %v", filePath, statErr)
-		return ""
-	}
-
-	// Respect gitignore rules unless explicitly bypassed.
-	// The IgnoreGitignore flag allows processing files that would normally be excluded
-	// by .gitignore rules - useful for documentation files, context gathering, or
-	// when users need to process specific files regardless of Git's ignore patterns.
-	if isGitIgnored(filePath, config) {
-		if config.IgnoreGitignore {
-			logger.Verbose("processing gitignored file (bypass enabled): %s", filePath)
-		} else {
-			logger.Verbose("skipping gitignored file: %s", filePath)
-			return ""
-		}
-	}
-
-	// Check if file should be processed based on filters
-	if !shouldProcess(filePath, config) {
-		if len(config.excludeNames) > 0 && slices.Contains(config.excludeNames, filepath.Base(filePath)) {
-			logger.Verbose("skipping file (in exclude-names list): %s", filePath)
-		}
-		return ""
-	}
-
-	// Read file content
-	content, err := os.ReadFile(filePath)
-	if err != nil {
-		logger.Warn("cannot read %s: %v", filePath, err)
-		return ""
-	}
-
-	// Skip binary files
-	if isBinaryFile(content) {
-		logger.Verbose("skipping binary file: %s", filePath)
-		return ""
-	}
+// Clone returns a copy of the Config that can be modified independently.
+// Filter slices are copied deeply; the GitClient and Formatter are shared.
+func (c *Config) Clone() *Config {
+	configMu.Lock()
+	defer configMu.Unlock()
 
-	// Process the content
-	return processor(filePath, content)
-}
-
-
-// processPaths processes multiple file or directory paths according to the configuration.
-// It creates a customized processor function that tracks progress and formats output
-// using the config's Format template. The function first discovers all files to process
-// upfront, then processes them, avoiding redundant directory scans.
-//
-// Parameters:
-//   - paths: List of file or directory paths to process
-//   - config: Configuration for file filtering and processing
-//   - logger: Logger for status and error messages
-//
-// Returns:
-//   - A string containing the combined formatted content
-//   - Stats struct with information about processed files and content
-//   - An error if the processing fails, including ErrNoFilesProcessed if paths were provided,
-//     files were found (stats.FilesTotal > 0), but no files were processed due to filtering
-func processPaths(paths []string, config *Config, logger *Logger) (string, Stats, error) {
-	contentBuilder := &strings.Builder{}
-	processedFiles := 0
-
-	// Discover all files upfront to avoid redundant directory scans
-	var allFiles []string
-
-	// First, discover all files from all paths
-	for _, path := range paths {
-		logger.Verbose("Processing path: %s", path)
-
-		info, err := os.Stat(path)
-		if err != nil {
-			logger.Warn("%v", err)
-			continue
-		}
-
-		if info.IsDir() {
-			files, err := getFilesFromDir(path, config)
-			if err != nil {
-				logger.Warn("Error getting files from directory %s: %v", path, err)
-				continue
-			}
-			allFiles = append(allFiles, files...)
-		} else {
-			// It's a single file
-			allFiles = append(allFiles, path)
-		}
-	}
-
-	// Store total file count for stats and progress tracking
-	totalFiles := len(allFiles)
-	logger.Verbose("Found %d total files across all paths", totalFiles)
-
-	// Process all discovered files
-	for _, file := range allFiles {
-		// Create a processor function that tracks progress
-		processor := func(filepath string, fileContent []byte) string {
-			processedFiles++
-			logger.Verbose("Processing file (%d/%d): %s", processedFiles, totalFiles, filepath)
-
-			// Format the output using the custom format
-			output := config.Format
-			output = strings.ReplaceAll(output, "{path}", filepath)
-			output = strings.ReplaceAll(output, "{content}", string(fileContent))
-			return output
-		}
-
-		// Process the file directly without rediscovering it
-		output := processFile(file, logger, config, processor)
-		if output != "" {
-			contentBuilder.WriteString(output)
-		}
-	}
-
-	content := contentBuilder.String()
-
-	// Calculate statistics for the content
-	chars, lines, tokens := CalculateStatistics(content)
-
-	// Create and populate Stats struct
-	stats := Stats{
-		FilesProcessed: processedFiles,
-		FilesTotal:     totalFiles,
-		Lines:          lines,
-		Chars:          chars,
-		Tokens:         tokens,
-	}
-
-	// Check if paths were provided but no files ended up being processed
-	// Only return an error if paths exist but no files were processed due to filtering
-	if len(paths) > 0 && stats.FilesProcessed == 0 && stats.FilesTotal > 0 {
-		return content, stats, ErrNoFilesProcessed
-	}
-
-	return content, stats, nil
-}
-
-// WrapInContext wraps the content in top-level context tags.
-// This provides consistent formatting for the final output, making it easier
-// to identify the boundaries of the collected content.
-//
-// Parameter:
-//   - content: The raw content to wrap
-//
-// Returns:
-//   - The content wrapped with <context> tags
-func WrapInContext(content string) string {
-	return "<context>\n" + content + "</context>"
-}
-
-// estimateTokenCount provides a simple approximation of token count in text.
-// This is an internal helper function that uses a basic whitespace-based approach:
-//   - Counts transitions from non-whitespace sequences to whitespace
-//   - Treats any continuous sequence of non-whitespace characters as one token
-//   - Adds a final count if text ends with non-whitespace characters
-//
-// Note that this method:
-//   - Is significantly less sophisticated than actual LLM tokenizers
-//   - Doesn't account for subword tokenization used by most modern LLMs
-//   - May undercount tokens for punctuation that would be separate tokens in LLMs
-//   - May overcount for common words that LLMs represent as single tokens
-//   - Is intended for rough estimation purposes only, with accuracy varying
-//     by content type and language
-func estimateTokenCount(text string) int {
-	count := 0
-	inToken := false
-	for _, r := range text {
-		if unicode.IsSpace(r) {
-			if inToken {
-				count++
-				inToken = false
-			}
-		} else {
-			inToken = true
-		}
-	}
-	if inToken {
-		count++ // Count the last token if text ends with non-whitespace
-	}
-	return count
-}
-
-// CalculateStatistics calculates useful statistics about the content.
-// This function analyzes the provided content and returns counts of characters,
-// lines, and tokens (words or code-like tokens) it contains.
-//
-// Parameter:
-//   - content: The content to analyze
-//
-// Returns:
-//   - charCount: Total number of characters in the content
-//   - lineCount: Total number of lines in the content (based on newlines)
-//   - tokenCount: Estimated number of tokens/words in the content
-func CalculateStatistics(content string) (charCount, lineCount, tokenCount int) {
-	charCount = len(content)
-	lineCount = strings.Count(content, "\n") + 1
-	tokenCount = estimateTokenCount(content)
-	return charCount, lineCount, tokenCount
+	clone := *c
+	clone.includeExts = slices.Clone(c.includeExts)
+	clone.excludeExts = slices.Clone(c.excludeExts)
+	clone.excludeNames = slices.Clone(c.excludeNames)
+	clone.hiddenAllowlist = slices.Clone(c.hiddenAllowlist)
+	clone.pathRules = slices.Clone(c.pathRules)
+	clone.trimPriority = slices.Clone(c.trimPriority)
+	clone.transformers = slices.Clone(c.transformers)
+	clone.fileFilters = slices.Clone(c.fileFilters)
+	clone.rootLabels = slices.Clone(c.rootLabels)
+	return &clone
 }
 
 // ProcessProject collects and formats content from files in the specified paths.
@@ -641,8 +373,8 @@ func CalculateStatistics(content string) (charCount, lineCount, tokenCount int)
 // formatting according to the provided configuration.
 //
 // If config is nil, default configuration is used. For backward compatibility, the function
-// calls ProcessConfig() on the configuration, but this is unnecessary when using the functional
-// options pattern.
+// calls ProcessConfig() on a private copy of the configuration, but this is unnecessary when
+// using the functional options pattern. The caller's Config is never modified.
 //
 // Parameters:
 //   - paths: List of file or directory paths to process
@@ -651,12 +383,18 @@ func CalculateStatistics(content string) (charCount, lineCount, tokenCount int)
 // Returns:
 //   - The formatted content wrapped in context tags
 //   - Stats struct with information about processed files and content
-//   - An error if no paths are provided or if processing fails
+//   - An error if no paths are provided, if processing fails, if the output exceeds
+//     the target model's context window in strict mode (ErrContextWindowExceeded),
+//     or if files are skipped for a reason given to WithStrictSkips (ErrFilesSkipped)
 func ProcessProject(paths []string, config *Config) (string, Stats, error) {
 	if config == nil {
 		config = NewConfig()
 	}
 
+	// Work on a private copy so the caller's Config is never mutated and
+	// can be shared across concurrent calls
+	config = config.Clone()
+
 	// For backward compatibility with existing code
 	// This call is unnecessary when using the functional options pattern
 	config.ProcessConfig()
@@ -666,58 +404,25 @@ func ProcessProject(paths []string, config *Config) (string, Stats, error) {
 	if len(paths) == 0 {
 		return "", Stats{}, fmt.Errorf("no paths provided")
 	}
+	return processProject(paths, config, logger)
+}
 
+// processProject processes and wraps paths for ProcessProject and
+// ProcessChanges, using a private copy of the config (internal helper)
+func processProject(paths []string, config *Config, logger *Logger) (string, Stats, error) {
 	// Process paths
 	content, stats, err := processPaths(paths, config, logger)
 	if err != nil {
 		return "", Stats{}, err
 	}
 
-	// Wrap content in context tag
-	formattedContent := WrapInContext(content)
-
-	return formattedContent, stats, nil
-}
-
-// WriteToFile writes the content to a file at the specified path.
-// This is a convenience function for saving the output of ProcessProject
-// directly to a file. The file is created with 0644 permissions.
-// Parent directories are automatically created if they don't exist.
-//
-// By default, it will not overwrite existing files unless overwrite is set to true.
-// If the file exists and overwrite is false, it returns ErrFileExists.
-//
-// Parameters:
-//   - content: The content to write to the file
-//   - filePath: The path where the file should be created
-//   - overwrite: If true, existing files will be overwritten; if false, returns an error when the file exists
-//
-// Returns:
-//   - An error if the file cannot be written (e.g., due to directory creation failure,
-//     permissions issues, file already exists with overwrite=false, or other I/O errors)
-func WriteToFile(content, filePath string, overwrite bool) error {
-	// Check if file exists and handle overwrite flag
-	if !overwrite {
-		_, err := os.Stat(filePath)
-		if err == nil {
-			// File exists and overwrite is false, return error
-			return fmt.Errorf("%w: %s", ErrFileExists, filePath)
-		} else if !os.IsNotExist(err) {
-			// Some other error occurred
-			return fmt.Errorf("failed to check if file %q exists: %w", filePath, err)
-		}
-		// File doesn't exist, proceed with creation
+	// Warn, or fail in strict mode, when the output won't fit the target model
+	if err := checkContextWindow(stats.Tokens, config, logger); err != nil {
+		return "", stats, err
 	}
 
-	// Create parent directories if they don't exist
-	dirPath := filepath.Dir(filePath)
-	if err := os.MkdirAll(dirPath, 0755); err != nil {
-		return fmt.Errorf("failed to create parent directories for %q: %w", filePath, err)
-	}
+	// Wrap content using the configured formatter
+	formattedContent := wrapOutput(content, stats, config)
 
-	// Write the file
-	if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
-		return fmt.Errorf("failed to write to file %q: %w", filePath, err)
-	}
-	return nil
+	return formattedContent, stats, nil
 }
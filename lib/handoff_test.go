@@ -9,6 +9,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"testing"
 )
@@ -95,6 +96,38 @@ func TestShouldProcess(t *testing.T) {
 			},
 			shouldProc: false,
 		},
+		{
+			name: "Match exclude name wildcard",
+			file: "src/user_mock.go",
+			config: &Config{
+				excludeNames: []string{"*_mock.go"},
+			},
+			shouldProc: false,
+		},
+		{
+			name: "Match exclude name wildcard with multiple dots",
+			file: "api.generated.ts",
+			config: &Config{
+				excludeNames: []string{"*.generated.ts"},
+			},
+			shouldProc: false,
+		},
+		{
+			name: "No match exclude name wildcard",
+			file: "user.go",
+			config: &Config{
+				excludeNames: []string{"*_mock.go"},
+			},
+			shouldProc: true,
+		},
+		{
+			name: "Malformed exclude name pattern compared literally",
+			file: "[weird.txt",
+			config: &Config{
+				excludeNames: []string{"[weird.txt"},
+			},
+			shouldProc: false,
+		},
 		{
 			name:       "Default config (no filters)",
 			file:       "anything.txt",
@@ -215,7 +248,7 @@ func TestProcessFile(t *testing.T) {
 	logger := NewLogger(false)
 
 	// Test processing a valid file
-	result := processFile(filePath, logger, config, processor)
+	result := processFile(filePath, nil, logger, config, processor)
 	expected := "PROCESSED: " + filePath + "\n" + fileContent
 	if result != expected {
 		t.Errorf("processFile() = %q, want %q", result, expected)
@@ -223,7 +256,7 @@ func TestProcessFile(t *testing.T) {
 
 	// Test file that doesn't exist
 	nonExistentPath := filepath.Join(tmpDir, "non-existent")
-	result = processFile(nonExistentPath, logger, config, processor)
+	result = processFile(nonExistentPath, nil, logger, config, processor)
 	if result != "" {
 		t.Errorf("processFile() for non-existent file returned %q, want empty string", result)
 	}
@@ -237,7 +270,7 @@ func TestProcessFile(t *testing.T) {
 	}
 
 	// Test processing a binary file
-	result = processFile(binaryFilePath, logger, config, processor)
+	result = processFile(binaryFilePath, nil, logger, config, processor)
 	if result != "" {
 		t.Errorf("processFile() for binary file returned %q, want empty string", result)
 	}
@@ -247,7 +280,7 @@ func TestProcessFile(t *testing.T) {
 		excludeExts: []string{".txt"},
 		GitClient:   NewMockGitClient(false),
 	}
-	result = processFile(filePath, logger, configWithExclude, processor)
+	result = processFile(filePath, nil, logger, configWithExclude, processor)
 	if result != "" {
 		t.Errorf("processFile() for excluded extension returned %q, want empty string", result)
 	}
@@ -885,3 +918,240 @@ func TestWriteToFile(t *testing.T) {
 		}
 	})
 }
+
+// TestProcessPaths_StatsMatchContent tests that per-file accumulated statistics
+// match a full scan of the combined content
+func TestProcessPaths_StatsMatchContent(t *testing.T) {
+	tmpDir, _ := createTestDir(t)
+	defer func() {
+		if cleanErr := os.RemoveAll(tmpDir); cleanErr != nil {
+			t.Logf("Failed to clean up test directory: %v", cleanErr)
+		}
+	}()
+
+	config := NewConfig(WithGitClient(NewMockGitClient(false)))
+	content, stats, err := processPaths([]string{tmpDir}, config, NewLogger(false))
+	if err != nil {
+		t.Fatalf("processPaths failed: %v", err)
+	}
+
+	chars, lines, tokens := CalculateStatistics(content)
+	if stats.Chars != chars {
+		t.Errorf("stats.Chars = %d, want %d", stats.Chars, chars)
+	}
+	if stats.Lines != lines {
+		t.Errorf("stats.Lines = %d, want %d", stats.Lines, lines)
+	}
+	if stats.Tokens != tokens {
+		t.Errorf("stats.Tokens = %d, want %d", stats.Tokens, tokens)
+	}
+
+	// Included files are listed in output order
+	if len(stats.IncludedFiles) != stats.FilesProcessed {
+		t.Fatalf("len(stats.IncludedFiles) = %d, want %d", len(stats.IncludedFiles), stats.FilesProcessed)
+	}
+	offset := 0
+	for i, path := range stats.IncludedFiles {
+		if path != stats.Files[i].Path {
+			t.Errorf("stats.IncludedFiles[%d] = %q, want %q", i, path, stats.Files[i].Path)
+		}
+		index := strings.Index(content[offset:], "<"+path+">")
+		if index < 0 {
+			t.Fatalf("%s is missing from the content after offset %d", path, offset)
+		}
+		offset += index
+	}
+}
+
+// TestConfigClone tests that a cloned Config can be modified independently
+func TestConfigClone(t *testing.T) {
+	original := NewConfig(
+		WithInclude(".go,.md"),
+		WithExcludeNames("go.sum"),
+		WithGitClient(NewMockGitClient(false)),
+	)
+
+	clone := original.Clone()
+	clone.includeExts[0] = ".txt"
+	clone.Verbose = true
+
+	if !equalSlices(original.includeExts, []string{".go", ".md"}) {
+		t.Errorf("original includeExts = %v, want [.go .md]", original.includeExts)
+	}
+	if original.Verbose {
+		t.Error("original Verbose changed after modifying clone")
+	}
+	if clone.GitClient != original.GitClient {
+		t.Error("clone should share the original GitClient")
+	}
+}
+
+// TestConfigFilters tests reporting the effective filters, including legacy string fields
+func TestConfigFilters(t *testing.T) {
+	config := &Config{include: "go,md", excludeNamesStr: "go.sum"}
+	WithHiddenAllowlist(".github")(config)
+
+	filters := config.Filters()
+	if !equalSlices(filters.Include, []string{".go", ".md"}) {
+		t.Errorf("Filters().Include = %v, want [.go .md]", filters.Include)
+	}
+	if !equalSlices(filters.ExcludeNames, []string{"go.sum"}) {
+		t.Errorf("Filters().ExcludeNames = %v, want [go.sum]", filters.ExcludeNames)
+	}
+	if !equalSlices(filters.HiddenAllowlist, []string{".github"}) {
+		t.Errorf("Filters().HiddenAllowlist = %v, want [.github]", filters.HiddenAllowlist)
+	}
+	if len(filters.Exclude) != 0 {
+		t.Errorf("Filters().Exclude = %v, want none", filters.Exclude)
+	}
+
+	filters.Include[0] = ".txt"
+	if config.includeExts[0] != ".go" {
+		t.Error("modifying Filters() changed the config")
+	}
+}
+
+// TestProcessProjectDoesNotMutateConfig tests that ProcessProject leaves the caller's Config untouched
+// and can be called concurrently with a shared Config
+func TestProcessProjectDoesNotMutateConfig(t *testing.T) {
+	tmpDir, _ := createTestDir(t)
+	defer func() {
+		if cleanErr := os.RemoveAll(tmpDir); cleanErr != nil {
+			t.Logf("Failed to clean up test directory: %v", cleanErr)
+		}
+	}()
+
+	config := &Config{
+		Format:    DefaultFormat,
+		include:   ".go",
+		GitClient: NewMockGitClient(false),
+	}
+
+	const workers = 8
+	errs := make(chan error, workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			_, _, err := ProcessProject([]string{tmpDir}, config)
+			errs <- err
+		}()
+	}
+	for i := 0; i < workers; i++ {
+		if err := <-errs; err != nil {
+			t.Errorf("ProcessProject failed: %v", err)
+		}
+	}
+
+	if config.includeExts != nil {
+		t.Errorf("ProcessProject mutated caller's config: includeExts = %v", config.includeExts)
+	}
+}
+
+// TestIncludeContentRegex tests that only files with matching content are processed
+func TestIncludeContentRegex(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "handoff-content-regex-test-")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer func() {
+		if cleanErr := os.RemoveAll(tmpDir); cleanErr != nil {
+			t.Logf("Failed to clean up temp directory: %v", cleanErr)
+		}
+	}()
+
+	files := map[string]string{
+		"payment.go": "package billing\n\nvar svc PaymentService\n",
+		"user.go":    "package users\n\ntype User struct{}\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to create file %s: %v", name, err)
+		}
+	}
+
+	config := NewConfig(
+		WithGitClient(NewMockGitClient(false)),
+		WithIncludeContentRegex(regexp.MustCompile(`\bPaymentService\b`)),
+	)
+	content, stats, err := ProcessProject([]string{tmpDir}, config)
+	if err != nil {
+		t.Fatalf("ProcessProject failed: %v", err)
+	}
+
+	if !strings.Contains(content, "payment.go") {
+		t.Error("content should include payment.go, which references PaymentService")
+	}
+	if strings.Contains(content, "user.go") {
+		t.Error("content should not include user.go, which doesn't reference PaymentService")
+	}
+	if stats.FilesProcessed != 1 || stats.FilesTotal != 2 {
+		t.Errorf("stats = %d/%d files, want 1/2", stats.FilesProcessed, stats.FilesTotal)
+	}
+
+	// No file matching the expression is reported as ErrNoFilesProcessed
+	config = NewConfig(WithGitClient(NewMockGitClient(false)), WithIncludeContentRegex(regexp.MustCompile("NoSuchSymbol")))
+	if _, _, err := ProcessProject([]string{tmpDir}, config); !errors.Is(err, ErrNoFilesProcessed) {
+		t.Errorf("expected ErrNoFilesProcessed, got %v", err)
+	}
+}
+
+// TestFileExists tests the FileExists function
+func TestFileExists(t *testing.T) {
+	// Create temporary test directory
+	tempDir, err := os.MkdirTemp("", "handoff-test-")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer func() {
+		if cleanErr := os.RemoveAll(tempDir); cleanErr != nil {
+			t.Logf("Failed to clean up temp directory: %v", cleanErr)
+		}
+	}()
+
+	// Create test file
+	existingFilePath := filepath.Join(tempDir, "existing.txt")
+	err = os.WriteFile(existingFilePath, []byte("Test content"), 0644)
+	if err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	// Path to non-existent file
+	nonExistentPath := filepath.Join(tempDir, "nonexistent.txt")
+
+	// Test cases
+	testCases := []struct {
+		name     string
+		path     string
+		expected bool
+		wantErr  bool
+	}{
+		{
+			name:     "Existing file",
+			path:     existingFilePath,
+			expected: true,
+			wantErr:  false,
+		},
+		{
+			name:     "Non-existent file",
+			path:     nonExistentPath,
+			expected: false,
+			wantErr:  false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			exists, err := FileExists(tc.path)
+
+			// Check error condition
+			if (err != nil) != tc.wantErr {
+				t.Errorf("FileExists(%q) error = %v, wantErr %v", tc.path, err, tc.wantErr)
+				return
+			}
+
+			// Check return value
+			if exists != tc.expected {
+				t.Errorf("FileExists(%q) = %v, want %v", tc.path, exists, tc.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,234 @@
+package handoff
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// WithModifiedAfter keeps only files last changed after t, so a handoff can
+// focus on recently touched code. A file's last change is the date of the
+// last commit touching it when git has one, since checkouts reset modification
+// times; untracked files and repositories without git use the file's
+// modification time. The zero time disables the filter.
+func WithModifiedAfter(t time.Time) Option {
+	return func(c *Config) {
+		c.ModifiedAfter = t
+	}
+}
+
+// ParseDate parses a date such as 2024-01-01 in the local time zone, or an
+// RFC 3339 time, for use with WithModifiedAfter.
+func ParseDate(value string) (time.Time, error) {
+	date, err := time.ParseInLocation(time.DateOnly, value, time.Local)
+	if err == nil {
+		return date, nil
+	}
+	if date, err = time.Parse(time.RFC3339, value); err == nil {
+		return date, nil
+	}
+	return time.Time{}, fmt.Errorf("%q: want a date such as 2024-01-01 or an RFC 3339 time", value)
+}
+
+// ParsePeriod parses a non-negative duration that may use d (days) and w
+// (weeks) units in addition to those accepted by time.ParseDuration, so that
+// WithModifiedAfter(time.Now().Add(-period)) keeps recently changed files.
+func ParsePeriod(value string) (time.Duration, error) {
+	for suffix, unit := range map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour} {
+		if number, ok := strings.CutSuffix(value, suffix); ok {
+			n, err := strconv.Atoi(number)
+			if err != nil || n < 0 {
+				return 0, fmt.Errorf("%q: want a period such as 7d, 2w, or 36h", value)
+			}
+			return time.Duration(n) * unit, nil
+		}
+	}
+	period, err := time.ParseDuration(value)
+	if err != nil || period < 0 {
+		return 0, fmt.Errorf("%q: want a period such as 7d, 2w, or 36h", value)
+	}
+	return period, nil
+}
+
+// lastModified returns when a file was last changed: its last commit date when
+// available and the file has no uncommitted changes, otherwise its
+// modification time. It returns the zero time when neither can be determined
+// (internal helper).
+func lastModified(filePath string, config *Config) time.Time {
+	info, statErr := os.Stat(filePath)
+	if dater, ok := config.GitClient.(CommitDater); ok && config.GitClient.IsAvailable() && !hasUncommittedChanges(filePath, config) {
+		if committed, err := dater.LastCommitTime(filePath); err == nil && !committed.IsZero() {
+			return committed
+		}
+	}
+	if statErr != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
+
+// hasUncommittedChanges reports whether a file differs from HEAD, staged or
+// not, listing the changes once per directory. Files whose changes can't be
+// listed count as unchanged. (internal helper)
+func hasUncommittedChanges(filePath string, config *Config) bool {
+	dir := filepath.Dir(filePath)
+	changed, ok := config.uncommitted[dir]
+	if !ok {
+		if config.uncommitted == nil {
+			config.uncommitted = make(map[string]map[string]bool)
+		}
+		if files, err := config.GitClient.ChangedFiles(dir, ""); err == nil {
+			changed = make(map[string]bool, len(files))
+			for _, file := range files {
+				changed[filepath.Clean(file)] = true
+			}
+		}
+		config.uncommitted[dir] = changed
+	}
+	return changed[filepath.Clean(filePath)]
+}
+
+// AuthorMatch selects which commits decide whether a file belongs to an author
+type AuthorMatch string
+
+const (
+	// AuthorLast matches files whose most recent commit is by the author
+	AuthorLast AuthorMatch = "last"
+
+	// AuthorMost matches files where the author made more commits than anyone else
+	AuthorMost AuthorMatch = "most"
+)
+
+// ParseAuthorMatch converts a name such as "last" or "most" into an AuthorMatch.
+func ParseAuthorMatch(name string) (AuthorMatch, error) {
+	switch match := AuthorMatch(strings.ToLower(name)); match {
+	case AuthorLast, AuthorMost:
+		return match, nil
+	}
+	return "", fmt.Errorf("unknown author match %q (want last or most)", name)
+}
+
+// WithAuthor keeps only files belonging to an author according to git history,
+// which is useful for gathering context about a teammate's subsystem. The
+// author matches case-insensitively against any part of "Name <email>". By
+// default a file belongs to the author of its last commit; AuthorMost assigns
+// it to whoever made the most commits to it. Files without commits never match.
+// An empty author disables the filter.
+func WithAuthor(author string, match AuthorMatch) Option {
+	return func(c *Config) {
+		c.Author = author
+		c.AuthorMatch = match
+	}
+}
+
+// fileAuthor returns the author a file belongs to under the given match rule,
+// or an empty string when git history is unavailable (internal helper)
+func fileAuthor(filePath string, match AuthorMatch, gitClient GitClient) string {
+	lister, ok := gitClient.(AuthorLister)
+	if !ok || !gitClient.IsAvailable() {
+		return ""
+	}
+	authors, err := lister.CommitAuthors(filePath)
+	if err != nil || len(authors) == 0 {
+		return ""
+	}
+	if match != AuthorMost {
+		return authors[0]
+	}
+
+	// Count commits per author; ties go to the most recent author
+	counts := make(map[string]int)
+	for _, author := range authors {
+		counts[author]++
+	}
+	best := authors[0]
+	for _, author := range authors {
+		if counts[author] > counts[best] {
+			best = author
+		}
+	}
+	return best
+}
+
+// matchesAuthor reports whether a "Name <email>" author matches the filter (internal helper)
+func matchesAuthor(author, filter string) bool {
+	return author != "" && strings.Contains(strings.ToLower(author), strings.ToLower(filter))
+}
+
+// FileOrder selects the order in which files appear in the output
+type FileOrder string
+
+const (
+	// OrderDiscovery keeps files in the order they are discovered (the default)
+	OrderDiscovery FileOrder = "discovery"
+
+	// OrderChurn puts the most frequently changed files first, by lines added
+	// and deleted across the git history
+	OrderChurn FileOrder = "churn"
+
+	// OrderEntryPoints puts likely entry points such as main.go, cmd/*, and
+	// index.ts first, then the other source files, most imported first, then
+	// tests and fixtures
+	OrderEntryPoints FileOrder = "entry"
+)
+
+// ParseFileOrder converts a name such as "churn" into a FileOrder.
+func ParseFileOrder(name string) (FileOrder, error) {
+	switch order := FileOrder(strings.ToLower(name)); order {
+	case OrderDiscovery, OrderChurn, OrderEntryPoints:
+		return order, nil
+	}
+	return "", fmt.Errorf("unknown order %q (want discovery, churn, or entry)", name)
+}
+
+// WithOrder sets the order of files in the output. With OrderChurn, hot files
+// come first on the theory that they are the most relevant context, and under a
+// token budget the least-changed files are trimmed first among files the trim
+// priority doesn't distinguish. With OrderEntryPoints, files are read the way a
+// developer would read the program, from its entry points inward.
+func WithOrder(order FileOrder) Option {
+	return func(c *Config) {
+		c.Order = order
+	}
+}
+
+// sortByChurn orders files by descending churn, keeping discovery order for
+// files with equal churn. Churn is read once per directory argument, or per
+// parent directory for file arguments (internal helper).
+func sortByChurn(files []formattedFile, paths []string, config *Config, logger *Logger) {
+	counter, ok := config.GitClient.(ChurnCounter)
+	if !ok || !config.GitClient.IsAvailable() {
+		logger.Warn("ordering by churn requires git; keeping discovery order")
+		return
+	}
+
+	churn := make(map[string]int)
+	seen := make(map[string]bool)
+	for _, path := range paths {
+		dir := path
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			dir = filepath.Dir(path)
+		}
+		if seen[dir] {
+			continue
+		}
+		seen[dir] = true
+
+		dirChurn, err := counter.FileChurn(dir)
+		if err != nil {
+			logger.Verbose("cannot read churn for %s: %v", dir, err)
+			continue
+		}
+		for file, lines := range dirChurn {
+			churn[filepath.Clean(config.displayPath(file))] = lines
+		}
+	}
+
+	sort.SliceStable(files, func(i, j int) bool {
+		return churn[filepath.Clean(files[i].path)] > churn[filepath.Clean(files[j].path)]
+	})
+}
@@ -0,0 +1,224 @@
+package handoff
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestModifiedAfter tests filtering by commit date or modification time
+func TestModifiedAfter(t *testing.T) {
+	dir := t.TempDir()
+	oldFile := filepath.Join(dir, "old.txt")
+	newFile := filepath.Join(dir, "new.txt")
+	for _, path := range []string{oldFile, newFile} {
+		if err := os.WriteFile(path, []byte(filepath.Base(path)+" content\n"), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", path, err)
+		}
+	}
+	longAgo := time.Now().AddDate(-1, 0, 0)
+	if err := os.Chtimes(oldFile, longAgo, longAgo); err != nil {
+		t.Fatalf("Failed to set times: %v", err)
+	}
+	cutoff := time.Now().AddDate(0, 0, -7)
+
+	t.Run("Modification time without git", func(t *testing.T) {
+		config := NewConfig(WithGitClient(NewMockGitClient(false)), WithModifiedAfter(cutoff))
+		content, _, err := ProcessProject([]string{dir}, config)
+		if err != nil {
+			t.Fatalf("ProcessProject failed: %v", err)
+		}
+		if !strings.Contains(content, "new.txt content") || strings.Contains(content, "old.txt content") {
+			t.Errorf("expected only new.txt, got:\n%s", content)
+		}
+	})
+
+	t.Run("Commit date takes precedence", func(t *testing.T) {
+		// A fresh checkout gives every file a recent modification time, so the
+		// commit date decides when there is one
+		git := NewMockGitClient(true)
+		git.SetLastCommitTime(newFile, longAgo)
+		git.SetLastCommitTime(oldFile, time.Now())
+		config := NewConfig(WithGitClient(git), WithModifiedAfter(cutoff))
+		content, _, err := ProcessProject([]string{newFile, oldFile}, config)
+		if err != nil {
+			t.Fatalf("ProcessProject failed: %v", err)
+		}
+		if strings.Contains(content, "new.txt content") || !strings.Contains(content, "old.txt content") {
+			t.Errorf("expected only old.txt, got:\n%s", content)
+		}
+	})
+
+	t.Run("Uncommitted changes use modification time", func(t *testing.T) {
+		// An edit since the last commit makes the file recent even though
+		// its commit is old
+		git := NewMockGitClient(true)
+		git.SetLastCommitTime(newFile, longAgo)
+		git.SetLastCommitTime(oldFile, longAgo)
+		git.SetChangedFiles(dir, "", []string{newFile})
+		config := NewConfig(WithGitClient(git), WithModifiedAfter(cutoff))
+		content, _, err := ProcessProject([]string{newFile, oldFile}, config)
+		if err != nil {
+			t.Fatalf("ProcessProject failed: %v", err)
+		}
+		if !strings.Contains(content, "new.txt content") || strings.Contains(content, "old.txt content") {
+			t.Errorf("expected only new.txt, got:\n%s", content)
+		}
+	})
+
+	t.Run("Client without CommitDater", func(t *testing.T) {
+		git := NewMockGitClient(true)
+		git.SetLastCommitTime(newFile, longAgo)
+		config := NewConfig(WithGitClient(basicGitClient{git}), WithModifiedAfter(cutoff))
+		content, _, err := ProcessProject([]string{newFile, oldFile}, config)
+		if err != nil {
+			t.Fatalf("ProcessProject failed: %v", err)
+		}
+		if !strings.Contains(content, "new.txt content") || strings.Contains(content, "old.txt content") {
+			t.Errorf("expected only new.txt, got:\n%s", content)
+		}
+	})
+}
+
+// TestWithAuthor tests selecting files by their last or predominant author
+func TestWithAuthor(t *testing.T) {
+	dir := t.TempDir()
+	paths := make(map[string]string)
+	for _, name := range []string{"alice.go", "shared.go", "bob.go", "untracked.go"} {
+		paths[name] = filepath.Join(dir, name)
+		if err := os.WriteFile(paths[name], []byte("// "+name+"\n"), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	alice, bob := "Alice Smith <alice@example.com>", "Bob Jones <bob@example.com>"
+	git := NewMockGitClient(true)
+	git.SetCommitAuthors(paths["alice.go"], []string{alice})
+	git.SetCommitAuthors(paths["shared.go"], []string{bob, alice, alice})
+	git.SetCommitAuthors(paths["bob.go"], []string{bob, bob, alice})
+
+	testCases := []struct {
+		name   string
+		author string
+		match  AuthorMatch
+		want   []string
+	}{
+		{"Last author by name", "alice", AuthorLast, []string{"alice.go"}},
+		{"Last author by email", "BOB@example", AuthorLast, []string{"shared.go", "bob.go"}},
+		{"Most commits", "alice", AuthorMost, []string{"alice.go", "shared.go"}},
+		{"Default is last", "bob", "", []string{"shared.go", "bob.go"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := NewConfig(WithGitClient(git), WithAuthor(tc.author, tc.match))
+			content, _, err := ProcessProject([]string{dir}, config)
+			if err != nil {
+				t.Fatalf("ProcessProject failed: %v", err)
+			}
+			for name := range paths {
+				want := false
+				for _, w := range tc.want {
+					want = want || w == name
+				}
+				if got := strings.Contains(content, "// "+name); got != want {
+					t.Errorf("%s included = %v, want %v", name, got, want)
+				}
+			}
+		})
+	}
+
+	// A client without AuthorLister matches no files
+	config := NewConfig(WithGitClient(basicGitClient{git}), WithAuthor("alice", AuthorLast))
+	if content, _, err := ProcessProject([]string{dir}, config); err == nil {
+		t.Errorf("expected no files without AuthorLister, got:\n%s", content)
+	}
+}
+
+// TestParseAuthorMatch tests parsing author match names
+func TestParseAuthorMatch(t *testing.T) {
+	if match, err := ParseAuthorMatch("Most"); err != nil || match != AuthorMost {
+		t.Errorf("ParseAuthorMatch(\"Most\") = %q, %v", match, err)
+	}
+	if _, err := ParseAuthorMatch("first"); err == nil {
+		t.Error("ParseAuthorMatch(\"first\") succeeded, want error")
+	}
+}
+
+// TestOrderChurn tests putting the most frequently changed files first
+func TestOrderChurn(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"a.go", "b.go", "c.go"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("// "+name+"\n"), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	git := NewMockGitClient(true)
+	git.SetFileChurn(dir, map[string]int{
+		filepath.Join(dir, "b.go"): 120,
+		filepath.Join(dir, "c.go"): 30,
+	})
+
+	config := NewConfig(WithGitClient(git), WithOrder(OrderChurn))
+	content, _, err := ProcessProject([]string{dir}, config)
+	if err != nil {
+		t.Fatalf("ProcessProject failed: %v", err)
+	}
+	b, c, a := strings.Index(content, "// b.go"), strings.Index(content, "// c.go"), strings.Index(content, "// a.go")
+	if b < 0 || c < 0 || a < 0 || !(b < c && c < a) {
+		t.Errorf("expected b.go, c.go, a.go order, got:\n%s", content)
+	}
+
+	// A client without ChurnCounter keeps discovery order
+	config = NewConfig(WithGitClient(basicGitClient{git}), WithOrder(OrderChurn))
+	content, _, err = ProcessProject([]string{dir}, config)
+	if err != nil {
+		t.Fatalf("ProcessProject failed: %v", err)
+	}
+	a, b, c = strings.Index(content, "// a.go"), strings.Index(content, "// b.go"), strings.Index(content, "// c.go")
+	if a < 0 || b < 0 || c < 0 || !(a < b && b < c) {
+		t.Errorf("expected discovery order without ChurnCounter, got:\n%s", content)
+	}
+
+	if _, err := ParseFileOrder("size"); err == nil {
+		t.Error("ParseFileOrder(\"size\") succeeded, want error")
+	}
+}
+
+// TestParsePeriod tests parsing periods with day and week units
+func TestParsePeriod(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{value: "7d", want: 7 * 24 * time.Hour},
+		{value: "2w", want: 14 * 24 * time.Hour},
+		{value: "36h", want: 36 * time.Hour},
+		{value: "7 days", wantErr: true},
+		{value: "-3d", wantErr: true},
+		{value: "-1h", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParsePeriod(tt.value)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParsePeriod(%q) = %v, %v; want %v, error %v", tt.value, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+// TestParseDate tests parsing dates and RFC 3339 times
+func TestParseDate(t *testing.T) {
+	if got, err := ParseDate("2024-01-01"); err != nil || !got.Equal(time.Date(2024, 1, 1, 0, 0, 0, 0, time.Local)) {
+		t.Errorf("ParseDate(2024-01-01) = %v, %v", got, err)
+	}
+	if got, err := ParseDate("2024-06-01T08:00:00Z"); err != nil || !got.Equal(time.Date(2024, 6, 1, 8, 0, 0, 0, time.UTC)) {
+		t.Errorf("ParseDate(RFC 3339) = %v, %v", got, err)
+	}
+	if _, err := ParseDate("last week"); err == nil {
+		t.Error("ParseDate(last week) succeeded, want error")
+	}
+}
@@ -0,0 +1,49 @@
+package handoff
+
+// Hooks are callbacks invoked as files move through the processing pipeline,
+// for building progress displays and audit logs without reimplementing it.
+// Any of the callbacks may be nil. They are called synchronously, in discovery
+// order, so slow callbacks slow down processing.
+type Hooks struct {
+	// OnFileStart is called for each discovered file before it is filtered and read
+	OnFileStart func(path string)
+
+	// OnFileSkipped is called when a file is left out of the output, with the
+	// reason it was skipped
+	OnFileSkipped func(path string, reason SkipReason)
+
+	// OnFileDone is called when a file has been processed, with its statistics.
+	// Files may still be trimmed afterwards to fit a token budget; Stats.Files
+	// reports what ended up in the output. Files dropped outright because they
+	// alone exceed the budget are never reported as done.
+	OnFileDone func(path string, stat FileStat)
+}
+
+// WithHooks sets the callbacks invoked as files are processed, replacing any
+// hooks set earlier.
+func WithHooks(hooks Hooks) Option {
+	return func(c *Config) {
+		c.Hooks = hooks
+	}
+}
+
+// fileStarted calls OnFileStart if it is set
+func (h Hooks) fileStarted(path string) {
+	if h.OnFileStart != nil {
+		h.OnFileStart(path)
+	}
+}
+
+// fileSkipped calls OnFileSkipped if it is set
+func (h Hooks) fileSkipped(path string, reason SkipReason) {
+	if h.OnFileSkipped != nil {
+		h.OnFileSkipped(path, reason)
+	}
+}
+
+// fileDone calls OnFileDone if it is set
+func (h Hooks) fileDone(path string, stat FileStat) {
+	if h.OnFileDone != nil {
+		h.OnFileDone(path, stat)
+	}
+}
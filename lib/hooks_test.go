@@ -0,0 +1,82 @@
+package handoff
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// TestHooks tests that lifecycle hooks see every file with its outcome
+func TestHooks(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string][]byte{
+		"main.go":   []byte("package main\n"),
+		"notes.txt": []byte("excluded\n"),
+		"image.bin": {0x89, 'P', 'N', 'G', 0x00, 0x01},
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), content, 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	var started []string
+	skipped := make(map[string]SkipReason)
+	done := make(map[string]FileStat)
+	config := NewConfig(
+		WithGitClient(NewMockGitClient(false)),
+		WithExclude(".txt"),
+		WithHooks(Hooks{
+			OnFileStart:   func(path string) { started = append(started, filepath.Base(path)) },
+			OnFileSkipped: func(path string, reason SkipReason) { skipped[filepath.Base(path)] = reason },
+			OnFileDone:    func(path string, stat FileStat) { done[filepath.Base(path)] = stat },
+		}),
+	)
+
+	_, stats, err := ProcessProject([]string{dir}, config)
+	if err != nil {
+		t.Fatalf("ProcessProject failed: %v", err)
+	}
+
+	if len(started) != len(files) {
+		t.Errorf("OnFileStart called for %v, want all %d files", started, len(files))
+	}
+	wantSkipped := map[string]SkipReason{"notes.txt": SkipFiltered, "image.bin": SkipBinary}
+	if !reflect.DeepEqual(skipped, wantSkipped) {
+		t.Errorf("OnFileSkipped got %v, want %v", skipped, wantSkipped)
+	}
+	if want := map[SkipReason]int{SkipFiltered: 1, SkipBinary: 1}; !reflect.DeepEqual(stats.Skipped, want) {
+		t.Errorf("Stats.Skipped = %v, want %v", stats.Skipped, want)
+	}
+	listed := make(map[string]SkipReason)
+	for _, file := range stats.SkippedFiles {
+		listed[filepath.Base(file.Path)] = file.Reason
+	}
+	if len(stats.SkippedFiles) != len(wantSkipped) || !reflect.DeepEqual(listed, wantSkipped) {
+		t.Errorf("Stats.SkippedFiles = %v, want %v", stats.SkippedFiles, wantSkipped)
+	}
+	if len(done) != 1 || len(stats.Files) != 1 || done["main.go"] != stats.Files[0] {
+		t.Errorf("OnFileDone got %v, want the stats of main.go %v", done, stats.Files)
+	}
+}
+
+// TestHooksOptional tests that processing works with only some hooks set
+func TestHooksOptional(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	count := 0
+	config := NewConfig(
+		WithGitClient(NewMockGitClient(false)),
+		WithHooks(Hooks{OnFileDone: func(string, FileStat) { count++ }}),
+	)
+	if _, _, err := ProcessProject([]string{dir}, config); err != nil {
+		t.Fatalf("ProcessProject failed: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("OnFileDone called %d times, want 1", count)
+	}
+}
@@ -0,0 +1,166 @@
+package handoff
+
+import (
+	"fmt"
+	"hash/fnv"
+	"html"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// HTMLFormatter renders output as a single self-contained HTML page with a
+// sidebar file tree, collapsible per-file sections, and copy buttons, for
+// reviewing what is about to be sent to a model.
+type HTMLFormatter struct {
+	// Title is the page title; empty uses "Handoff context"
+	Title string
+}
+
+// NewHTMLFormatter creates an HTMLFormatter with the default title.
+func NewHTMLFormatter() *HTMLFormatter {
+	return &HTMLFormatter{}
+}
+
+// htmlFileAnchor returns a stable element ID for a file path (internal helper)
+func htmlFileAnchor(path string) string {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(path))
+	return fmt.Sprintf("file-%08x", h.Sum32())
+}
+
+// FormatFile renders a file as a collapsible section with a copy button.
+func (f *HTMLFormatter) FormatFile(info FileInfo, content []byte) string {
+	path := html.EscapeString(info.Path)
+	return fmt.Sprintf(`<details class="file" id="%s" data-path="%s" open>
+<summary><span class="path">%s</span><button type="button" class="copy">Copy</button></summary>
+<pre><code>%s</code></pre>
+</details>
+`, htmlFileAnchor(info.Path), path, path, html.EscapeString(string(content)))
+}
+
+// FormatSection renders a supplementary section as a collapsible block.
+func (f *HTMLFormatter) FormatSection(name, content string) string {
+	return fmt.Sprintf(`<details class="section" open>
+<summary><span class="path">%s</span><button type="button" class="copy">Copy</button></summary>
+<pre><code>%s</code></pre>
+</details>
+`, html.EscapeString(name), html.EscapeString(strings.TrimSuffix(content, "\n")))
+}
+
+// htmlFilePattern finds the files rendered by FormatFile so Wrap can build the
+// file tree without the formatter keeping state between calls
+var htmlFilePattern = regexp.MustCompile(`<details class="file" id="([^"]+)" data-path="([^"]*)"`)
+
+// htmlTreeNode is a directory or file in the sidebar tree (internal helper)
+type htmlTreeNode struct {
+	name     string
+	anchor   string
+	children map[string]*htmlTreeNode
+}
+
+// Wrap embeds the rendered files in a complete HTML page with a file tree sidebar.
+func (f *HTMLFormatter) Wrap(body string) string {
+	title := f.Title
+	if title == "" {
+		title = "Handoff context"
+	}
+
+	root := &htmlTreeNode{children: map[string]*htmlTreeNode{}}
+	files := htmlFilePattern.FindAllStringSubmatch(body, -1)
+	for _, match := range files {
+		path := html.UnescapeString(match[2])
+		node := root
+		for _, part := range strings.Split(strings.Trim(strings.ReplaceAll(path, "\\", "/"), "/"), "/") {
+			child, ok := node.children[part]
+			if !ok {
+				child = &htmlTreeNode{name: part, children: map[string]*htmlTreeNode{}}
+				node.children[part] = child
+			}
+			node = child
+		}
+		node.anchor = match[1]
+	}
+
+	var tree strings.Builder
+	writeHTMLTree(&tree, root)
+
+	return fmt.Sprintf(htmlPage, html.EscapeString(title), html.EscapeString(title), len(files), tree.String(), body)
+}
+
+// writeHTMLTree renders a tree node's children as nested lists, directories first (internal helper)
+func writeHTMLTree(b *strings.Builder, node *htmlTreeNode) {
+	names := make([]string, 0, len(node.children))
+	for name := range node.children {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		a, c := node.children[names[i]], node.children[names[j]]
+		if (len(a.children) > 0) != (len(c.children) > 0) {
+			return len(a.children) > 0
+		}
+		return names[i] < names[j]
+	})
+
+	b.WriteString("<ul>\n")
+	for _, name := range names {
+		child := node.children[name]
+		b.WriteString("<li>")
+		if child.anchor != "" {
+			fmt.Fprintf(b, `<a href="#%s">%s</a>`, child.anchor, html.EscapeString(name))
+		} else {
+			fmt.Fprintf(b, `<span class="dir">%s/</span>`, html.EscapeString(name))
+		}
+		if len(child.children) > 0 {
+			b.WriteString("\n")
+			writeHTMLTree(b, child)
+		}
+		b.WriteString("</li>\n")
+	}
+	b.WriteString("</ul>\n")
+}
+
+// htmlPage is the page template: title, heading, file count, tree, and body
+const htmlPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>%s</title>
+<style>
+body { margin: 0; display: flex; font-family: system-ui, sans-serif; color: #1f2328; }
+nav { position: sticky; top: 0; height: 100vh; overflow: auto; width: 18rem; flex-shrink: 0; padding: 1rem; box-sizing: border-box; background: #f6f8fa; border-right: 1px solid #d0d7de; font-size: 0.875rem; }
+nav ul { list-style: none; margin: 0; padding-left: 1rem; }
+nav > ul { padding-left: 0; }
+nav a { color: #0969da; text-decoration: none; }
+nav a:hover { text-decoration: underline; }
+.dir { color: #59636e; }
+main { flex: 1; min-width: 0; padding: 1rem 2rem; }
+details { margin-bottom: 1rem; border: 1px solid #d0d7de; border-radius: 6px; }
+summary { display: flex; align-items: center; justify-content: space-between; padding: 0.5rem 0.75rem; background: #f6f8fa; cursor: pointer; font-family: ui-monospace, monospace; }
+pre { margin: 0; padding: 0.75rem; overflow-x: auto; font-size: 0.8125rem; }
+button.copy { font-size: 0.75rem; cursor: pointer; }
+</style>
+</head>
+<body>
+<nav>
+<h1>%s</h1>
+<p>%d files</p>
+%s</nav>
+<main>
+%s</main>
+<script>
+document.querySelectorAll("button.copy").forEach(function (button) {
+  button.addEventListener("click", function (event) {
+    event.preventDefault();
+    var code = button.closest("details").querySelector("code");
+    navigator.clipboard.writeText(code.textContent).then(function () {
+      button.textContent = "Copied";
+      setTimeout(function () { button.textContent = "Copy"; }, 1500);
+    });
+  });
+});
+</script>
+</body>
+</html>
+`
@@ -0,0 +1,49 @@
+package handoff
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestHTMLFormatter tests rendering files, the file tree, and escaping
+func TestHTMLFormatter(t *testing.T) {
+	formatter := NewHTMLFormatter()
+
+	body := formatter.FormatFile(FileInfo{Path: "src/app/main.go"}, []byte("if a < b && c {\n}")) +
+		formatter.FormatFile(FileInfo{Path: "README.md"}, []byte("# <Title>")) +
+		formatter.FormatSection("git-log", "abc123 fix\n")
+	page := formatter.Wrap(body)
+
+	for _, want := range []string{
+		"<!DOCTYPE html>",
+		"<title>Handoff context</title>",
+		"<p>2 files</p>",
+		`<span class="dir">src/</span>`,
+		`<a href="#` + htmlFileAnchor("src/app/main.go") + `">main.go</a>`,
+		`<a href="#` + htmlFileAnchor("README.md") + `">README.md</a>`,
+		`id="` + htmlFileAnchor("src/app/main.go") + `"`,
+		"if a &lt; b &amp;&amp; c {",
+		"# &lt;Title&gt;",
+		`<button type="button" class="copy">Copy</button>`,
+		"abc123 fix",
+	} {
+		if !strings.Contains(page, want) {
+			t.Errorf("page is missing %q", want)
+		}
+	}
+
+	// Directories are listed before files at each level
+	if strings.Index(page, `<span class="dir">src/</span>`) > strings.Index(page, `">README.md</a>`) {
+		t.Error("directories should be listed before files in the tree")
+	}
+}
+
+// TestHTMLFileAnchor tests that anchors are stable and distinct
+func TestHTMLFileAnchor(t *testing.T) {
+	if htmlFileAnchor("a.go") != htmlFileAnchor("a.go") {
+		t.Error("anchors should be stable")
+	}
+	if htmlFileAnchor("a.go") == htmlFileAnchor("b.go") {
+		t.Error("anchors should differ between paths")
+	}
+}
@@ -0,0 +1,315 @@
+package handoff
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// ignorePattern is a single compiled gitignore-style pattern
+type ignorePattern struct {
+	re      *regexp.Regexp
+	negate  bool
+	dirOnly bool
+}
+
+// ignoreMatcher applies gitignore-style patterns relative to a root directory.
+// It is used by the fallback walker to honor the same ignore sources git would
+// when git itself cannot be used.
+type ignoreMatcher struct {
+	root     string
+	patterns []ignorePattern
+}
+
+// newFallbackIgnoreMatcher builds a matcher for a directory walked without git (internal helper).
+// It loads the user's global ignore file and, when dir is inside a repository,
+// the repository's info/exclude file, following the .git file of worktrees and
+// submodules to their git directory. Patterns are matched relative to the
+// repository root, or relative to dir when no repository is found.
+func newFallbackIgnoreMatcher(dir string) *ignoreMatcher {
+	root := dir
+	if absDir, err := filepath.Abs(dir); err == nil {
+		root = absDir
+	}
+
+	var commonDir string
+	if repoRoot := findRepoRoot(root); repoRoot != "" {
+		root = repoRoot
+		commonDir = gitCommonDir(gitDirAt(repoRoot))
+	}
+
+	var patterns []ignorePattern
+	if excludesPath := excludesFilePath(commonDir); excludesPath != "" {
+		patterns = append(patterns, loadIgnoreFile(excludesPath)...)
+	}
+	if commonDir != "" {
+		patterns = append(patterns, loadIgnoreFile(filepath.Join(commonDir, "info", "exclude"))...)
+	}
+
+	return &ignoreMatcher{root: root, patterns: patterns}
+}
+
+// globalIgnorePath returns the location of the user's global ignore file
+// (internal helper)
+func globalIgnorePath() string {
+	return excludesFilePath("")
+}
+
+// excludesFilePath returns the ignore file git reads for a repository whose
+// shared git directory is commonDir, or outside any repository when commonDir
+// is empty: core.excludesFile from the repository's config, ~/.gitconfig, or
+// $XDG_CONFIG_HOME/git/config, in that order of precedence, or git's default
+// of $XDG_CONFIG_HOME/git/ignore or ~/.config/git/ignore (internal helper)
+func excludesFilePath(commonDir string) string {
+	home, _ := os.UserHomeDir()
+	configHome := os.Getenv("XDG_CONFIG_HOME")
+	if configHome == "" && home != "" {
+		configHome = filepath.Join(home, ".config")
+	}
+
+	// Read the config files from lowest to highest precedence
+	var configs []string
+	if configHome != "" {
+		configs = append(configs, filepath.Join(configHome, "git", "config"))
+	}
+	if home != "" {
+		configs = append(configs, filepath.Join(home, ".gitconfig"))
+	}
+	if commonDir != "" {
+		configs = append(configs, filepath.Join(commonDir, "config"))
+	}
+	var path string
+	for _, config := range configs {
+		if value := gitConfigValue(config, "core", "excludesfile"); value != "" {
+			path = value
+		}
+	}
+
+	switch {
+	case path == "" && configHome == "":
+		return ""
+	case path == "":
+		return filepath.Join(configHome, "git", "ignore")
+	case strings.HasPrefix(path, "~/") && home != "":
+		return filepath.Join(home, path[2:])
+	}
+	return path
+}
+
+// gitConfigValue returns the last value of a key in a section of a git config
+// file, or an empty string when it isn't set. Section and key names are
+// matched case-insensitively; subsections, includes, and escapes other than
+// surrounding quotes are not supported. (internal helper)
+func gitConfigValue(path, section, key string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	var current, value string
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "" || line[0] == '#' || line[0] == ';':
+		case line[0] == '[':
+			current = strings.TrimSpace(strings.Trim(line, "[]"))
+		case strings.EqualFold(current, section):
+			name, setting, found := strings.Cut(line, "=")
+			if found && strings.EqualFold(strings.TrimSpace(name), key) {
+				value = strings.Trim(strings.TrimSpace(setting), `"`)
+			}
+		}
+	}
+	return value
+}
+
+// findRepoRoot returns the nearest ancestor of dir (inclusive) that contains a
+// .git directory, or the .git file of a worktree or submodule, or an empty
+// string if there is none (internal helper)
+func findRepoRoot(dir string) string {
+	for {
+		if gitDirAt(dir) != "" {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// gitDirAt returns the git directory of a repository rooted at dir: its .git
+// directory, or the directory named by a "gitdir:" line in a .git file, as
+// worktrees and submodules have. It returns an empty string when dir holds
+// neither. (internal helper)
+func gitDirAt(dir string) string {
+	gitPath := filepath.Join(dir, ".git")
+	info, err := os.Stat(gitPath)
+	if err != nil {
+		return ""
+	}
+	if info.IsDir() {
+		return gitPath
+	}
+	data, err := os.ReadFile(gitPath)
+	if err != nil {
+		return ""
+	}
+	target, found := strings.CutPrefix(strings.TrimSpace(string(data)), "gitdir:")
+	if !found {
+		return ""
+	}
+	return resolveGitPath(dir, strings.TrimSpace(target))
+}
+
+// gitCommonDir returns the directory holding the info/exclude file and config
+// shared by a repository's worktrees: the main repository's git directory,
+// named by the commondir file of a linked worktree, or gitDir itself
+// (internal helper)
+func gitCommonDir(gitDir string) string {
+	data, err := os.ReadFile(filepath.Join(gitDir, "commondir"))
+	if err != nil {
+		return gitDir
+	}
+	return resolveGitPath(gitDir, strings.TrimSpace(string(data)))
+}
+
+// resolveGitPath resolves a path git recorded relative to base (internal helper)
+func resolveGitPath(base, path string) string {
+	path = filepath.FromSlash(path)
+	if filepath.IsAbs(path) {
+		return filepath.Clean(path)
+	}
+	return filepath.Join(base, path)
+}
+
+// loadIgnoreFile reads and compiles the patterns in an ignore file.
+// Missing or unreadable files yield no patterns. (internal helper)
+func loadIgnoreFile(path string) []ignorePattern {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	return parseIgnorePatterns(string(data))
+}
+
+// parseIgnorePatterns compiles gitignore-style patterns, one per line (internal helper)
+func parseIgnorePatterns(content string) []ignorePattern {
+	var patterns []ignorePattern
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if !strings.HasSuffix(line, "\\ ") {
+			line = strings.TrimRight(line, " ")
+		}
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		var p ignorePattern
+		if strings.HasPrefix(line, "!") {
+			p.negate = true
+			line = line[1:]
+		} else if strings.HasPrefix(line, "\\!") || strings.HasPrefix(line, "\\#") {
+			line = line[1:]
+		}
+		if strings.HasSuffix(line, "/") {
+			p.dirOnly = true
+			line = strings.TrimSuffix(line, "/")
+		}
+		if line == "" {
+			continue
+		}
+
+		re, err := regexp.Compile(ignorePatternToRegexp(line))
+		if err != nil {
+			continue
+		}
+		p.re = re
+		patterns = append(patterns, p)
+	}
+	return patterns
+}
+
+// ignorePatternToRegexp converts a gitignore glob into an equivalent regular
+// expression matched against slash-separated relative paths (internal helper).
+// Patterns containing a slash are anchored to the root; others match a name at any depth.
+func ignorePatternToRegexp(pattern string) string {
+	anchored := strings.Contains(pattern, "/")
+	pattern = strings.TrimPrefix(pattern, "/")
+
+	var b strings.Builder
+	if anchored {
+		b.WriteString("^")
+	} else {
+		b.WriteString("(?:^|/)")
+	}
+
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		switch {
+		case strings.HasPrefix(pattern[i:], "**/"):
+			b.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(pattern[i:], "/**") && i+3 == len(pattern):
+			b.WriteString("/.*")
+			i += 2
+		case strings.HasPrefix(pattern[i:], "**"):
+			b.WriteString(".*")
+			i++
+		case c == '*':
+			b.WriteString("[^/]*")
+		case c == '?':
+			b.WriteString("[^/]")
+		case c == '[':
+			end := strings.IndexByte(pattern[i+1:], ']')
+			if end < 0 {
+				b.WriteString(regexp.QuoteMeta(string(c)))
+				continue
+			}
+			class := pattern[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			b.WriteString("[" + strings.ReplaceAll(class, `\`, `\\`) + "]")
+			i += end + 1
+		case c == '\\' && i+1 < len(pattern):
+			i++
+			b.WriteString(regexp.QuoteMeta(string(pattern[i])))
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+
+	b.WriteString("$")
+	return b.String()
+}
+
+// matches reports whether a path is ignored. The last matching pattern wins,
+// so negated patterns can re-include paths excluded by earlier ones.
+func (m *ignoreMatcher) matches(path string, isDir bool) bool {
+	if len(m.patterns) == 0 {
+		return false
+	}
+
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return false
+	}
+	rel, err := filepath.Rel(m.root, absPath)
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return false
+	}
+	rel = filepath.ToSlash(rel)
+
+	ignored := false
+	for _, p := range m.patterns {
+		if p.dirOnly && !isDir {
+			continue
+		}
+		if p.re.MatchString(rel) {
+			ignored = !p.negate
+		}
+	}
+	return ignored
+}
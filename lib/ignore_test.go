@@ -0,0 +1,164 @@
+package handoff
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestIgnoreMatcher tests gitignore-style pattern matching
+func TestIgnoreMatcher(t *testing.T) {
+	root := filepath.Join(string(filepath.Separator), "repo")
+
+	testCases := []struct {
+		name     string
+		patterns string
+		path     string
+		isDir    bool
+		want     bool
+	}{
+		{name: "Simple name at root", patterns: "*.log", path: "debug.log", want: true},
+		{name: "Simple name nested", patterns: "*.log", path: "a/b/debug.log", want: true},
+		{name: "No match", patterns: "*.log", path: "main.go", want: false},
+		{name: "Anchored pattern", patterns: "/build", path: "build", isDir: true, want: true},
+		{name: "Anchored pattern not nested", patterns: "/build", path: "src/build", isDir: true, want: false},
+		{name: "Directory-only matches dir", patterns: "tmp/", path: "tmp", isDir: true, want: true},
+		{name: "Directory-only skips file", patterns: "tmp/", path: "tmp", isDir: false, want: false},
+		{name: "Double star prefix", patterns: "**/fixtures", path: "a/b/fixtures", isDir: true, want: true},
+		{name: "Double star middle", patterns: "docs/**/draft.md", path: "docs/x/y/draft.md", want: true},
+		{name: "Double star middle zero dirs", patterns: "docs/**/draft.md", path: "docs/draft.md", want: true},
+		{name: "Trailing double star", patterns: "out/**", path: "out/a/b.txt", want: true},
+		{name: "Negation re-includes", patterns: "*.log\n!keep.log", path: "keep.log", want: false},
+		{name: "Later pattern wins", patterns: "!keep.log\n*.log", path: "keep.log", want: true},
+		{name: "Comments and blanks ignored", patterns: "# comment\n\n*.tmp", path: "x.tmp", want: true},
+		{name: "Question mark", patterns: "file?.txt", path: "file1.txt", want: true},
+		{name: "Character class", patterns: "file[0-9].txt", path: "file7.txt", want: true},
+		{name: "Negated character class", patterns: "file[!0-9].txt", path: "file7.txt", want: false},
+		{name: "Star does not cross slash", patterns: "src/*.go", path: "src/pkg/a.go", want: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			m := &ignoreMatcher{root: root, patterns: parseIgnorePatterns(tc.patterns)}
+			path := filepath.Join(root, filepath.FromSlash(tc.path))
+			if got := m.matches(path, tc.isDir); got != tc.want {
+				t.Errorf("matches(%q) with patterns %q = %v, want %v", tc.path, tc.patterns, got, tc.want)
+			}
+		})
+	}
+}
+
+// TestFallbackWalkerHonorsIgnoreSources tests that the non-git walker applies
+// the global ignore file and .git/info/exclude
+func TestFallbackWalkerHonorsIgnoreSources(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "handoff-ignore-test-")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer func() {
+		if cleanErr := os.RemoveAll(tmpDir); cleanErr != nil {
+			t.Logf("Failed to clean up temp directory: %v", cleanErr)
+		}
+	}()
+
+	configHome := filepath.Join(tmpDir, "config")
+	repo := filepath.Join(tmpDir, "repo")
+	files := map[string]string{
+		filepath.Join(configHome, "git", "ignore"):        "*.swp\n",
+		filepath.Join(repo, ".git", "info", "exclude"):    "scratch/\n",
+		filepath.Join(repo, "main.go"):                    "package main\n",
+		filepath.Join(repo, "main.go.swp"):                "swap",
+		filepath.Join(repo, "scratch", "notes.txt"):       "notes",
+		filepath.Join(repo, "src", "scratch", "deep.txt"): "deep",
+		filepath.Join(repo, "src", "util.go"):             "package src\n",
+	}
+	for path, content := range files {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory for %s: %v", path, err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to create file %s: %v", path, err)
+		}
+	}
+	t.Setenv("HOME", tmpDir)
+	t.Setenv("XDG_CONFIG_HOME", configHome)
+
+	found, err := getFilesWithFilepathWalk(repo, NewConfig())
+	if err != nil {
+		t.Fatalf("getFilesWithFilepathWalk failed: %v", err)
+	}
+
+	got := make(map[string]bool)
+	for _, file := range found {
+		rel, _ := filepath.Rel(repo, file.path)
+		got[filepath.ToSlash(rel)] = true
+	}
+
+	for _, want := range []string{"main.go", "src/util.go"} {
+		if !got[want] {
+			t.Errorf("walker should include %s, got %v", want, got)
+		}
+	}
+	for _, reject := range []string{"main.go.swp", "scratch/notes.txt", "src/scratch/deep.txt"} {
+		if got[reject] {
+			t.Errorf("walker should exclude %s, got %v", reject, got)
+		}
+	}
+
+	// Walking a nested directory must still apply root-relative patterns
+	nested, err := getFilesWithFilepathWalk(filepath.Join(repo, "src"), NewConfig())
+	if err != nil {
+		t.Fatalf("getFilesWithFilepathWalk failed: %v", err)
+	}
+	for _, file := range nested {
+		if filepath.Base(file.path) == "deep.txt" {
+			t.Errorf("walker should exclude nested scratch directory, got %s", file.path)
+		}
+	}
+}
+
+// TestFallbackWalkerHonorsWorktreeIgnores tests that the walker follows a
+// worktree's .git file to the main repository's exclude file and config
+func TestFallbackWalkerHonorsWorktreeIgnores(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("HOME", tmpDir)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(tmpDir, "config"))
+
+	mainGit := filepath.Join(tmpDir, "main", ".git")
+	worktree := filepath.Join(tmpDir, "wt")
+	files := map[string]string{
+		filepath.Join(tmpDir, "custom-ignore"):                 "*.log\n",
+		filepath.Join(mainGit, "config"):                       "[core]\n\texcludesFile = ~/custom-ignore\n[remote \"origin\"]\n\texcludesFile = wrong\n",
+		filepath.Join(mainGit, "info", "exclude"):              "scratch/\n",
+		filepath.Join(mainGit, "worktrees", "wt", "commondir"): "../..\n",
+		filepath.Join(worktree, ".git"):                        "gitdir: " + filepath.Join(mainGit, "worktrees", "wt") + "\n",
+		filepath.Join(worktree, "main.go"):                     "package main\n",
+		filepath.Join(worktree, "debug.log"):                   "log",
+		filepath.Join(worktree, "scratch", "notes.txt"):        "notes",
+	}
+	for path, content := range files {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory for %s: %v", path, err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to create file %s: %v", path, err)
+		}
+	}
+
+	if root := findRepoRoot(filepath.Join(worktree, "scratch")); root != worktree {
+		t.Errorf("findRepoRoot() = %q, want the worktree %q", root, worktree)
+	}
+	found, err := getFilesWithFilepathWalk(worktree, NewConfig())
+	if err != nil {
+		t.Fatalf("getFilesWithFilepathWalk failed: %v", err)
+	}
+	var got []string
+	for _, file := range found {
+		rel, _ := filepath.Rel(worktree, file.path)
+		got = append(got, filepath.ToSlash(rel))
+	}
+	if strings.Join(got, ",") != "main.go" {
+		t.Errorf("walker found %v, want only main.go", got)
+	}
+}
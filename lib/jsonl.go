@@ -0,0 +1,64 @@
+package handoff
+
+import (
+	"encoding/json"
+)
+
+// JSONLFormatter renders one JSON object per line for each file, suitable for
+// streaming into vector database loaders and jq-based pipelines. Each line has
+// the form {"path":..., "lang":..., "tokens":..., "content":...}, with a
+// "root" label before the path for files under a directory labeled with
+// WithRootLabel, a "status" after it with WithGitStatus, and a "checksum" with WithChecksums.
+type JSONLFormatter struct{}
+
+// NewJSONLFormatter creates a JSONLFormatter.
+func NewJSONLFormatter() *JSONLFormatter {
+	return &JSONLFormatter{}
+}
+
+// jsonlFile is the JSON Lines record for a file (internal helper)
+type jsonlFile struct {
+	Root     string `json:"root,omitempty"`
+	Path     string `json:"path"`
+	Status   string `json:"status,omitempty"`
+	Checksum string `json:"checksum,omitempty"`
+	Lang     string `json:"lang"`
+	Tokens   int    `json:"tokens"`
+	Content  string `json:"content"`
+}
+
+// jsonlSection is the JSON Lines record for a supplementary section (internal helper)
+type jsonlSection struct {
+	Section string `json:"section"`
+	Content string `json:"content"`
+}
+
+// FormatFile renders a file as a single JSON line.
+func (f *JSONLFormatter) FormatFile(info FileInfo, content []byte) string {
+	return jsonLine(jsonlFile{
+		Root:     info.Root,
+		Path:     info.Path,
+		Status:   string(info.Status),
+		Checksum: info.Checksum,
+		Lang:     fenceLanguage(info.Path, content),
+		Tokens:   estimateTokenCount(string(content)),
+		Content:  string(content),
+	})
+}
+
+// FormatSection renders a supplementary section as a JSON line with a "section" key.
+func (f *JSONLFormatter) FormatSection(name, content string) string {
+	return jsonLine(jsonlSection{Section: name, Content: content})
+}
+
+// Wrap returns the lines unchanged; JSON Lines output has no envelope.
+func (f *JSONLFormatter) Wrap(body string) string {
+	return body
+}
+
+// jsonLine encodes a record as a newline-terminated JSON line (internal helper).
+// The records contain only strings and integers, so encoding cannot fail.
+func jsonLine(record any) string {
+	data, _ := json.Marshal(record)
+	return string(data) + "\n"
+}
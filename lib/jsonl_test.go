@@ -0,0 +1,56 @@
+package handoff
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestJSONLFormatter tests that each file becomes one JSON object per line
+func TestJSONLFormatter(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"main.go":   "package main\n\nfunc main() {}\n",
+		"notes.txt": "line one\n\"quoted\" line two\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	config := NewConfig(WithGitClient(NewMockGitClient(false)), WithFormatter(NewJSONLFormatter()))
+	output, _, err := ProcessProject([]string{dir}, config)
+	if err != nil {
+		t.Fatalf("ProcessProject failed: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(output, "\n"), "\n")
+	if len(lines) != len(files) {
+		t.Fatalf("got %d lines, want %d:\n%s", len(lines), len(files), output)
+	}
+
+	for _, line := range lines {
+		var record jsonlFile
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("line is not valid JSON: %v\n%s", err, line)
+		}
+		want, ok := files[filepath.Base(record.Path)]
+		if !ok || record.Content != want {
+			t.Errorf("record %+v does not match a written file", record)
+		}
+		if record.Tokens != estimateTokenCount(want) {
+			t.Errorf("tokens = %d, want %d", record.Tokens, estimateTokenCount(want))
+		}
+		if filepath.Base(record.Path) == "main.go" && record.Lang != "go" {
+			t.Errorf("lang = %q, want go", record.Lang)
+		}
+	}
+
+	section := NewJSONLFormatter().FormatSection("git-log", "abc fix\n")
+	if section != `{"section":"git-log","content":"abc fix\n"}`+"\n" {
+		t.Errorf("FormatSection() = %q", section)
+	}
+}
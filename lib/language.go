@@ -0,0 +1,161 @@
+package handoff
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// shebangSampleSize is the number of bytes read when looking for a shebang line
+const shebangSampleSize = 256
+
+// shebangExtensions maps script interpreters to the extension conventionally
+// used for their files, so extensionless scripts can be matched by include and
+// exclude filters
+var shebangExtensions = map[string]string{
+	"sh":      ".sh",
+	"bash":    ".sh",
+	"zsh":     ".sh",
+	"dash":    ".sh",
+	"ksh":     ".sh",
+	"fish":    ".fish",
+	"python":  ".py",
+	"python2": ".py",
+	"python3": ".py",
+	"node":    ".js",
+	"nodejs":  ".js",
+	"deno":    ".ts",
+	"ts-node": ".ts",
+	"bun":     ".ts",
+	"ruby":    ".rb",
+	"perl":    ".pl",
+	"php":     ".php",
+	"lua":     ".lua",
+	"Rscript": ".r",
+	"pwsh":    ".ps1",
+	"tclsh":   ".tcl",
+	"awk":     ".awk",
+}
+
+// detectShebangExt returns the extension associated with the interpreter named in
+// a file's shebang line (e.g., ".sh" for "#!/usr/bin/env bash"), or an empty string
+// if the file has no recognized shebang or cannot be read. (internal helper)
+func detectShebangExt(file string) string {
+	f, err := os.Open(file)
+	if err != nil {
+		return ""
+	}
+	defer func() { _ = f.Close() }()
+
+	sample := make([]byte, shebangSampleSize)
+	n, err := io.ReadFull(f, sample)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return ""
+	}
+	return shebangExt(sample[:n])
+}
+
+// shebangExt parses a shebang line from the start of content and maps its
+// interpreter to an extension (internal helper)
+func shebangExt(content []byte) string {
+	if !bytes.HasPrefix(content, []byte("#!")) {
+		return ""
+	}
+	line := string(content[2:])
+	if i := strings.IndexByte(line, '\n'); i >= 0 {
+		line = line[:i]
+	}
+
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return ""
+	}
+
+	// For "#!/usr/bin/env [-S] interpreter", the interpreter follows env and its flags
+	interpreter := filepath.Base(fields[0])
+	if interpreter == "env" {
+		interpreter = ""
+		for _, field := range fields[1:] {
+			if !strings.HasPrefix(field, "-") && !strings.Contains(field, "=") {
+				interpreter = filepath.Base(field)
+				break
+			}
+		}
+	}
+
+	if ext, ok := shebangExtensions[interpreter]; ok {
+		return ext
+	}
+	// Handle versioned interpreters such as python3.12 or ruby2.7
+	if base := strings.TrimRight(interpreter, "0123456789."); base != interpreter {
+		return shebangExtensions[base]
+	}
+	return ""
+}
+
+// fenceLanguages maps file extensions to the language names used as code fence info strings
+var fenceLanguages = map[string]string{
+	".go":    "go",
+	".py":    "python",
+	".js":    "javascript",
+	".jsx":   "jsx",
+	".mjs":   "javascript",
+	".ts":    "typescript",
+	".tsx":   "tsx",
+	".rb":    "ruby",
+	".rs":    "rust",
+	".java":  "java",
+	".kt":    "kotlin",
+	".swift": "swift",
+	".c":     "c",
+	".h":     "c",
+	".cpp":   "cpp",
+	".cc":    "cpp",
+	".hpp":   "cpp",
+	".cs":    "csharp",
+	".php":   "php",
+	".pl":    "perl",
+	".lua":   "lua",
+	".r":     "r",
+	".sh":    "bash",
+	".fish":  "fish",
+	".ps1":   "powershell",
+	".sql":   "sql",
+	".html":  "html",
+	".css":   "css",
+	".scss":  "scss",
+	".md":    "markdown",
+	".json":  "json",
+	".yaml":  "yaml",
+	".yml":   "yaml",
+	".toml":  "toml",
+	".xml":   "xml",
+	".proto": "protobuf",
+}
+
+// fenceLanguage returns the code fence language for a file, detected from its
+// extension or, for extensionless scripts, its shebang line (internal helper)
+func fenceLanguage(path string, content []byte) string {
+	ext := strings.ToLower(filepath.Ext(path))
+	if ext == "" {
+		ext = shebangExt(content)
+	}
+	return fenceLanguages[ext]
+}
+
+// codeFence returns a backtick fence long enough that no backtick run inside
+// content can close it early (internal helper)
+func codeFence(content []byte) string {
+	longest, run := 0, 0
+	for _, b := range content {
+		if b == '`' {
+			run++
+			longest = max(longest, run)
+		} else {
+			run = 0
+		}
+	}
+	return strings.Repeat("`", max(3, longest+1))
+}
@@ -0,0 +1,69 @@
+package handoff
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestShebangExt tests mapping shebang interpreters to extensions
+func TestShebangExt(t *testing.T) {
+	testCases := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"Direct interpreter", "#!/bin/bash\necho hi\n", ".sh"},
+		{"Env interpreter", "#!/usr/bin/env python3\nprint('hi')\n", ".py"},
+		{"Env with flags", "#!/usr/bin/env -S node --harmony\n", ".js"},
+		{"Interpreter with arguments", "#!/usr/bin/perl -w\n", ".pl"},
+		{"Versioned interpreter", "#!/usr/bin/python3.12\n", ".py"},
+		{"Space after bang", "#! /bin/sh\n", ".sh"},
+		{"Unknown interpreter", "#!/usr/bin/env frobnicate\n", ""},
+		{"No shebang", "echo hi\n", ""},
+		{"Empty shebang", "#!\n", ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := shebangExt([]byte(tc.content)); got != tc.want {
+				t.Errorf("shebangExt(%q) = %q, want %q", tc.content, got, tc.want)
+			}
+		})
+	}
+}
+
+// TestShouldProcessShebang tests that include and exclude filters match
+// extensionless scripts by their detected language
+func TestShouldProcessShebang(t *testing.T) {
+	dir := t.TempDir()
+	deploy := filepath.Join(dir, "deploy")
+	if err := os.WriteFile(deploy, []byte("#!/usr/bin/env bash\nset -e\n"), 0755); err != nil {
+		t.Fatalf("Failed to write script: %v", err)
+	}
+	notes := filepath.Join(dir, "NOTES")
+	if err := os.WriteFile(notes, []byte("plain text\n"), 0644); err != nil {
+		t.Fatalf("Failed to write notes: %v", err)
+	}
+
+	testCases := []struct {
+		name   string
+		file   string
+		config *Config
+		want   bool
+	}{
+		{"Include matches detected language", deploy, &Config{includeExts: []string{".sh"}}, true},
+		{"Include rejects other language", deploy, &Config{includeExts: []string{".py"}}, false},
+		{"Exclude matches detected language", deploy, &Config{excludeExts: []string{".sh"}}, false},
+		{"No shebang is not included", notes, &Config{includeExts: []string{".sh"}}, false},
+		{"No filters processes script", deploy, &Config{}, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := shouldProcess(tc.file, tc.config); got != tc.want {
+				t.Errorf("shouldProcess(%q) = %v, want %v", tc.file, got, tc.want)
+			}
+		})
+	}
+}
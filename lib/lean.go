@@ -0,0 +1,161 @@
+package handoff
+
+import (
+	"bytes"
+	"strings"
+)
+
+// commentSyntax describes how a language writes comments: a line comment
+// prefix and block comment delimiters, either of which may be empty
+type commentSyntax struct {
+	line       string
+	blockStart string
+	blockEnd   string
+}
+
+// commentSyntaxes maps the languages in fenceLanguages to their comment syntax.
+// Python docstrings are treated as block comments.
+var commentSyntaxes = map[string]commentSyntax{
+	"go":         {"//", "/*", "*/"},
+	"javascript": {"//", "/*", "*/"},
+	"jsx":        {"//", "/*", "*/"},
+	"typescript": {"//", "/*", "*/"},
+	"tsx":        {"//", "/*", "*/"},
+	"rust":       {"//", "/*", "*/"},
+	"java":       {"//", "/*", "*/"},
+	"kotlin":     {"//", "/*", "*/"},
+	"swift":      {"//", "/*", "*/"},
+	"c":          {"//", "/*", "*/"},
+	"cpp":        {"//", "/*", "*/"},
+	"csharp":     {"//", "/*", "*/"},
+	"php":        {"//", "/*", "*/"},
+	"css":        {"", "/*", "*/"},
+	"scss":       {"//", "/*", "*/"},
+	"protobuf":   {"//", "/*", "*/"},
+	"python":     {"#", `"""`, `"""`},
+	"ruby":       {"#", "=begin", "=end"},
+	"perl":       {"#", "", ""},
+	"bash":       {"#", "", ""},
+	"fish":       {"#", "", ""},
+	"r":          {"#", "", ""},
+	"powershell": {"#", "<#", "#>"},
+	"lua":        {"--", "--[[", "]]"},
+	"sql":        {"--", "/*", "*/"},
+}
+
+// WithLeanComments adds a transformer that shortens comment blocks longer than
+// maxLines lines to their first line of text, keeping the block's delimiters,
+// so well-documented code keeps its one-line summaries without paying for
+// full doc comments. It sits between full content and dropping comments
+// entirely. Files in languages without a known comment syntax are unchanged,
+// and a maxLines of zero or less adds nothing.
+func WithLeanComments(maxLines int) Option {
+	if maxLines <= 0 {
+		return func(*Config) {}
+	}
+	return WithTransformer(func(path string, content []byte) []byte {
+		syntax, ok := commentSyntaxes[fenceLanguage(path, content)]
+		if !ok {
+			return content
+		}
+		return leanComments(content, syntax, maxLines)
+	})
+}
+
+// leanComments shortens comment blocks of more than maxLines lines. Only
+// comments starting a line are recognized, so comment markers inside strings
+// or after code are left alone. Runs of line comments keep their first line;
+// block comments keep their opening line, their first line of text, and their
+// closing line. (internal helper)
+func leanComments(content []byte, syntax commentSyntax, maxLines int) []byte {
+	lines := bytes.SplitAfter(content, []byte("\n"))
+	var out bytes.Buffer
+	changed := false
+
+	for i := 0; i < len(lines); {
+		trimmed := strings.TrimSpace(string(lines[i]))
+
+		// A run of line comments, stopping at directives such as //go:build
+		if isLineComment(trimmed, syntax.line) {
+			end := i + 1
+			for end < len(lines) && isLineComment(strings.TrimSpace(string(lines[end])), syntax.line) {
+				end++
+			}
+			if end-i > maxLines {
+				out.Write(lines[i])
+				changed = true
+			} else {
+				writeLines(&out, lines[i:end])
+			}
+			i = end
+			continue
+		}
+
+		// A block comment that doesn't close on its opening line
+		if syntax.blockStart != "" && strings.HasPrefix(trimmed, syntax.blockStart) &&
+			!strings.Contains(trimmed[len(syntax.blockStart):], syntax.blockEnd) {
+			end := i + 1
+			for end < len(lines) && !strings.Contains(string(lines[end]), syntax.blockEnd) {
+				end++
+			}
+			if end == len(lines) {
+				// Unterminated; leave the rest of the file as it is
+				writeLines(&out, lines[i:])
+				break
+			}
+			end++
+			if end-i > maxLines {
+				out.Write(lines[i])
+				if strings.TrimSpace(strings.TrimPrefix(trimmed, syntax.blockStart)) == "" {
+					// The summary is on the first line of text after the opening
+					for _, line := range lines[i+1 : end-1] {
+						if strings.Trim(string(line), " \t\r\n*") != "" {
+							out.Write(line)
+							break
+						}
+					}
+				}
+				out.Write(lines[end-1])
+				changed = true
+			} else {
+				writeLines(&out, lines[i:end])
+			}
+			i = end
+			continue
+		}
+
+		out.Write(lines[i])
+		i++
+	}
+
+	if !changed {
+		return content
+	}
+	return out.Bytes()
+}
+
+// isLineComment reports whether a trimmed line is a line comment other than a
+// compiler directive or shebang (internal helper)
+func isLineComment(trimmed, prefix string) bool {
+	if prefix == "" || !strings.HasPrefix(trimmed, prefix) {
+		return false
+	}
+	rest := trimmed[len(prefix):]
+	switch {
+	case prefix == "//" && (strings.HasPrefix(rest, "go:") || strings.HasPrefix(rest, "+build") || strings.HasPrefix(rest, "/ <reference")):
+		return false
+	case prefix == "#" && strings.HasPrefix(rest, "!"):
+		return false
+	case prefix == "--" && strings.HasPrefix(rest, "[["):
+		// Lua block comments open with --[[
+		return false
+	}
+	return true
+}
+
+// writeLines writes lines unchanged (internal helper)
+func writeLines(out *bytes.Buffer, lines [][]byte) {
+	for _, line := range lines {
+		out.Write(line)
+	}
+}
@@ -0,0 +1,118 @@
+package handoff
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestLeanComments tests shortening long comment blocks to their first line
+func TestLeanComments(t *testing.T) {
+	goSyntax := commentSyntaxes["go"]
+	testCases := []struct {
+		name    string
+		content string
+		syntax  commentSyntax
+		want    string
+	}{
+		{
+			"Long line comment run",
+			"// Add sums two numbers.\n// It never overflows.\n// Really.\nfunc Add() {}\n",
+			goSyntax,
+			"// Add sums two numbers.\nfunc Add() {}\n",
+		},
+		{
+			"Short line comment run kept",
+			"// Add sums.\n// Really.\nfunc Add() {}\n",
+			goSyntax,
+			"// Add sums.\n// Really.\nfunc Add() {}\n",
+		},
+		{
+			"Directives end a run",
+			"//go:build linux\n// a\n// b\n// c\npackage main\n",
+			goSyntax,
+			"//go:build linux\n// a\npackage main\n",
+		},
+		{
+			"Block comment with summary after opening",
+			"/*\n * Summary.\n * Details.\n * More details.\n */\nx := 1\n",
+			goSyntax,
+			"/*\n * Summary.\n */\nx := 1\n",
+		},
+		{
+			"Block comment with summary on opening line",
+			"\t/* Summary.\n\t   Details.\n\t   More. */\n",
+			goSyntax,
+			"\t/* Summary.\n\t   More. */\n",
+		},
+		{
+			"Single-line block comment kept",
+			"/* short */\n/* also short */\n",
+			goSyntax,
+			"/* short */\n/* also short */\n",
+		},
+		{
+			"Unterminated block comment kept",
+			"/*\na\nb\nc\n",
+			goSyntax,
+			"/*\na\nb\nc\n",
+		},
+		{
+			"Trailing comments after code kept",
+			"x := 1 // a\ny := 2 // b\nz := 3 // c\n",
+			goSyntax,
+			"x := 1 // a\ny := 2 // b\nz := 3 // c\n",
+		},
+		{
+			"Python docstring",
+			"def f():\n    \"\"\"Summary.\n\n    Details.\n    \"\"\"\n    return 1\n",
+			commentSyntaxes["python"],
+			"def f():\n    \"\"\"Summary.\n    \"\"\"\n    return 1\n",
+		},
+		{
+			"Shebang kept",
+			"#!/bin/sh\n# a\n# b\n# c\necho hi\n",
+			commentSyntaxes["bash"],
+			"#!/bin/sh\n# a\necho hi\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := string(leanComments([]byte(tc.content), tc.syntax, 2)); got != tc.want {
+				t.Errorf("leanComments(%q) = %q, want %q", tc.content, got, tc.want)
+			}
+		})
+	}
+}
+
+// TestWithLeanComments tests that the transformer applies by language
+func TestWithLeanComments(t *testing.T) {
+	dir := t.TempDir()
+	comment := "// one\n// two\n// three\n"
+	files := map[string]string{
+		"main.go":   comment + "package main\n",
+		"notes.txt": comment,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	config := NewConfig(WithGitClient(NewMockGitClient(false)), WithLeanComments(1))
+	content, _, err := ProcessProject([]string{dir}, config)
+	if err != nil {
+		t.Fatalf("ProcessProject failed: %v", err)
+	}
+	if strings.Contains(content, "// two\n// three\npackage main") {
+		t.Errorf("Comment in main.go was not shortened:\n%s", content)
+	}
+	if !strings.Contains(content, "// one\npackage main") {
+		t.Errorf("Summary line of main.go was not kept:\n%s", content)
+	}
+	if !strings.Contains(content, comment) {
+		t.Errorf("Comment in notes.txt was changed:\n%s", content)
+	}
+}
@@ -0,0 +1,57 @@
+package handoff
+
+import "bytes"
+
+// LineEnding describes the line endings used in a file
+type LineEnding string
+
+const (
+	// LineEndingNone marks a file without line breaks
+	LineEndingNone LineEnding = "none"
+
+	// LineEndingLF marks a file using only Unix line endings (\n)
+	LineEndingLF LineEnding = "lf"
+
+	// LineEndingCRLF marks a file using only Windows line endings (\r\n)
+	LineEndingCRLF LineEnding = "crlf"
+
+	// LineEndingMixed marks a file using both LF and CRLF line endings
+	LineEndingMixed LineEnding = "mixed"
+)
+
+// detectLineEndings classifies the line endings in content (internal helper)
+func detectLineEndings(content []byte) LineEnding {
+	lines := bytes.Count(content, []byte("\n"))
+	if lines == 0 {
+		return LineEndingNone
+	}
+	switch crlf := bytes.Count(content, []byte("\r\n")); crlf {
+	case 0:
+		return LineEndingLF
+	case lines:
+		return LineEndingCRLF
+	}
+	return LineEndingMixed
+}
+
+// warnLineEndings warns when processed files use inconsistent line endings,
+// a common hidden cause of oversized diffs, and lists the files that differ
+// from LF in verbose mode (internal helper)
+func warnLineEndings(files []FileStat, logger *Logger) {
+	counts := make(map[LineEnding]int)
+	for _, file := range files {
+		counts[file.LineEndings]++
+	}
+	if counts[LineEndingMixed] == 0 && (counts[LineEndingCRLF] == 0 || counts[LineEndingLF] == 0) {
+		return
+	}
+
+	logger.Warn("inconsistent line endings: %d LF, %d CRLF, and %d mixed LF/CRLF files",
+		counts[LineEndingLF], counts[LineEndingCRLF], counts[LineEndingMixed])
+
+	for _, file := range files {
+		if file.LineEndings == LineEndingCRLF || file.LineEndings == LineEndingMixed {
+			logger.Verbose("%s line endings: %s", file.LineEndings, file.Path)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package handoff
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestDetectLineEndings tests classifying the line endings in content
+func TestDetectLineEndings(t *testing.T) {
+	testCases := map[string]LineEnding{
+		"":                   LineEndingNone,
+		"no newline":         LineEndingNone,
+		"a\nb\n":             LineEndingLF,
+		"a\r\nb\r\n":         LineEndingCRLF,
+		"a\r\nb\nc\r\n":      LineEndingMixed,
+		"lone\rcarriage\n":   LineEndingLF,
+		"a\r\nno final line": LineEndingCRLF,
+	}
+
+	for content, want := range testCases {
+		if got := detectLineEndings([]byte(content)); got != want {
+			t.Errorf("detectLineEndings(%q) = %q, want %q", content, got, want)
+		}
+	}
+}
+
+// TestFileStatsLineEndings tests that per-file stats report line endings as the files were read
+func TestFileStatsLineEndings(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"unix.txt":    "one\ntwo\n",
+		"windows.txt": "one\r\ntwo\r\n",
+		"mixed.txt":   "one\r\ntwo\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	// Stripping carriage returns must not hide the endings on disk
+	config := NewConfig(WithGitClient(NewMockGitClient(false)), WithStripTrailingWhitespace(true))
+	_, stats, err := ProcessProject([]string{dir}, config)
+	if err != nil {
+		t.Fatalf("ProcessProject failed: %v", err)
+	}
+
+	want := map[string]LineEnding{
+		"unix.txt":    LineEndingLF,
+		"windows.txt": LineEndingCRLF,
+		"mixed.txt":   LineEndingMixed,
+	}
+	if len(stats.Files) != len(want) {
+		t.Fatalf("got %d file stats, want %d", len(stats.Files), len(want))
+	}
+	tokens := 0
+	for _, file := range stats.Files {
+		if got := file.LineEndings; got != want[filepath.Base(file.Path)] {
+			t.Errorf("%s line endings = %q, want %q", file.Path, got, want[filepath.Base(file.Path)])
+		}
+		tokens += file.Tokens
+	}
+	if tokens != stats.Tokens {
+		t.Errorf("per-file tokens sum to %d, want the total %d", tokens, stats.Tokens)
+	}
+}
+
+// TestFileStatsLineEndingsTrimmed tests that shortened files keep their line ending report
+func TestFileStatsLineEndingsTrimmed(t *testing.T) {
+	dir := t.TempDir()
+	content := strings.Repeat("some words on a windows line\r\n", 50)
+	if err := os.WriteFile(filepath.Join(dir, "long.txt"), []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	config := NewConfig(WithGitClient(NewMockGitClient(false)), WithMaxTokens(100), WithTrimStrategy(TrimTailTruncate))
+	_, stats, err := ProcessProject([]string{dir}, config)
+	if err != nil {
+		t.Fatalf("ProcessProject failed: %v", err)
+	}
+	if stats.FilesTrimmed != 1 || len(stats.Files) != 1 {
+		t.Fatalf("expected one shortened file, got %d trimmed and %v", stats.FilesTrimmed, stats.Files)
+	}
+	if got := stats.Files[0]; got.LineEndings != LineEndingCRLF || got.Encoding != EncodingUTF8 {
+		t.Errorf("shortened file reported %q line endings and %q encoding, want crlf and utf-8", got.LineEndings, got.Encoding)
+	}
+}
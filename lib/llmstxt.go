@@ -0,0 +1,220 @@
+package handoff
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// LLMsTxtFileName is the name of the llms.txt index file
+	LLMsTxtFileName = "llms.txt"
+
+	// LLMsFullTxtFileName is the name of the file holding the full concatenated content
+	LLMsFullTxtFileName = "llms-full.txt"
+)
+
+// maxDescriptionLength caps the per-file descriptions in the llms.txt index, in runes
+const maxDescriptionLength = 120
+
+// LLMsTxt holds an llms.txt / llms-full.txt pair generated from a project.
+// Index is a short Markdown overview listing the key files with descriptions;
+// Full is the formatted content of every file, as ProcessProject would produce.
+type LLMsTxt struct {
+	Index string
+	Full  string
+}
+
+// GenerateLLMsTxt builds an llms.txt index and llms-full.txt content from the
+// same discovery pipeline as ProcessProject, so filters, budgets, and formatting
+// apply to both. The title heads the index; when empty, the name of the first
+// path's directory is used.
+func GenerateLLMsTxt(paths []string, config *Config, title string) (LLMsTxt, Stats, error) {
+	if config == nil {
+		config = NewConfig()
+	}
+	config = config.Clone()
+	config.ProcessConfig()
+
+	logger := NewLogger(config.Verbose)
+
+	if len(paths) == 0 {
+		return LLMsTxt{}, Stats{}, fmt.Errorf("no paths provided")
+	}
+
+	result, err := assemblePaths(paths, config, logger)
+	if err != nil {
+		return LLMsTxt{}, Stats{}, err
+	}
+
+	if err := checkContextWindow(result.stats.Tokens, config, logger); err != nil {
+		return LLMsTxt{}, result.stats, err
+	}
+
+	if title == "" {
+		title = projectTitle(paths[0])
+	}
+
+	return LLMsTxt{
+		Index: llmsIndex(title, paths, result.files),
+		Full:  wrapOutput(result.content(), result.stats, config),
+	}, result.stats, nil
+}
+
+// projectTitle derives a project name from a path: the directory itself, or
+// the directory containing a file (internal helper)
+func projectTitle(path string) string {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return filepath.Base(path)
+	}
+	if info, err := os.Stat(abs); err == nil && !info.IsDir() {
+		abs = filepath.Dir(abs)
+	}
+	return filepath.Base(abs)
+}
+
+// indexPath returns a file's path relative to the directory argument that
+// contains it, using forward slashes for links (internal helper)
+func indexPath(path string, roots []string) string {
+	for _, root := range roots {
+		if info, err := os.Stat(root); err != nil || !info.IsDir() {
+			continue
+		}
+		if rel, err := filepath.Rel(root, path); err == nil && !strings.HasPrefix(rel, "..") {
+			return filepath.ToSlash(rel)
+		}
+	}
+	return filepath.ToSlash(filepath.Clean(path))
+}
+
+// llmsIndex renders the llms.txt index: a title, an optional summary taken
+// from the README, and the files grouped by top-level directory and sorted by
+// path (internal helper)
+func llmsIndex(title string, roots []string, files []formattedFile) string {
+	var summary string
+	groups := make(map[string][]string)
+	for _, file := range files {
+		path := indexPath(file.path, roots)
+
+		if summary == "" && strings.HasPrefix(strings.ToLower(path), "readme") {
+			summary = readmeSummary(string(file.content))
+		}
+
+		group := "Files"
+		if dir, _, ok := strings.Cut(path, "/"); ok {
+			group = dir
+		}
+
+		entry := fmt.Sprintf("- [%s](%s)", path, path)
+		if description := describeFile(path, string(file.content)); description != "" {
+			entry += ": " + description
+		}
+		groups[group] = append(groups[group], entry)
+	}
+
+	// Root files come first, then directories in name order
+	names := make([]string, 0, len(groups))
+	for name := range groups {
+		if name != "Files" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	if _, ok := groups["Files"]; ok {
+		names = append([]string{"Files"}, names...)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n", title)
+	if summary != "" {
+		fmt.Fprintf(&b, "\n> %s\n", summary)
+	}
+	for _, name := range names {
+		fmt.Fprintf(&b, "\n## %s\n\n", name)
+		sort.Strings(groups[name])
+		for _, entry := range groups[name] {
+			b.WriteString(entry)
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
+
+// readmeSummary returns the first line of prose in a README, skipping
+// headings, badges, and images (internal helper)
+func readmeSummary(content string) string {
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "[![") ||
+			strings.HasPrefix(line, "![") || strings.HasPrefix(line, "<") || strings.HasPrefix(line, "```") {
+			continue
+		}
+		return shortenDescription(line)
+	}
+	return ""
+}
+
+// commentPrefixes are the line comment markers recognized when describing source files
+var commentPrefixes = []string{"///", "//!", "//", "#", "--", ";;", ";", "/**", "/*", "*", `"""`, "'''"}
+
+// describeFile returns a one-line description of a file for the llms.txt index:
+// the first heading of a Markdown file, or the leading comment of a source file.
+// Files without either return an empty description (internal helper).
+func describeFile(path, content string) string {
+	ext := strings.ToLower(filepath.Ext(path))
+	if ext == ".md" || ext == ".markdown" {
+		for _, line := range strings.Split(content, "\n") {
+			line = strings.TrimSpace(line)
+			if strings.HasPrefix(line, "#") {
+				return shortenDescription(strings.TrimSpace(strings.TrimLeft(line, "#")))
+			}
+		}
+		return readmeSummary(content)
+	}
+
+	for i, line := range strings.Split(content, "\n") {
+		if i >= 30 {
+			break
+		}
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#!") || strings.HasPrefix(line, "//go:") ||
+			strings.HasPrefix(line, "// +build") || strings.Contains(line, "-*-") {
+			continue
+		}
+
+		text, ok := "", false
+		for _, prefix := range commentPrefixes {
+			if strings.HasPrefix(line, prefix) {
+				text, ok = strings.TrimPrefix(line, prefix), true
+				break
+			}
+		}
+		if !ok {
+			// Code before any comment means there is no leading description
+			return ""
+		}
+		text = strings.TrimSpace(strings.TrimSuffix(strings.TrimSuffix(strings.TrimSpace(text), "*/"), `"""`))
+		if text == "" || strings.Trim(text, "-=*#/ ") == "" {
+			continue
+		}
+		return shortenDescription(text)
+	}
+	return ""
+}
+
+// shortenDescription keeps the first sentence of a description and caps its
+// length (internal helper)
+func shortenDescription(text string) string {
+	if i := strings.Index(text, ". "); i >= 0 {
+		text = text[:i+1]
+	}
+	if utf8.RuneCountInString(text) > maxDescriptionLength {
+		runes := []rune(text)
+		text = strings.TrimSpace(string(runes[:maxDescriptionLength-3])) + "..."
+	}
+	return text
+}
@@ -0,0 +1,95 @@
+package handoff
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestGenerateLLMsTxt tests building the index and full content from a project
+func TestGenerateLLMsTxt(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"README.md":       "# Widget\n\n[![CI](badge.svg)](ci)\n\nWidget turns gears into sprockets. It is fast.\n",
+		"main.go":         "package main\n\nfunc main() {}\n",
+		"lib/gears.go":    "// Package lib implements the gear train. More detail follows.\npackage lib\n",
+		"docs/install.md": "Intro text\n\n## Installing Widget\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	config := NewConfig(WithGitClient(NewMockGitClient(false)))
+	result, stats, err := GenerateLLMsTxt([]string{dir}, config, "")
+	if err != nil {
+		t.Fatalf("GenerateLLMsTxt failed: %v", err)
+	}
+	if stats.FilesProcessed != len(files) {
+		t.Errorf("FilesProcessed = %d, want %d", stats.FilesProcessed, len(files))
+	}
+
+	for _, want := range []string{
+		"# " + filepath.Base(dir) + "\n",
+		"> Widget turns gears into sprockets.\n",
+		"## Files\n\n",
+		"- [README.md](README.md): Widget\n",
+		"- [main.go](main.go)\n",
+		"## lib\n\n- [lib/gears.go](lib/gears.go): Package lib implements the gear train.\n",
+		"## docs\n\n- [docs/install.md](docs/install.md): Installing Widget\n",
+	} {
+		if !strings.Contains(result.Index, want) {
+			t.Errorf("index is missing %q:\n%s", want, result.Index)
+		}
+	}
+	if strings.Index(result.Index, "## docs") > strings.Index(result.Index, "## lib") {
+		t.Error("directory groups should be sorted by name")
+	}
+
+	full, _, err := ProcessProject([]string{dir}, config)
+	if err != nil {
+		t.Fatalf("ProcessProject failed: %v", err)
+	}
+	if result.Full != full {
+		t.Error("full content should match ProcessProject output")
+	}
+
+	named, _, err := GenerateLLMsTxt([]string{dir}, config, "My Project")
+	if err != nil || !strings.HasPrefix(named.Index, "# My Project\n") {
+		t.Errorf("index with title = %q, %v", named.Index, err)
+	}
+}
+
+// TestDescribeFile tests extracting descriptions from headings and leading comments
+func TestDescribeFile(t *testing.T) {
+	testCases := []struct {
+		path    string
+		content string
+		want    string
+	}{
+		{"notes.md", "# Release notes\n\nText", "Release notes"},
+		{"a.go", "//go:build linux\n\n// Package a does things.\npackage a", "Package a does things."},
+		{"run.py", "#!/usr/bin/env python3\n# -*- coding: utf-8 -*-\n\"\"\"Runs the job.\"\"\"\n", "Runs the job."},
+		{"a.c", "/*\n * Parses headers.\n */\nint x;", "Parses headers."},
+		{"a.sql", "-- Creates the schema\nCREATE TABLE t;", "Creates the schema"},
+		{"a.go", "package a\n\n// Not a file comment\n", ""},
+		{"data.json", "{}", ""},
+	}
+
+	for _, tc := range testCases {
+		if got := describeFile(tc.path, tc.content); got != tc.want {
+			t.Errorf("describeFile(%q, %q) = %q, want %q", tc.path, tc.content, got, tc.want)
+		}
+	}
+
+	long := "// " + strings.Repeat("word ", 50)
+	if got := describeFile("a.go", long); len([]rune(got)) > maxDescriptionLength || !strings.HasSuffix(got, "...") {
+		t.Errorf("long description = %q, want it shortened", got)
+	}
+}
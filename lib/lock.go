@@ -0,0 +1,84 @@
+package handoff
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// ErrOutputLocked is returned by WriteToFile when another process holds the
+// lock on the output file
+var ErrOutputLocked = errors.New("output file is locked by another handoff run")
+
+// lockStaleAfter is how old a lock file holding no PID must be before it is
+// assumed to have been left behind by a process that crashed while creating it
+const lockStaleAfter = time.Minute
+
+// lockFileName returns the path of the lock file guarding an output file
+func lockFileName(path string) string {
+	return path + ".lock"
+}
+
+// lockOutputFile takes an advisory lock on path by creating a lock file next to
+// it, failing fast with ErrOutputLocked when another writer holds it. The lock
+// file holds the writer's PID; a lock whose writer is no longer running is
+// removed and the lock retaken. The returned function releases the lock
+// (internal helper).
+func lockOutputFile(path string) (func(), error) {
+	lockPath := lockFileName(path)
+	for attempt := 0; attempt < 2; attempt++ {
+		file, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+		if err == nil {
+			_, _ = fmt.Fprintf(file, "%d\n", os.Getpid())
+			_ = file.Close()
+			return func() { _ = os.Remove(lockPath) }, nil
+		}
+		if !os.IsExist(err) {
+			return nil, fmt.Errorf("failed to lock %q: %w", path, err)
+		}
+
+		// Break locks left behind by writers that never finished
+		if !lockAbandoned(lockPath) {
+			break
+		}
+		_ = os.Remove(lockPath)
+	}
+	return nil, fmt.Errorf("%w: %s (remove %s if no other run is active)", ErrOutputLocked, path, lockPath)
+}
+
+// lockAbandoned reports whether a lock file was left behind by a writer that
+// never finished: the process whose PID it holds is gone or, when the writer
+// stopped before recording its PID, the file is older than lockStaleAfter
+// (internal helper)
+func lockAbandoned(lockPath string) bool {
+	data, err := os.ReadFile(lockPath)
+	if err != nil {
+		return false
+	}
+	if pid, err := strconv.Atoi(strings.TrimSpace(string(data))); err == nil && pid > 0 {
+		return !processRunning(pid)
+	}
+	info, err := os.Stat(lockPath)
+	return err == nil && time.Since(info.ModTime()) >= lockStaleAfter
+}
+
+// processRunning reports whether a process with the PID exists. Windows finds
+// only running processes; elsewhere a null signal probes the process, which
+// fails with EPERM for processes of other users that are still running.
+// (internal helper)
+func processRunning(pid int) bool {
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	if runtime.GOOS == "windows" {
+		return true
+	}
+	err = process.Signal(syscall.Signal(0))
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
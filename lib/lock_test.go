@@ -0,0 +1,74 @@
+package handoff
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// TestWriteToFileLocking tests that writes fail fast while another writer holds the lock
+func TestWriteToFileLocking(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.md")
+
+	unlock, err := lockOutputFile(path)
+	if err != nil {
+		t.Fatalf("lockOutputFile failed: %v", err)
+	}
+	if err := WriteToFile("second writer", path, true); !errors.Is(err, ErrOutputLocked) {
+		t.Errorf("WriteToFile while locked error = %v, want ErrOutputLocked", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("a locked write should not create the file")
+	}
+
+	unlock()
+	if err := WriteToFile("content", path, true); err != nil {
+		t.Fatalf("WriteToFile after unlock failed: %v", err)
+	}
+	if _, err := os.Stat(lockFileName(path)); !os.IsNotExist(err) {
+		t.Error("the lock file should be removed after writing")
+	}
+
+	// A lock whose writer is still running holds however old it is
+	lockPath := lockFileName(path)
+	if err := os.WriteFile(lockPath, []byte(strconv.Itoa(os.Getpid())+"\n"), 0644); err != nil {
+		t.Fatalf("Failed to write lock file: %v", err)
+	}
+	stale := time.Now().Add(-2 * lockStaleAfter)
+	if err := os.Chtimes(lockPath, stale, stale); err != nil {
+		t.Fatalf("Failed to age lock file: %v", err)
+	}
+	if err := WriteToFile("while running", path, true); !errors.Is(err, ErrOutputLocked) {
+		t.Errorf("WriteToFile with a live writer's old lock error = %v, want ErrOutputLocked", err)
+	}
+
+	// A lock left behind by a crashed writer is broken, however new it is
+	exited := exec.Command(os.Args[0], "-test.run=^$")
+	if err := exited.Run(); err != nil {
+		t.Fatalf("Failed to run a short-lived process: %v", err)
+	}
+	if err := os.WriteFile(lockPath, []byte(strconv.Itoa(exited.Process.Pid)+"\n"), 0644); err != nil {
+		t.Fatalf("Failed to write lock file: %v", err)
+	}
+	if err := WriteToFile("after crash", path, true); err != nil {
+		t.Errorf("WriteToFile with a crashed writer's lock failed: %v", err)
+	}
+
+	// A lock holding no PID is broken once it is stale
+	if err := os.WriteFile(lockPath, nil, 0644); err != nil {
+		t.Fatalf("Failed to write lock file: %v", err)
+	}
+	if err := WriteToFile("while creating", path, true); !errors.Is(err, ErrOutputLocked) {
+		t.Errorf("WriteToFile with a new empty lock error = %v, want ErrOutputLocked", err)
+	}
+	if err := os.Chtimes(lockPath, stale, stale); err != nil {
+		t.Fatalf("Failed to age lock file: %v", err)
+	}
+	if err := WriteToFile("after stale", path, true); err != nil {
+		t.Errorf("WriteToFile with a stale empty lock failed: %v", err)
+	}
+}
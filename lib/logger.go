@@ -0,0 +1,47 @@
+package handoff
+
+import (
+	"fmt"
+	"os"
+)
+
+// Logger provides a simple logging interface with different log levels.
+// All messages are sent to stderr with appropriate prefixes for their level.
+type Logger struct {
+	// verbose determines whether Verbose-level messages are displayed
+	verbose bool
+}
+
+// NewLogger creates a new Logger instance with the specified verbosity setting.
+// When verbose is false, calls to the Verbose method will be suppressed.
+func NewLogger(verbose bool) *Logger {
+	return &Logger{
+		verbose: verbose,
+	}
+}
+
+// Info logs an informational message to stderr.
+// These messages are always displayed regardless of the verbose setting.
+func (l *Logger) Info(format string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, format+"\n", args...)
+}
+
+// Warn logs a warning message to stderr.
+// Warning messages are prefixed with "warning: " and are always displayed.
+func (l *Logger) Warn(format string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, "warning: "+format+"\n", args...)
+}
+
+// Error logs an error message to stderr.
+// Error messages are prefixed with "error: " and are always displayed.
+func (l *Logger) Error(format string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, "error: "+format+"\n", args...)
+}
+
+// Verbose logs a message to stderr only if verbose mode is enabled.
+// These messages are useful for detailed progress information.
+func (l *Logger) Verbose(format string, args ...interface{}) {
+	if l.verbose {
+		fmt.Fprintf(os.Stderr, format+"\n", args...)
+	}
+}
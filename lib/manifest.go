@@ -0,0 +1,144 @@
+package handoff
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+)
+
+// ManifestVersion is the version of the Manifest layout, raised when fields
+// change meaning or are removed
+const ManifestVersion = 1
+
+// Manifest describes a handoff for downstream automation, such as CI jobs or
+// retrieval pipelines, that need the file list, statistics, and settings of an
+// output without parsing it. It is typically written as JSON next to the
+// output.
+type Manifest struct {
+	// Version is the ManifestVersion the manifest was written with
+	Version int `json:"version"`
+
+	// Generated is when the manifest was created
+	Generated time.Time `json:"generated"`
+
+	// Digest is the SHA-256 digest of the output, as "sha256:" and hex digits;
+	// for output split into parts, of the parts joined in order
+	Digest string `json:"digest"`
+
+	// Parts lists the digest of each part when the output was split
+	Parts []string `json:"parts,omitempty"`
+
+	// Stats holds the included files in output order, per-file statistics,
+	// and the skipped files with their reasons
+	Stats Stats `json:"stats"`
+
+	// Config is a snapshot of the settings that produced the output
+	Config ManifestConfig `json:"config"`
+}
+
+// ManifestConfig is the snapshot of the settings in a Manifest. Its fields
+// mirror the Config fields and filters of the same names; zero values are
+// omitted.
+type ManifestConfig struct {
+	Include         []string     `json:"include,omitempty"`
+	Exclude         []string     `json:"exclude,omitempty"`
+	ExcludeNames    []string     `json:"excludeNames,omitempty"`
+	HiddenAllowlist []string     `json:"hiddenAllowlist,omitempty"`
+	PathRules       []PathRule   `json:"pathRules,omitempty"`
+	Root            string       `json:"root,omitempty"`
+	Format          string       `json:"format,omitempty"`
+	MaxFileSize     int64        `json:"maxFileSize,omitempty"`
+	MaxFileLines    int          `json:"maxFileLines,omitempty"`
+	MaxTokens       int          `json:"maxTokens,omitempty"`
+	TrimStrategy    TrimStrategy `json:"trimStrategy,omitempty"`
+	Order           FileOrder    `json:"order,omitempty"`
+	GroupByLanguage bool         `json:"groupByLanguage,omitempty"`
+	GitStatus       bool         `json:"gitStatus,omitempty"`
+	Checksums       bool         `json:"checksums,omitempty"`
+	Model           string       `json:"model,omitempty"`
+	Query           string       `json:"query,omitempty"`
+	DirectoryCap    int          `json:"directoryCap,omitempty"`
+	Sample          int          `json:"sample,omitempty"`
+	SampleSeed      uint64       `json:"sampleSeed,omitempty"`
+	ChunkTokens     int          `json:"chunkTokens,omitempty"`
+	IgnoreGitignore bool         `json:"ignoreGitignore,omitempty"`
+}
+
+// NewManifest describes the output produced with config, given as one string
+// or, when it was split with ProcessProjectChunks, as its parts in order, and
+// the statistics returned with it.
+func NewManifest(stats Stats, config *Config, outputs ...string) Manifest {
+	if config == nil {
+		config = NewConfig()
+	}
+	filters := config.Filters()
+
+	whole := sha256.New()
+	var parts []string
+	for _, output := range outputs {
+		whole.Write([]byte(output))
+		if len(outputs) > 1 {
+			parts = append(parts, contentDigest(output))
+		}
+	}
+
+	return Manifest{
+		Version:   ManifestVersion,
+		Generated: time.Now().UTC().Truncate(time.Second),
+		Digest:    "sha256:" + hex.EncodeToString(whole.Sum(nil)),
+		Parts:     parts,
+		Stats:     stats,
+		Config: ManifestConfig{
+			Include:         filters.Include,
+			Exclude:         filters.Exclude,
+			ExcludeNames:    filters.ExcludeNames,
+			HiddenAllowlist: filters.HiddenAllowlist,
+			PathRules:       filters.PathRules,
+			Root:            config.Root,
+			Format:          config.Format,
+			MaxFileSize:     config.MaxFileSize,
+			MaxFileLines:    config.MaxFileLines,
+			MaxTokens:       config.MaxTokens,
+			TrimStrategy:    config.TrimStrategy,
+			Order:           config.Order,
+			GroupByLanguage: config.GroupByLanguage,
+			GitStatus:       config.GitStatus,
+			Checksums:       config.Checksums,
+			Model:           config.Model.Name,
+			Query:           config.Query,
+			DirectoryCap:    config.DirectoryCap,
+			Sample:          config.Sample,
+			SampleSeed:      config.SampleSeed,
+			ChunkTokens:     config.ChunkTokens,
+			IgnoreGitignore: config.IgnoreGitignore,
+		},
+	}
+}
+
+// JSON encodes the manifest as indented JSON followed by a newline. The
+// manifest holds only strings, numbers, and times, so encoding cannot fail.
+func (m Manifest) JSON() []byte {
+	data, _ := json.MarshalIndent(m, "", "  ")
+	return append(data, '\n')
+}
+
+// WithChecksums adds the SHA-256 digest of each file's content, as included in
+// the output, to its header and to Stats.Files, and so to the Manifest. A
+// caller can later check that a patch suggested by a model was based on the
+// exact bytes it was shown. The default output and style presets note the
+// digest in a comment such as <!-- sha256:9f86d0... --> before the file's
+// block, templates can place it with the {checksum} placeholder, and JSON
+// Lines output carries it as a field.
+func WithChecksums(checksums bool) Option {
+	return func(c *Config) {
+		c.Checksums = checksums
+	}
+}
+
+// contentDigest returns the SHA-256 digest of content as "sha256:" and hex
+// digits (internal helper)
+func contentDigest(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,106 @@
+package handoff
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestNewManifest tests describing an output for downstream automation
+func TestNewManifest(t *testing.T) {
+	config := NewConfig(WithInclude(".go"), WithMaxTokens(5000), WithOrder(OrderChurn))
+	stats := Stats{
+		FilesProcessed: 1,
+		IncludedFiles:  []string{"main.go"},
+		SkippedFiles:   []SkippedFile{{Path: "logo.png", Reason: SkipBinary}},
+	}
+
+	manifest := NewManifest(stats, config, "<context>\n</context>")
+	sum := sha256.Sum256([]byte("<context>\n</context>"))
+	if want := "sha256:" + hex.EncodeToString(sum[:]); manifest.Digest != want {
+		t.Errorf("Digest = %s, want %s", manifest.Digest, want)
+	}
+	if manifest.Parts != nil {
+		t.Errorf("Parts = %v, want none for a single output", manifest.Parts)
+	}
+	if manifest.Version != ManifestVersion || manifest.Generated.IsZero() {
+		t.Errorf("Version = %d, Generated = %v", manifest.Version, manifest.Generated)
+	}
+	if !reflect.DeepEqual(manifest.Config.Include, []string{".go"}) || manifest.Config.MaxTokens != 5000 || manifest.Config.Order != OrderChurn {
+		t.Errorf("Config = %+v", manifest.Config)
+	}
+
+	// The JSON form round-trips
+	var decoded Manifest
+	if err := json.Unmarshal(manifest.JSON(), &decoded); err != nil {
+		t.Fatalf("JSON() is invalid: %v", err)
+	}
+	if !reflect.DeepEqual(decoded.Stats, stats) {
+		t.Errorf("decoded Stats = %+v, want %+v", decoded.Stats, stats)
+	}
+
+	// Split output is digested whole and by part
+	manifest = NewManifest(stats, config, "part one", "part two")
+	if manifest.Digest != contentDigest("part onepart two") {
+		t.Errorf("Digest = %s, want the digest of the joined parts", manifest.Digest)
+	}
+	if want := []string{contentDigest("part one"), contentDigest("part two")}; !reflect.DeepEqual(manifest.Parts, want) {
+		t.Errorf("Parts = %v, want %v", manifest.Parts, want)
+	}
+}
+
+// TestWithChecksums tests adding a digest of each file's content
+func TestWithChecksums(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "main.go")
+	if err := os.WriteFile(path, []byte("package main\n"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	sum := sha256.Sum256([]byte("package main\n"))
+	want := "sha256:" + hex.EncodeToString(sum[:])
+
+	config := NewConfig(WithGitClient(NewMockGitClient(false)), WithChecksums(true))
+	content, stats, err := ProcessProject([]string{path}, config)
+	if err != nil {
+		t.Fatalf("ProcessProject failed: %v", err)
+	}
+	if !strings.Contains(content, "<!-- "+want+" -->\n<"+path+">") {
+		t.Errorf("content is missing the checksum header:\n%s", content)
+	}
+	if len(stats.Files) != 1 || stats.Files[0].Checksum != want {
+		t.Errorf("Files = %+v, want checksum %s", stats.Files, want)
+	}
+	if manifest := NewManifest(stats, config, content); manifest.Stats.Files[0].Checksum != want || !manifest.Config.Checksums {
+		t.Errorf("Manifest = %+v, want the checksum entry", manifest)
+	}
+
+	// A template placing {checksum} itself gets no comment
+	config = NewConfig(WithGitClient(NewMockGitClient(false)), WithChecksums(true), WithFormat("{checksum} {path:base}\n"))
+	content, _, err = ProcessProject([]string{path}, config)
+	if err != nil {
+		t.Fatalf("ProcessProject failed: %v", err)
+	}
+	if !strings.Contains(content, "\n"+want+" main.go\n") || strings.Contains(content, "<!--") {
+		t.Errorf("content = %q, want the checksum from the template", content)
+	}
+
+	// A file cut down to fit the budget gets the digest of what was kept
+	config = NewConfig(WithGitClient(NewMockGitClient(false)), WithChecksums(true), WithMaxTokens(40), WithTrimStrategy(TrimTailTruncate))
+	long := strings.Repeat("// a comment line to trim\n", 50)
+	if err := os.WriteFile(path, []byte(long), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	_, stats, err = ProcessProject([]string{path}, config)
+	if err != nil {
+		t.Fatalf("ProcessProject failed: %v", err)
+	}
+	full := sha256.Sum256([]byte(long))
+	if len(stats.Files) != 1 || stats.Files[0].Checksum == "" || stats.Files[0].Checksum == "sha256:"+hex.EncodeToString(full[:]) {
+		t.Errorf("Files = %+v, want the checksum of the trimmed content", stats.Files)
+	}
+}
@@ -0,0 +1,120 @@
+package handoff
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// DefaultResponseReserve is the number of tokens of a model's context window kept
+// free for its response when checking whether the output fits.
+const DefaultResponseReserve = 8192
+
+// ErrContextWindowExceeded is returned in strict mode when the estimated tokens in
+// the output exceed the target model's context window minus the response reserve
+var ErrContextWindowExceeded = errors.New("output exceeds the model's context window")
+
+// ModelProfile describes a target model's context window.
+type ModelProfile struct {
+	// Name identifies the model (e.g., "claude-sonnet")
+	Name string
+
+	// ContextWindow is the model's context window size in tokens
+	ContextWindow int
+}
+
+// modelProfiles lists the built-in model profiles
+var modelProfiles = []ModelProfile{
+	{Name: "claude-opus", ContextWindow: 200000},
+	{Name: "claude-sonnet", ContextWindow: 200000},
+	{Name: "claude-haiku", ContextWindow: 200000},
+	{Name: "gpt-4o", ContextWindow: 128000},
+	{Name: "gpt-4o-mini", ContextWindow: 128000},
+	{Name: "gpt-4.1", ContextWindow: 1047576},
+	{Name: "gemini-1.5-pro", ContextWindow: 2097152},
+	{Name: "gemini-2.5-pro", ContextWindow: 1048576},
+	{Name: "gemini-2.5-flash", ContextWindow: 1048576},
+}
+
+// LookupModel returns the built-in profile for a model name, ignoring case.
+func LookupModel(name string) (ModelProfile, error) {
+	for _, profile := range modelProfiles {
+		if strings.EqualFold(profile.Name, name) {
+			return profile, nil
+		}
+	}
+	return ModelProfile{}, fmt.Errorf("unknown model %q (known models: %s)", name, strings.Join(ModelNames(), ", "))
+}
+
+// ModelNames returns the names of the built-in model profiles.
+func ModelNames() []string {
+	names := make([]string, len(modelProfiles))
+	for i, profile := range modelProfiles {
+		names[i] = profile.Name
+	}
+	return names
+}
+
+// FitsContext reports whether output with the given statistics fits the
+// context window of the named built-in model, looked up as with LookupModel,
+// after keeping reserveTokens free for the response. The surplus is the
+// number of tokens left over, or negative by the number over, so a caller can
+// decide to trim, split, or switch models before sending anything. An unknown
+// model never fits and has no surplus.
+func FitsContext(stats Stats, model string, reserveTokens int) (fits bool, surplus int) {
+	profile, err := LookupModel(model)
+	if err != nil {
+		return false, 0
+	}
+	surplus = profile.ContextWindow - reserveTokens - stats.Tokens
+	return surplus >= 0, surplus
+}
+
+// WithModel sets the target model. When the estimated tokens in the output exceed
+// the model's context window minus the response reserve, a warning is logged, or
+// ErrContextWindowExceeded is returned in strict mode (see WithStrict).
+// Use LookupModel for built-in profiles or construct a ModelProfile directly.
+func WithModel(profile ModelProfile) Option {
+	return func(c *Config) {
+		c.Model = profile
+	}
+}
+
+// WithResponseReserve sets the number of context window tokens kept free for the
+// model's response. The default is DefaultResponseReserve.
+func WithResponseReserve(tokens int) Option {
+	return func(c *Config) {
+		c.ResponseReserve = tokens
+	}
+}
+
+// WithStrict sets whether exceeding the target model's context window is an
+// error rather than a warning.
+func WithStrict(strict bool) Option {
+	return func(c *Config) {
+		c.Strict = strict
+	}
+}
+
+// checkContextWindow compares the estimated tokens against the target model's
+// usable context window, warning or failing in strict mode (internal helper)
+func checkContextWindow(tokens int, config *Config, logger *Logger) error {
+	if config.Model.ContextWindow <= 0 {
+		return nil
+	}
+
+	available := config.Model.ContextWindow - config.ResponseReserve
+	if tokens <= available {
+		logger.Verbose("Estimated %d tokens fit %s's %d-token window (%d reserved for the response)",
+			tokens, config.Model.Name, config.Model.ContextWindow, config.ResponseReserve)
+		return nil
+	}
+
+	if config.Strict {
+		return fmt.Errorf("%w: estimated %d tokens, %s allows %d after reserving %d for the response",
+			ErrContextWindowExceeded, tokens, config.Model.Name, available, config.ResponseReserve)
+	}
+	logger.Warn("estimated %d tokens exceed the %d available for %s after reserving %d for the response",
+		tokens, available, config.Model.Name, config.ResponseReserve)
+	return nil
+}
@@ -0,0 +1,100 @@
+package handoff
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestLookupModel tests resolving built-in model profiles
+func TestLookupModel(t *testing.T) {
+	profile, err := LookupModel("Claude-Sonnet")
+	if err != nil {
+		t.Fatalf("LookupModel failed: %v", err)
+	}
+	if profile.Name != "claude-sonnet" || profile.ContextWindow != 200000 {
+		t.Errorf("LookupModel() = %+v, want claude-sonnet with 200000 tokens", profile)
+	}
+
+	_, err = LookupModel("gpt-9")
+	if err == nil {
+		t.Fatal("LookupModel(\"gpt-9\") succeeded, want error")
+	}
+	if !strings.Contains(err.Error(), "gpt-4o") {
+		t.Errorf("error %q should list known models", err)
+	}
+}
+
+// TestCheckContextWindow tests warning and strict failures against a model's window
+func TestCheckContextWindow(t *testing.T) {
+	model := ModelProfile{Name: "tiny", ContextWindow: 1000}
+
+	testCases := []struct {
+		name    string
+		tokens  int
+		config  *Config
+		wantErr bool
+	}{
+		{"No model", 5000, NewConfig(), false},
+		{"Fits", 800, NewConfig(WithModel(model), WithResponseReserve(200), WithStrict(true)), false},
+		{"Exceeds with warning", 900, NewConfig(WithModel(model), WithResponseReserve(200)), false},
+		{"Exceeds in strict mode", 900, NewConfig(WithModel(model), WithResponseReserve(200), WithStrict(true)), true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := checkContextWindow(tc.tokens, tc.config, NewLogger(false))
+			if tc.wantErr != errors.Is(err, ErrContextWindowExceeded) {
+				t.Errorf("checkContextWindow() error = %v, wantErr %v", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+// TestProcessProjectStrictModel tests that ProcessProject fails in strict mode
+// when the output exceeds the model's context window
+func TestProcessProjectStrictModel(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte(strings.Repeat("word ", 100)), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	config := NewConfig(
+		WithGitClient(NewMockGitClient(false)),
+		WithModel(ModelProfile{Name: "tiny", ContextWindow: 60}),
+		WithResponseReserve(10),
+		WithStrict(true),
+	)
+	_, _, err := ProcessProject([]string{dir}, config)
+	if !errors.Is(err, ErrContextWindowExceeded) {
+		t.Errorf("ProcessProject() error = %v, want ErrContextWindowExceeded", err)
+	}
+}
+
+// TestFitsContext tests checking statistics against a built-in model
+func TestFitsContext(t *testing.T) {
+	testCases := []struct {
+		name        string
+		tokens      int
+		model       string
+		reserve     int
+		wantFits    bool
+		wantSurplus int
+	}{
+		{"fits", 100000, "gpt-4o", 8000, true, 20000},
+		{"exactly fits", 120000, "GPT-4o", 8000, true, 0},
+		{"over", 125000, "gpt-4o", 8000, false, -5000},
+		{"unknown model", 10, "no-such-model", 0, false, 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fits, surplus := FitsContext(Stats{Tokens: tc.tokens}, tc.model, tc.reserve)
+			if fits != tc.wantFits || surplus != tc.wantSurplus {
+				t.Errorf("FitsContext() = %v, %d, want %v, %d", fits, surplus, tc.wantFits, tc.wantSurplus)
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package handoff
+
+import (
+	"bufio"
+	"bytes"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// entryPointNames are file names that conventionally hold a program's entry point
+var entryPointNames = []string{
+	"main.go", "main.py", "__main__.py", "app.py", "manage.py", "wsgi.py", "asgi.py",
+	"index.js", "index.ts", "index.jsx", "index.tsx", "index.mjs",
+	"main.js", "main.ts", "app.js", "app.ts", "server.js", "server.ts",
+	"main.rs", "lib.rs", "main.c", "main.cpp", "main.java", "program.cs", "main.kt", "main.swift",
+}
+
+// fixtureDirNames are directory names whose contents are test support files,
+// ordered with the tests
+var fixtureDirNames = []string{"fixtures", "__fixtures__", "mocks", "__mocks__"}
+
+// importLinePattern matches lines that import other code in common languages
+var importLinePattern = regexp.MustCompile(`^\s*(?:import\b|from\s+\S+\s+import\b|export\s.*\bfrom\b|use\s|mod\s|#include\b|require\b|.*\brequire\s*\(|"[^"]+"\s*$|\w+\s+"[^"]+"\s*$)`)
+
+// Tiers of the entry point order, read in this order
+const (
+	tierEntryPoint = iota
+	tierCore
+	tierTests
+)
+
+// sortByEntryPoints orders files the way a developer would read a program:
+// likely entry points first, then the other source files, most imported first,
+// then tests and fixtures. Files that rank equally keep discovery order
+// (internal helper).
+func sortByEntryPoints(files []formattedFile) {
+	tiers := make(map[string]int, len(files))
+	for _, file := range files {
+		tiers[file.path] = entryPointTier(file.path)
+	}
+	degrees := importDegrees(files)
+
+	sort.SliceStable(files, func(i, j int) bool {
+		a, b := files[i].path, files[j].path
+		if tiers[a] != tiers[b] {
+			return tiers[a] < tiers[b]
+		}
+		return degrees[a] > degrees[b]
+	})
+}
+
+// entryPointTier classifies a file as an entry point, a core file, or a test
+// or fixture (internal helper)
+func entryPointTier(path string) int {
+	if isTestFile(path) {
+		return tierTests
+	}
+	slashPath := filepath.ToSlash(filepath.Clean(path))
+	dirs := strings.Split(filepath.ToSlash(filepath.Dir(slashPath)), "/")
+	for _, dir := range dirs {
+		if slices.Contains(fixtureDirNames, strings.ToLower(dir)) {
+			return tierTests
+		}
+	}
+
+	if slices.Contains(entryPointNames, strings.ToLower(filepath.Base(slashPath))) {
+		return tierEntryPoint
+	}
+	// Files directly inside a cmd/<name> directory are Go command entry points
+	if len(dirs) >= 2 && dirs[len(dirs)-2] == "cmd" {
+		return tierEntryPoint
+	}
+	return tierCore
+}
+
+// importDegrees estimates how many other files import each file, by matching
+// a file's name without its extension, or a Go file's package directory,
+// against the import lines of the other files (internal helper)
+func importDegrees(files []formattedFile) map[string]int {
+	imports := make([]string, len(files))
+	for i, file := range files {
+		var lines []string
+		scanner := bufio.NewScanner(bytes.NewReader(file.content))
+		for scanner.Scan() {
+			if line := scanner.Text(); importLinePattern.MatchString(line) {
+				lines = append(lines, line)
+			}
+		}
+		imports[i] = strings.Join(lines, "\n")
+	}
+
+	// Files sharing a name, such as the files of a Go package, share their
+	// imports' references, so patterns are compiled once per name
+	patterns := make(map[string]*regexp.Regexp)
+	degrees := make(map[string]int, len(files))
+	for i, file := range files {
+		name := importName(file.path)
+		if name == "" {
+			continue
+		}
+		pattern, ok := patterns[name]
+		if !ok {
+			pattern = regexp.MustCompile(`(?:^|[^\w-])` + regexp.QuoteMeta(name) + `(?:$|[^\w-])`)
+			patterns[name] = pattern
+		}
+		for j := range files {
+			if i != j && pattern.MatchString(imports[j]) {
+				degrees[file.path]++
+			}
+		}
+	}
+	return degrees
+}
+
+// importName returns the name other files use to import a file: the package
+// directory for Go files and the file name without its extension otherwise.
+// Names too generic to identify a file return an empty string (internal helper).
+func importName(path string) string {
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	if strings.EqualFold(filepath.Ext(path), ".go") {
+		name = filepath.Base(filepath.Dir(path))
+	}
+	switch strings.ToLower(name) {
+	case "", ".", "/", "index", "main", "__init__", "mod", "lib", "app":
+		return ""
+	}
+	return name
+}
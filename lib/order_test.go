@@ -0,0 +1,68 @@
+package handoff
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestEntryPointTier tests classifying files for the entry point order
+func TestEntryPointTier(t *testing.T) {
+	testCases := map[string]int{
+		"main.go":                 tierEntryPoint,
+		"cmd/server/server.go":    tierEntryPoint,
+		"src/index.ts":            tierEntryPoint,
+		"app.py":                  tierEntryPoint,
+		"lib/handoff.go":          tierCore,
+		"cmd/README.md":           tierCore,
+		"lib/handoff_test.go":     tierTests,
+		"tests/test_app.py":       tierTests,
+		"src/__mocks__/client.ts": tierTests,
+		"fixtures/main.go":        tierTests,
+	}
+
+	for path, want := range testCases {
+		if got := entryPointTier(filepath.FromSlash(path)); got != want {
+			t.Errorf("entryPointTier(%q) = %d, want %d", path, got, want)
+		}
+	}
+}
+
+// TestOrderEntryPoints tests putting entry points first, then the most
+// imported files, then tests
+func TestOrderEntryPoints(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"a_test.go":      "package main\n",
+		"helpers/h.go":   "package helpers\n",
+		"main.go":        "package main\n\nimport (\n\t\"example.com/app/store\"\n)\n",
+		"server/api.go":  "package server\n\nimport \"example.com/app/store\"\n",
+		"store/store.go": "package store\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory for %s: %v", name, err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	config := NewConfig(WithGitClient(NewMockGitClient(false)), WithOrder(OrderEntryPoints))
+	_, stats, err := ProcessProject([]string{dir}, config)
+	if err != nil {
+		t.Fatalf("ProcessProject failed: %v", err)
+	}
+
+	var got []string
+	for _, path := range stats.IncludedFiles {
+		rel, _ := filepath.Rel(dir, path)
+		got = append(got, filepath.ToSlash(rel))
+	}
+	want := "main.go store/store.go helpers/h.go server/api.go a_test.go"
+	if strings.Join(got, " ") != want {
+		t.Errorf("order = %v, want %s", got, want)
+	}
+}
@@ -0,0 +1,117 @@
+package handoff
+
+import (
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// expandPlaceholders replaces {name} placeholders in a template with their
+// values in a single pass, so substituted text, such as file content that
+// happens to contain "{path}", is never expanded again. Doubled braces, {{ and
+// }}, produce literal braces, so "{{path}}" renders as "{path}"; braces around
+// anything value doesn't recognize are kept as written. A placeholder may
+// chain modifiers after colons, such as {content:trim:indent=2}; see
+// applyModifier. (internal helper)
+func expandPlaceholders(template string, value func(name string) (string, bool)) string {
+	var out strings.Builder
+	for i := 0; i < len(template); {
+		switch {
+		case strings.HasPrefix(template[i:], "{{"):
+			out.WriteByte('{')
+			i += 2
+		case strings.HasPrefix(template[i:], "}}"):
+			out.WriteByte('}')
+			i += 2
+		case template[i] == '{':
+			if end := strings.IndexAny(template[i+1:], "{}"); end >= 0 && template[i+1+end] == '}' {
+				if expanded, ok := expandPlaceholder(template[i+1:i+1+end], value); ok {
+					out.WriteString(expanded)
+					i += end + 2
+					continue
+				}
+			}
+			out.WriteByte('{')
+			i++
+		default:
+			out.WriteByte(template[i])
+			i++
+		}
+	}
+	return out.String()
+}
+
+// expandPlaceholder expands a placeholder's name and applies its modifiers,
+// reporting false if the name or any modifier is unknown (internal helper)
+func expandPlaceholder(placeholder string, value func(name string) (string, bool)) (string, bool) {
+	name, modifiers, _ := strings.Cut(placeholder, ":")
+	expanded, ok := value(name)
+	if !ok {
+		return "", false
+	}
+	if modifiers == "" {
+		return expanded, true
+	}
+	for _, modifier := range strings.Split(modifiers, ":") {
+		if expanded, ok = applyModifier(expanded, modifier); !ok {
+			return "", false
+		}
+	}
+	return expanded, true
+}
+
+// applyModifier transforms a placeholder's value, reporting false for an
+// unknown modifier (internal helper). Modifiers are:
+//   - trim: remove leading and trailing whitespace
+//   - trimlines: remove leading and trailing blank lines, keeping indentation
+//   - indent=N: indent each non-empty line by N spaces
+//   - base, dir, ext: the last element, directory, or extension of a path
+//   - upper, lower: change case
+func applyModifier(value, modifier string) (string, bool) {
+	switch modifier {
+	case "trim":
+		return strings.TrimSpace(value), true
+	case "trimlines":
+		return trimBlankLines(value), true
+	case "base":
+		return filepath.Base(value), true
+	case "dir":
+		return filepath.Dir(value), true
+	case "ext":
+		return filepath.Ext(value), true
+	case "upper":
+		return strings.ToUpper(value), true
+	case "lower":
+		return strings.ToLower(value), true
+	}
+
+	if width, ok := strings.CutPrefix(modifier, "indent="); ok {
+		n, err := strconv.Atoi(width)
+		if err != nil || n < 0 {
+			return "", false
+		}
+		prefix := strings.Repeat(" ", n)
+		lines := strings.SplitAfter(value, "\n")
+		for i, line := range lines {
+			if strings.TrimSpace(line) != "" {
+				lines[i] = prefix + line
+			}
+		}
+		return strings.Join(lines, ""), true
+	}
+	return "", false
+}
+
+// trimBlankLines removes blank lines from the start and end of value, along
+// with the final line break, leaving the indentation of the first line intact
+// (internal helper)
+func trimBlankLines(value string) string {
+	lines := strings.Split(value, "\n")
+	for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,58 @@
+package handoff
+
+import "testing"
+
+// TestExpandPlaceholders tests single-pass placeholder expansion with escaped braces
+func TestExpandPlaceholders(t *testing.T) {
+	values := map[string]string{"path": "a.go", "content": "x := \"{path}\""}
+	value := func(name string) (string, bool) {
+		v, ok := values[name]
+		return v, ok
+	}
+
+	testCases := map[string]string{
+		"<{path}>\n{content}": "<a.go>\nx := \"{path}\"",
+		"{{path}}: {path}":    "{path}: a.go",
+		"{{{path}}}":          "{a.go}",
+		`{"file": "{path}"}`:  `{"file": "a.go"}`,
+		"{unknown} {path":     "{unknown} {path",
+		"{ {path}":            "{ a.go",
+		"}} and }":            "} and }",
+		"no placeholders":     "no placeholders",
+		"{path}{path}":        "a.goa.go",
+	}
+
+	for template, want := range testCases {
+		if got := expandPlaceholders(template, value); got != want {
+			t.Errorf("expandPlaceholders(%q) = %q, want %q", template, got, want)
+		}
+	}
+}
+
+// TestPlaceholderModifiers tests modifiers applied to placeholder values
+func TestPlaceholderModifiers(t *testing.T) {
+	values := map[string]string{"path": "src/app/main.go", "content": "\n  func main() {\n\n}\n\n"}
+	value := func(name string) (string, bool) {
+		v, ok := values[name]
+		return v, ok
+	}
+
+	testCases := map[string]string{
+		"{path:base}":             "main.go",
+		"{path:dir}":              "src/app",
+		"{path:ext}":              ".go",
+		"{path:base:upper}":       "MAIN.GO",
+		"{content:trim}":          "func main() {\n\n}",
+		"{content:trim:indent=2}": "  func main() {\n\n  }",
+		"{content:trimlines}":     "  func main() {\n\n}",
+		"{content:indent=x}":      "{content:indent=x}",
+		"{path:unknown}":          "{path:unknown}",
+		"{{path:base}}":           "{path:base}",
+	}
+
+	for template, want := range testCases {
+		if got := expandPlaceholders(template, value); got != want {
+			t.Errorf("expandPlaceholders(%q) = %q, want %q", template, got, want)
+		}
+	}
+}
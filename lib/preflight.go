@@ -0,0 +1,158 @@
+package handoff
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// PreflightProblem names a kind of problem found by Preflight
+type PreflightProblem string
+
+const (
+	// PreflightUnreadable marks a file or directory that can't be opened,
+	// such as one without read permission or a path that doesn't exist
+	PreflightUnreadable PreflightProblem = "unreadable"
+
+	// PreflightBrokenSymlink marks a symlink whose target doesn't exist
+	PreflightBrokenSymlink PreflightProblem = "broken symlink"
+
+	// PreflightTooLarge marks a file over the size limit set with WithMaxFileSize
+	PreflightTooLarge PreflightProblem = "too large"
+
+	// PreflightSpecial marks a named pipe, socket, or device, which is skipped
+	PreflightSpecial PreflightProblem = "special file"
+)
+
+// PreflightIssue describes a file or directory that a run would fail to read
+// or leave out
+type PreflightIssue struct {
+	Path    string           `json:"path"`
+	Problem PreflightProblem `json:"problem"`
+
+	// Detail explains the problem, such as the error or the file's size
+	Detail string `json:"detail,omitempty"`
+}
+
+// Preflight checks the files ProcessProject would process for the given paths
+// without reading their content, and returns the problems that would make a
+// run skip them: unreadable files and directories, broken symlinks, files over
+// the size limit, and special files. Running it first lets a long run be fixed
+// up front instead of failing or silently leaving files out halfway through.
+// Files left out by filters aren't checked. Issues are sorted by path, which
+// is relative to the root with WithRoot.
+func Preflight(paths []string, config *Config) ([]PreflightIssue, error) {
+	if config == nil {
+		config = NewConfig()
+	}
+	config = config.Clone()
+	config.ProcessConfig()
+
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no paths provided")
+	}
+
+	logger := NewLogger(config.Verbose)
+	var issues []PreflightIssue
+	report := func(path string, problem PreflightProblem, detail string) {
+		issues = append(issues, PreflightIssue{Path: config.displayPath(path), Problem: problem, Detail: detail})
+	}
+
+	// Check the paths themselves, which discovery would only warn about, and
+	// the directories for entries that discovery drops without a word
+	var existing []string
+	for _, path := range config.resolvePaths(paths) {
+		if path == StdinPath {
+			continue
+		}
+		info, err := os.Stat(path)
+		switch {
+		case err != nil && isGlob(path):
+			existing = append(existing, path)
+		case err != nil:
+			if target, linkErr := os.Readlink(path); linkErr == nil {
+				report(path, PreflightBrokenSymlink, "target "+target+" does not exist")
+			} else {
+				report(path, PreflightUnreadable, pathErrorDetail(err))
+			}
+		case info.IsDir():
+			preflightDir(path, config, logger, report)
+			existing = append(existing, path)
+		default:
+			existing = append(existing, path)
+		}
+	}
+
+	discovered, err := discoverFiles(existing, config, logger)
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range discovered {
+		if file.info == nil || filterReason(file.path, file.info, config, logger) != "" {
+			continue
+		}
+		switch {
+		case !file.info.Mode().IsRegular():
+			report(file.path, PreflightSpecial, file.info.Mode().Type().String())
+		case config.MaxFileSize > 0 && file.info.Size() > config.MaxFileSize:
+			report(file.path, PreflightTooLarge, fmt.Sprintf("%d bytes, over the %d-byte limit", file.info.Size(), config.MaxFileSize))
+		default:
+			f, err := os.Open(file.path)
+			if err != nil {
+				report(file.path, PreflightUnreadable, pathErrorDetail(err))
+				continue
+			}
+			_ = f.Close()
+		}
+	}
+
+	slices.SortStableFunc(issues, func(a, b PreflightIssue) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+	return issues, nil
+}
+
+// preflightDir walks a directory for unreadable subdirectories and broken
+// symlinks that pass the filters, skipping hidden directories as discovery
+// does (internal helper)
+func preflightDir(dir string, config *Config, logger *Logger, report func(string, PreflightProblem, string)) {
+	_ = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if !isGitIgnored(path, config) || config.IgnoreGitignore {
+				report(path, PreflightUnreadable, pathErrorDetail(err))
+			}
+			if d != nil && d.IsDir() && path != dir {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			if path != dir && isHiddenSkipped(d.Name(), config) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.Type()&fs.ModeSymlink == 0 || isHiddenSkipped(d.Name(), config) {
+			return nil
+		}
+		if _, statErr := os.Stat(path); errors.Is(statErr, fs.ErrNotExist) && filterReason(path, nil, config, logger) == "" {
+			target, _ := os.Readlink(path)
+			report(path, PreflightBrokenSymlink, "target "+target+" does not exist")
+		}
+		return nil
+	})
+}
+
+// pathErrorDetail returns the cause of a path error without repeating the
+// path (internal helper)
+func pathErrorDetail(err error) string {
+	var pathErr *fs.PathError
+	if errors.As(err, &pathErr) {
+		return pathErr.Err.Error()
+	}
+	return err.Error()
+}
@@ -0,0 +1,54 @@
+package handoff
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+)
+
+// TestPreflight tests reporting files a run would fail to read or leave out
+func TestPreflight(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Creating symlinks requires extra privileges on Windows")
+	}
+	dir := t.TempDir()
+	for name, size := range map[string]int{"main.go": 10, "big.go": 200, "notes.txt": 500} {
+		if err := os.WriteFile(filepath.Join(dir, name), make([]byte, size), 0644); err != nil {
+			t.Fatalf("Failed to write file: %v", err)
+		}
+	}
+	if err := os.Symlink(filepath.Join(dir, "gone.go"), filepath.Join(dir, "link.go")); err != nil {
+		t.Fatalf("Failed to create symlink: %v", err)
+	}
+
+	config := NewConfig(
+		WithGitClient(NewMockGitClient(false)),
+		WithRoot(dir),
+		WithInclude(".go"),
+		WithMaxFileSize(100),
+	)
+	issues, err := Preflight([]string{".", "missing.go"}, config)
+	if err != nil {
+		t.Fatalf("Preflight failed: %v", err)
+	}
+
+	// The oversized notes.txt is filtered out, so it isn't reported
+	want := []PreflightIssue{
+		{Path: "big.go", Problem: PreflightTooLarge, Detail: "200 bytes, over the 100-byte limit"},
+		{Path: "link.go", Problem: PreflightBrokenSymlink, Detail: "target " + filepath.Join(dir, "gone.go") + " does not exist"},
+		{Path: "missing.go", Problem: PreflightUnreadable, Detail: "no such file or directory"},
+	}
+	if !reflect.DeepEqual(issues, want) {
+		t.Errorf("Preflight() = %+v, want %+v", issues, want)
+	}
+
+	// A clean selection has no issues
+	if issues, err := Preflight([]string{"main.go"}, config); err != nil || len(issues) != 0 {
+		t.Errorf("Preflight() = %+v, %v; want no issues", issues, err)
+	}
+	if _, err := Preflight(nil, config); err == nil {
+		t.Error("Preflight() with no paths should return an error")
+	}
+}
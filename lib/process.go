@@ -0,0 +1,266 @@
+package handoff
+
+import (
+	"os"
+)
+
+// ProcessorFunc is a function type that processes a file's content and returns formatted output.
+// It receives the file path and raw content and should return the processed content as a string.
+// This type is used for custom file processing in internal functions like processFile and processPathWithProcessor.
+type ProcessorFunc func(filePath string, content []byte) string
+
+// processFile processes a single file with the given processor function and configuration.
+// It applies various filters (gitignore, extension/name filters, binary detection) and
+// passes the valid file's content to the processor function to generate formatted output.
+//
+// If the file should be skipped (doesn't exist, is gitignored, doesn't match filters,
+// or is binary), an empty string is returned and appropriate messages are logged.
+//
+// The file is stat'ed only when info is nil; callers that already hold the file's
+// info from discovery should pass it to avoid a redundant stat call.
+//
+// Parameters:
+//   - filePath: The path to the file to process
+//   - info: File info from discovery, or nil to stat the file
+//   - logger: Logger for status and error messages
+//   - config: Configuration options controlling filtering
+//   - processor: Function to process the file content
+//
+// Returns a formatted string for valid files or an empty string for skipped files.
+func processFile(filePath string, info os.FileInfo, logger *Logger, config *Config, processor ProcessorFunc) string {
+	output, _ := processFileMeta(discoveredFile{path: filePath, info: info}, logger, config, processor)
+	return output
+}
+
+// fileMeta describes a processed file as it was read, before its content was
+// transformed (internal helper)
+type fileMeta struct {
+	lineEndings LineEnding
+	encoding    Encoding
+	transcoded  bool
+
+	// read is the text content as read, before it was transformed, which a
+	// checkpoint records so a resumed run can filter it again
+	read []byte
+
+	// skipped is the reason a skipped file was left out
+	skipped SkipReason
+
+	// err stops the run when the error handler aborts it
+	err error
+}
+
+// processFileMeta is processFile that also describes the file as it was read,
+// for per-file statistics. Skipped files are described only by the reason they
+// were skipped, which is also reported to the OnFileSkipped hook. A file
+// named directly as a path may have its binary content dumped, and the
+// filters run only if applyFilters hasn't run them already.
+func processFileMeta(file discoveredFile, logger *Logger, config *Config, processor ProcessorFunc) (string, fileMeta) {
+	filePath, info := file.path, file.info
+	skip := func(reason SkipReason) (string, fileMeta) {
+		return skipFile(filePath, reason, config)
+	}
+
+	// Check if file exists when discovery didn't provide its info
+	if info == nil {
+		var meta fileMeta
+		if info, meta = statFile(filePath, logger, config); info == nil {
+			return "", meta
+		}
+	}
+
+	// Skip files that can't or shouldn't be read before reading any content
+	file.info = info
+	if reason := unreadableReason(file, logger, config); reason != "" {
+		return skip(reason)
+	}
+
+	// Read file content, rejecting binary files from an initial sample
+	var content []byte
+	var binary bool
+	abort, err := config.attempt(filePath, func() (err error) {
+		content, binary, err = readFileContent(filePath, info.Size(), config.MaxFileSize, config.throttle)
+		return err
+	})
+	if abort {
+		return "", fileMeta{err: abortRun(err)}
+	}
+	if err != nil {
+		logger.Warn("cannot read %s: %v", filePath, err)
+		return skip(SkipReadError)
+	}
+
+	// Skip binary files, unless asked to dump them; dumps bypass the text
+	// transformations and content filters below
+	if binary {
+		return processBinary(file, info, logger, config, processor)
+	}
+
+	output, meta := processContent(filePath, content, logger, config, processor)
+	meta.read = content
+	return output, meta
+}
+
+// unreadableReason returns the reason a file is skipped before its content is
+// read: it isn't a regular file, the filters reject it, or it exceeds the size
+// limit. It returns an empty string for files to read. (internal helper)
+func unreadableReason(file discoveredFile, logger *Logger, config *Config) SkipReason {
+	// Directories cannot be read as files
+	if file.info.IsDir() {
+		logger.Verbose("skipping directory: %s", file.path)
+		return SkipFiltered
+	}
+
+	// Pipes, sockets, and devices can block forever when read, so skip them
+	// before any filter looks at their content
+	if !file.info.Mode().IsRegular() {
+		logger.Verbose("skipping special file (%s): %s", file.info.Mode().Type(), file.path)
+		return SkipSpecial
+	}
+
+	// Apply gitignore and extension/name filters
+	if reason := file.filterReason(config, logger); reason != "" {
+		return reason
+	}
+
+	// Skip files that exceed the size limit before reading any content
+	if config.MaxFileSize > 0 && file.info.Size() > config.MaxFileSize {
+		logger.Verbose("skipping large file (%d bytes exceeds limit of %d): %s", file.info.Size(), config.MaxFileSize, file.path)
+		return SkipTooLarge
+	}
+	return ""
+}
+
+// statFile looks up the info of a file discovery didn't describe, returning nil
+// info with the reason the file is skipped, or the error aborting the run,
+// when it can't be read (internal helper)
+func statFile(filePath string, logger *Logger, config *Config) (os.FileInfo, fileMeta) {
+	var info os.FileInfo
+	abort, err := config.attempt(filePath, func() (err error) {
+		info, err = os.Stat(filePath)
+		return err
+	})
+	if abort {
+		return nil, fileMeta{err: abortRun(err)}
+	}
+	if err != nil {
+		// Skip without warning if the file simply doesn't exist
+		if !os.IsNotExist(err) {
+			logger.Warn("stat %s: %v", filePath, err)
+		}
+		_, meta := skipFile(filePath, SkipReadError, config)
+		return nil, meta
+	}
+	return info, fileMeta{}
+}
+
+// processBinary passes a bounded dump of a binary file to the processor when
+// binary files are included, and skips it otherwise (internal helper)
+func processBinary(file discoveredFile, info os.FileInfo, logger *Logger, config *Config, processor ProcessorFunc) (string, fileMeta) {
+	filePath := file.path
+	if config.IncludeBinary == "" || !binaryAllowed(filePath, file.explicit, config) {
+		logger.Verbose("skipping binary file: %s", filePath)
+		return skipFile(filePath, SkipBinary, config)
+	}
+	var dump []byte
+	abort, err := config.attempt(filePath, func() (err error) {
+		dump, err = readBinaryDump(filePath, info.Size(), config.IncludeBinary, config.throttle)
+		return err
+	})
+	if abort {
+		return "", fileMeta{err: abortRun(err)}
+	}
+	if err != nil {
+		logger.Warn("cannot read %s: %v", filePath, err)
+		return skipFile(filePath, SkipReadError, config)
+	}
+	logger.Verbose("including binary file as %s: %s", config.IncludeBinary, filePath)
+	return processor(filePath, dump), fileMeta{encoding: EncodingUTF8, lineEndings: detectLineEndings(dump)}
+}
+
+// skipFile reports a skipped file to the OnFileSkipped hook and describes it
+// by the reason it was skipped (internal helper)
+func skipFile(filePath string, reason SkipReason, config *Config) (string, fileMeta) {
+	config.Hooks.fileSkipped(config.displayPath(filePath), reason)
+	return "", fileMeta{skipped: reason}
+}
+
+// processContent applies the text transformations and content filters to
+// content read from filePath and passes the result to the processor, for
+// processFileMeta and standard input (internal helper)
+func processContent(filePath string, content []byte, logger *Logger, config *Config, processor ProcessorFunc) (string, fileMeta) {
+	skip := func(reason SkipReason) (string, fileMeta) {
+		return skipFile(filePath, reason, config)
+	}
+
+	// Convert other encodings to UTF-8 when asked; UTF-16 is unreadable otherwise
+	meta := fileMeta{encoding: detectEncoding(content)}
+	if config.Transcode && meta.encoding != EncodingUTF8 {
+		content = transcode(content, meta.encoding)
+		meta.transcoded = true
+		logger.Verbose("transcoded %s from %s", filePath, meta.encoding)
+	} else if meta.encoding == EncodingUTF16LE || meta.encoding == EncodingUTF16BE {
+		logger.Verbose("skipping %s file (transcoding is disabled): %s", meta.encoding, filePath)
+		return skip(SkipBinary)
+	}
+
+	// Describe the file before transformations change its content
+	meta.lineEndings = detectLineEndings(content)
+
+	// Compose decomposed characters so filters match them like composed text
+	if config.NormalizeUnicode {
+		content = normalizeNFC(content)
+	}
+
+	// Remove or escape terminal control sequences, such as colors in captured logs
+	if config.SanitizeControl != "" {
+		content = sanitizeControl(content, config.SanitizeControl)
+	}
+
+	// Skip files the content filters reject, keeping only matches in grep mode
+	content, reason := filterContent(filePath, content, logger, config)
+	if reason != "" {
+		return skip(reason)
+	}
+
+	// Elide embedded data so the surrounding code stays readable
+	if config.CollapseBlobs {
+		content = collapseBlobs(content)
+	}
+
+	// Apply content transformers such as tab expansion
+	content = applyTransformers(filePath, content, config.transformers)
+
+	// Process the content
+	return processor(filePath, content), meta
+}
+
+// filterContent applies the line limit, content regular expression, and grep
+// filters to content, returning the reason a file is skipped or, in grep mode,
+// its matches rendered in place of the content (internal helper)
+func filterContent(filePath string, content []byte, logger *Logger, config *Config) ([]byte, SkipReason) {
+	// Skip pathological files, such as generated schemas, regardless of extension
+	if config.MaxFileLines > 0 {
+		if lines := countLines(content); lines > config.MaxFileLines {
+			logger.Verbose("skipping long file (%d lines exceeds limit of %d): %s", lines, config.MaxFileLines, filePath)
+			return nil, SkipTooLarge
+		}
+	}
+
+	// Skip files whose content doesn't match the content filter
+	if config.includeContent != nil && !config.includeContent.Match(content) {
+		logger.Verbose("skipping file (content does not match %s): %s", config.includeContent, filePath)
+		return nil, SkipFiltered
+	}
+
+	// In grep mode, keep only matching files and render their matches
+	if config.grep != nil {
+		grepped, matched := grepContent(content, config.grep, config.grepContext)
+		if !matched {
+			logger.Verbose("skipping file (no lines match %s): %s", config.grep, filePath)
+			return nil, SkipFiltered
+		}
+		content = []byte(grepped)
+	}
+	return content, ""
+}
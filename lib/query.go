@@ -0,0 +1,190 @@
+package handoff
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// BM25 parameters: bm25K1 controls how quickly repeated terms stop adding to a
+// score, and bm25B how strongly scores are normalized by document length
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// WithQuery ranks files by their lexical relevance to a query, scoring their
+// paths and contents with BM25, and keeps only the best matches: at most top
+// files when top is positive, and only as many as fit the token budget when
+// one is set. Kept files are output most relevant first; files that don't
+// match any query term, or don't make the cut, are reported with
+// SkipIrrelevant. An empty query disables ranking.
+func WithQuery(query string, top int) Option {
+	return func(c *Config) {
+		c.Query = query
+		c.QueryTop = top
+	}
+}
+
+// rankedFile is a candidate file with its relevance score (internal helper)
+type rankedFile struct {
+	file   discoveredFile
+	terms  map[string]int
+	length int
+	score  float64
+	tokens int
+}
+
+// rankFiles scores the files that pass the name and extension filters against
+// config.Query and keeps the most relevant, returning the kept files, most
+// relevant first, and the files left out. Files the filters reject are kept,
+// after the ranked ones, so they are reported as skipped for their own reason.
+// (internal helper)
+func rankFiles(files []discoveredFile, config *Config, logger *Logger) ([]discoveredFile, []discoveredFile) {
+	query := uniqueTerms(queryTerms(config.Query))
+	if len(query) == 0 {
+		return files, nil
+	}
+
+	quiet := NewLogger(false)
+	var candidates []*rankedFile
+	var rest []discoveredFile
+	totalLength := 0
+	for _, file := range files {
+		if file.info == nil || !file.info.Mode().IsRegular() || file.filterReason(config, quiet) != "" ||
+			(config.MaxFileSize > 0 && file.info.Size() > config.MaxFileSize) {
+			rest = append(rest, file)
+			continue
+		}
+		content, err := readFile(file.path, config.throttle)
+		if err != nil {
+			rest = append(rest, file)
+			continue
+		}
+		terms := queryTerms(file.path + "\n" + string(content))
+		counts := make(map[string]int)
+		for _, term := range terms {
+			counts[term]++
+		}
+		candidates = append(candidates, &rankedFile{
+			file:   file,
+			terms:  counts,
+			length: len(terms),
+			tokens: estimateTokenCount(string(content)),
+		})
+		totalLength += len(terms)
+	}
+	if len(candidates) == 0 {
+		return files, nil
+	}
+
+	// Score each candidate with BM25
+	averageLength := float64(totalLength) / float64(len(candidates))
+	for _, term := range query {
+		containing := 0
+		for _, candidate := range candidates {
+			if candidate.terms[term] > 0 {
+				containing++
+			}
+		}
+		if containing == 0 {
+			continue
+		}
+		idf := math.Log((float64(len(candidates))-float64(containing)+0.5)/(float64(containing)+0.5) + 1)
+		for _, candidate := range candidates {
+			frequency := float64(candidate.terms[term])
+			if frequency == 0 {
+				continue
+			}
+			norm := 1 - bm25B + bm25B*float64(candidate.length)/max(averageLength, 1)
+			candidate.score += idf * frequency * (bm25K1 + 1) / (frequency + bm25K1*norm)
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].score > candidates[j].score
+	})
+
+	// Keep the best matches that fit the limits, in rank order
+	var kept, omitted []discoveredFile
+	budget := config.MaxTokens
+	for _, candidate := range candidates {
+		switch {
+		case candidate.score == 0:
+			logger.Verbose("Left out %s (no query terms)", candidate.file.path)
+		case config.QueryTop > 0 && len(kept) >= config.QueryTop:
+			logger.Verbose("Left out %s (relevance %.2f, beyond the top %d)", candidate.file.path, candidate.score, config.QueryTop)
+		case config.MaxTokens > 0 && candidate.tokens > budget:
+			logger.Verbose("Left out %s (relevance %.2f, %d tokens over the remaining budget)", candidate.file.path, candidate.score, candidate.tokens)
+		default:
+			logger.Verbose("Ranked %s (relevance %.2f)", candidate.file.path, candidate.score)
+			kept = append(kept, candidate.file)
+			budget -= candidate.tokens
+			continue
+		}
+		omitted = append(omitted, candidate.file)
+	}
+	return append(kept, rest...), omitted
+}
+
+// queryTerms splits text into lowercase terms: each alphanumeric word, its
+// camelCase parts, and adjacent pairs of parts, so "reconnectWebSocket"
+// matches "reconnect", "websocket", and "socket" (internal helper)
+func queryTerms(text string) []string {
+	var terms []string
+	words := strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, word := range words {
+		parts := camelParts(word)
+		if len(word) > 1 {
+			terms = append(terms, strings.ToLower(word))
+		}
+		if len(parts) < 2 {
+			continue
+		}
+		for i, part := range parts {
+			if len(part) > 1 {
+				terms = append(terms, strings.ToLower(part))
+			}
+			if i > 0 && len(parts) > 2 {
+				terms = append(terms, strings.ToLower(parts[i-1]+part))
+			}
+		}
+	}
+	return terms
+}
+
+// camelParts splits a word before an upper-case letter that follows a
+// lower-case one ("webSocket") or that starts a word after an acronym
+// ("HTTPServer") (internal helper)
+func camelParts(word string) []string {
+	runes := []rune(word)
+	var parts []string
+	start := 0
+	for i := 1; i < len(runes); i++ {
+		if !unicode.IsUpper(runes[i]) {
+			continue
+		}
+		prev := runes[i-1]
+		if unicode.IsLower(prev) || unicode.IsDigit(prev) ||
+			(unicode.IsUpper(prev) && i+1 < len(runes) && unicode.IsLower(runes[i+1])) {
+			parts = append(parts, string(runes[start:i]))
+			start = i
+		}
+	}
+	return append(parts, string(runes[start:]))
+}
+
+// uniqueTerms removes repeated terms, keeping the first of each (internal helper)
+func uniqueTerms(terms []string) []string {
+	seen := make(map[string]bool, len(terms))
+	var unique []string
+	for _, term := range terms {
+		if !seen[term] {
+			seen[term] = true
+			unique = append(unique, term)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,89 @@
+package handoff
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestQueryTerms tests splitting text into terms for relevance ranking
+func TestQueryTerms(t *testing.T) {
+	testCases := map[string][]string{
+		"websocket reconnect": {"websocket", "reconnect"},
+		"reconnectWebSocket":  {"reconnectwebsocket", "reconnect", "web", "reconnectweb", "socket", "websocket"},
+		"HTTPServer":          {"httpserver", "http", "server"},
+		"max_retry, a":        {"max", "retry"},
+	}
+
+	for text, want := range testCases {
+		if got := queryTerms(text); !reflect.DeepEqual(got, want) {
+			t.Errorf("queryTerms(%q) = %q, want %q", text, got, want)
+		}
+	}
+}
+
+// TestQuery tests keeping the files most relevant to a query, most relevant first
+func TestQuery(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"socket.go": "package net\n\n// reconnectWebSocket retries the websocket connection\nfunc reconnectWebSocket() {}\n\n// reconnect again\n",
+		"client.go": "package net\n\n// dial opens a websocket\nfunc dial() {}\n",
+		"math.go":   "package util\n\nfunc add(a, b int) int { return a + b }\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	rank := func(top int) Stats {
+		config := NewConfig(WithGitClient(NewMockGitClient(false)), WithQuery("websocket reconnect logic", top))
+		_, stats, err := ProcessProject([]string{dir}, config)
+		if err != nil {
+			t.Fatalf("ProcessProject failed: %v", err)
+		}
+		return stats
+	}
+
+	stats := rank(0)
+	want := []string{filepath.Join(dir, "socket.go"), filepath.Join(dir, "client.go")}
+	if !reflect.DeepEqual(stats.IncludedFiles, want) {
+		t.Errorf("IncludedFiles = %v, want %v", stats.IncludedFiles, want)
+	}
+	if stats.Skipped[SkipIrrelevant] != 1 {
+		t.Errorf("Skipped = %v, want math.go irrelevant", stats.Skipped)
+	}
+
+	stats = rank(1)
+	if !reflect.DeepEqual(stats.IncludedFiles, want[:1]) {
+		t.Errorf("IncludedFiles with top 1 = %v, want %v", stats.IncludedFiles, want[:1])
+	}
+	if stats.Skipped[SkipIrrelevant] != 2 {
+		t.Errorf("Skipped with top 1 = %v, want 2 irrelevant", stats.Skipped)
+	}
+}
+
+// TestQueryTokenBudget tests that a query keeps only the matches that fit the budget
+func TestQueryTokenBudget(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"a.go": "// websocket websocket websocket\n" + strings.Repeat("x := 1\n", 200),
+		"b.go": "// websocket\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	config := NewConfig(WithGitClient(NewMockGitClient(false)), WithQuery("websocket", 0), WithMaxTokens(100))
+	_, stats, err := ProcessProject([]string{dir}, config)
+	if err != nil {
+		t.Fatalf("ProcessProject failed: %v", err)
+	}
+	if want := []string{filepath.Join(dir, "b.go")}; !reflect.DeepEqual(stats.IncludedFiles, want) {
+		t.Errorf("IncludedFiles = %v, want %v", stats.IncludedFiles, want)
+	}
+}
@@ -0,0 +1,107 @@
+package handoff
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// DefaultMaxFileSize is the default maximum size in bytes of a file that will be read.
+// Larger files are skipped before any content is loaded into memory.
+const DefaultMaxFileSize int64 = 10 << 20 // 10 MiB
+
+// WithMaxFileSize sets the maximum size in bytes of files to process.
+// Files larger than this are skipped without being read. A value of zero
+// or less disables the limit.
+func WithMaxFileSize(maxFileSize int64) Option {
+	return func(c *Config) {
+		c.MaxFileSize = maxFileSize
+	}
+}
+
+// WithMaxFileLines sets the maximum number of lines in files to process.
+// Files with more lines, such as giant generated schemas, are skipped entirely
+// even when their extension is included. A value of zero or less disables the
+// limit.
+func WithMaxFileLines(maxLines int) Option {
+	return func(c *Config) {
+		c.MaxFileLines = maxLines
+	}
+}
+
+// countLines returns the number of lines in content, counting a final line
+// without a trailing newline (internal helper)
+func countLines(content []byte) int {
+	lines := bytes.Count(content, []byte("\n"))
+	if len(content) > 0 && content[len(content)-1] != '\n' {
+		lines++
+	}
+	return lines
+}
+
+// readFileContent reads a file in two stages to avoid loading large binary files
+// into memory (internal helper). It first reads a sample of up to binarySampleSize
+// bytes and rejects the file if the sample looks binary, unless it starts with
+// a UTF-16 byte order mark; only then is the remainder
+// streamed into a buffer sized from the file info.
+//
+// Parameters:
+//   - filePath: The path to the file to read
+//   - size: The expected file size, used to pre-size the buffer
+//   - maxSize: The maximum number of bytes to read, or zero or less for no limit
+//   - throttle: The pace of reads set with WithIOThrottle, or nil for full speed
+//
+// Returns:
+//   - content: The full file content, or nil if the file is binary
+//   - binary: True if the file was detected as binary
+//   - err: Any error encountered opening or reading the file
+func readFileContent(filePath string, size, maxSize int64, throttle *ioThrottle) (content []byte, binary bool, err error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return nil, false, err
+	}
+	defer func() {
+		if closeErr := f.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}()
+	src := throttle.reader(f)
+
+	// Read the initial sample used for binary detection
+	sample := make([]byte, binarySampleSize)
+	n, err := io.ReadFull(src, sample)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return nil, false, fmt.Errorf("failed to read sample: %w", err)
+	}
+	sample = sample[:n]
+	// UTF-16 text is full of zero bytes; recognize it by its byte order mark
+	// so it can be transcoded instead of rejected
+	utf16Text := hasUTF16BOM(sample)
+	if !utf16Text && isBinaryFile(sample) {
+		return nil, true, nil
+	}
+
+	// Stream the remainder, never reading beyond maxSize
+	reader := src
+	if maxSize > 0 {
+		reader = io.LimitReader(src, maxSize-int64(n))
+	}
+	var buf bytes.Buffer
+	if size > int64(n) && (maxSize <= 0 || size <= maxSize) {
+		buf.Grow(int(size))
+	}
+	buf.Write(sample)
+	if _, err := io.Copy(&buf, reader); err != nil {
+		return nil, false, fmt.Errorf("failed to read content: %w", err)
+	}
+
+	content = buf.Bytes()
+	// The sample check only covers the start of the file; re-check the whole
+	// content so binary data later in the file is still detected
+	if !utf16Text && isBinaryFile(content) {
+		return nil, true, nil
+	}
+	return content, false, nil
+}
@@ -0,0 +1,185 @@
+package handoff
+
+import (
+	"bytes"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// TestReadFileContent tests sample-first reading of text and binary files
+func TestReadFileContent(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "handoff-reader-test-")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer func() {
+		if cleanErr := os.RemoveAll(tmpDir); cleanErr != nil {
+			t.Logf("Failed to clean up temp directory: %v", cleanErr)
+		}
+	}()
+
+	largeText := strings.Repeat("line of text\n", 1000)
+	lateBinary := append([]byte(strings.Repeat("a", binarySampleSize*2)), 0x00)
+
+	testCases := []struct {
+		name       string
+		content    []byte
+		maxSize    int64
+		wantBinary bool
+		want       []byte
+	}{
+		{
+			name:    "Small text file",
+			content: []byte("hello\nworld\n"),
+			want:    []byte("hello\nworld\n"),
+		},
+		{
+			name:    "Empty file",
+			content: []byte{},
+			want:    []byte{},
+		},
+		{
+			name:    "Text larger than sample",
+			content: []byte(largeText),
+			want:    []byte(largeText),
+		},
+		{
+			name:       "Binary in sample",
+			content:    []byte{0x00, 0x01, 0x02, 0x03},
+			wantBinary: true,
+		},
+		{
+			name:       "Null byte after sample",
+			content:    lateBinary,
+			wantBinary: true,
+		},
+		{
+			name:    "Reading stops at max size",
+			content: []byte(largeText),
+			maxSize: 1000,
+			want:    []byte(largeText[:1000]),
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(tmpDir, "file"+string(rune('a'+i)))
+			if err := os.WriteFile(path, tc.content, 0644); err != nil {
+				t.Fatalf("Failed to write test file: %v", err)
+			}
+
+			content, binary, err := readFileContent(path, int64(len(tc.content)), tc.maxSize, nil)
+			if err != nil {
+				t.Fatalf("readFileContent() error = %v", err)
+			}
+			if binary != tc.wantBinary {
+				t.Errorf("readFileContent() binary = %v, want %v", binary, tc.wantBinary)
+			}
+			if !tc.wantBinary && !bytes.Equal(content, tc.want) {
+				t.Errorf("readFileContent() content length = %d, want %d", len(content), len(tc.want))
+			}
+		})
+	}
+
+	if _, _, err := readFileContent(filepath.Join(tmpDir, "missing"), 0, 0, nil); err == nil {
+		t.Error("readFileContent() for missing file should return an error")
+	}
+}
+
+// TestProcessFileMaxFileSize tests that files over the size limit are skipped
+func TestProcessFileMaxFileSize(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "handoff-maxsize-test-")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer func() {
+		if cleanErr := os.RemoveAll(tmpDir); cleanErr != nil {
+			t.Logf("Failed to clean up temp directory: %v", cleanErr)
+		}
+	}()
+
+	filePath := filepath.Join(tmpDir, "big.txt")
+	if err := os.WriteFile(filePath, []byte(strings.Repeat("x", 100)), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	processor := func(file string, content []byte) string { return string(content) }
+	logger := NewLogger(false)
+
+	limited := NewConfig(WithGitClient(NewMockGitClient(false)), WithMaxFileSize(50))
+	if result := processFile(filePath, nil, logger, limited, processor); result != "" {
+		t.Errorf("processFile() for oversized file returned %d bytes, want empty string", len(result))
+	}
+
+	unlimited := NewConfig(WithGitClient(NewMockGitClient(false)), WithMaxFileSize(0))
+	if result := processFile(filePath, nil, logger, unlimited, processor); len(result) != 100 {
+		t.Errorf("processFile() with no limit returned %d bytes, want 100", len(result))
+	}
+}
+
+// TestProcessFileMaxFileLines tests that files over the line limit are skipped
+// even when their extension is explicitly included
+func TestProcessFileMaxFileLines(t *testing.T) {
+	tmpDir := t.TempDir()
+	filePath := filepath.Join(tmpDir, "schema.json")
+	if err := os.WriteFile(filePath, []byte(strings.Repeat("{}\n", 10)), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	processor := func(file string, content []byte) string { return string(content) }
+	logger := NewLogger(false)
+
+	limited := NewConfig(WithGitClient(NewMockGitClient(false)), WithInclude(".json"), WithMaxFileLines(9))
+	if result := processFile(filePath, nil, logger, limited, processor); result != "" {
+		t.Errorf("processFile() for a file over the line limit returned %q, want empty string", result)
+	}
+
+	atLimit := NewConfig(WithGitClient(NewMockGitClient(false)), WithInclude(".json"), WithMaxFileLines(10))
+	if result := processFile(filePath, nil, logger, atLimit, processor); result == "" {
+		t.Error("processFile() for a file at the line limit returned nothing")
+	}
+}
+
+// TestCountLines tests counting lines with and without a trailing newline
+func TestCountLines(t *testing.T) {
+	for content, want := range map[string]int{"": 0, "a": 1, "a\n": 1, "a\nb": 2, "a\nb\n": 2, "\n\n": 2} {
+		if got := countLines([]byte(content)); got != want {
+			t.Errorf("countLines(%q) = %d, want %d", content, got, want)
+		}
+	}
+}
+
+// TestSpecialFiles tests that sockets and other special files are skipped
+// rather than read
+func TestSpecialFiles(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Unix domain sockets don't appear as special files on Windows")
+	}
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	listener, err := net.Listen("unix", filepath.Join(dir, "agent.sock"))
+	if err != nil {
+		t.Skipf("Cannot create a Unix domain socket: %v", err)
+	}
+	defer func() { _ = listener.Close() }()
+
+	// Found by walking the directory or given explicitly
+	for _, path := range []string{dir, filepath.Join(dir, "agent.sock")} {
+		var skipped []SkipReason
+		config := NewConfig(
+			WithGitClient(NewMockGitClient(false)),
+			WithHooks(Hooks{OnFileSkipped: func(path string, reason SkipReason) { skipped = append(skipped, reason) }}),
+		)
+		_, _, _ = ProcessProject([]string{path}, config)
+		if !reflect.DeepEqual(skipped, []SkipReason{SkipSpecial}) {
+			t.Errorf("skipped %v for %s, want [%s]", skipped, path, SkipSpecial)
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package handoff
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// WithRoot resolves relative path arguments against dir instead of the
+// process working directory, and shows the paths of files under dir relative
+// to it in the output, Stats, and hooks. This lets a server or editor plugin
+// process a project without changing directory, which would affect every
+// goroutine in the process. An empty dir uses the working directory.
+func WithRoot(dir string) Option {
+	return func(c *Config) {
+		c.Root = dir
+	}
+}
+
+// WithRootLabel labels the files under the directory path, so output
+// combining several unrelated projects, such as a frontend and a backend
+// repository, shows which project each file belongs to. The label fills the {root} placeholder and the "root" field of JSON Lines
+// output. When labeled directories are nested, the innermost label applies.
+func WithRootLabel(path, label string) Option {
+	return func(c *Config) {
+		c.rootLabels = append(c.rootLabels, rootLabel{path: path, label: label})
+	}
+}
+
+// rootLabel is a directory labeled with WithRootLabel
+type rootLabel struct {
+	path  string
+	label string
+}
+
+// FindConfigFile returns the path of the first of ConfigFileNames found in
+// root, or in the working directory when root is empty, and whether it exists.
+// When none exists, the path is that of DefaultConfigFileName.
+func FindConfigFile(root string) (string, bool, error) {
+	for _, name := range ConfigFileNames {
+		path := filepath.Join(root, name)
+		_, err := os.Stat(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		return path, err == nil, err
+	}
+	return filepath.Join(root, DefaultConfigFileName), false, nil
+}
+
+// WatchPaths returns the files and directories ProcessProject reads for
+// paths, resolved against the root: a glob pattern is replaced by the
+// directory it is matched in, and standard input is left out. A change under
+// one of these paths may change the output.
+func (c *Config) WatchPaths(paths []string) []string {
+	watched := make([]string, 0, len(paths))
+	for _, path := range c.resolvePaths(paths) {
+		if path == StdinPath {
+			continue
+		}
+		if _, err := os.Stat(path); err != nil && isGlob(path) {
+			path, _ = splitGlob(path)
+		}
+		watched = append(watched, path)
+	}
+	return watched
+}
+
+// WatchFiles returns the files on disk whose changes may change the output of
+// ProcessProject for paths, so a watcher can poll them without walking ignored
+// trees such as node_modules or .git. Directories and glob patterns are
+// expanded the way discovery expands directories, leaving out gitignored and
+// hidden files, and files the extension and name filters reject are left out;
+// filters that need git or file content are not applied. Files named directly
+// are returned even when missing, so their appearing counts as a change. Paths
+// are resolved against the root, and standard input is left out.
+func (c *Config) WatchFiles(paths []string) ([]string, error) {
+	config := c.Clone()
+	config.ProcessConfig()
+
+	var files []string
+	for _, path := range config.WatchPaths(paths) {
+		if info, err := os.Stat(path); err != nil || !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+		found, err := getFilesFromDir(path, config)
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range found {
+			if shouldProcess(file.path, config) {
+				files = append(files, file.path)
+			}
+		}
+	}
+	return files, nil
+}
+
+// resolvePaths joins relative paths onto the root, leaving absolute paths
+// as they are (internal helper)
+func (c *Config) resolvePaths(paths []string) []string {
+	if c.Root == "" {
+		return paths
+	}
+	resolved := make([]string, len(paths))
+	for i, path := range paths {
+		resolved[i] = c.resolvePath(path)
+	}
+	return resolved
+}
+
+// resolvePath joins a relative path onto the root (internal helper)
+func (c *Config) resolvePath(path string) string {
+	if c.Root == "" || path == StdinPath || filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(c.Root, path)
+}
+
+// rootLabelFor returns the label of the innermost labeled directory holding
+// path, or an empty string if none holds it (internal helper)
+func (c *Config) rootLabelFor(path string) string {
+	if len(c.rootLabels) == 0 || path == StdinPath {
+		return ""
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return ""
+	}
+	label, depth := "", -1
+	for _, root := range c.rootLabels {
+		dir, err := filepath.Abs(c.resolvePath(root.path))
+		if err != nil || !withinDir(dir, abs) {
+			continue
+		}
+		if d := strings.Count(dir, string(filepath.Separator)); d > depth {
+			label, depth = root.label, d
+		}
+	}
+	return label
+}
+
+// withinDir reports whether path is dir or lies under it (internal helper)
+func withinDir(dir, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// displayPath returns path as shown in the output, Stats, and hooks: relative
+// to the root when it lies under it, composed to NFC, and escaped with
+// WithEscapePaths; standard input is shown by its name (internal helper)
+func (c *Config) displayPath(path string) string {
+	if path == StdinPath {
+		return cleanPath(c.stdinName(), c.EscapePaths)
+	}
+	return cleanPath(c.rootRelative(path), c.EscapePaths)
+}
+
+// rootRelative returns path relative to the root when it lies under it, and
+// unchanged otherwise, still naming the file on disk (internal helper)
+func (c *Config) rootRelative(path string) string {
+	if c.Root == "" || !withinDir(c.Root, path) {
+		return path
+	}
+	rel, _ := filepath.Rel(c.Root, path)
+	return rel
+}
@@ -0,0 +1,169 @@
+package handoff
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestWithRoot tests resolving and displaying paths against an explicit root
+func TestWithRoot(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "src"), 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	for name, content := range map[string]string{
+		"main.go":        "package main\n",
+		"src/util.go":    "package src\n",
+		"src/notes.json": "{}\n",
+	} {
+		if err := os.WriteFile(filepath.Join(root, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write file: %v", err)
+		}
+	}
+	outside := filepath.Join(t.TempDir(), "outside.go")
+	if err := os.WriteFile(outside, []byte("package outside\n"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	var started []string
+	config := NewConfig(
+		WithGitClient(NewMockGitClient(false)),
+		WithRoot(root),
+		WithInclude(".go"),
+		WithHooks(Hooks{OnFileStart: func(path string) { started = append(started, path) }}),
+	)
+	content, stats, err := ProcessProject([]string{"main.go", "src", outside}, config)
+	if err != nil {
+		t.Fatalf("ProcessProject failed: %v", err)
+	}
+
+	// Files under the root are shown relative to it; others as given
+	want := []string{"main.go", filepath.Join("src", "util.go"), outside}
+	if !reflect.DeepEqual(stats.IncludedFiles, want) {
+		t.Errorf("IncludedFiles = %v, want %v", stats.IncludedFiles, want)
+	}
+	wantSkipped := []SkippedFile{{Path: filepath.Join("src", "notes.json"), Reason: SkipFiltered}}
+	if !reflect.DeepEqual(stats.SkippedFiles, wantSkipped) {
+		t.Errorf("SkippedFiles = %v, want %v", stats.SkippedFiles, wantSkipped)
+	}
+	if !reflect.DeepEqual(started, []string{"main.go", filepath.Join("src", "notes.json"), filepath.Join("src", "util.go"), outside}) {
+		t.Errorf("OnFileStart paths = %v", started)
+	}
+	if strings.Contains(content, root) {
+		t.Errorf("content shows the root:\n%s", content)
+	}
+	if !strings.Contains(content, "<main.go>\n") {
+		t.Errorf("content is missing main.go:\n%s", content)
+	}
+
+	// DiscoverFiles resolves and shows paths the same way
+	files, err := DiscoverFiles([]string{"."}, config)
+	if err != nil {
+		t.Fatalf("DiscoverFiles failed: %v", err)
+	}
+	if !reflect.DeepEqual(files, want[:2]) {
+		t.Errorf("DiscoverFiles = %v, want %v", files, want[:2])
+	}
+}
+
+// TestFindConfigFile tests finding the default config file in a root
+func TestFindConfigFile(t *testing.T) {
+	root := t.TempDir()
+	path, exists, err := FindConfigFile(root)
+	if err != nil || exists {
+		t.Errorf("FindConfigFile() = %q, %v, %v; want missing", path, exists, err)
+	}
+
+	// A JSON config file is found when there is no YAML one
+	want := filepath.Join(root, ".handoff.json")
+	if err := os.WriteFile(want, []byte("{}"), 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+	path, exists, err = FindConfigFile(root)
+	if err != nil || !exists || path != want {
+		t.Errorf("FindConfigFile() = %q, %v, %v; want %q", path, exists, err, want)
+	}
+
+	want = filepath.Join(root, DefaultConfigFileName)
+	if err := os.WriteFile(want, []byte("{}"), 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+	path, exists, err = FindConfigFile(root)
+	if err != nil || !exists || path != want {
+		t.Errorf("FindConfigFile() = %q, %v, %v; want %q", path, exists, err, want)
+	}
+}
+
+// TestWithRootLabel tests labeling the files of several projects
+func TestWithRootLabel(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"web/app.ts", "api/main.go", "api/vendor/lib.go", "notes.md"} {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte("x\n"), 0644); err != nil {
+			t.Fatalf("Failed to write file: %v", err)
+		}
+	}
+
+	config := NewConfig(
+		WithGitClient(NewMockGitClient(false)),
+		WithRoot(dir),
+		WithRootLabel("web", "frontend"),
+		WithRootLabel("api", "backend"),
+		WithRootLabel(filepath.Join("api", "vendor"), "vendored"),
+		WithFormat("[{root}] {path}\n"),
+	)
+	content, _, err := ProcessProject([]string{"."}, config)
+	if err != nil {
+		t.Fatalf("ProcessProject failed: %v", err)
+	}
+	for _, want := range []string{
+		"[frontend] " + filepath.Join("web", "app.ts"),
+		"[backend] " + filepath.Join("api", "main.go"),
+		"[vendored] " + filepath.Join("api", "vendor", "lib.go"),
+		"[] notes.md",
+	} {
+		if !strings.Contains(content, want+"\n") {
+			t.Errorf("content is missing %q:\n%s", want, content)
+		}
+	}
+
+	// JSON Lines output carries the label as a field
+	config = NewConfig(
+		WithGitClient(NewMockGitClient(false)),
+		WithRootLabel(filepath.Join(dir, "web"), "frontend"),
+		WithFormatter(NewJSONLFormatter()),
+	)
+	content, _, err = ProcessProject([]string{filepath.Join(dir, "web")}, config)
+	if err != nil {
+		t.Fatalf("ProcessProject failed: %v", err)
+	}
+	if !strings.Contains(content, `{"root":"frontend","path":`) {
+		t.Errorf("JSON Lines output is missing the root label:\n%s", content)
+	}
+}
+
+// TestWatchPaths tests resolving the paths to watch for changes
+func TestWatchPaths(t *testing.T) {
+	root := filepath.Join("projects", "app")
+	config := NewConfig(WithRoot(root))
+	got := config.WatchPaths([]string{"src", StdinPath, "lib/**/*.go", "*.md", "/abs"})
+	want := []string{
+		filepath.Join(root, "src"),
+		filepath.Join(root, "lib"),
+		root,
+		"/abs",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("WatchPaths = %v, want %v", got, want)
+	}
+
+	if got := NewConfig().WatchPaths([]string{"src"}); !reflect.DeepEqual(got, []string{"src"}) {
+		t.Errorf("WatchPaths without root = %v, want [src]", got)
+	}
+}
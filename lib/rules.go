@@ -0,0 +1,75 @@
+package handoff
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// PathRule overrides the global include/exclude extension filters for files
+// under a particular directory. For example, a rule for "docs" with no Include
+// processes every file under docs/, while a rule for "src" with Include ".go"
+// restricts src/ to Go files.
+type PathRule struct {
+	// Path is the slash-separated directory the rule applies to (e.g., "docs" or "cmd/server").
+	// It matches wherever those directory names appear consecutively in a file's path.
+	Path string `json:"path" yaml:"path"`
+
+	// Include lists the extensions to include under Path; empty includes all extensions
+	Include []string `json:"include,omitempty" yaml:"include,omitempty"`
+
+	// Exclude lists the extensions to exclude under Path
+	Exclude []string `json:"exclude,omitempty" yaml:"exclude,omitempty"`
+}
+
+// WithPathRules sets path-scoped filter overrides.
+// For a file under one or more rule paths, the most specific (longest) rule's
+// Include and Exclude replace the global include/exclude extension filters.
+// Exclude-names filters still apply everywhere.
+func WithPathRules(rules []PathRule) Option {
+	return func(c *Config) {
+		c.pathRules = normalizePathRules(rules)
+	}
+}
+
+// normalizePathRules cleans rule paths and normalizes extensions to the same
+// dotted form used by the global filters (internal helper)
+func normalizePathRules(rules []PathRule) []PathRule {
+	if len(rules) == 0 {
+		return nil
+	}
+
+	normalized := make([]PathRule, 0, len(rules))
+	for _, rule := range rules {
+		path := strings.Trim(filepath.ToSlash(filepath.Clean(filepath.FromSlash(rule.Path))), "/")
+		if path == "" || path == "." {
+			continue
+		}
+		normalized = append(normalized, PathRule{
+			Path:    path,
+			Include: processExtensions(strings.Join(rule.Include, ",")),
+			Exclude: processExtensions(strings.Join(rule.Exclude, ",")),
+		})
+	}
+	return normalized
+}
+
+// matchPathRule returns the most specific rule whose path contains the file,
+// or nil if no rule applies (internal helper)
+func matchPathRule(file string, rules []PathRule) *PathRule {
+	if len(rules) == 0 {
+		return nil
+	}
+
+	dir := "/" + strings.Trim(filepath.ToSlash(filepath.Dir(filepath.Clean(file))), "/") + "/"
+
+	var best *PathRule
+	for i := range rules {
+		if !strings.Contains(dir, "/"+rules[i].Path+"/") {
+			continue
+		}
+		if best == nil || len(rules[i].Path) > len(best.Path) {
+			best = &rules[i]
+		}
+	}
+	return best
+}
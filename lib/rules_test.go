@@ -0,0 +1,59 @@
+package handoff
+
+import (
+	"testing"
+)
+
+// TestPathRules tests that path-scoped rules override the global extension filters
+func TestPathRules(t *testing.T) {
+	config := NewConfig(
+		WithInclude(".go,.md"),
+		WithPathRules([]PathRule{
+			{Path: "docs/"},
+			{Path: "src", Include: []string{"go"}},
+			{Path: "src/generated", Exclude: []string{".go"}},
+		}),
+	)
+
+	testCases := []struct {
+		file string
+		want bool
+	}{
+		{file: "project/docs/diagram.svg", want: true},
+		{file: "project/docs/guide.md", want: true},
+		{file: "project/src/main.go", want: true},
+		{file: "project/src/README.md", want: false},
+		{file: "project/src/generated/types.go", want: false},
+		{file: "project/src/generated/schema.json", want: true},
+		{file: "project/README.md", want: true},
+		{file: "project/notes.txt", want: false},
+		{file: "project/mydocs/file.txt", want: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.file, func(t *testing.T) {
+			if got := shouldProcess(tc.file, config); got != tc.want {
+				t.Errorf("shouldProcess(%q) = %v, want %v", tc.file, got, tc.want)
+			}
+		})
+	}
+}
+
+// TestNormalizePathRules tests cleaning of rule paths and extensions
+func TestNormalizePathRules(t *testing.T) {
+	rules := normalizePathRules([]PathRule{
+		{Path: "./src/", Include: []string{"go", " .md "}},
+		{Path: "", Include: []string{".txt"}},
+		{Path: "/", Include: []string{".txt"}},
+	})
+
+	if len(rules) != 1 {
+		t.Fatalf("normalizePathRules() returned %d rules, want 1: %v", len(rules), rules)
+	}
+	if rules[0].Path != "src" {
+		t.Errorf("rule path = %q, want %q", rules[0].Path, "src")
+	}
+	if !equalSlices(rules[0].Include, []string{".go", ".md"}) {
+		t.Errorf("rule include = %v, want [.go .md]", rules[0].Include)
+	}
+}
@@ -0,0 +1,172 @@
+package handoff
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SampleMode selects which files are kept when a directory holds more files
+// than its cap
+type SampleMode string
+
+const (
+	// SampleFirst keeps the first files in discovery order (the default)
+	SampleFirst SampleMode = "first"
+
+	// SampleRandom keeps a random selection of files
+	SampleRandom SampleMode = "random"
+
+	// SampleNewest keeps the most recently changed files, by last commit date
+	// when git is available and modification time otherwise
+	SampleNewest SampleMode = "newest"
+)
+
+// ParseSampleMode converts a name such as "newest" into a SampleMode.
+func ParseSampleMode(name string) (SampleMode, error) {
+	switch mode := SampleMode(strings.ToLower(name)); mode {
+	case SampleFirst, SampleRandom, SampleNewest:
+		return mode, nil
+	}
+	return "", fmt.Errorf("unknown sample mode %q (want first, random, or newest)", name)
+}
+
+// WithDirectoryCap limits the files processed from any one directory to n,
+// choosing which to keep by mode, so enormous flat directories such as
+// migrations/ or locales/ convey their structure without every file. Files
+// left out are reported with SkipSampled and summarized in an omitted-files
+// section. A value of zero or less disables the cap.
+func WithDirectoryCap(n int, mode SampleMode) Option {
+	return func(c *Config) {
+		c.DirectoryCap = n
+		c.DirectorySample = mode
+	}
+}
+
+// WithSample limits the files processed across all paths to a random
+// selection of n, giving a representative taste of a codebase too large to
+// hand off whole. Files left out are reported with SkipSampled and summarized
+// in an omitted-files section. A value of zero or less disables sampling.
+func WithSample(n int) Option {
+	return func(c *Config) {
+		c.Sample = n
+	}
+}
+
+// WithSampleSeed sets the seed for random sampling, by WithSample or a
+// directory cap with SampleRandom, so a sample can be reproduced. Zero picks a
+// seed at random, which the omitted-files section reports.
+func WithSampleSeed(seed uint64) Option {
+	return func(c *Config) {
+		c.SampleSeed = seed
+	}
+}
+
+// sampleFiles applies the directory cap and then the overall sample to the
+// files that pass the name and extension filters, returning the kept files
+// in discovery order along with the files left out and a note describing each
+// cut. Files the filters reject are kept so they are reported as skipped for
+// their own reason. (internal helper)
+func sampleFiles(files []discoveredFile, config *Config) ([]discoveredFile, []discoveredFile, []string) {
+	if config.DirectoryCap <= 0 && config.Sample <= 0 {
+		return files, nil, nil
+	}
+
+	// Group the eligible files by directory, quietly, since processing
+	// reports filtered files later
+	quiet := NewLogger(false)
+	var dirs []string
+	eligible := make(map[string][]int)
+	for i, file := range files {
+		if file.info == nil || file.info.IsDir() || file.filterReason(config, quiet) != "" {
+			continue
+		}
+		dir := filepath.Dir(file.path)
+		if _, ok := eligible[dir]; !ok {
+			dirs = append(dirs, dir)
+		}
+		eligible[dir] = append(eligible[dir], i)
+	}
+
+	seed := config.SampleSeed
+	if seed == 0 {
+		seed = rand.Uint64()
+	}
+	rng := rand.New(rand.NewPCG(seed, 0))
+
+	omit := make(map[int]bool)
+	var notes []string
+	var remaining []int
+	for _, dir := range dirs {
+		indexes := eligible[dir]
+		if config.DirectoryCap <= 0 || len(indexes) <= config.DirectoryCap {
+			remaining = append(remaining, indexes...)
+			continue
+		}
+		kept := chooseSample(files, indexes, config.DirectoryCap, config.DirectorySample, rng, config)
+		omitIndexes(omit, indexes, kept)
+		remaining = append(remaining, kept...)
+
+		var how string
+		switch config.DirectorySample {
+		case SampleRandom:
+			how = fmt.Sprintf("%d chosen at random (seed %d)", len(kept), seed)
+		case SampleNewest:
+			how = fmt.Sprintf("the newest %d", len(kept))
+		default:
+			how = fmt.Sprintf("the first %d", len(kept))
+		}
+		notes = append(notes, fmt.Sprintf("%s: %d of %d files omitted, showing %s", config.displayPath(dir), len(indexes)-len(kept), len(indexes), how))
+	}
+
+	if config.Sample > 0 && len(remaining) > config.Sample {
+		kept := chooseSample(files, remaining, config.Sample, SampleRandom, rng, config)
+		omitIndexes(omit, remaining, kept)
+		notes = append(notes, fmt.Sprintf("%d of %d files omitted, showing %d chosen at random across all paths (seed %d)",
+			len(remaining)-len(kept), len(remaining), len(kept), seed))
+	}
+
+	var kept, omitted []discoveredFile
+	for i, file := range files {
+		if omit[i] {
+			omitted = append(omitted, file)
+		} else {
+			kept = append(kept, file)
+		}
+	}
+	return kept, omitted, notes
+}
+
+// omitIndexes marks the indexes that were not kept as omitted (internal helper)
+func omitIndexes(omit map[int]bool, indexes, kept []int) {
+	keep := make(map[int]bool, len(kept))
+	for _, i := range kept {
+		keep[i] = true
+	}
+	for _, i := range indexes {
+		if !keep[i] {
+			omit[i] = true
+		}
+	}
+}
+
+// chooseSample picks n of the files at the given indexes by mode (internal helper)
+func chooseSample(files []discoveredFile, indexes []int, n int, mode SampleMode, rng *rand.Rand, config *Config) []int {
+	chosen := append([]int(nil), indexes...)
+	switch mode {
+	case SampleRandom:
+		rng.Shuffle(len(chosen), func(i, j int) { chosen[i], chosen[j] = chosen[j], chosen[i] })
+	case SampleNewest:
+		modified := make(map[int]time.Time, len(chosen))
+		for _, i := range chosen {
+			modified[i] = lastModified(files[i].path, config)
+		}
+		sort.SliceStable(chosen, func(a, b int) bool {
+			return modified[chosen[a]].After(modified[chosen[b]])
+		})
+	}
+	return chosen[:n]
+}
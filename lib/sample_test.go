@@ -0,0 +1,113 @@
+package handoff
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestDirectoryCap tests keeping at most a number of files per directory
+func TestDirectoryCap(t *testing.T) {
+	dir := t.TempDir()
+	migrations := filepath.Join(dir, "migrations")
+	if err := os.Mkdir(migrations, 0755); err != nil {
+		t.Fatalf("Failed to create migrations: %v", err)
+	}
+	base := time.Now().Add(-time.Hour)
+	for i := 1; i <= 5; i++ {
+		path := filepath.Join(migrations, fmt.Sprintf("%03d.sql", i))
+		if err := os.WriteFile(path, []byte(fmt.Sprintf("-- migration %d\n", i)), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", path, err)
+		}
+		modified := base.Add(time.Duration(i) * time.Minute)
+		if err := os.Chtimes(path, modified, modified); err != nil {
+			t.Fatalf("Failed to set times of %s: %v", path, err)
+		}
+	}
+	for _, name := range []string{"main.go", "notes.txt"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("content\n"), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	testCases := []struct {
+		mode SampleMode
+		want []string
+		note string
+	}{
+		{SampleFirst, []string{"001.sql", "002.sql"}, "showing the first 2"},
+		{SampleNewest, []string{"004.sql", "005.sql"}, "showing the newest 2"},
+		{SampleRandom, nil, "showing 2 chosen at random (seed 7)"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(string(tc.mode), func(t *testing.T) {
+			config := NewConfig(WithGitClient(NewMockGitClient(false)), WithExclude(".txt"), WithDirectoryCap(2, tc.mode), WithSampleSeed(7))
+			content, stats, err := ProcessProject([]string{dir}, config)
+			if err != nil {
+				t.Fatalf("ProcessProject failed: %v", err)
+			}
+
+			var sql []string
+			for _, path := range stats.IncludedFiles {
+				if filepath.Ext(path) == ".sql" {
+					sql = append(sql, filepath.Base(path))
+				}
+			}
+			if len(sql) != 2 || (tc.want != nil && strings.Join(sql, " ") != strings.Join(tc.want, " ")) {
+				t.Errorf("kept %v, want 2 files %v", sql, tc.want)
+			}
+			if stats.Skipped[SkipSampled] != 3 || stats.Skipped[SkipFiltered] != 1 {
+				t.Errorf("Skipped = %v, want 3 sampled out and 1 filtered", stats.Skipped)
+			}
+			note := "<omitted-files>\n" + migrations + ": 3 of 5 files omitted, " + tc.note + "\n</omitted-files>"
+			if !strings.Contains(content, note) {
+				t.Errorf("content is missing %q:\n%s", note, content)
+			}
+		})
+	}
+
+	if _, err := ParseSampleMode("oldest"); err == nil {
+		t.Error("ParseSampleMode(\"oldest\") succeeded, want error")
+	}
+}
+
+// TestSample tests choosing a reproducible random selection of files
+func TestSample(t *testing.T) {
+	dir := t.TempDir()
+	for i := 1; i <= 10; i++ {
+		sub := filepath.Join(dir, fmt.Sprintf("pkg%d", i%3))
+		if err := os.MkdirAll(sub, 0755); err != nil {
+			t.Fatalf("Failed to create %s: %v", sub, err)
+		}
+		path := filepath.Join(sub, fmt.Sprintf("file%d.go", i))
+		if err := os.WriteFile(path, []byte("package pkg\n"), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", path, err)
+		}
+	}
+
+	sample := func(seed uint64) ([]string, string) {
+		config := NewConfig(WithGitClient(NewMockGitClient(false)), WithSample(4), WithSampleSeed(seed))
+		content, stats, err := ProcessProject([]string{dir}, config)
+		if err != nil {
+			t.Fatalf("ProcessProject failed: %v", err)
+		}
+		if stats.FilesProcessed != 4 || stats.FilesTotal != 10 || stats.Skipped[SkipSampled] != 6 {
+			t.Errorf("FilesProcessed = %d, FilesTotal = %d, Skipped = %v, want 4, 10, and 6 sampled out",
+				stats.FilesProcessed, stats.FilesTotal, stats.Skipped)
+		}
+		return stats.IncludedFiles, content
+	}
+
+	first, content := sample(42)
+	note := "6 of 10 files omitted, showing 4 chosen at random across all paths (seed 42)"
+	if !strings.Contains(content, note) {
+		t.Errorf("content is missing %q:\n%s", note, content)
+	}
+	if again, _ := sample(42); strings.Join(again, " ") != strings.Join(first, " ") {
+		t.Errorf("seed 42 sampled %v, then %v; want the same files", first, again)
+	}
+}
@@ -0,0 +1,142 @@
+package handoff
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// ControlMode selects how ANSI escape sequences and other control characters
+// in file content are sanitized
+type ControlMode string
+
+const (
+	// ControlStrip removes ANSI escape sequences and control characters
+	ControlStrip ControlMode = "strip"
+
+	// ControlEscape replaces control characters with visible escapes such as
+	// \x1b, so sequences stay readable without affecting the terminal
+	ControlEscape ControlMode = "escape"
+)
+
+// ParseControlMode converts a name such as "strip" into a ControlMode.
+func ParseControlMode(name string) (ControlMode, error) {
+	switch mode := ControlMode(strings.ToLower(name)); mode {
+	case ControlStrip, ControlEscape:
+		return mode, nil
+	}
+	return "", fmt.Errorf("unknown control mode %q (want strip or escape)", name)
+}
+
+// WithSanitizeControl sets how ANSI escape sequences and stray control bytes,
+// common in captured logs, are sanitized before content filters run. Tabs,
+// newlines, and carriage returns are kept. An empty mode leaves content as is.
+func WithSanitizeControl(mode ControlMode) Option {
+	return func(c *Config) {
+		c.SanitizeControl = mode
+	}
+}
+
+// isControl reports whether r is a control character other than tab, newline,
+// or carriage return (internal helper)
+func isControl(r rune) bool {
+	return (r < 0x20 && r != '\t' && r != '\n' && r != '\r') || (r >= 0x7f && r <= 0x9f)
+}
+
+// sanitizeControl strips or escapes control characters in content, returning
+// content unchanged when it has none (internal helper)
+func sanitizeControl(content []byte, mode ControlMode) []byte {
+	if !hasControl(content) {
+		return content
+	}
+
+	var out bytes.Buffer
+	out.Grow(len(content))
+	for len(content) > 0 {
+		r, size := utf8.DecodeRune(content)
+		if r == utf8.RuneError || !isControl(r) {
+			out.Write(content[:size])
+			content = content[size:]
+			continue
+		}
+
+		switch mode {
+		case ControlEscape:
+			if r < 0x80 {
+				fmt.Fprintf(&out, `\x%02x`, r)
+			} else {
+				fmt.Fprintf(&out, `\u%04x`, r)
+			}
+		default:
+			// Drop whole escape sequences so their parameters don't leave debris
+			if r == 0x1b {
+				size = escapeSequenceLength(content)
+			}
+		}
+		content = content[size:]
+	}
+	return out.Bytes()
+}
+
+// hasControl reports whether content has any control character to sanitize (internal helper)
+func hasControl(content []byte) bool {
+	for len(content) > 0 {
+		r, size := utf8.DecodeRune(content)
+		if r != utf8.RuneError && isControl(r) {
+			return true
+		}
+		content = content[size:]
+	}
+	return false
+}
+
+// escapeSequenceLength returns the length in bytes of the escape sequence at
+// the start of b, which begins with ESC. CSI sequences such as colors run to
+// their final byte; OSC and other string sequences run to their terminator, or
+// to the end of the line when unterminated (internal helper).
+func escapeSequenceLength(b []byte) int {
+	if len(b) < 2 {
+		return len(b)
+	}
+
+	switch c := b[1]; {
+	case c == '[':
+		// Parameter and intermediate bytes, then a final byte
+		for i := 2; i < len(b); i++ {
+			if b[i] >= 0x40 && b[i] <= 0x7e {
+				return i + 1
+			}
+			if b[i] < 0x20 || b[i] > 0x3f {
+				return i
+			}
+		}
+		return len(b)
+	case c == ']' || c == 'P' || c == 'X' || c == '^' || c == '_':
+		// String terminated by BEL or ESC \
+		for i := 2; i < len(b); i++ {
+			switch {
+			case b[i] == 0x07:
+				return i + 1
+			case b[i] == 0x1b && i+1 < len(b) && b[i+1] == '\\':
+				return i + 2
+			case b[i] == '\n':
+				return i
+			}
+		}
+		return len(b)
+	case c >= 0x20 && c <= 0x2f:
+		// Intermediate bytes, such as character set selection, then a final byte
+		i := 2
+		for i < len(b) && b[i] >= 0x20 && b[i] <= 0x2f {
+			i++
+		}
+		if i < len(b) && b[i] >= 0x30 && b[i] <= 0x7e {
+			return i + 1
+		}
+		return i
+	case c >= 0x30 && c <= 0x7e:
+		return 2
+	}
+	return 1
+}
@@ -0,0 +1,72 @@
+package handoff
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// TestSanitizeControl tests stripping and escaping control sequences
+func TestSanitizeControl(t *testing.T) {
+	testCases := []struct {
+		name    string
+		content string
+		mode    ControlMode
+		want    string
+	}{
+		{"clean", "plain\ttext\r\n", ControlStrip, "plain\ttext\r\n"},
+		{"color", "\x1b[31merror\x1b[0m: failed\n", ControlStrip, "error: failed\n"},
+		{"private csi", "\x1b[?25lhidden cursor\x1b[?25h", ControlStrip, "hidden cursor"},
+		{"osc title", "\x1b]0;title\x07done", ControlStrip, "done"},
+		{"osc hyperlink", "\x1b]8;;https://example.com\x1b\\link\x1b]8;;\x1b\\", ControlStrip, "link"},
+		{"charset", "\x1b(Bok", ControlStrip, "ok"},
+		{"stray bytes", "a\x00b\x08c\x7fd", ControlStrip, "abcd"},
+		{"c1 control", "a\u009bb", ControlStrip, "ab"},
+		{"unterminated osc", "\x1b]0;title\nnext", ControlStrip, "\nnext"},
+		{"trailing esc", "end\x1b", ControlStrip, "end"},
+		{"invalid utf8", "bad\xff\x1b[1m", ControlStrip, "bad\xff"},
+		{"escape color", "\x1b[31merror\x1b[0m", ControlEscape, `\x1b[31merror\x1b[0m`},
+		{"escape bytes", "a\x00b\u009bc", ControlEscape, `a\x00b\u009bc`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := string(sanitizeControl([]byte(tc.content), tc.mode)); got != tc.want {
+				t.Errorf("sanitizeControl(%q, %s) = %q, want %q", tc.content, tc.mode, got, tc.want)
+			}
+		})
+	}
+}
+
+// TestParseControlMode tests parsing control mode names
+func TestParseControlMode(t *testing.T) {
+	if mode, err := ParseControlMode("Escape"); err != nil || mode != ControlEscape {
+		t.Errorf("ParseControlMode(Escape) = %q, %v; want escape", mode, err)
+	}
+	if _, err := ParseControlMode("remove"); err == nil {
+		t.Errorf("expected error for unknown control mode")
+	}
+}
+
+// TestSanitizeControlProcessing tests that sanitized content is what filters see
+func TestSanitizeControlProcessing(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "build.log"), []byte("\x1b[1mERROR\x1b[0m disk full\n"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	config := NewConfig(
+		WithGitClient(NewMockGitClient(false)),
+		WithGrep(regexp.MustCompile("ERROR disk"), -1),
+		WithSanitizeControl(ControlStrip),
+	)
+	content, _, err := ProcessProject([]string{dir}, config)
+	if err != nil {
+		t.Fatalf("ProcessProject failed: %v", err)
+	}
+	if strings.Contains(content, "\x1b") || !strings.Contains(content, "ERROR disk full") {
+		t.Errorf("expected sanitized log content, got:\n%q", content)
+	}
+}
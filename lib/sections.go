@@ -0,0 +1,106 @@
+package handoff
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// SectionFormatter is an optional interface a Formatter can implement to control
+// how supplementary sections, such as recent commit history, are rendered.
+// Formatters that don't implement it get sections rendered as tagged blocks.
+type SectionFormatter interface {
+	// FormatSection renders a named section with the given content
+	FormatSection(name, content string) string
+}
+
+// FormatSection renders a section as a block surrounded by name tags,
+// mirroring the path tags used for files.
+func (f *TemplateFormatter) FormatSection(name, content string) string {
+	return formatTaggedSection(name, content)
+}
+
+// WithGitLog appends a section listing the last n commits that touched the
+// processed paths, giving the reader context about recent changes.
+// A value of zero or less disables the section.
+func WithGitLog(n int) Option {
+	return func(c *Config) {
+		c.GitLog = n
+	}
+}
+
+// WithGitLogStat sets whether the commit history section includes a summary
+// of changed files and lines for each commit.
+func WithGitLogStat(stat bool) Option {
+	return func(c *Config) {
+		c.GitLogStat = stat
+	}
+}
+
+// formatSection renders a section using the formatter when it implements
+// SectionFormatter, falling back to tagged blocks otherwise. (internal helper)
+func formatSection(formatter Formatter, name, content string) string {
+	if sf, ok := formatter.(SectionFormatter); ok {
+		return sf.FormatSection(name, content)
+	}
+	return formatTaggedSection(name, content)
+}
+
+// formatTaggedSection renders content between <name> and </name> tags (internal helper)
+func formatTaggedSection(name, content string) string {
+	if !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+	return "<" + name + ">\n" + content + "</" + name + ">\n\n"
+}
+
+// buildSections renders all enabled supplementary sections for the processed paths.
+// Sections that cannot be produced are logged as warnings and omitted. (internal helper)
+func buildSections(paths []string, config *Config, formatter Formatter, logger *Logger) []string {
+	var sections []string
+	if log := gitLogSection(paths, config, logger); log != "" {
+		sections = append(sections, formatSection(formatter, "git-log", log))
+	}
+	if deps := dependenciesSection(paths, config, logger); deps != "" {
+		sections = append(sections, formatSection(formatter, "dependencies", deps))
+	}
+	if env := environmentSection(paths, config); env != "" {
+		sections = append(sections, formatSection(formatter, "environment", env))
+	}
+	if diff := gitDiffSection(config, logger); diff != "" {
+		sections = append(sections, formatSection(formatter, "git-diff", diff))
+	}
+	return sections
+}
+
+// gitLogSection returns the recent commit history for the given paths, or an
+// empty string when the section is disabled or unavailable. (internal helper)
+func gitLogSection(paths []string, config *Config, logger *Logger) string {
+	lister, ok := config.GitClient.(CommitLister)
+	if config.GitLog <= 0 || len(paths) == 0 || !ok || !config.GitClient.IsAvailable() {
+		return ""
+	}
+
+	// git accepts absolute pathspecs inside the work tree, so run from the
+	// first path's directory and pass every path in absolute form
+	var absPaths []string
+	for _, path := range paths {
+		absPath, err := filepath.Abs(path)
+		if err != nil {
+			logger.Warn("cannot resolve %s for git log: %v", path, err)
+			return ""
+		}
+		absPaths = append(absPaths, absPath)
+	}
+	dir := absPaths[0]
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		dir = filepath.Dir(dir)
+	}
+
+	log, err := lister.RecentCommits(dir, absPaths, config.GitLog, config.GitLogStat)
+	if err != nil {
+		logger.Warn("cannot read git history: %v", err)
+		return ""
+	}
+	return strings.TrimSpace(log)
+}
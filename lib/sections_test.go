@@ -0,0 +1,75 @@
+package handoff
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestFormatSection tests section rendering with and without SectionFormatter support
+func TestFormatSection(t *testing.T) {
+	want := "<git-log>\nabc123 fix bug\n</git-log>\n\n"
+
+	if got := formatSection(NewTemplateFormatter(""), "git-log", "abc123 fix bug"); got != want {
+		t.Errorf("formatSection() with TemplateFormatter = %q, want %q", got, want)
+	}
+	if got := formatSection(upperFormatter{}, "git-log", "abc123 fix bug\n"); got != want {
+		t.Errorf("formatSection() with plain Formatter = %q, want %q", got, want)
+	}
+}
+
+// TestWithGitLog tests that the commit history section is appended when enabled
+func TestWithGitLog(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "handoff-gitlog-test-")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer func() {
+		if cleanErr := os.RemoveAll(tmpDir); cleanErr != nil {
+			t.Logf("Failed to clean up temp directory: %v", cleanErr)
+		}
+	}()
+
+	filePath := filepath.Join(tmpDir, "main.go")
+	if err := os.WriteFile(filePath, []byte("package main\n"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	absDir, err := filepath.Abs(tmpDir)
+	if err != nil {
+		t.Fatalf("Failed to resolve temp directory: %v", err)
+	}
+	mockGit := NewMockGitClient(true)
+	mockGit.SetFilesInDir(tmpDir, []string{filePath})
+	mockGit.SetRecentCommits(absDir, "abc1234 2026-01-02 Add main\n")
+
+	testCases := []struct {
+		name    string
+		client  GitClient
+		gitLog  int
+		wantLog bool
+	}{
+		{name: "Disabled by default", client: mockGit, gitLog: 0, wantLog: false},
+		{name: "Enabled", client: mockGit, gitLog: 5, wantLog: true},
+		{name: "Client without CommitLister", client: basicGitClient{mockGit}, gitLog: 5, wantLog: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := NewConfig(WithGitClient(tc.client), WithGitLog(tc.gitLog))
+			content, stats, err := ProcessProject([]string{tmpDir}, config)
+			if err != nil {
+				t.Fatalf("ProcessProject failed: %v", err)
+			}
+
+			hasLog := strings.Contains(content, "<git-log>\nabc1234 2026-01-02 Add main\n</git-log>")
+			if hasLog != tc.wantLog {
+				t.Errorf("content has git log section = %v, want %v\n%s", hasLog, tc.wantLog, content)
+			}
+			if tc.wantLog && stats.Chars <= len("<git-log>") {
+				t.Errorf("stats should include the git log section, got %d chars", stats.Chars)
+			}
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package handoff
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrFilesSkipped is returned when files are skipped for a reason given to
+// WithStrictSkips
+var ErrFilesSkipped = errors.New("files were skipped")
+
+// SkipReason explains why a file was left out of the output. It is reported to
+// Hooks.OnFileSkipped, counted in Stats.Skipped, listed in Stats.SkippedFiles
+// and the appendix added by WithSkippedAppendix, and checked by WithStrictSkips.
+type SkipReason string
+
+const (
+	// SkipBinary marks a file detected as binary, including UTF-16 text when
+	// transcoding is disabled
+	SkipBinary SkipReason = "binary"
+
+	// SkipGitIgnored marks a file excluded by .gitignore rules
+	SkipGitIgnored SkipReason = "gitignored"
+
+	// SkipHidden marks a hidden file that is not on the hidden allowlist
+	SkipHidden SkipReason = "hidden"
+
+	// SkipFiltered marks a file rejected by extension, name, content, history,
+	// or .gitattributes filters. Custom FileFilters may report their own
+	// reasons instead; a filter that gives none is reported as SkipFiltered.
+	SkipFiltered SkipReason = "filtered"
+
+	// SkipTooLarge marks a file over the size or line limit
+	SkipTooLarge SkipReason = "too large"
+
+	// SkipReadError marks a file that could not be stat'ed or read
+	SkipReadError SkipReason = "read error"
+
+	// SkipSpecial marks a named pipe, socket, or device, which is never read
+	// since reading it can block forever
+	SkipSpecial SkipReason = "special file"
+
+	// SkipSampled marks a file left out by a directory cap or random sample
+	SkipSampled SkipReason = "sampled out"
+
+	// SkipIrrelevant marks a file left out of a query's best matches
+	SkipIrrelevant SkipReason = "irrelevant"
+
+	// SkipDuplicate marks a second name for a file already included, through a
+	// hard link, a symlink, or a path given twice
+	SkipDuplicate SkipReason = "duplicate"
+
+	// SkipOverBudget marks a file whose content alone exceeds the token budget
+	// under the drop strategy, so it could never be kept
+	SkipOverBudget SkipReason = "over budget"
+)
+
+// SkippedFile records a discovered file left out of the output and why
+type SkippedFile struct {
+	Path   string     `json:"path"`
+	Reason SkipReason `json:"reason"`
+}
+
+// skipReasons lists every reason a file can be skipped, for parsing
+var skipReasons = []SkipReason{
+	SkipBinary, SkipGitIgnored, SkipHidden, SkipFiltered, SkipTooLarge,
+	SkipReadError, SkipSpecial, SkipSampled, SkipIrrelevant, SkipDuplicate,
+	SkipOverBudget,
+}
+
+// ParseSkipReason converts a name such as "binary" or "read error" into a
+// SkipReason. Hyphens may stand in for spaces, as in "read-error".
+func ParseSkipReason(name string) (SkipReason, error) {
+	normalized := strings.ReplaceAll(strings.ToLower(strings.TrimSpace(name)), "-", " ")
+	for _, reason := range skipReasons {
+		if string(reason) == normalized {
+			return reason, nil
+		}
+	}
+	return "", fmt.Errorf("unknown skip reason %q (want binary, gitignored, hidden, filtered, too-large, read-error, special-file, sampled-out, irrelevant, duplicate, or over-budget)", name)
+}
+
+// WithSkippedAppendix sets whether a section listing the files left out of
+// the output is appended, one "path (reason)" entry per file grouped by
+// reason, so the reader knows what the context doesn't show.
+func WithSkippedAppendix(appendix bool) Option {
+	return func(c *Config) {
+		c.SkippedAppendix = appendix
+	}
+}
+
+// WithStrictSkips makes skipping a file for any of the given reasons an error
+// wrapping ErrFilesSkipped, naming each such file, rather than a note in
+// Stats. For example, WithStrictSkips(SkipReadError, SkipBinary) fails a run
+// that would silently leave out unreadable or binary files.
+func WithStrictSkips(reasons ...SkipReason) Option {
+	return func(c *Config) {
+		c.StrictSkips = reasons
+	}
+}
+
+// skippedEntries describes skipped files as "path (reason)", grouped by
+// reason in the order each reason first appears, keeping discovery order
+// within a reason (internal helper)
+func skippedEntries(files []SkippedFile) []string {
+	var order []SkipReason
+	byReason := make(map[SkipReason][]string)
+	for _, file := range files {
+		if _, ok := byReason[file.Reason]; !ok {
+			order = append(order, file.Reason)
+		}
+		byReason[file.Reason] = append(byReason[file.Reason], fmt.Sprintf("%s (%s)", file.Path, file.Reason))
+	}
+	entries := make([]string, 0, len(files))
+	for _, reason := range order {
+		entries = append(entries, byReason[reason]...)
+	}
+	return entries
+}
+
+// skippedAppendixSection renders the skipped files as a section, or returns
+// an empty string when the appendix is disabled or no files were skipped
+// (internal helper)
+func skippedAppendixSection(files []SkippedFile, config *Config, formatter Formatter) string {
+	if !config.SkippedAppendix || len(files) == 0 {
+		return ""
+	}
+	return formatSection(formatter, "skipped-files", strings.Join(skippedEntries(files), "\n"))
+}
+
+// checkStrictSkips returns an error wrapping ErrFilesSkipped that names the
+// files skipped for a reason given to WithStrictSkips (internal helper)
+func checkStrictSkips(files []SkippedFile, config *Config) error {
+	if len(config.StrictSkips) == 0 {
+		return nil
+	}
+	var strict []SkippedFile
+	for _, file := range files {
+		for _, reason := range config.StrictSkips {
+			if file.Reason == reason {
+				strict = append(strict, file)
+				break
+			}
+		}
+	}
+	if len(strict) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w: %s", ErrFilesSkipped, strings.Join(skippedEntries(strict), ", "))
+}
@@ -0,0 +1,94 @@
+package handoff
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestParseSkipReason tests parsing skip reason names
+func TestParseSkipReason(t *testing.T) {
+	for name, want := range map[string]SkipReason{
+		"binary":     SkipBinary,
+		"Read-Error": SkipReadError,
+		"too large":  SkipTooLarge,
+		" duplicate": SkipDuplicate,
+	} {
+		if got, err := ParseSkipReason(name); err != nil || got != want {
+			t.Errorf("ParseSkipReason(%q) = %q, %v; want %q", name, got, err, want)
+		}
+	}
+	if _, err := ParseSkipReason("unknown"); err == nil {
+		t.Error("ParseSkipReason(\"unknown\") succeeded, want error")
+	}
+}
+
+// writeSkipFixture writes a directory with one text file, one binary file,
+// and one file the extension filter excludes
+func writeSkipFixture(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	files := map[string][]byte{
+		"main.go":   []byte("package main\n"),
+		"logo.png":  {0x89, 'P', 'N', 'G', 0, 0, 0, 0},
+		"notes.log": []byte("log line\n"),
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), content, 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+	return dir
+}
+
+// TestWithSkippedAppendix tests listing the skipped files by reason
+func TestWithSkippedAppendix(t *testing.T) {
+	dir := writeSkipFixture(t)
+	config := NewConfig(WithGitClient(NewMockGitClient(false)), WithExclude(".log"), WithSkippedAppendix(true))
+	content, stats, err := ProcessProject([]string{dir}, config)
+	if err != nil {
+		t.Fatalf("ProcessProject failed: %v", err)
+	}
+	if !strings.Contains(content, "<skipped-files>") {
+		t.Fatalf("content is missing the skipped-files section:\n%s", content)
+	}
+	for _, file := range stats.SkippedFiles {
+		if entry := file.Path + " (" + string(file.Reason) + ")"; !strings.Contains(content, entry) {
+			t.Errorf("content is missing %q:\n%s", entry, content)
+		}
+	}
+	if len(stats.SkippedFiles) != 2 {
+		t.Errorf("SkippedFiles = %v, want the binary and excluded files", stats.SkippedFiles)
+	}
+
+	// Nothing is appended by default
+	content, _, err = ProcessProject([]string{dir}, NewConfig(WithGitClient(NewMockGitClient(false)), WithExclude(".log")))
+	if err != nil {
+		t.Fatalf("ProcessProject failed: %v", err)
+	}
+	if strings.Contains(content, "<skipped-files>") {
+		t.Errorf("content has a skipped-files section without the option:\n%s", content)
+	}
+}
+
+// TestWithStrictSkips tests failing when files are skipped for given reasons
+func TestWithStrictSkips(t *testing.T) {
+	dir := writeSkipFixture(t)
+
+	config := NewConfig(WithGitClient(NewMockGitClient(false)), WithExclude(".log"), WithStrictSkips(SkipBinary))
+	_, _, err := ProcessProject([]string{dir}, config)
+	if !errors.Is(err, ErrFilesSkipped) {
+		t.Fatalf("ProcessProject error = %v, want ErrFilesSkipped", err)
+	}
+	if !strings.Contains(err.Error(), "logo.png (binary)") || strings.Contains(err.Error(), "notes.log") {
+		t.Errorf("error = %q, want only logo.png named", err)
+	}
+
+	// Skips for other reasons are allowed
+	config = NewConfig(WithGitClient(NewMockGitClient(false)), WithExclude(".log,.png"), WithStrictSkips(SkipBinary))
+	if _, _, err := ProcessProject([]string{dir}, config); err != nil {
+		t.Errorf("ProcessProject failed with only filtered files skipped: %v", err)
+	}
+}
@@ -0,0 +1,138 @@
+package handoff
+
+import (
+	"strings"
+)
+
+// Stats holds statistics about processed files and content.
+// It's returned by file processing functions to provide information
+// about the operation results without relying on logging.
+type Stats struct {
+	// FilesProcessed is the number of files successfully processed
+	FilesProcessed int `json:"filesProcessed"`
+
+	// FilesTotal is the total number of candidate files found before filtering
+	FilesTotal int `json:"filesTotal"`
+
+	// FilesTrimmed is the number of processed files dropped or shortened to fit the token budget
+	FilesTrimmed int `json:"filesTrimmed"`
+
+	// Lines is the number of lines in the processed content
+	Lines int `json:"lines"`
+
+	// Chars is the number of characters in the processed content
+	Chars int `json:"chars"`
+
+	// Tokens is an estimated count of tokens in the processed content
+	Tokens int `json:"tokens"`
+
+	// IncludedFiles lists the paths of the files in the output, in output order
+	IncludedFiles []string `json:"includedFiles,omitempty"`
+
+	// Files holds statistics for each file in the output, in output order
+	Files []FileStat `json:"files,omitempty"`
+
+	// Skipped counts the discovered files left out of the output, by reason;
+	// files dropped to fit the token budget are counted in FilesTrimmed instead,
+	// except those that alone exceed it, which are skipped as SkipOverBudget
+	Skipped map[SkipReason]int `json:"skipped,omitempty"`
+
+	// SkippedFiles lists the files counted in Skipped with their reasons
+	SkippedFiles []SkippedFile `json:"skippedFiles,omitempty"`
+}
+
+// FileStat holds statistics about a single file in the output. Lines, Chars,
+// and Tokens describe the file's formatted output, which is what counts toward
+// the totals in Stats; LineEndings and Encoding describe the file as it was read.
+type FileStat struct {
+	Path        string     `json:"path"`
+	Lines       int        `json:"lines"`
+	Chars       int        `json:"chars"`
+	Tokens      int        `json:"tokens"`
+	LineEndings LineEnding `json:"lineEndings"`
+	Encoding    Encoding   `json:"encoding"`
+
+	// Transcoded is set when the file was converted to UTF-8 from Encoding
+	Transcoded bool `json:"transcoded,omitempty"`
+
+	// GitStatus is the file's git status, when WithGitStatus is set and the
+	// file is in a repository
+	GitStatus FileStatus `json:"gitStatus,omitempty"`
+
+	// Checksum is the SHA-256 digest of the file's content as included in the
+	// output, as "sha256:" and hex digits, when WithChecksums is set
+	Checksum string `json:"checksum,omitempty"`
+}
+
+// Note: The global gitAvailable variable and its initialization have been replaced
+// with a GitClient interface. This allows for better dependency injection and testing.
+// See git_client.go for the implementation details.
+
+// estimateTokenCount provides a simple approximation of token count in text.
+// This is an internal helper function that uses a basic whitespace-based approach:
+//   - Counts transitions from non-whitespace sequences to whitespace
+//   - Treats any continuous sequence of non-whitespace characters as one token
+//   - Adds a final count if text ends with non-whitespace characters
+//
+// Note that this method:
+//   - Is significantly less sophisticated than actual LLM tokenizers
+//   - Doesn't account for subword tokenization used by most modern LLMs
+//   - May undercount tokens for punctuation that would be separate tokens in LLMs
+//   - May overcount for common words that LLMs represent as single tokens
+//   - Is intended for rough estimation purposes only, with accuracy varying
+//     by content type and language
+//
+// TokenCounter applies the same estimate to text written in pieces.
+func estimateTokenCount(text string) int {
+	var c TokenCounter
+	_, _ = c.WriteString(text)
+	return c.Tokens()
+}
+
+// CalculateStatistics calculates useful statistics about the content.
+// This function analyzes the provided content and returns counts of characters,
+// lines, and tokens (words or code-like tokens) it contains. It is a wrapper
+// around Analyze kept for compatibility; use Analyze for rune, word, and
+// non-empty line counts.
+//
+// Parameter:
+//   - content: The content to analyze
+//
+// Returns:
+//   - charCount: Total number of bytes in the content
+//   - lineCount: Number of line breaks plus one, so a trailing newline starts
+//     an extra, empty line (see ContentStats.Lines for the editor-style count)
+//   - tokenCount: Estimated number of tokens/words in the content
+func CalculateStatistics(content string) (charCount, lineCount, tokenCount int) {
+	s := Analyze(content)
+	return s.Bytes, s.LineBreaks + 1, s.Tokens
+}
+
+// contentStats accumulates character, newline, and token counts across multiple
+// pieces of content, so statistics can be summed per file instead of re-scanning
+// the combined output. (internal helper)
+type contentStats struct {
+	chars    int
+	newlines int
+	tokens   int
+}
+
+// add accumulates the statistics for a piece of content
+func (s *contentStats) add(content string) {
+	s.chars += len(content)
+	s.newlines += strings.Count(content, "\n")
+	s.tokens += estimateTokenCount(content)
+}
+
+// merge accumulates statistics gathered separately
+func (s *contentStats) merge(other contentStats) {
+	s.chars += other.chars
+	s.newlines += other.newlines
+	s.tokens += other.tokens
+}
+
+// lines returns the line count for the accumulated content, matching
+// CalculateStatistics for the equivalent concatenated string
+func (s *contentStats) lines() int {
+	return s.newlines + 1
+}
@@ -0,0 +1,71 @@
+package handoff
+
+import (
+	"io"
+	"os"
+)
+
+// StdinPath is the path argument that reads standard input as one document,
+// so piped content such as a log or an error trace can be bundled with files
+const StdinPath = "-"
+
+// DefaultStdinName is the path shown for standard input when no name is set
+const DefaultStdinName = "stdin"
+
+// WithStdin sets the reader used for the path "-" and the path shown for it,
+// which also selects the language of its code fence; for example, naming it
+// "trace.log" or "query.sql". A nil reader reads os.Stdin and an empty name
+// uses DefaultStdinName. The reader is read once, by the first call that
+// processes "-".
+func WithStdin(r io.Reader, name string) Option {
+	return func(c *Config) {
+		c.Stdin = r
+		c.StdinName = name
+	}
+}
+
+// stdinName returns the path shown for standard input (internal helper)
+func (c *Config) stdinName() string {
+	if c.StdinName == "" {
+		return DefaultStdinName
+	}
+	return c.StdinName
+}
+
+// processStdin reads standard input and processes it like the content of a
+// file named directly as a path. Name and extension filters don't apply, since
+// standard input was asked for explicitly, but the size limits, binary
+// detection, and content filters do. (internal helper)
+func processStdin(logger *Logger, config *Config, processor ProcessorFunc) (string, fileMeta) {
+	var r io.Reader = os.Stdin
+	if config.Stdin != nil {
+		r = config.Stdin
+	}
+	if config.MaxFileSize > 0 {
+		r = io.LimitReader(r, config.MaxFileSize+1)
+	}
+	content, err := io.ReadAll(r)
+	if err != nil {
+		// Standard input can't be read again, so a retry skips it
+		if config.ErrorHandler != nil && config.ErrorHandler(config.stdinName(), err) == ErrorAbort {
+			return "", fileMeta{err: abortRun(err)}
+		}
+		logger.Warn("cannot read standard input: %v", err)
+		return skipFile(StdinPath, SkipReadError, config)
+	}
+	if config.MaxFileSize > 0 && int64(len(content)) > config.MaxFileSize {
+		logger.Verbose("skipping standard input (exceeds limit of %d bytes)", config.MaxFileSize)
+		return skipFile(StdinPath, SkipTooLarge, config)
+	}
+
+	sample := content[:min(len(content), binarySampleSize)]
+	if !hasUTF16BOM(sample) && isBinaryFile(content) {
+		if config.IncludeBinary == "" {
+			logger.Verbose("skipping binary standard input")
+			return skipFile(StdinPath, SkipBinary, config)
+		}
+		dump := dumpBinary(content[:min(len(content), binaryDumpLimit)], int64(len(content)), config.IncludeBinary)
+		return processor(StdinPath, dump), fileMeta{encoding: EncodingUTF8, lineEndings: detectLineEndings(dump)}
+	}
+	return processContent(StdinPath, content, logger, config, processor)
+}
@@ -0,0 +1,69 @@
+package handoff
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestStdinPath tests bundling standard input with files
+func TestStdinPath(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "main.go")
+	if err := os.WriteFile(file, []byte("package main\n"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	trace := "panic: runtime error\n\tmain.go:12\n"
+	config := NewConfig(
+		WithGitClient(NewMockGitClient(false)),
+		WithInclude(".go"),
+		WithStdin(strings.NewReader(trace), "trace.log"),
+		WithFormat("<{path}>\n{content}</{path}>\n"),
+	)
+	content, stats, err := ProcessProject([]string{file, StdinPath, StdinPath}, config)
+	if err != nil {
+		t.Fatalf("ProcessProject failed: %v", err)
+	}
+
+	// Standard input is included once, despite the extension filter
+	if want := []string{file, "trace.log"}; !reflect.DeepEqual(stats.IncludedFiles, want) {
+		t.Errorf("IncludedFiles = %v, want %v", stats.IncludedFiles, want)
+	}
+	if !strings.Contains(content, "<trace.log>\n"+trace+"</trace.log>\n") {
+		t.Errorf("content is missing standard input:\n%s", content)
+	}
+}
+
+// TestStdinPathDefaults tests standard input without a name and with binary content
+func TestStdinPathDefaults(t *testing.T) {
+	config := NewConfig(
+		WithGitClient(NewMockGitClient(false)),
+		WithStdin(strings.NewReader("hello\n"), ""),
+	)
+	_, stats, err := ProcessProject([]string{StdinPath}, config)
+	if err != nil {
+		t.Fatalf("ProcessProject failed: %v", err)
+	}
+	if want := []string{DefaultStdinName}; !reflect.DeepEqual(stats.IncludedFiles, want) {
+		t.Errorf("IncludedFiles = %v, want %v", stats.IncludedFiles, want)
+	}
+
+	// Binary input is skipped like a binary file
+	var skipped []SkippedFile
+	config = NewConfig(
+		WithGitClient(NewMockGitClient(false)),
+		WithStdin(strings.NewReader("\x00\x01\x02"), ""),
+		WithHooks(Hooks{OnFileSkipped: func(path string, reason SkipReason) {
+			skipped = append(skipped, SkippedFile{Path: path, Reason: reason})
+		}}),
+	)
+	if _, _, err = ProcessProject([]string{StdinPath}, config); err != ErrNoFilesProcessed {
+		t.Errorf("ProcessProject error = %v, want %v", err, ErrNoFilesProcessed)
+	}
+	if want := []SkippedFile{{Path: DefaultStdinName, Reason: SkipBinary}}; !reflect.DeepEqual(skipped, want) {
+		t.Errorf("skipped = %v, want %v", skipped, want)
+	}
+}
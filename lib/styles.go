@@ -0,0 +1,180 @@
+package handoff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Style is an output preset bundling a per-file template with the tag wrapped
+// around the whole output, tuned for a particular target.
+type Style struct {
+	// Name identifies the style (e.g., "markdown")
+	Name string
+
+	// Format is the per-file template (see TemplateFormatter for placeholders)
+	Format string
+
+	// WrapperTag is the tag wrapped around the output; empty leaves it unwrapped
+	WrapperTag string
+
+	// Version numbers revisions of a built-in style, so output can be pinned
+	// to a revision by its versioned name (e.g., "claude@1")
+	Version int
+}
+
+// styles lists the built-in output styles
+var styles = []Style{
+	{
+		// Plain XML elements with the path as an attribute
+		Name:       "xml",
+		Format:     "<file path=\"{path}\">\n{content}\n</file>\n\n",
+		WrapperTag: "context",
+		Version:    1,
+	},
+	{
+		// Headings with language-tagged fences that render well on GitHub
+		Name:    "markdown",
+		Format:  "## {path}\n\n{fence}{lang}\n{content}\n{fence}\n\n",
+		Version: 1,
+	},
+	{
+		// Bare content with a one-line path header and no markup
+		Name:    "minimal",
+		Format:  "--- {path} ---\n{content}\n\n",
+		Version: 1,
+	},
+	{
+		// The document structure Anthropic recommends for long-context prompts
+		Name:       "claude",
+		Format:     "<document>\n<source>{path}</source>\n<document_content>\n{content}\n</document_content>\n</document>\n\n",
+		WrapperTag: "documents",
+		Version:    1,
+	},
+	{
+		// Labeled Markdown fences, which chat interfaces render as code blocks
+		Name:    "chatgpt",
+		Format:  "File: `{path}`\n{fence}{lang}\n{content}\n{fence}\n\n",
+		Version: 1,
+	},
+	{
+		// One-line separators and no fences, closing tags, or blank-line
+		// padding, spending as few tokens as possible on markup
+		Name:    "compact",
+		Format:  "=== {path} ===\n{content:trimlines}\n",
+		Version: 1,
+	},
+}
+
+// templateAliases are descriptive names for built-in styles in Templates
+var templateAliases = map[string]string{
+	"claude-xml":      "claude",
+	"markdown-fenced": "markdown",
+}
+
+// Templates is the registry of named output templates. Every built-in style is
+// registered under its name, which selects its latest version, and under its
+// versioned name (e.g., "claude-xml@1"), which keeps selecting that revision
+// when the style changes. Embedders can register their own templates at
+// startup to share them between projects; the map is not safe for concurrent
+// modification.
+var Templates = builtinTemplates()
+
+// builtinTemplates registers the built-in styles and their aliases by plain
+// and versioned name (internal helper)
+func builtinTemplates() map[string]Style {
+	templates := make(map[string]Style)
+	register := func(name string, style Style) {
+		style.Name = name
+		templates[name] = style
+		templates[fmt.Sprintf("%s@%d", name, style.Version)] = style
+	}
+	for _, style := range styles {
+		register(style.Name, style)
+	}
+	for alias, name := range templateAliases {
+		for _, style := range styles {
+			if style.Name == name {
+				register(alias, style)
+			}
+		}
+	}
+	return templates
+}
+
+// WithNamedFormat renders output using the template registered in Templates
+// under name, such as "claude-xml" or "markdown-fenced@1". Names not in the
+// registry leave the format unchanged; use LookupStyle to validate a name.
+func WithNamedFormat(name string) Option {
+	return func(c *Config) {
+		if style, ok := Templates[name]; ok {
+			c.Formatter = NewStyleFormatter(style)
+		}
+	}
+}
+
+// LookupStyle returns the built-in style or registered template with the
+// given name, ignoring case.
+func LookupStyle(name string) (Style, error) {
+	for _, style := range styles {
+		if strings.EqualFold(style.Name, name) {
+			return style, nil
+		}
+	}
+	for templateName, style := range Templates {
+		if strings.EqualFold(templateName, name) {
+			return style, nil
+		}
+	}
+	return Style{}, fmt.Errorf("unknown style %q (known styles: %s)", name, strings.Join(StyleNames(), ", "))
+}
+
+// StyleNames returns the names of the built-in styles.
+func StyleNames() []string {
+	names := make([]string, len(styles))
+	for i, style := range styles {
+		names[i] = style.Name
+	}
+	return names
+}
+
+// WithStyle renders output using a style preset, replacing the Format template
+// and the default context wrapper. Use LookupStyle for built-in styles.
+func WithStyle(style Style) Option {
+	return func(c *Config) {
+		c.Formatter = NewStyleFormatter(style)
+	}
+}
+
+// StyleFormatter renders files with a Style's template and wraps the output in
+// the style's wrapper tag.
+type StyleFormatter struct {
+	TemplateFormatter
+
+	// WrapperTag is the tag wrapped around the output; empty leaves it unwrapped
+	WrapperTag string
+}
+
+// NewStyleFormatter creates a StyleFormatter for the given style.
+func NewStyleFormatter(style Style) *StyleFormatter {
+	return &StyleFormatter{
+		TemplateFormatter: *NewTemplateFormatter(style.Format),
+		WrapperTag:        style.WrapperTag,
+	}
+}
+
+// Wrap wraps the body in the style's wrapper tag, if any.
+func (f *StyleFormatter) Wrap(body string) string {
+	if f.WrapperTag == "" {
+		return body
+	}
+	return wrapInTag(f.WrapperTag, body, nil)
+}
+
+// wrapWithAttrs wraps the body in the style's wrapper tag carrying attrs, if
+// the style has one.
+func (f *StyleFormatter) wrapWithAttrs(body string, attrs []ContextAttr) string {
+	if f.WrapperTag == "" {
+		return body
+	}
+	return wrapInTag(f.WrapperTag, body, attrs)
+}
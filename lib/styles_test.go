@@ -0,0 +1,129 @@
+package handoff
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestLookupStyle tests resolving built-in styles
+func TestLookupStyle(t *testing.T) {
+	for _, name := range StyleNames() {
+		style, err := LookupStyle(strings.ToUpper(name))
+		if err != nil {
+			t.Errorf("LookupStyle(%q) failed: %v", name, err)
+			continue
+		}
+		if style.Name != name || !strings.Contains(style.Format, "{content") {
+			t.Errorf("LookupStyle(%q) = %+v, want a template for %s", name, style, name)
+		}
+	}
+
+	if _, err := LookupStyle("yaml"); err == nil {
+		t.Error("LookupStyle(\"yaml\") succeeded, want error")
+	}
+}
+
+// TestStyleFormatter tests rendering and wrapping with style presets
+func TestStyleFormatter(t *testing.T) {
+	testCases := []struct {
+		style string
+		want  string
+	}{
+		{
+			style: "markdown",
+			want:  "## main.go\n\n```go\npackage main\n```\n\n",
+		},
+		{
+			style: "claude",
+			want:  "<documents>\n<document>\n<source>main.go</source>\n<document_content>\npackage main\n</document_content>\n</document>\n\n</documents>",
+		},
+		{
+			style: "minimal",
+			want:  "--- main.go ---\npackage main\n\n",
+		},
+		{
+			style: "compact",
+			want:  "=== main.go ===\npackage main\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.style, func(t *testing.T) {
+			style, err := LookupStyle(tc.style)
+			if err != nil {
+				t.Fatalf("LookupStyle failed: %v", err)
+			}
+			formatter := NewStyleFormatter(style)
+			got := formatter.Wrap(formatter.FormatFile(FileInfo{Path: "main.go"}, []byte("package main")))
+			if got != tc.want {
+				t.Errorf("output = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+// TestWithStyle tests that ProcessProject renders output using a style preset
+func TestWithStyle(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "app.py"), []byte("print('hi')"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	style, err := LookupStyle("xml")
+	if err != nil {
+		t.Fatalf("LookupStyle failed: %v", err)
+	}
+	content, _, err := ProcessProject([]string{dir}, NewConfig(WithGitClient(NewMockGitClient(false)), WithStyle(style)))
+	if err != nil {
+		t.Fatalf("ProcessProject failed: %v", err)
+	}
+
+	want := "<context>\n<file path=\"" + filepath.Join(dir, "app.py") + "\">\nprint('hi')\n</file>\n\n</context>"
+	if content != want {
+		t.Errorf("content = %q, want %q", content, want)
+	}
+}
+
+// TestTemplates tests the registry of named, versioned templates
+func TestTemplates(t *testing.T) {
+	for _, name := range []string{"claude-xml", "claude-xml@1", "markdown-fenced", "minimal", "minimal@1", "xml@1"} {
+		if _, ok := Templates[name]; !ok {
+			t.Errorf("Templates[%q] is missing", name)
+		}
+	}
+	if Templates["claude-xml"].Format != Templates["claude"].Format || Templates["claude-xml"].WrapperTag != "documents" {
+		t.Errorf("Templates[\"claude-xml\"] = %+v, want the claude style", Templates["claude-xml"])
+	}
+
+	style, err := LookupStyle("Markdown-Fenced@1")
+	if err != nil || style.Name != "markdown-fenced" || style.Version != 1 {
+		t.Errorf("LookupStyle(\"Markdown-Fenced@1\") = %+v, %v, want markdown-fenced version 1", style, err)
+	}
+}
+
+// TestWithNamedFormat tests selecting a registered template by name
+func TestWithNamedFormat(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "app.py"), []byte("print('hi')"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	content, _, err := ProcessProject([]string{dir}, NewConfig(WithGitClient(NewMockGitClient(false)), WithNamedFormat("minimal")))
+	if err != nil {
+		t.Fatalf("ProcessProject failed: %v", err)
+	}
+	if want := "--- " + filepath.Join(dir, "app.py") + " ---\nprint('hi')\n\n"; content != want {
+		t.Errorf("content = %q, want %q", content, want)
+	}
+
+	// Unknown names keep the default format
+	content, _, err = ProcessProject([]string{dir}, NewConfig(WithGitClient(NewMockGitClient(false)), WithNamedFormat("nope")))
+	if err != nil {
+		t.Fatalf("ProcessProject failed: %v", err)
+	}
+	if !strings.HasPrefix(content, "<context>\n<"+filepath.Join(dir, "app.py")+">") {
+		t.Errorf("content with an unknown name = %q, want the default format", content)
+	}
+}
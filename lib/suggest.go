@@ -0,0 +1,211 @@
+package handoff
+
+import (
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// ProjectSurvey describes what SuggestFileConfig found in a project
+type ProjectSurvey struct {
+	// Files is the number of files discovery found
+	Files int
+
+	// Languages counts the files of each recognized language, most common first
+	Languages []LanguageCount
+
+	// IgnoreFiles lists the project's .gitignore and .gitattributes files and the
+	// user's global ignore file, when present, which discovery respects
+	IgnoreFiles []string
+}
+
+// LanguageCount is the number of files of a language found in a project
+type LanguageCount struct {
+	Name  string
+	Files int
+}
+
+// suggestedExcludeNames are lockfiles and generated files that are rarely worth
+// handing off; the ones present in a project are suggested as exclusions
+var suggestedExcludeNames = []string{
+	"go.sum", "package-lock.json", "yarn.lock", "pnpm-lock.yaml", "Cargo.lock",
+	"poetry.lock", "Pipfile.lock", "uv.lock", "Gemfile.lock", "composer.lock",
+	"*.min.js", "*.min.css", "*.pb.go", "*_mock.go",
+}
+
+// suggestedExcludeExts are extensions of text files that are rarely worth
+// handing off, such as vector images and source maps; the ones present in a
+// project are suggested as exclusions
+var suggestedExcludeExts = []string{".svg", ".map", ".snap"}
+
+// docExtensions are the extensions of documentation files, which the
+// suggested docs profile includes
+var docExtensions = []string{".md", ".mdx", ".rst", ".adoc", ".txt"}
+
+// suggestedHiddenNames are hidden files and directories that usually hold
+// useful project configuration; the ones present are suggested for the
+// hidden allowlist
+var suggestedHiddenNames = []string{".github", ".gitlab-ci.yml", ".golangci.yml", ".editorconfig"}
+
+// SuggestFileConfig inspects a project directory and suggests starter
+// settings for its config file: the extensions of the languages present as
+// includes, lockfiles and generated files present as excluded names, asset
+// extensions present as excluded extensions, useful hidden configuration in
+// the hidden allowlist, and profiles for the documentation alone and for the
+// code without its tests, when the project has them. It also reports what it
+// found. The config controls discovery and can be nil for defaults.
+func SuggestFileConfig(dir string, config *Config) (*FileConfig, ProjectSurvey, error) {
+	files, err := DiscoverFiles([]string{dir}, config)
+	if err != nil {
+		return nil, ProjectSurvey{}, err
+	}
+
+	survey := ProjectSurvey{Files: len(files)}
+	found := scanProject(files)
+	for name, count := range found.languages {
+		survey.Languages = append(survey.Languages, LanguageCount{Name: name, Files: count})
+	}
+	sort.Slice(survey.Languages, func(i, j int) bool {
+		a, b := survey.Languages[i], survey.Languages[j]
+		return a.Files > b.Files || (a.Files == b.Files && a.Name < b.Name)
+	})
+
+	survey.IgnoreFiles = presentNames(dir, []string{".gitignore", ".gitattributes"})
+	if path := globalIgnorePath(); path != "" {
+		if _, err := os.Stat(path); err == nil {
+			survey.IgnoreFiles = append(survey.IgnoreFiles, path)
+		}
+	}
+
+	suggested := &FileConfig{
+		Include:         strings.Join(sortedKeys(found.extensions), ","),
+		Exclude:         strings.Join(sortedKeys(found.excludeExts), ","),
+		ExcludeNames:    strings.Join(sortedKeys(found.excludeNames), ","),
+		HiddenAllowlist: strings.Join(presentNames(dir, suggestedHiddenNames), ","),
+		Profiles:        suggestProfiles(found),
+	}
+	return suggested, survey, nil
+}
+
+// projectScan is what SuggestFileConfig found among a project's files
+// (internal helper)
+type projectScan struct {
+	// languages counts the files of each recognized language, and extensions
+	// are the extensions of those files
+	languages  map[string]int
+	extensions map[string]bool
+
+	// excludeExts and excludeNames are the suggested exclusions present
+	excludeExts  map[string]bool
+	excludeNames map[string]bool
+
+	// docExts are the documentation extensions present
+	docExts map[string]bool
+
+	// testNames are the excluded-name patterns matching the test files present
+	testNames map[string]bool
+}
+
+// scanProject sorts a project's files into what SuggestFileConfig suggests.
+// Files suggested for exclusion don't count towards the languages present.
+// (internal helper)
+func scanProject(files []string) *projectScan {
+	found := &projectScan{
+		languages:    make(map[string]int),
+		extensions:   make(map[string]bool),
+		excludeExts:  make(map[string]bool),
+		excludeNames: make(map[string]bool),
+		docExts:      make(map[string]bool),
+		testNames:    make(map[string]bool),
+	}
+	for _, file := range files {
+		excluded := false
+		for _, pattern := range suggestedExcludeNames {
+			if matchesExcludeName(filepath.Base(file), []string{pattern}) {
+				found.excludeNames[pattern] = true
+				excluded = true
+			}
+		}
+		ext := strings.ToLower(filepath.Ext(file))
+		if slices.Contains(suggestedExcludeExts, ext) {
+			found.excludeExts[ext] = true
+			excluded = true
+		}
+		if excluded {
+			continue
+		}
+		if language := fenceLanguages[ext]; language != "" {
+			found.languages[language]++
+			found.extensions[ext] = true
+		}
+		if slices.Contains(docExtensions, ext) {
+			found.docExts[ext] = true
+		}
+		if pattern := testNamePattern(filepath.Base(file)); pattern != "" {
+			found.testNames[pattern] = true
+		}
+	}
+	return found
+}
+
+// presentNames returns the names that exist in dir (internal helper)
+func presentNames(dir string, names []string) []string {
+	var present []string
+	for _, name := range names {
+		if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+			present = append(present, name)
+		}
+	}
+	return present
+}
+
+// suggestProfiles suggests a docs profile including the documentation
+// extensions found and a code profile excluding the test files found, or
+// returns nil when there are neither. A profile replaces the fields it sets,
+// so the code profile repeats the excluded names it adds the tests to.
+// (internal helper)
+func suggestProfiles(found *projectScan) map[string]*FileConfig {
+	profiles := make(map[string]*FileConfig)
+	if len(found.docExts) > 0 {
+		profiles["docs"] = &FileConfig{Include: strings.Join(sortedKeys(found.docExts), ",")}
+	}
+	if len(found.testNames) > 0 {
+		names := maps.Clone(found.testNames)
+		maps.Copy(names, found.excludeNames)
+		profiles["code"] = &FileConfig{ExcludeNames: strings.Join(sortedKeys(names), ",")}
+	}
+	if len(profiles) == 0 {
+		return nil
+	}
+	return profiles
+}
+
+// testNamePattern returns the excluded-name pattern matching a test file
+// named like base, such as "*_test.go" for "cache_test.go", or an empty
+// string when base isn't named like a test (internal helper)
+func testNamePattern(base string) string {
+	ext := filepath.Ext(base)
+	stem := strings.TrimSuffix(strings.ToLower(base), strings.ToLower(ext))
+	for _, suffix := range []string{"_test", ".test", ".spec", "_spec"} {
+		if strings.HasSuffix(stem, suffix) {
+			return "*" + suffix + ext
+		}
+	}
+	if strings.HasPrefix(stem, "test_") {
+		return "test_*" + ext
+	}
+	return ""
+}
+
+// sortedKeys returns the keys of a set in sorted order (internal helper)
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
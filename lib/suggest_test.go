@@ -0,0 +1,84 @@
+package handoff
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// TestSuggestFileConfig tests suggesting starter settings from a project's files
+func TestSuggestFileConfig(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"main.go":                      "package main\n",
+		"util.go":                      "package main\n",
+		"go.sum":                       "example.com/mod v1.0.0 h1:abc=\n",
+		"README.md":                    "# Project\n",
+		"web/app.min.js":               "var a=1;\n",
+		"web/app.ts":                   "export {}\n",
+		"web/app.test.ts":              "test()\n",
+		"web/icon.svg":                 "<svg/>\n",
+		"Makefile":                     "all:\n",
+		".gitignore":                   "dist/\n",
+		".github/workflows/ci.yml":     "on: push\n",
+		"assets/logo.unknownextension": "data\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write file: %v", err)
+		}
+	}
+
+	suggested, survey, err := SuggestFileConfig(dir, NewConfig(WithGitClient(NewMockGitClient(false))))
+	if err != nil {
+		t.Fatalf("SuggestFileConfig failed: %v", err)
+	}
+
+	// The extensions of the languages found are included; unknown extensions,
+	// suggested exclusions and the Makefile are not
+	want := &FileConfig{
+		Include:         ".go,.md,.ts",
+		Exclude:         ".svg",
+		ExcludeNames:    "*.min.js,go.sum",
+		HiddenAllowlist: ".github",
+		Profiles: map[string]*FileConfig{
+			"docs": {Include: ".md"},
+			"code": {ExcludeNames: "*.min.js,*.test.ts,go.sum"},
+		},
+	}
+	if !reflect.DeepEqual(suggested, want) {
+		t.Errorf("SuggestFileConfig() = %+v, want %+v", suggested, want)
+	}
+
+	if survey.Files != 10 {
+		t.Errorf("survey.Files = %d, want 10", survey.Files)
+	}
+	wantLanguages := []LanguageCount{{Name: "go", Files: 2}, {Name: "typescript", Files: 2}, {Name: "markdown", Files: 1}}
+	if !reflect.DeepEqual(survey.Languages, wantLanguages) {
+		t.Errorf("survey.Languages = %v, want %v", survey.Languages, wantLanguages)
+	}
+	if len(survey.IgnoreFiles) == 0 || survey.IgnoreFiles[0] != ".gitignore" {
+		t.Errorf("survey.IgnoreFiles = %v, want .gitignore first", survey.IgnoreFiles)
+	}
+}
+
+// TestTestNamePattern tests deriving excluded-name patterns from test files
+func TestTestNamePattern(t *testing.T) {
+	for base, want := range map[string]string{
+		"cache_test.go":  "*_test.go",
+		"App.spec.tsx":   "*.spec.tsx",
+		"test_parser.py": "test_*.py",
+		"user_spec.rb":   "*_spec.rb",
+		"main.go":        "",
+		"testing.go":     "",
+	} {
+		if got := testNamePattern(base); got != want {
+			t.Errorf("testNamePattern(%q) = %q, want %q", base, got, want)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package handoff
+
+import (
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// throttleChunkSize is the largest read passed through a throttled reader at
+// once, so reads are paced evenly rather than in one burst per file
+const throttleChunkSize = 32 << 10
+
+// WithIOThrottle limits file reads to bytesPerSec bytes per second, so that
+// processing a repository on NFS or another network mount in the background
+// doesn't saturate the link. The limit is shared by every run using the
+// config and its clones, including concurrent ones. A value of zero or less
+// disables it.
+func WithIOThrottle(bytesPerSec int64) Option {
+	return func(c *Config) {
+		c.IOThrottle = bytesPerSec
+		c.throttle = nil
+		if bytesPerSec > 0 {
+			c.throttle = &ioThrottle{rate: bytesPerSec}
+		}
+	}
+}
+
+// readFile reads a whole file like os.ReadFile, paced by throttle (internal helper)
+func readFile(path string, throttle *ioThrottle) ([]byte, error) {
+	if throttle == nil {
+		return os.ReadFile(path)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+	return io.ReadAll(throttle.reader(f))
+}
+
+// ioThrottle paces reads to a number of bytes per second (internal helper)
+type ioThrottle struct {
+	rate int64
+
+	mu sync.Mutex
+	// next is when the reads so far are paid for; idle time earns no credit
+	next time.Time
+}
+
+// wait blocks until n more bytes may have been read (internal helper)
+func (t *ioThrottle) wait(n int) {
+	t.mu.Lock()
+	now := time.Now()
+	if t.next.Before(now) {
+		t.next = now
+	}
+	t.next = t.next.Add(time.Duration(float64(n) / float64(t.rate) * float64(time.Second)))
+	delay := t.next.Sub(now)
+	t.mu.Unlock()
+	time.Sleep(delay)
+}
+
+// reader wraps r so reads from it are paced, or returns r when t is nil (internal helper)
+func (t *ioThrottle) reader(r io.Reader) io.Reader {
+	if t == nil {
+		return r
+	}
+	return &throttledReader{r: r, throttle: t}
+}
+
+// throttledReader is an io.Reader paced by an ioThrottle (internal helper)
+type throttledReader struct {
+	r        io.Reader
+	throttle *ioThrottle
+}
+
+// Read reads at most throttleChunkSize bytes, then waits until they are paid for
+func (r *throttledReader) Read(p []byte) (int, error) {
+	if len(p) > throttleChunkSize {
+		p = p[:throttleChunkSize]
+	}
+	n, err := r.r.Read(p)
+	if n > 0 {
+		r.throttle.wait(n)
+	}
+	return n, err
+}
@@ -0,0 +1,66 @@
+package handoff
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestIOThrottle tests pacing reads to a number of bytes per second
+func TestIOThrottle(t *testing.T) {
+	data := bytes.Repeat([]byte("x"), 100<<10)
+	throttle := &ioThrottle{rate: 1 << 20}
+
+	start := time.Now()
+	read, err := io.ReadAll(throttle.reader(bytes.NewReader(data)))
+	if err != nil {
+		t.Fatalf("ReadAll failed: %v", err)
+	}
+	if !bytes.Equal(read, data) {
+		t.Errorf("read %d bytes, want %d", len(read), len(data))
+	}
+	// 100 KiB at 1 MiB per second takes at least about 98 ms
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Errorf("read took %v, want at least 90ms", elapsed)
+	}
+
+	// A nil throttle leaves the reader as it is
+	var none *ioThrottle
+	r := bytes.NewReader(data)
+	if none.reader(r) != io.Reader(r) {
+		t.Error("nil throttle wrapped the reader")
+	}
+}
+
+// TestWithIOThrottle tests that throttled processing produces the same output
+func TestWithIOThrottle(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte(strings.Repeat("// line\n", 1000)), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	want, _, err := ProcessProject([]string{dir}, NewConfig(WithGitClient(NewMockGitClient(false))))
+	if err != nil {
+		t.Fatalf("ProcessProject failed: %v", err)
+	}
+	config := NewConfig(WithGitClient(NewMockGitClient(false)), WithIOThrottle(1<<20))
+	got, _, err := ProcessProject([]string{dir}, config)
+	if err != nil {
+		t.Fatalf("ProcessProject failed: %v", err)
+	}
+	if got != want {
+		t.Errorf("throttled output differs:\n%s\nwant:\n%s", got, want)
+	}
+	if config.IOThrottle != 1<<20 || config.Clone().throttle != config.throttle {
+		t.Errorf("IOThrottle = %d, want clones to share the throttle", config.IOThrottle)
+	}
+
+	// Zero disables the limit
+	if config := NewConfig(WithIOThrottle(1<<20), WithIOThrottle(0)); config.throttle != nil {
+		t.Error("WithIOThrottle(0) kept the throttle")
+	}
+}
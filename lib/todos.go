@@ -0,0 +1,69 @@
+package handoff
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// todoTextLimit is the most characters of a marker's comment kept in the
+// index, so a marker on a minified line doesn't swamp it
+const todoTextLimit = 200
+
+// todoMarker matches a TODO, FIXME, or HACK marker, optionally followed by
+// an owner in parentheses, either starting a comment or a line, or anywhere
+// when followed by a colon. Markers mentioned in prose, such as "a TODO,
+// FIXME, or HACK marker", don't match.
+var todoMarker = regexp.MustCompile(`(?:^|//|#|/\*|\*|--|;|<!--)\s*((?:TODO|FIXME|HACK)(?:\([^)]*\))?)(?::|\s|$)|\b((?:TODO|FIXME|HACK)(?:\([^)]*\))?):`)
+
+// WithTodoIndex sets whether a section indexing the TODO, FIXME, and HACK
+// markers in the included files is appended, one path:line entry per marker
+// followed by its comment, such as "lib/cache.go:42: TODO: evict expired
+// entries". It gives the reader a ready-made list of known issues. Line
+// numbers count lines of the content as included, which match the file's
+// unless a transform such as WithGrep or WithLeanComments removes lines.
+func WithTodoIndex(index bool) Option {
+	return func(c *Config) {
+		c.TodoIndex = index
+	}
+}
+
+// todoIndexSection renders the index of markers in files as a section, or
+// returns an empty string when the index is disabled or there are no markers
+// (internal helper)
+func todoIndexSection(files []formattedFile, config *Config, formatter Formatter) string {
+	if !config.TodoIndex {
+		return ""
+	}
+	var entries []string
+	for _, file := range files {
+		entries = append(entries, findTodos(file.path, file.content)...)
+	}
+	if len(entries) == 0 {
+		return ""
+	}
+	return formatSection(formatter, "todo-index", strings.Join(entries, "\n"))
+}
+
+// findTodos returns a "path:line: comment" entry for each marker in content,
+// with the comment running from the marker to the end of its line (internal helper)
+func findTodos(path string, content []byte) []string {
+	var entries []string
+	for i, text := range strings.Split(string(content), "\n") {
+		loc := todoMarker.FindStringSubmatchIndex(text)
+		if loc == nil {
+			continue
+		}
+		start := loc[2]
+		if start < 0 {
+			start = loc[4]
+		}
+		comment := strings.TrimSpace(text[start:])
+		comment = strings.TrimSpace(strings.TrimSuffix(strings.TrimSuffix(comment, "*/"), "-->"))
+		if len(comment) > todoTextLimit {
+			comment = strings.ToValidUTF8(comment[:todoTextLimit], "") + "..."
+		}
+		entries = append(entries, fmt.Sprintf("%s:%d: %s", path, i+1, comment))
+	}
+	return entries
+}
@@ -0,0 +1,94 @@
+package handoff
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestFindTodos tests finding markers and their comments
+func TestFindTodos(t *testing.T) {
+	content := strings.Join([]string{
+		"package cache",
+		"",
+		"// TODO: evict expired entries",
+		"func Get() {",
+		"\tx := 1 // FIXME(alice): racy */",
+		"\t/* HACK work around the driver bug */",
+		"\tlog(\"TODO list\", \"a TODO, FIXME, or HACK marker\")",
+		"\t// TODOS and todo: aren't markers",
+		"}",
+		"# TODO",
+		"<!-- FIXME: broken link -->",
+	}, "\n")
+
+	got := findTodos("cache.go", []byte(content))
+	want := []string{
+		"cache.go:3: TODO: evict expired entries",
+		"cache.go:5: FIXME(alice): racy",
+		"cache.go:6: HACK work around the driver bug",
+		"cache.go:10: TODO",
+		"cache.go:11: FIXME: broken link",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("findTodos =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	long := findTodos("min.js", []byte("// TODO: "+strings.Repeat("x", 500)))
+	if len(long) != 1 || len(long[0]) > len("min.js:1: ")+todoTextLimit+len("...") {
+		t.Errorf("findTodos of a long line = %q, want it cut to %d characters", long, todoTextLimit)
+	}
+}
+
+// TestWithTodoIndex tests appending the index of markers to the output
+func TestWithTodoIndex(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"a.go": "package a\n\n// TODO: handle errors\n",
+		"b.go": "package b\n\n// FIXME: " + strings.Repeat("slow ", 200) + "\n",
+		"c.go": "package c\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write file: %v", err)
+		}
+	}
+
+	config := NewConfig(WithGitClient(NewMockGitClient(false)), WithTodoIndex(true))
+	content, _, err := ProcessProject([]string{dir}, config)
+	if err != nil {
+		t.Fatalf("ProcessProject failed: %v", err)
+	}
+	want := "<todo-index>\n" + filepath.Join(dir, "a.go") + ":3: TODO: handle errors\n"
+	if !strings.Contains(content, want) || !strings.Contains(content, filepath.Join(dir, "b.go")+":3: FIXME: slow") {
+		t.Errorf("content is missing the index entries:\n%s", content)
+	}
+
+	// A file dropped to fit the budget is left out of the index
+	config = NewConfig(WithGitClient(NewMockGitClient(false)), WithTodoIndex(true), WithMaxTokens(150))
+	content, stats, err := ProcessProject([]string{dir}, config)
+	if err != nil {
+		t.Fatalf("ProcessProject failed: %v", err)
+	}
+	if stats.FilesTrimmed == 0 && stats.Skipped[SkipOverBudget] == 0 {
+		t.Fatalf("FilesTrimmed = 0, Skipped = %v, want b.go dropped", stats.Skipped)
+	}
+	if strings.Contains(content, "FIXME: slow") {
+		t.Errorf("index lists a dropped file:\n%s", content)
+	}
+	if !strings.Contains(content, "a.go:3: TODO: handle errors") {
+		t.Errorf("index is missing a kept file:\n%s", content)
+	}
+
+	// Without markers, no section is added
+	config = NewConfig(WithGitClient(NewMockGitClient(false)), WithTodoIndex(true))
+	content, _, err = ProcessProject([]string{filepath.Join(dir, "c.go")}, config)
+	if err != nil {
+		t.Fatalf("ProcessProject failed: %v", err)
+	}
+	if strings.Contains(content, "todo-index") {
+		t.Errorf("content has an empty index:\n%s", content)
+	}
+}
@@ -0,0 +1,114 @@
+package handoff
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// TokenCounter estimates the number of tokens in text written to it in pieces,
+// using the same whitespace-based estimate as the statistics in Stats. A word
+// split across two writes is counted once, and so is a multi-byte character.
+//
+// A counter created with a limit reports once the estimate passes it, so callers
+// enforcing a token budget can stop feeding content as soon as it is exceeded
+// instead of building the over-budget text first. The zero value is ready to
+// use and has no limit.
+type TokenCounter struct {
+	limit   int
+	tokens  int
+	inToken bool
+
+	// partial holds the leading bytes of a multi-byte character cut off by the
+	// end of the previous write
+	partial []byte
+}
+
+// NewTokenCounter returns a counter that reports Exceeded once more than limit
+// tokens have been written. A limit of zero or less disables it.
+func NewTokenCounter(limit int) *TokenCounter {
+	return &TokenCounter{limit: limit}
+}
+
+// Write counts the tokens in p. It implements io.Writer and never fails, so
+// content can be copied or formatted straight into the counter.
+func (c *TokenCounter) Write(p []byte) (int, error) {
+	n := len(p)
+	if len(c.partial) > 0 {
+		for len(p) > 0 && !utf8.FullRune(c.partial) {
+			c.partial = append(c.partial, p[0])
+			p = p[1:]
+		}
+		if !utf8.FullRune(c.partial) {
+			return n, nil
+		}
+		r, _ := utf8.DecodeRune(c.partial)
+		c.partial = c.partial[:0]
+		c.addRune(r)
+	}
+
+	for len(p) > 0 {
+		if !utf8.FullRune(p) {
+			c.partial = append(c.partial, p...)
+			break
+		}
+		r, size := utf8.DecodeRune(p)
+		c.addRune(r)
+		p = p[size:]
+	}
+	return n, nil
+}
+
+// WriteString counts the tokens in s
+func (c *TokenCounter) WriteString(s string) (int, error) {
+	if len(c.partial) > 0 {
+		return c.Write([]byte(s))
+	}
+	for _, r := range s {
+		c.addRune(r)
+	}
+	return len(s), nil
+}
+
+// addRune advances the count by one character
+func (c *TokenCounter) addRune(r rune) {
+	if unicode.IsSpace(r) {
+		if c.inToken {
+			c.tokens++
+			c.inToken = false
+		}
+	} else {
+		c.inToken = true
+	}
+}
+
+// Tokens returns the estimated number of tokens written so far, including a
+// word still in progress
+func (c *TokenCounter) Tokens() int {
+	if c.inToken || len(c.partial) > 0 {
+		return c.tokens + 1
+	}
+	return c.tokens
+}
+
+// Exceeded reports whether more tokens than the limit have been written
+func (c *TokenCounter) Exceeded() bool {
+	return c.limit > 0 && c.Tokens() > c.limit
+}
+
+// Reset clears the count, keeping the limit
+func (c *TokenCounter) Reset() {
+	*c = TokenCounter{limit: c.limit, partial: c.partial[:0]}
+}
+
+// exceedsTokens reports whether text holds more than limit estimated tokens,
+// scanning only as far as needed to decide (internal helper)
+func exceedsTokens(text string, limit int) bool {
+	var c TokenCounter
+	for _, r := range text {
+		c.addRune(r)
+		if c.Tokens() > limit {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,138 @@
+package handoff
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestTokenCounter tests that counting text in pieces matches counting it whole
+func TestTokenCounter(t *testing.T) {
+	texts := []string{
+		"",
+		"one",
+		"one two  three\n",
+		"  leading and trailing  ",
+		"café naïve résumé",
+		"split\u2003by ideographic\u3000spaces",
+		"invalid \xff bytes\xe2",
+	}
+
+	for _, text := range texts {
+		want := estimateTokenCount(text)
+		for size := 1; size <= 4; size++ {
+			var c TokenCounter
+			for i := 0; i < len(text); i += size {
+				if _, err := c.Write([]byte(text[i:min(i+size, len(text))])); err != nil {
+					t.Fatalf("Write failed: %v", err)
+				}
+			}
+			if got := c.Tokens(); got != want {
+				t.Errorf("%q in %d-byte writes: Tokens() = %d, want %d", text, size, got, want)
+			}
+		}
+	}
+
+	// Words split across writes are counted once
+	var c TokenCounter
+	_, _ = io.Copy(&c, strings.NewReader("hello world"))
+	_, _ = c.WriteString("wide")
+	_, _ = c.WriteString(" web")
+	if got := c.Tokens(); got != 3 {
+		t.Errorf("Tokens() = %d, want 3", got)
+	}
+}
+
+// TestTokenCounterLimit tests that Exceeded reports once the limit is passed
+func TestTokenCounterLimit(t *testing.T) {
+	c := NewTokenCounter(2)
+	_, _ = c.WriteString("one two")
+	if c.Exceeded() {
+		t.Error("Exceeded() = true at the limit, want false")
+	}
+	_, _ = c.WriteString(" t")
+	if !c.Exceeded() {
+		t.Error("Exceeded() = false past the limit, want true")
+	}
+
+	c.Reset()
+	if c.Tokens() != 0 || c.Exceeded() {
+		t.Errorf("after Reset, Tokens() = %d and Exceeded() = %v, want 0 and false", c.Tokens(), c.Exceeded())
+	}
+	_, _ = c.WriteString("a b c")
+	if !c.Exceeded() {
+		t.Error("Reset dropped the limit")
+	}
+
+	var unlimited TokenCounter
+	_, _ = unlimited.WriteString(strings.Repeat("word ", 1000))
+	if unlimited.Exceeded() {
+		t.Error("zero TokenCounter reported Exceeded, want no limit")
+	}
+}
+
+// TestExceedsTokens tests the early-exit token limit check
+func TestExceedsTokens(t *testing.T) {
+	testCases := []struct {
+		text  string
+		limit int
+		want  bool
+	}{
+		{"", 0, false},
+		{"one", 0, true},
+		{"one two", 2, false},
+		{"one two ", 2, false},
+		{"one two three", 2, true},
+	}
+
+	for _, tc := range testCases {
+		if got := exceedsTokens(tc.text, tc.limit); got != tc.want {
+			t.Errorf("exceedsTokens(%q, %d) = %v, want %v", tc.text, tc.limit, got, tc.want)
+		}
+	}
+}
+
+// TestProcessPathsDropsOversizedFiles tests that a file larger than the whole
+// budget is skipped without displacing files that fit alongside the rest
+func TestProcessPathsDropsOversizedFiles(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"main.go":      strings.Repeat("code ", 20),
+		"main_test.go": strings.Repeat("test ", 10),
+		"huge.go":      strings.Repeat("huge ", 500),
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	var done, skipped []string
+	config := NewConfig(WithGitClient(NewMockGitClient(false)), WithMaxTokens(100),
+		WithHooks(Hooks{
+			OnFileDone:    func(path string, _ FileStat) { done = append(done, filepath.Base(path)) },
+			OnFileSkipped: func(path string, _ SkipReason) { skipped = append(skipped, filepath.Base(path)) },
+		}))
+	content, stats, err := processPaths([]string{dir}, config, NewLogger(false))
+	if err != nil {
+		t.Fatalf("processPaths failed: %v", err)
+	}
+
+	if strings.Contains(content, "huge.go") || !strings.Contains(content, "main_test.go") {
+		t.Errorf("expected huge.go to be dropped and the rest kept, got:\n%s", content)
+	}
+	if stats.FilesProcessed != 2 || stats.FilesTrimmed != 0 {
+		t.Errorf("FilesProcessed = %d, FilesTrimmed = %d, want 2 and 0", stats.FilesProcessed, stats.FilesTrimmed)
+	}
+	if stats.Skipped[SkipOverBudget] != 1 || len(stats.SkippedFiles) != 1 || filepath.Base(stats.SkippedFiles[0].Path) != "huge.go" {
+		t.Errorf("Skipped = %v, SkippedFiles = %v, want huge.go skipped as over budget", stats.Skipped, stats.SkippedFiles)
+	}
+	if strings.Join(done, ",") != "main.go,main_test.go" {
+		t.Errorf("OnFileDone called for %v, want main.go and main_test.go", done)
+	}
+	if strings.Join(skipped, ",") != "huge.go" {
+		t.Errorf("OnFileSkipped called for %v, want huge.go", skipped)
+	}
+}
@@ -0,0 +1,94 @@
+package handoff
+
+import (
+	"bytes"
+	"unicode/utf8"
+)
+
+// Transformer rewrites a file's content after it is read and filtered and
+// before it is formatted. Transformers run in the order they were added and
+// must be safe for concurrent use.
+type Transformer func(path string, content []byte) []byte
+
+// WithTransformer adds a content transformer to the processing pipeline.
+// Transformers are applied to every processed file in the order they are
+// added, so options that add built-in transformers compose with custom ones.
+func WithTransformer(transformer Transformer) Option {
+	return func(c *Config) {
+		if transformer != nil {
+			c.transformers = append(c.transformers, transformer)
+		}
+	}
+}
+
+// WithExpandTabs adds a transformer that replaces tabs with spaces up to the
+// next multiple of width columns, so tab-indented files render consistently
+// and token estimates don't depend on editor settings. A width of zero or
+// less adds nothing.
+func WithExpandTabs(width int) Option {
+	if width <= 0 {
+		return func(*Config) {}
+	}
+	return WithTransformer(func(_ string, content []byte) []byte {
+		return expandTabs(content, width)
+	})
+}
+
+// WithStripTrailingWhitespace adds a transformer that removes trailing spaces,
+// tabs, and carriage returns from every line, which removes noise from files
+// authored on Windows and keeps regenerated output stable. Note that this also
+// removes Markdown hard line breaks written as two trailing spaces.
+func WithStripTrailingWhitespace(strip bool) Option {
+	if !strip {
+		return func(*Config) {}
+	}
+	return WithTransformer(func(_ string, content []byte) []byte {
+		return stripTrailingWhitespace(content)
+	})
+}
+
+// applyTransformers runs the configured transformers over content (internal helper)
+func applyTransformers(path string, content []byte, transformers []Transformer) []byte {
+	for _, transform := range transformers {
+		content = transform(path, content)
+	}
+	return content
+}
+
+// expandTabs replaces each tab with spaces up to the next tab stop, counting
+// columns in characters (internal helper)
+func expandTabs(content []byte, width int) []byte {
+	if !bytes.ContainsRune(content, '\t') {
+		return content
+	}
+
+	out := make([]byte, 0, len(content)+len(content)/8)
+	column := 0
+	for len(content) > 0 {
+		r, size := utf8.DecodeRune(content)
+		switch r {
+		case '\t':
+			spaces := width - column%width
+			out = append(out, bytes.Repeat([]byte{' '}, spaces)...)
+			column += spaces
+		case '\n':
+			out = append(out, '\n')
+			column = 0
+		default:
+			out = append(out, content[:size]...)
+			column++
+		}
+		content = content[size:]
+	}
+	return out
+}
+
+// stripTrailingWhitespace trims spaces, tabs, and carriage returns from the end
+// of each line, keeping line breaks (internal helper)
+func stripTrailingWhitespace(content []byte) []byte {
+	lines := bytes.Split(content, []byte("\n"))
+	for i, line := range lines {
+		lines[i] = bytes.TrimRight(line, " \t\r")
+	}
+	return bytes.Join(lines, []byte("\n"))
+}
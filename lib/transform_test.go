@@ -0,0 +1,75 @@
+package handoff
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestExpandTabs tests expanding tabs to the next tab stop
+func TestExpandTabs(t *testing.T) {
+	testCases := []struct {
+		content string
+		width   int
+		want    string
+	}{
+		{"no tabs", 4, "no tabs"},
+		{"\tx", 4, "    x"},
+		{"ab\tc", 4, "ab  c"},
+		{"abcd\te", 4, "abcd    e"},
+		{"\t\tx\n\ty", 2, "    x\n  y"},
+		{"é\tx", 4, "é   x"},
+	}
+
+	for _, tc := range testCases {
+		if got := string(expandTabs([]byte(tc.content), tc.width)); got != tc.want {
+			t.Errorf("expandTabs(%q, %d) = %q, want %q", tc.content, tc.width, got, tc.want)
+		}
+	}
+}
+
+// TestStripTrailingWhitespace tests trimming spaces, tabs, and carriage returns per line
+func TestStripTrailingWhitespace(t *testing.T) {
+	testCases := map[string]string{
+		"clean\nlines\n":           "clean\nlines\n",
+		"crlf\r\nendings\r\n":      "crlf\nendings\n",
+		"spaces  \ntabs\t\t\nend ": "spaces\ntabs\nend",
+		"  \r\n\tindent kept  \n":  "\n\tindent kept\n",
+		"mid\rline\n":              "mid\rline\n",
+	}
+
+	for content, want := range testCases {
+		if got := string(stripTrailingWhitespace([]byte(content))); got != want {
+			t.Errorf("stripTrailingWhitespace(%q) = %q, want %q", content, got, want)
+		}
+	}
+}
+
+// TestTransformers tests that built-in and custom transformers run in order
+func TestTransformers(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte("func main() {\n\treturn\n}\n"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	var seen string
+	config := NewConfig(
+		WithGitClient(NewMockGitClient(false)),
+		WithExpandTabs(2),
+		WithTransformer(func(path string, content []byte) []byte {
+			seen = path
+			return []byte(strings.ToUpper(string(content)))
+		}),
+	)
+	content, _, err := ProcessProject([]string{dir}, config)
+	if err != nil {
+		t.Fatalf("ProcessProject failed: %v", err)
+	}
+	if !strings.Contains(content, "FUNC MAIN() {\n  RETURN\n}") {
+		t.Errorf("expected tab-expanded, upper-cased content, got:\n%s", content)
+	}
+	if seen != filepath.Join(dir, "main.go") {
+		t.Errorf("transformer got path %q, want the file path", seen)
+	}
+}
@@ -0,0 +1,81 @@
+package handoff
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// WithNormalizeUnicode sets whether content is normalized to Unicode NFC,
+// composing base letters and combining marks into precomposed characters.
+// Files saved with decomposed text, as macOS tooling often produces, then match
+// content filters and grep and count the same as their composed equivalents.
+// Content that is not valid UTF-8 is left unchanged.
+func WithNormalizeUnicode(normalize bool) Option {
+	return func(c *Config) {
+		c.NormalizeUnicode = normalize
+	}
+}
+
+// WithEscapePaths sets whether displayed paths percent-encode characters that
+// would make path tags ambiguous or unreadable downstream: control characters,
+// bidirectional formatting characters, bytes that aren't valid UTF-8, and the
+// percent sign itself, so "a\nb.go" is shown as "a%0Ab.go". Displayed paths
+// are always composed to NFC, with or without escaping.
+func WithEscapePaths(escape bool) Option {
+	return func(c *Config) {
+		c.EscapePaths = escape
+	}
+}
+
+// cleanPath composes a displayed path to NFC and, when escape is set,
+// percent-encodes the characters WithEscapePaths describes (internal helper)
+func cleanPath(path string, escape bool) string {
+	if hasNonASCII([]byte(path)) {
+		path = string(normalizeNFC([]byte(path)))
+	}
+	if !escape || (!strings.ContainsFunc(path, needsEscape) && utf8.ValidString(path)) {
+		return path
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(path); {
+		r, size := utf8.DecodeRuneInString(path[i:])
+		if (r == utf8.RuneError && size == 1) || needsEscape(r) {
+			for _, c := range []byte(path[i : i+size]) {
+				fmt.Fprintf(&b, "%%%02X", c)
+			}
+		} else {
+			b.WriteString(path[i : i+size])
+		}
+		i += size
+	}
+	return b.String()
+}
+
+// needsEscape reports whether WithEscapePaths encodes a character (internal helper)
+func needsEscape(r rune) bool {
+	return r == '%' || unicode.IsControl(r) || unicode.Is(unicode.Bidi_Control, r)
+}
+
+// normalizeNFC normalizes content to NFC, leaving ASCII and content that is
+// not valid UTF-8 unchanged (internal helper)
+func normalizeNFC(content []byte) []byte {
+	if !hasNonASCII(content) || !utf8.Valid(content) {
+		return content
+	}
+	return norm.NFC.Bytes(content)
+}
+
+// hasNonASCII reports whether content has any byte outside ASCII (internal helper)
+func hasNonASCII(content []byte) bool {
+	for _, b := range content {
+		if b >= utf8.RuneSelf {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,119 @@
+package handoff
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// TestNormalizeNFC tests composing decomposed text to NFC
+func TestNormalizeNFC(t *testing.T) {
+	testCases := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"ascii", "plain text\n", "plain text\n"},
+		{"composed", "caf\u00e9\n", "caf\u00e9\n"},
+		{"acute", "cafe\u0301", "caf\u00e9"},
+		{"two marks", "e\u0323\u0302", "\u1ec7"},
+		{"marks out of order", "e\u0302\u0323", "\u1ec7"},
+		{"blocked mark", "a\u0301\u0301", "\u00e1\u0301"},
+		{"no composite", "q\u0301", "q\u0301"},
+		{"hangul", "\u1112\u1161\u11ab\u1100\u1173\u11af", "\ud55c\uae00"},
+		{"exclusion", "\u0915\u093c", "\u0915\u093c"},
+		{"precomposed with mark", "\u00e1\u0323", "\u1ea1\u0301"},
+		{"angstrom sign", "\u212b", "\u00c5"},
+		{"ohm sign", "\u2126", "\u03a9"},
+		{"invalid utf8", "e\u0301\xff", "e\u0301\xff"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := string(normalizeNFC([]byte(tc.content))); got != tc.want {
+				t.Errorf("normalizeNFC(%q) = %q, want %q", tc.content, got, tc.want)
+			}
+		})
+	}
+}
+
+// TestNormalizeUnicodeFilters tests that normalized content matches filters written in composed form
+func TestNormalizeUnicodeFilters(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("Café menu\n"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	config := NewConfig(
+		WithGitClient(NewMockGitClient(false)),
+		WithIncludeContentRegex(regexp.MustCompile("Caf\u00e9")),
+	)
+	if _, _, err := ProcessProject([]string{dir}, config); err == nil {
+		t.Errorf("expected decomposed content not to match without normalization")
+	}
+
+	config = NewConfig(
+		WithGitClient(NewMockGitClient(false)),
+		WithIncludeContentRegex(regexp.MustCompile("Caf\u00e9")),
+		WithNormalizeUnicode(true),
+	)
+	content, _, err := ProcessProject([]string{dir}, config)
+	if err != nil {
+		t.Fatalf("ProcessProject failed: %v", err)
+	}
+	if !strings.Contains(content, "Caf\u00e9 menu") {
+		t.Errorf("expected composed content, got:\n%s", content)
+	}
+}
+
+// TestCleanPath tests normalizing and escaping displayed paths
+func TestCleanPath(t *testing.T) {
+	testCases := []struct {
+		name   string
+		path   string
+		escape bool
+		want   string
+	}{
+		{"plain", "src/main.go", true, "src/main.go"},
+		{"decomposed", "cafe\u0301.go", false, "caf\u00e9.go"},
+		{"control kept without escaping", "a\nb.go", false, "a\nb.go"},
+		{"control", "a\nb.go", true, "a%0Ab.go"},
+		{"percent", "100%.md", true, "100%25.md"},
+		{"bidi override", "evil\u202eog.go", true, "evil%E2%80%AEog.go"},
+		{"invalid UTF-8", "bad\xff.go", true, "bad%FF.go"},
+		{"composed and escaped", "cafe\u0301\t.go", true, "caf\u00e9%09.go"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := cleanPath(tc.path, tc.escape); got != tc.want {
+				t.Errorf("cleanPath(%q, %v) = %q, want %q", tc.path, tc.escape, got, tc.want)
+			}
+		})
+	}
+}
+
+// TestWithEscapePaths tests that path tags show cleaned paths while files are
+// still read by their names on disk
+func TestWithEscapePaths(t *testing.T) {
+	dir := t.TempDir()
+	name := "cafe\u0301\tmenu.txt"
+	if err := os.WriteFile(filepath.Join(dir, name), []byte("soup\n"), 0644); err != nil {
+		t.Skipf("Cannot create a file with this name: %v", err)
+	}
+
+	config := NewConfig(WithGitClient(NewMockGitClient(false)), WithRoot(dir), WithEscapePaths(true))
+	content, stats, err := ProcessProject([]string{name}, config)
+	if err != nil {
+		t.Fatalf("ProcessProject failed: %v", err)
+	}
+	want := "caf\u00e9%09menu.txt"
+	if !strings.Contains(content, "<"+want+">\n```\nsoup\n") {
+		t.Errorf("content is missing the cleaned path tag:\n%s", content)
+	}
+	if len(stats.IncludedFiles) != 1 || stats.IncludedFiles[0] != want {
+		t.Errorf("IncludedFiles = %q, want [%q]", stats.IncludedFiles, want)
+	}
+}
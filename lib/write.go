@@ -0,0 +1,78 @@
+package handoff
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// ErrFileExists is returned when WriteToFile is called with overwrite=false and the file already exists
+var ErrFileExists = errors.New("file already exists and overwrite is not allowed")
+
+// WriteToFile writes the content to a file at the specified path.
+// This is a convenience function for saving the output of ProcessProject
+// directly to a file. The file is created with 0644 permissions.
+// Parent directories are automatically created if they don't exist.
+//
+// By default, it will not overwrite existing files unless overwrite is set to true.
+// If the file exists and overwrite is false, it returns ErrFileExists.
+//
+// While writing, it holds an advisory lock (a "<path>.lock" file) so that
+// concurrent handoff runs targeting the same file fail fast with
+// ErrOutputLocked instead of interleaving their writes.
+//
+// Parameters:
+//   - content: The content to write to the file
+//   - filePath: The path where the file should be created
+//   - overwrite: If true, existing files will be overwritten; if false, returns an error when the file exists
+//
+// Returns:
+//   - An error if the file cannot be written (e.g., due to directory creation failure,
+//     permissions issues, file already exists with overwrite=false, the file is
+//     locked by another writer, or other I/O errors)
+func WriteToFile(content, filePath string, overwrite bool) error {
+	// Create parent directories if they don't exist
+	dirPath := filepath.Dir(filePath)
+	if err := os.MkdirAll(dirPath, 0755); err != nil {
+		return fmt.Errorf("failed to create parent directories for %q: %w", filePath, err)
+	}
+
+	// Hold the lock across the existence check and the write so concurrent
+	// runs cannot interleave
+	unlock, err := lockOutputFile(filePath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	// Check if file exists and handle overwrite flag
+	if !overwrite {
+		exists, err := FileExists(filePath)
+		if err != nil {
+			return err
+		}
+		if exists {
+			return fmt.Errorf("%w: %s", ErrFileExists, filePath)
+		}
+	}
+
+	// Write the file
+	if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to write to file %q: %w", filePath, err)
+	}
+	return nil
+}
+
+// FileExists reports whether a file exists at path. An error is returned
+// when existence can't be determined, such as when permission is denied.
+func FileExists(path string) (bool, error) {
+	_, err := os.Stat(path)
+	if err == nil {
+		return true, nil
+	}
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	return false, fmt.Errorf("cannot check if file %q exists: %w", path, err)
+}
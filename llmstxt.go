@@ -0,0 +1,55 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	handoff "github.com/phrazzld/handoff/lib"
+)
+
+// runLLMsTxt implements "handoff llms-txt": it writes an llms.txt index of the
+// collected files and an llms-full.txt with their full content to -output-dir.
+func runLLMsTxt(args []string) {
+	var outputDir, title string
+	flag.StringVar(&outputDir, "output-dir", ".", "Directory to write "+handoff.LLMsTxtFileName+" and "+handoff.LLMsFullTxtFileName+" to")
+	flag.StringVar(&title, "title", "", "Project name heading the index (default: the first path's directory name)")
+
+	config, cli := parseConfigArgs(args)
+	logger := handoff.NewLogger(config.Verbose)
+
+	if flag.NArg() < 1 {
+		logger.Error("usage: %s llms-txt [-output-dir dir] [options] path1 [path2 ...]", os.Args[0])
+		os.Exit(1)
+	}
+
+	result, stats, err := handoff.GenerateLLMsTxt(flag.Args(), config, title)
+	if err != nil {
+		logger.Error("Failed to process project: %v", err)
+		os.Exit(1)
+	}
+
+	if cli.dryRun {
+		out, done := startPreview(cli.noPager)
+		fmt.Fprintf(out, "### DRY RUN: %s ###\n", handoff.LLMsTxtFileName)
+		fmt.Fprintln(out, result.Index)
+		fmt.Fprintf(out, "### DRY RUN: %s ###\n", handoff.LLMsFullTxtFileName)
+		fmt.Fprintln(out, result.Full)
+		done()
+		return
+	}
+
+	for _, file := range []struct{ name, content string }{
+		{handoff.LLMsTxtFileName, result.Index},
+		{handoff.LLMsFullTxtFileName, result.Full},
+	} {
+		path := filepath.Join(outputDir, file.name)
+		if err := handoff.WriteToFile(file.content, path, cli.force); err != nil {
+			logger.Error("Failed to write to file %s: %v", path, err)
+			os.Exit(1)
+		}
+	}
+	logger.Info("Wrote %s and %s for %d files (~%d tokens) to %s",
+		handoff.LLMsTxtFileName, handoff.LLMsFullTxtFileName, stats.FilesProcessed, stats.Tokens, outputDir)
+}
@@ -1,217 +1,200 @@
 package main
 
 import (
-	"errors"
 	"flag"
 	"fmt"
 	"os"
-	"os/exec"
-	"path/filepath"
+	"sort"
 	"strings"
 
 	handoff "github.com/phrazzld/handoff/lib"
 )
 
-// ErrClipboardFailed is returned when all clipboard commands fail
-var ErrClipboardFailed = errors.New("clipboard commands failed")
+// envFlagsName is the environment variable holding default flags, which are
+// parsed before the command-line arguments
+const envFlagsName = "HANDOFF_FLAGS"
 
 // parseConfig defines and parses command-line flags, processes include/exclude extensions,
 // and returns a populated Config struct from the library package.
-// It also returns the CLI-specific options as separate values (output file path, force flag, and dry run flag).
-func parseConfig() (*handoff.Config, string, bool, bool) {
-	// Define flags for CLI use
-	var (
-		verbose         bool
-		include         string
-		exclude         string
-		excludeNames    string
-		format          = "<{path}>\n```\n{content}\n```\n</{path}>\n\n"
-		dryRun          bool
-		outputFile      string
-		force           bool
-		ignoreGitignore bool
-	)
-
-	// Define flag bindings
-	flag.BoolVar(&verbose, "verbose", false, "Enable verbose output")
-	flag.BoolVar(&dryRun, "dry-run", false, "Preview what would be copied without actually copying")
-	flag.StringVar(&include, "include", "", "Comma-separated list of file extensions to include (e.g., .txt,.go)")
-	flag.StringVar(&exclude, "exclude", "", "Comma-separated list of file extensions to exclude (e.g., .exe,.bin)")
-	flag.StringVar(&excludeNames, "exclude-names", "", "Comma-separated list of file names to exclude (e.g., package-lock.json,yarn.lock)")
-	flag.StringVar(&format, "format", format, "Custom format for output. Use {path} and {content} as placeholders")
-	flag.StringVar(&outputFile, "output", "", "Write output to the specified file instead of clipboard (e.g., HANDOFF.md)")
-	flag.BoolVar(&force, "force", false, "Allow overwriting existing files when using -output flag")
-	flag.BoolVar(&ignoreGitignore, "ignore-gitignore", false, "Process files even if they are gitignored (bypasses .gitignore rules; default: false)")
-
-	// Parse command-line flags
-	flag.Parse()
-
-	// Create config with functional options based on CLI flags
-	var options []handoff.Option
-
-	if verbose {
-		options = append(options, handoff.WithVerbose(verbose))
-	}
+// It also returns the CLI-specific options that control where the output goes.
+func parseConfig() (*handoff.Config, cliOptions) {
+	return parseConfigArgs(os.Args[1:])
+}
 
-	if include != "" {
-		options = append(options, handoff.WithInclude(include))
-	}
+// optionFlags is a group of flags that converts its values into library options
+type optionFlags interface {
+	options() ([]handoff.Option, error)
+}
 
-	if exclude != "" {
-		options = append(options, handoff.WithExclude(exclude))
+// parseConfigArgs is parseConfig for an explicit argument list, letting
+// subcommands register extra flags before the shared flags are parsed.
+func parseConfigArgs(args []string) (*handoff.Config, cliOptions) {
+	var verbose bool
+	var configFile, profile string
+	flag.BoolVar(&verbose, "verbose", false, "Enable verbose output")
+	flag.StringVar(&configFile, "config", "", "Load settings from the specified YAML or JSON config file (default: "+strings.Join(handoff.ConfigFileNames, ", ")+" in the working directory, whichever is found first)")
+	flag.StringVar(&profile, "profile", "", "Apply the named profile from the config file over its other settings")
+	cli := registerCLIFlags()
+	source := registerSourceFlags()
+	groups := []optionFlags{
+		source,
+		registerFilterFlags(),
+		registerGitFlags(),
+		registerSelectFlags(),
+		registerContentFlags(),
+		registerOutputFlags(),
+		registerBudgetFlags(),
+	}
+	parseFlagArgs(args)
+
+	// Start with options from the config file; CLI flags are applied afterwards
+	// so they take precedence over file settings
+	fileConfig, err := handoff.LoadProjectConfig(configFile, source.root)
+	if err == nil && profile != "" {
+		fileConfig, err = fileConfig.Profile(profile)
 	}
-
-	if excludeNames != "" {
-		options = append(options, handoff.WithExcludeNames(excludeNames))
+	if err != nil {
+		handoff.NewLogger(verbose).Error("%v", err)
+		os.Exit(1)
 	}
+	options := fileConfig.Options()
 
-	if format != "" {
-		options = append(options, handoff.WithFormat(format))
+	// The clipboard command is a CLI setting; the flag overrides the file
+	if cli.clipboardCmd == "" {
+		cli.clipboardCmd = fileConfig.ClipboardCmd
 	}
 
-	if ignoreGitignore {
-		options = append(options, handoff.WithIgnoreGitignore(ignoreGitignore))
+	if verbose {
+		options = append(options, handoff.WithVerbose(verbose))
 	}
-
-	config := handoff.NewConfig(options...)
-
-	return config, outputFile, force, dryRun
-}
-
-// copyToClipboard copies text to the system clipboard with enhanced error reporting.
-func copyToClipboard(text string) error {
-	var errors []string
-
-	// Try pbcopy (macOS)
-	if _, err := exec.LookPath("pbcopy"); err == nil {
-		cmd := exec.Command("pbcopy")
-		cmd.Stdin = strings.NewReader(text)
-		if err := cmd.Run(); err == nil {
-			return nil // Success
-		} else {
-			errors = append(errors, fmt.Sprintf("pbcopy failed: %v", err))
-		}
-	} else {
-		errors = append(errors, "pbcopy not found")
-	}
-
-	// Try xclip (X11/Linux)
-	if _, err := exec.LookPath("xclip"); err == nil {
-		cmd := exec.Command("xclip", "-selection", "clipboard")
-		cmd.Stdin = strings.NewReader(text)
-		if err := cmd.Run(); err == nil {
-			return nil // Success
-		} else {
-			errors = append(errors, fmt.Sprintf("xclip failed: %v", err))
-		}
-	} else {
-		errors = append(errors, "xclip not found")
-	}
-
-	// Try wl-copy (Wayland/Linux)
-	if _, err := exec.LookPath("wl-copy"); err == nil {
-		cmd := exec.Command("wl-copy")
-		cmd.Stdin = strings.NewReader(text)
-		if err := cmd.Run(); err == nil {
-			return nil // Success
-		} else {
-			errors = append(errors, fmt.Sprintf("wl-copy failed: %v", err))
+	for _, group := range groups {
+		groupOptions, err := group.options()
+		if err != nil {
+			handoff.NewLogger(verbose).Error("%v", err)
+			os.Exit(1)
 		}
-	} else {
-		errors = append(errors, "wl-copy not found")
+		options = append(options, groupOptions...)
 	}
-
-	// If we get here, all clipboard commands failed
-	return fmt.Errorf("%w: %s", ErrClipboardFailed, strings.Join(errors, "; "))
+	return handoff.NewConfig(options...), *cli
 }
 
-// resolveOutputPath converts a relative path to an absolute path.
-// It returns the absolute path and any error encountered.
-func resolveOutputPath(path string) (string, error) {
-	if path == "" {
-		return "", fmt.Errorf("output path cannot be empty")
-	}
-
-	absPath, err := filepath.Abs(path)
+// parseFlagArgs parses the command-line flags after any personal defaults from
+// the environment, so flags given on the command line take precedence; flags
+// may follow the paths and have short aliases, as in GNU tools
+func parseFlagArgs(args []string) {
+	envArgs, err := splitFlags(os.Getenv(envFlagsName))
 	if err != nil {
-		return "", fmt.Errorf("failed to determine absolute path for %q: %w", path, err)
+		handoff.NewLogger(false).Error("Invalid %s: %v", envFlagsName, err)
+		os.Exit(1)
 	}
-
-	return absPath, nil
+	registerShortFlags(flag.CommandLine)
+	flag.Usage = printUsage
+	_ = flag.CommandLine.Parse(permuteArgs(flag.CommandLine, append(envArgs, args...)))
 }
 
-// checkFileExists checks if a file exists at the specified path.
-// It returns true if the file exists, false otherwise.
-// An error is returned if there's a problem checking file existence (e.g., permission issues).
-func checkFileExists(path string) (bool, error) {
-	_, err := os.Stat(path)
-	if err == nil {
-		// File exists
-		return true, nil
-	}
-	if os.IsNotExist(err) {
-		// File does not exist
-		return false, nil
-	}
-	// Some other error occurred (e.g., permission denied)
-	return false, fmt.Errorf("cannot check if file %q exists: %w", path, err)
+// loadConfigFileOptions loads functional options from a config file,
+// resolving path as handoff.LoadProjectConfig does.
+func loadConfigFileOptions(path, root string) ([]handoff.Option, error) {
+	fileConfig, err := handoff.LoadProjectConfig(path, root)
+	if err != nil {
+		return nil, err
+	}
+	return fileConfig.Options(), nil
 }
 
-// Note: processPathUsingLib function was removed as it was unused after refactoring
-
-// logStatisticsUsingLib logs statistics about the processed content
+// logStatistics logs statistics about the processed content
 // using the Stats struct returned by ProcessProject
-func logStatisticsUsingLib(stats handoff.Stats, config *handoff.Config, logger *handoff.Logger) {
+func logStatistics(stats handoff.Stats, config *handoff.Config, logger *handoff.Logger) {
 	// Log statistics
 	logger.Info("Handoff complete:")
 	logger.Info("- Files: %d/%d", stats.FilesProcessed, stats.FilesTotal)
+	if stats.FilesTrimmed > 0 {
+		logger.Info("- Files trimmed for token budget: %d", stats.FilesTrimmed)
+	}
+	if len(stats.Skipped) > 0 {
+		logger.Info("- Files skipped: %s", skippedSummary(stats.Skipped))
+	}
 	logger.Info("- Lines: %d", stats.Lines)
 	logger.Info("- Characters: %d", stats.Chars)
 	logger.Info("- Estimated tokens: %d", stats.Tokens)
 
 	if config.Verbose {
+		for _, file := range stats.SkippedFiles {
+			logger.Verbose("Skipped %s (%s)", file.Path, file.Reason)
+		}
 		logger.Verbose("Processed files successfully")
 	}
 }
 
-func main() {
-	// Parse command-line flags and get configuration
-	config, outputFile, force, dryRun := parseConfig()
-	logger := handoff.NewLogger(config.Verbose)
-
-	// Resolve output path if specified
-	var absOutputPath string
-	if outputFile != "" {
-		var err error
-		absOutputPath, err = resolveOutputPath(outputFile)
-		if err != nil {
-			logger.Error("Invalid output path: %v", err)
-			os.Exit(1)
+// skippedSummary describes skipped file counts, most common reason first,
+// e.g. "3 (2 filtered, 1 binary)"
+func skippedSummary(skipped map[handoff.SkipReason]int) string {
+	reasons := make([]handoff.SkipReason, 0, len(skipped))
+	total := 0
+	for reason, count := range skipped {
+		reasons = append(reasons, reason)
+		total += count
+	}
+	sort.Slice(reasons, func(i, j int) bool {
+		if skipped[reasons[i]] != skipped[reasons[j]] {
+			return skipped[reasons[i]] > skipped[reasons[j]]
 		}
-		logger.Verbose("Output will be written to: %s", absOutputPath)
+		return reasons[i] < reasons[j]
+	})
 
-		// Check if the file exists and handle according to force flag
-		exists, err := checkFileExists(absOutputPath)
-		if err != nil {
-			logger.Error("Error checking output file: %v", err)
-			os.Exit(1)
-		}
+	parts := make([]string, len(reasons))
+	for i, reason := range reasons {
+		parts[i] = fmt.Sprintf("%d %s", skipped[reason], reason)
+	}
+	return fmt.Sprintf("%d (%s)", total, strings.Join(parts, ", "))
+}
 
-		if exists && !force {
-			logger.Error("Output file %s already exists. Use -force flag to overwrite.", absOutputPath)
-			os.Exit(1)
-		} else if exists && force {
-			logger.Verbose("Output file %s exists, will be overwritten because -force flag is set", absOutputPath)
+// subcommands maps each subcommand name to the function running it with the
+// arguments that follow the name
+var subcommands = map[string]func(args []string){
+	"ask":      runAsk,
+	"plan":     runPlan,
+	"llms-txt": runLLMsTxt,
+	"init":     runInit,
+	"doctor":   runDoctor,
+	"diff":     runDiff,
+	"serve":    runServe,
+}
+
+func main() {
+	// Dispatch subcommands; anything else is treated as flags and paths
+	if len(os.Args) > 1 {
+		if run, ok := subcommands[os.Args[1]]; ok {
+			run(os.Args[2:])
+			return
 		}
 	}
 
+	// Parse command-line flags and get configuration
+	config, cli := parseConfig()
+	logger := handoff.NewLogger(config.Verbose)
+
+	// A preflight check only reports problems, so none of the output checks apply
+	if cli.preflight {
+		runPreflight(flag.Args(), config, logger)
+		return
+	}
+
+	// Check the output target before doing any work
+	target := prepareOutput(cli, config, logger)
+
 	// Check if we have any paths to process
 	if flag.NArg() < 1 {
 		logger.Error("usage: %s [options] path1 [path2 ...]", os.Args[0])
-		flag.PrintDefaults()
+		printFlags(flag.CommandLine)
 		os.Exit(1)
 	}
 
+	if config.ChunkTokens > 0 {
+		writeParts(flag.Args(), config, cli, target.path, logger)
+		return
+	}
+
 	// Process paths and get content
 	formattedContent, stats, err := handoff.ProcessProject(flag.Args(), config)
 	if err != nil {
@@ -219,29 +202,11 @@ func main() {
 		os.Exit(1)
 	}
 
-	// Handle output based on precedence: dry-run > output file > clipboard
-	if dryRun {
-		// Highest precedence: dry-run mode
-		fmt.Println("### DRY RUN: Content that would be generated ###")
-		fmt.Println(formattedContent)
-		logger.Info("Dry run complete. No file written or clipboard modified.")
-	} else if outputFile != "" {
-		// Medium precedence: write to file
-		logger.Verbose("Writing content (%d bytes) to file: %s", len(formattedContent), absOutputPath)
-		if err := handoff.WriteToFile(formattedContent, absOutputPath, force); err != nil {
-			logger.Error("Failed to write to file %s: %v", absOutputPath, err)
-			os.Exit(1)
-		}
-		logger.Info("Output successfully written to %s", absOutputPath)
-	} else {
-		// Lowest precedence: copy to clipboard (default behavior)
-		if err := copyToClipboard(formattedContent); err != nil {
-			logger.Error("Failed to copy to clipboard: %v", err)
-			os.Exit(1)
-		}
-		logger.Info("Content successfully copied to clipboard.")
+	deliverOutput(formattedContent, stats, target, cli, config, logger)
+	if !cli.dryRun {
+		writeManifest(cli, stats, config, logger, formattedContent)
 	}
 
 	// Log statistics
-	logStatisticsUsingLib(stats, config, logger)
+	logStatistics(stats, config, logger)
 }
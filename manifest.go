@@ -0,0 +1,36 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	handoff "github.com/phrazzld/handoff/lib"
+)
+
+// checkManifestPath reports an error if the -manifest file exists and may not
+// be overwritten, so the check happens before any work is done
+func checkManifestPath(path string, force bool) error {
+	exists, err := handoff.FileExists(path)
+	if err != nil {
+		return fmt.Errorf("error checking manifest file: %w", err)
+	}
+	if exists && !force {
+		return fmt.Errorf("manifest file %s already exists (use -force to overwrite)", path)
+	}
+	return nil
+}
+
+// writeManifest writes the -manifest file describing the outputs, if one was
+// requested. A failure is fatal, since automation relying on the manifest
+// would otherwise read a stale one.
+func writeManifest(cli cliOptions, stats handoff.Stats, config *handoff.Config, logger *handoff.Logger, outputs ...string) {
+	if cli.manifest == "" {
+		return
+	}
+	manifest := handoff.NewManifest(stats, config, outputs...)
+	if err := handoff.WriteToFile(string(manifest.JSON()), cli.manifest, cli.force); err != nil {
+		logger.Error("Failed to write manifest %s: %v", cli.manifest, err)
+		os.Exit(1)
+	}
+	logger.Verbose("Manifest written to %s", cli.manifest)
+}
@@ -0,0 +1,203 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	handoff "github.com/phrazzld/handoff/lib"
+)
+
+// outputTarget is the -output destination, checked before any work is done
+type outputTarget struct {
+	// path is the absolute path of a local output file, or empty for remote
+	// targets and the clipboard
+	path string
+
+	// gistToken authenticates gist:// uploads
+	gistToken string
+
+	// webhookHeaders are the parsed -output-header values for webhook targets
+	webhookHeaders http.Header
+}
+
+// prepareOutput validates the output target and the files the run will
+// write, exiting on problems so that no work is wasted on a run that can't
+// deliver its output
+func prepareOutput(cli cliOptions, config *handoff.Config, logger *handoff.Logger) outputTarget {
+	target := resolveOutputTarget(cli, logger)
+
+	// The checkpoint for -resume lives next to the output file
+	if cli.resume && !cli.dryRun {
+		if target.path == "" {
+			logger.Error("-resume requires -output with a file path")
+			os.Exit(1)
+		}
+		config.ResumeFile = resumeFileName(target.path)
+	}
+
+	// Split output goes to numbered files next to -output, which are checked as
+	// they are written, or to the clipboard one part at a time without -output
+	if config.ChunkTokens > 0 && !cli.dryRun && cli.outputFile != "" && target.path == "" {
+		logger.Error("-chunk-tokens writes numbered part files and requires -output with a file path, or no -output to copy parts to the clipboard")
+		os.Exit(1)
+	} else if target.path != "" && config.ChunkTokens <= 0 {
+		checkOutputFile(target.path, cli.force, logger)
+	}
+
+	// Like the output file, an existing manifest is only replaced with -force
+	if cli.manifest != "" && !cli.dryRun {
+		if err := checkManifestPath(cli.manifest, cli.force); err != nil {
+			logger.Error("%v", err)
+			os.Exit(1)
+		}
+	}
+	return target
+}
+
+// resolveOutputTarget parses the -output value into a target, checking
+// webhook headers, the gist token, and object storage URLs up front
+func resolveOutputTarget(cli cliOptions, logger *handoff.Logger) outputTarget {
+	var target outputTarget
+	var err error
+	switch output := cli.outputFile; {
+	case isWebhookOutput(output):
+		if target.webhookHeaders, err = parseOutputHeaders(cli.outputHeaders); err != nil {
+			logger.Error("Invalid -output-header: %v", err)
+			os.Exit(1)
+		}
+	case isGistOutput(output) && !cli.dryRun:
+		if target.gistToken, err = gistToken(); err != nil {
+			logger.Error("%v", err)
+			os.Exit(1)
+		}
+	case isObjectStorageOutput(output):
+		if _, err = objectStorageCommands(output); err != nil {
+			logger.Error("Invalid -output: %v", err)
+			os.Exit(1)
+		}
+	case output != "" && !isRemoteOutput(output):
+		if target.path, err = resolveOutputPath(output); err != nil {
+			logger.Error("Invalid output path: %v", err)
+			os.Exit(1)
+		}
+		logger.Verbose("Output will be written to: %s", target.path)
+	}
+	return target
+}
+
+// checkOutputFile exits if the output file exists and -force isn't set
+func checkOutputFile(path string, force bool, logger *handoff.Logger) {
+	exists, err := handoff.FileExists(path)
+	if err != nil {
+		logger.Error("Error checking output file: %v", err)
+		os.Exit(1)
+	}
+
+	if exists && !force {
+		logger.Error("Output file %s already exists. Use -force flag to overwrite.", path)
+		os.Exit(1)
+	} else if exists && force {
+		logger.Verbose("Output file %s exists, will be overwritten because -force flag is set", path)
+	}
+}
+
+// deliverOutput sends the content to its destination based on precedence:
+// dry-run > remote target or output file > clipboard
+func deliverOutput(content string, stats handoff.Stats, target outputTarget, cli cliOptions, config *handoff.Config, logger *handoff.Logger) {
+	output := cli.outputFile
+	if cli.dryRun {
+		// Highest precedence: dry-run mode
+		out, done := startPreview(cli.noPager)
+		fmt.Fprintln(out, "### DRY RUN: Content that would be generated ###")
+		fmt.Fprintln(out, content)
+		done()
+		logger.Info("Dry run complete. No file written or clipboard modified.")
+		return
+	}
+	if output != "" && !isRemoteOutput(output) {
+		// Medium precedence: write to file
+		logger.Verbose("Writing content (%d bytes) to file: %s", len(content), target.path)
+		if err := handoff.WriteToFile(content, target.path, cli.force); err != nil {
+			logger.Error("Failed to write to file %s: %v", target.path, err)
+			os.Exit(1)
+		}
+		logger.Info("Output successfully written to %s", target.path)
+		removeResumeFile(config, logger)
+		return
+	}
+	if output != "" {
+		// Medium precedence: upload to a remote target
+		deliverRemote(content, stats, target, output, logger)
+		return
+	}
+	// Lowest precedence: copy to clipboard (default behavior)
+	if err := copyToClipboard(content, cli.clipboardCmd, cli.verifyClipboard); err != nil {
+		logger.Error("Failed to copy to clipboard: %v", err)
+		os.Exit(1)
+	}
+	logger.Info("Content successfully copied to clipboard.")
+}
+
+// deliverRemote uploads the content to a gist, webhook, or object storage target
+func deliverRemote(content string, stats handoff.Stats, target outputTarget, output string, logger *handoff.Logger) {
+	switch {
+	case isGistOutput(output):
+		url, err := uploadGist(content, gistFileName(output), target.gistToken)
+		if err != nil {
+			logger.Error("%v", err)
+			os.Exit(1)
+		}
+		fmt.Println(url)
+		logger.Info("Output uploaded to secret gist %s", url)
+	case isWebhookOutput(output):
+		if err := postWebhook(output, content, stats, target.webhookHeaders); err != nil {
+			logger.Error("%v", err)
+			os.Exit(1)
+		}
+		logger.Info("Output posted to %s", output)
+	case isObjectStorageOutput(output):
+		if err := uploadObject(output, content); err != nil {
+			logger.Error("%v", err)
+			os.Exit(1)
+		}
+		logger.Info("Output uploaded to %s", output)
+	}
+}
+
+// resumeFileName returns the checkpoint path used by -resume for an output file
+func resumeFileName(outputPath string) string {
+	return outputPath + ".resume"
+}
+
+// removeResumeFile deletes the -resume checkpoint once the output has been saved
+func removeResumeFile(config *handoff.Config, logger *handoff.Logger) {
+	if config.ResumeFile == "" {
+		return
+	}
+	if err := os.Remove(config.ResumeFile); err != nil && !os.IsNotExist(err) {
+		logger.Warn("failed to remove %s: %v", config.ResumeFile, err)
+	}
+}
+
+// isRemoteOutput reports whether an -output value names a remote target
+// (gist, webhook, or object storage) rather than a local file.
+func isRemoteOutput(output string) bool {
+	return isGistOutput(output) || isWebhookOutput(output) || isObjectStorageOutput(output)
+}
+
+// resolveOutputPath converts a relative path to an absolute path.
+// It returns the absolute path and any error encountered.
+func resolveOutputPath(path string) (string, error) {
+	if path == "" {
+		return "", fmt.Errorf("output path cannot be empty")
+	}
+
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to determine absolute path for %q: %w", path, err)
+	}
+
+	return absPath, nil
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// defaultPager is used for dry-run previews when $PAGER is unset
+const defaultPager = "less -R"
+
+// pagerCommand returns the command used to page dry-run previews, or nil when
+// output should go straight to stdout: paging is disabled, stdout is not a
+// terminal, or the pager is "cat"
+func pagerCommand(noPager, terminal bool, pager string) []string {
+	if noPager || !terminal {
+		return nil
+	}
+	if strings.TrimSpace(pager) == "" {
+		pager = defaultPager
+	}
+	args := strings.Fields(pager)
+	if args[0] == "cat" {
+		return nil
+	}
+	return args
+}
+
+// isTerminal reports whether f is attached to a terminal
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// startPreview returns the writer for a dry-run preview and a function that
+// finishes it. When stdout is a terminal, the preview is piped through $PAGER
+// (less -R by default) so large outputs can be reviewed; otherwise, or when
+// the pager cannot be started, it is written to stdout. Like git, less is run
+// with LESS=FRX unless LESS is set, so previews that fit on one screen print
+// without paging.
+func startPreview(noPager bool) (io.Writer, func()) {
+	args := pagerCommand(noPager, isTerminal(os.Stdout), os.Getenv("PAGER"))
+	if args == nil {
+		return os.Stdout, func() {}
+	}
+
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if os.Getenv("LESS") == "" {
+		cmd.Env = append(os.Environ(), "LESS=FRX")
+	}
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return os.Stdout, func() {}
+	}
+	if err := cmd.Start(); err != nil {
+		return os.Stdout, func() {}
+	}
+	return stdin, func() {
+		_ = stdin.Close()
+		_ = cmd.Wait()
+	}
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestPagerCommand tests choosing how dry-run previews are paged
+func TestPagerCommand(t *testing.T) {
+	testCases := []struct {
+		name     string
+		noPager  bool
+		terminal bool
+		pager    string
+		want     []string
+	}{
+		{"default pager", false, true, "", []string{"less", "-R"}},
+		{"custom pager", false, true, "more -s", []string{"more", "-s"}},
+		{"not a terminal", false, false, "", nil},
+		{"disabled", true, true, "less", nil},
+		{"cat", false, true, "cat", nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := pagerCommand(tc.noPager, tc.terminal, tc.pager); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("pagerCommand(%v, %v, %q) = %v, want %v", tc.noPager, tc.terminal, tc.pager, got, tc.want)
+			}
+		})
+	}
+}
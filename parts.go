@@ -0,0 +1,115 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	handoff "github.com/phrazzld/handoff/lib"
+)
+
+// partFileName returns the file name for part n of an output split with
+// -chunk-tokens, inserting the part number before the extension
+// (e.g., HANDOFF.md becomes HANDOFF.part2.md)
+func partFileName(path string, n int) string {
+	ext := filepath.Ext(path)
+	return fmt.Sprintf("%s.part%d%s", strings.TrimSuffix(path, ext), n, ext)
+}
+
+// writeParts processes the paths into parts of at most -chunk-tokens tokens and
+// writes each to its own numbered file next to outputPath, copies them to the
+// clipboard one at a time when outputPath is empty, or prints them in dry-run
+// mode. No file is written if any part would overwrite an existing file
+// without -force.
+func writeParts(paths []string, config *handoff.Config, cli cliOptions, outputPath string, logger *handoff.Logger) {
+	parts, stats, err := handoff.ProcessProjectChunks(paths, config)
+	if err != nil {
+		logger.Error("Failed to process project: %v", err)
+		os.Exit(1)
+	}
+
+	if cli.dryRun {
+		out, done := startPreview(cli.noPager)
+		for i, part := range parts {
+			fmt.Fprintf(out, "### DRY RUN: Part %d of %d ###\n", i+1, len(parts))
+			fmt.Fprintln(out, part)
+		}
+		done()
+		logger.Info("Dry run complete. No file written or clipboard modified.")
+		logStatistics(stats, config, logger)
+		return
+	}
+
+	if outputPath == "" {
+		copyPart := func(part string) error {
+			return copyToClipboard(part, cli.clipboardCmd, cli.verifyClipboard)
+		}
+		if err := copyParts(parts, copyPart, os.Stdin, os.Stderr); err != nil {
+			logger.Error("%v", err)
+			os.Exit(1)
+		}
+		logger.Info("All %d parts copied to clipboard.", len(parts))
+		writeManifest(cli, stats, config, logger, parts...)
+		logStatistics(stats, config, logger)
+		return
+	}
+
+	writePartFiles(parts, outputPath, cli.force, logger)
+	logger.Info("Output split into %d parts: %s through %s", len(parts), partFileName(outputPath, 1), partFileName(outputPath, len(parts)))
+	removeResumeFile(config, logger)
+	writeManifest(cli, stats, config, logger, parts...)
+
+	logStatistics(stats, config, logger)
+}
+
+// writePartFiles writes each part to its numbered file next to outputPath,
+// checking first that none exists unless force is set
+func writePartFiles(parts []string, outputPath string, force bool, logger *handoff.Logger) {
+	if !force {
+		for i := range parts {
+			path := partFileName(outputPath, i+1)
+			exists, err := handoff.FileExists(path)
+			if err != nil {
+				logger.Error("Error checking output file: %v", err)
+				os.Exit(1)
+			}
+			if exists {
+				logger.Error("Output file %s already exists. Use -force flag to overwrite.", path)
+				os.Exit(1)
+			}
+		}
+	}
+
+	for i, part := range parts {
+		path := partFileName(outputPath, i+1)
+		logger.Verbose("Writing part %d (%d bytes) to file: %s", i+1, len(part), path)
+		if err := handoff.WriteToFile(part, path, force); err != nil {
+			logger.Error("Failed to write to file %s: %v", path, err)
+			os.Exit(1)
+		}
+	}
+}
+
+// copyParts copies each part to the clipboard in turn, waiting for Enter on in
+// between parts so each can be pasted before the next replaces it. Prompts go
+// to out. Closing in stops the sequence with an error naming the next part.
+func copyParts(parts []string, copy func(string) error, in io.Reader, out io.Writer) error {
+	reader := bufio.NewReader(in)
+	for i, part := range parts {
+		if i > 0 {
+			fmt.Fprintf(out, "Press Enter to copy part %d/%d...", i+1, len(parts))
+			if _, err := reader.ReadString('\n'); err != nil {
+				fmt.Fprintln(out)
+				return fmt.Errorf("stopped before part %d of %d: %v", i+1, len(parts), err)
+			}
+		}
+		if err := copy(part); err != nil {
+			return fmt.Errorf("failed to copy part %d of %d to clipboard: %w", i+1, len(parts), err)
+		}
+		fmt.Fprintf(out, "Part %d/%d copied to clipboard (%d bytes).\n", i+1, len(parts), len(part))
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestPartFileName tests numbering split output files
+func TestPartFileName(t *testing.T) {
+	testCases := []struct {
+		path string
+		n    int
+		want string
+	}{
+		{"HANDOFF.md", 2, "HANDOFF.part2.md"},
+		{"/tmp/out/context.txt", 1, "/tmp/out/context.part1.txt"},
+		{"context", 3, "context.part3"},
+	}
+
+	for _, tc := range testCases {
+		if got := partFileName(tc.path, tc.n); got != tc.want {
+			t.Errorf("partFileName(%q, %d) = %q, want %q", tc.path, tc.n, got, tc.want)
+		}
+	}
+}
+
+// TestCopyParts tests copying parts in turn with a prompt between them
+func TestCopyParts(t *testing.T) {
+	parts := []string{"one", "two", "three"}
+
+	var copied []string
+	copyPart := func(part string) error {
+		copied = append(copied, part)
+		return nil
+	}
+	var out strings.Builder
+	if err := copyParts(parts, copyPart, strings.NewReader("\n\n"), &out); err != nil {
+		t.Fatalf("copyParts failed: %v", err)
+	}
+	if !reflect.DeepEqual(copied, parts) {
+		t.Errorf("copied %v, want %v", copied, parts)
+	}
+	for _, want := range []string{"Part 1/3 copied", "Press Enter to copy part 2/3", "Press Enter to copy part 3/3", "Part 3/3 copied"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output %q is missing %q", out.String(), want)
+		}
+	}
+
+	// Closing the input stops before the next part
+	copied = nil
+	err := copyParts(parts, copyPart, strings.NewReader("\n"), &out)
+	if err == nil || !strings.Contains(err.Error(), "stopped before part 3 of 3") {
+		t.Errorf("copyParts with closed input error = %v, want stopped before part 3", err)
+	}
+	if len(copied) != 2 {
+		t.Errorf("copied %v, want the first two parts", copied)
+	}
+
+	// Clipboard failures are reported with the part
+	failing := func(string) error { return ErrClipboardFailed }
+	if err := copyParts(parts, failing, strings.NewReader(""), &out); !errors.Is(err, ErrClipboardFailed) {
+		t.Errorf("copyParts with failing clipboard error = %v, want ErrClipboardFailed", err)
+	}
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	handoff "github.com/phrazzld/handoff/lib"
+)
+
+// defaultPlanTemplate is the planning prompt used when no -template is given.
+// The {context} and {task} placeholders are replaced with the collected context
+// and the feature description.
+const defaultPlanTemplate = `You are a senior software engineer planning a change to the codebase below.
+
+{context}
+
+<task>
+{task}
+</task>
+
+Write an implementation plan for the task in Markdown. Include:
+1. A short summary of the approach and why it fits the existing architecture
+2. The files to create or modify, with the specific changes to each
+3. An ordered list of implementation steps small enough to review individually
+4. The tests to add or update
+5. Risks, open questions, and alternatives you considered
+
+Ground every step in the code shown above and follow its existing conventions.
+`
+
+// planPrompt renders a planning template with the context and task description
+func planPrompt(template, context, task string) string {
+	// Substitute the task first so placeholders inside the context are left alone
+	prompt := strings.ReplaceAll(template, "{task}", strings.TrimSpace(task))
+	return strings.ReplaceAll(prompt, "{context}", context)
+}
+
+// runPlan implements "handoff plan": it wraps the collected context and a feature
+// description in a planning prompt. With -send, the prompt goes to the configured
+// provider and the resulting plan is written to -output or stdout; otherwise the
+// prompt itself is written to -output or copied to the clipboard.
+func runPlan(args []string) {
+	var promptFile, templateFile string
+	var send bool
+	flag.StringVar(&promptFile, "prompt-file", "", "File describing the feature to plan (required)")
+	flag.StringVar(&templateFile, "template", "", "File with a custom planning prompt using {context} and {task} placeholders")
+	flag.BoolVar(&send, "send", false, "Send the prompt to the configured provider and write the plan it returns")
+	providers := registerProviderFlags()
+
+	config, cli := parseConfigArgs(args)
+	logger := handoff.NewLogger(config.Verbose)
+
+	if promptFile == "" || flag.NArg() < 1 {
+		logger.Error("usage: %s plan -prompt-file feature.md [-send] [options] path1 [path2 ...]", os.Args[0])
+		os.Exit(1)
+	}
+
+	task, template, err := readPlanFiles(promptFile, templateFile)
+	if err != nil {
+		logger.Error("%v", err)
+		os.Exit(1)
+	}
+
+	// Resolve the provider before collecting context so a missing key fails fast
+	var target *modelTarget
+	if send && !cli.dryRun {
+		if target, err = providers.resolve(); err != nil {
+			logger.Error("%v", err)
+			os.Exit(1)
+		}
+	}
+
+	content, stats, err := handoff.ProcessProject(flag.Args(), config)
+	if err != nil {
+		logger.Error("Failed to process project: %v", err)
+		os.Exit(1)
+	}
+	prompt := planPrompt(template, content, task)
+
+	deliverPlan(prompt, stats, send, target, cli, logger)
+}
+
+// readPlanFiles reads the task description and the planning template, which
+// defaults to defaultPlanTemplate when templateFile is empty
+func readPlanFiles(promptFile, templateFile string) (task, template string, err error) {
+	data, err := os.ReadFile(promptFile)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to read prompt file: %w", err)
+	}
+	template = defaultPlanTemplate
+	if templateFile != "" {
+		templateData, err := os.ReadFile(templateFile)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to read template: %w", err)
+		}
+		template = string(templateData)
+	}
+	return string(data), template, nil
+}
+
+// deliverPlan previews the planning prompt, sends it to the provider and
+// writes the plan, or saves the prompt to -output or the clipboard
+func deliverPlan(prompt string, stats handoff.Stats, send bool, target *modelTarget, cli cliOptions, logger *handoff.Logger) {
+	switch {
+	case cli.dryRun:
+		out, done := startPreview(cli.noPager)
+		fmt.Fprintln(out, "### DRY RUN: Planning prompt ###")
+		fmt.Fprintln(out, prompt)
+		done()
+	case send:
+		logger.Info("Sending %d files (~%d tokens) to %s for planning", stats.FilesProcessed, stats.Tokens, target.name)
+		plan, err := target.ask(prompt)
+		if err != nil {
+			logger.Error("%v", err)
+			os.Exit(1)
+		}
+		writeResponse(plan, cli, logger)
+	case cli.outputFile != "":
+		if err := handoff.WriteToFile(prompt, cli.outputFile, cli.force); err != nil {
+			logger.Error("Failed to write to file %s: %v", cli.outputFile, err)
+			os.Exit(1)
+		}
+		logger.Info("Planning prompt written to %s", cli.outputFile)
+	default:
+		if err := copyToClipboard(prompt, cli.clipboardCmd, cli.verifyClipboard); err != nil {
+			logger.Error("Failed to copy to clipboard: %v", err)
+			os.Exit(1)
+		}
+		logger.Info("Planning prompt copied to clipboard.")
+	}
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestPlanPrompt tests rendering the planning template
+func TestPlanPrompt(t *testing.T) {
+	context := "<context>\nuses {task} literally\n</context>"
+	prompt := planPrompt(defaultPlanTemplate, context, "\nAdd rate limiting to the API\n\n")
+
+	if !strings.Contains(prompt, context) {
+		t.Errorf("prompt should contain the context unchanged, got:\n%s", prompt)
+	}
+	if !strings.Contains(prompt, "<task>\nAdd rate limiting to the API\n</task>") {
+		t.Errorf("prompt should contain the trimmed task, got:\n%s", prompt)
+	}
+	if strings.Index(prompt, context) > strings.Index(prompt, "<task>") {
+		t.Error("context should come before the task")
+	}
+
+	custom := planPrompt("Task: {task}\n{context}", "CTX", "Do it")
+	if custom != "Task: Do it\nCTX" {
+		t.Errorf("planPrompt with custom template = %q, want %q", custom, "Task: Do it\nCTX")
+	}
+}
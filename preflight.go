@@ -0,0 +1,33 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	handoff "github.com/phrazzld/handoff/lib"
+)
+
+// runPreflight implements -preflight: it lists the files a run would fail to
+// read or leave out, one per line on stdout, without generating any content.
+// It exits with status 1 when problems are found, so scripts can gate a long
+// run on it.
+func runPreflight(paths []string, config *handoff.Config, logger *handoff.Logger) {
+	if len(paths) == 0 {
+		logger.Error("usage: %s -preflight [options] path1 [path2 ...]", os.Args[0])
+		os.Exit(1)
+	}
+
+	issues, err := handoff.Preflight(paths, config)
+	if err != nil {
+		logger.Error("Preflight failed: %v", err)
+		os.Exit(1)
+	}
+	for _, issue := range issues {
+		fmt.Printf("%s: %s (%s)\n", issue.Path, issue.Problem, issue.Detail)
+	}
+	if len(issues) > 0 {
+		logger.Error("preflight found %d problem(s)", len(issues))
+		os.Exit(1)
+	}
+	logger.Info("Preflight found no problems.")
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	handoff "github.com/phrazzld/handoff/lib"
+)
+
+// contextCache holds the most recently generated context and serves it over
+// HTTP, with an ETag based on its digest so clients can poll cheaply
+type contextCache struct {
+	mu        sync.RWMutex
+	content   string
+	etag      string
+	generated time.Time
+}
+
+// set replaces the cached context, reporting whether its content changed
+func (c *contextCache) set(content string, generated time.Time) bool {
+	sum := sha256.Sum256([]byte(content))
+	etag := `"sha256:` + hex.EncodeToString(sum[:]) + `"`
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if etag == c.etag {
+		return false
+	}
+	c.content, c.etag, c.generated = content, etag, generated
+	return true
+}
+
+// ServeHTTP writes the cached context, or 304 Not Modified when the request's
+// If-None-Match header carries the current ETag
+func (c *contextCache) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	c.mu.RLock()
+	content, etag, generated := c.content, c.etag, c.generated
+	c.mu.RUnlock()
+
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	http.ServeContent(w, r, "", generated, strings.NewReader(content))
+}
+
+// treeFingerprint hashes the path, size, and modification time of the files
+// under paths that can appear in the output, as Config.WatchFiles finds them,
+// so a change to any of them can be detected without reading it. Ignored trees
+// such as node_modules and .git are not walked. Files that can't be found or
+// read are hashed as such, so their appearing or disappearing counts as a
+// change too.
+func treeFingerprint(config *handoff.Config, paths []string) string {
+	h := sha256.New()
+	files, err := config.WatchFiles(paths)
+	if err != nil {
+		fmt.Fprintf(h, "error\x00%v\x00", err)
+	}
+	for _, file := range files {
+		info, err := os.Stat(file)
+		if err != nil {
+			fmt.Fprintf(h, "%s\x00error\x00", file)
+			continue
+		}
+		fmt.Fprintf(h, "%s\x00%d\x00%d\x00", file, info.Size(), info.ModTime().UnixNano())
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// runServe implements "handoff serve": it generates the context for the given
+// paths and serves it at /context until interrupted. With -watch, the files
+// are polled for changes and the context is regenerated whenever one changes,
+// so clients always fetch an up-to-date snapshot; the ETag lets them skip
+// downloading one they already have.
+func runServe(args []string) {
+	var addr string
+	var watch bool
+	var interval time.Duration
+	flag.StringVar(&addr, "addr", "localhost:8765", "Address to serve the context on")
+	flag.BoolVar(&watch, "watch", false, "Regenerate the context whenever a file under the paths changes")
+	flag.DurationVar(&interval, "watch-interval", time.Second, "With -watch, how often to check the files for changes")
+
+	config, _ := parseConfigArgs(args)
+	logger := handoff.NewLogger(config.Verbose)
+
+	if flag.NArg() < 1 || interval <= 0 {
+		logger.Error("usage: %s serve [-addr host:port] [-watch] [options] path1 [path2 ...]", os.Args[0])
+		os.Exit(1)
+	}
+	for _, path := range flag.Args() {
+		if path == handoff.StdinPath && watch {
+			logger.Error("serve -watch can't regenerate standard input; pass files or directories")
+			os.Exit(1)
+		}
+	}
+
+	cache := &contextCache{}
+	generate := func() error {
+		content, stats, err := handoff.ProcessProject(flag.Args(), config)
+		if err != nil {
+			return err
+		}
+		if cache.set(content, time.Now()) {
+			logger.Info("Serving %d files (~%d tokens)", stats.FilesProcessed, stats.Tokens)
+		}
+		return nil
+	}
+	if err := generate(); err != nil {
+		logger.Error("Failed to process project: %v", err)
+		os.Exit(1)
+	}
+
+	if watch {
+		go watchTree(config, flag.Args(), interval, generate, logger)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/context", cache)
+	logger.Info("Serving context at http://%s/context", addr)
+	if err := http.ListenAndServe(addr, mux); err != nil {
+		logger.Error("Failed to serve: %v", err)
+		os.Exit(1)
+	}
+}
+
+// watchTree polls the files under paths every interval and calls generate
+// when their fingerprint changes. A failed regeneration is logged and retried at the next
+// change, and the previous context keeps being served meanwhile.
+func watchTree(config *handoff.Config, paths []string, interval time.Duration, generate func() error, logger *handoff.Logger) {
+	last := treeFingerprint(config, paths)
+	for range time.Tick(interval) {
+		current := treeFingerprint(config, paths)
+		if current == last {
+			continue
+		}
+		last = current
+		logger.Verbose("Files changed, regenerating context")
+		if err := generate(); err != nil {
+			logger.Warn("Failed to regenerate context, still serving the previous one: %v", err)
+		}
+	}
+}
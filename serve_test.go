@@ -0,0 +1,115 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	handoff "github.com/phrazzld/handoff/lib"
+)
+
+// TestContextCache tests serving the cached context with an ETag
+func TestContextCache(t *testing.T) {
+	cache := &contextCache{}
+	if !cache.set("first", time.Now()) {
+		t.Fatal("set of new content = false, want true")
+	}
+	if cache.set("first", time.Now()) {
+		t.Error("set of unchanged content = true, want false")
+	}
+
+	get := func(ifNoneMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/context", nil)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		rec := httptest.NewRecorder()
+		cache.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := get("")
+	if rec.Code != http.StatusOK || rec.Body.String() != "first" {
+		t.Fatalf("GET = %d %q, want 200 %q", rec.Code, rec.Body.String(), "first")
+	}
+	etag := rec.Header().Get("ETag")
+	if len(etag) < len(`"sha256:"`) || etag[:8] != `"sha256:` {
+		t.Errorf("ETag = %q, want a quoted sha256 digest", etag)
+	}
+
+	// A client holding the current snapshot gets no body
+	if rec := get(etag); rec.Code != http.StatusNotModified || rec.Body.Len() != 0 {
+		t.Errorf("GET with current ETag = %d %q, want 304 and no body", rec.Code, rec.Body.String())
+	}
+
+	// A regenerated context gets a new ETag and is sent again
+	cache.set("second", time.Now())
+	rec = get(etag)
+	if rec.Code != http.StatusOK || rec.Body.String() != "second" {
+		t.Errorf("GET with stale ETag = %d %q, want 200 %q", rec.Code, rec.Body.String(), "second")
+	}
+	if rec.Header().Get("ETag") == etag {
+		t.Error("ETag didn't change with the content")
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/context", nil)
+	post := httptest.NewRecorder()
+	cache.ServeHTTP(post, req)
+	if post.Code != http.StatusMethodNotAllowed {
+		t.Errorf("POST = %d, want %d", post.Code, http.StatusMethodNotAllowed)
+	}
+}
+
+// TestTreeFingerprint tests detecting changes to files without reading them
+func TestTreeFingerprint(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "main.go")
+	if err := os.WriteFile(file, []byte("package main\n"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	config := handoff.NewConfig(handoff.WithGitClient(handoff.NewMockGitClient(false)), handoff.WithInclude(".go"))
+	paths := []string{dir}
+	before := treeFingerprint(config, paths)
+
+	if got := treeFingerprint(config, paths); got != before {
+		t.Error("fingerprint changed without a change to the files")
+	}
+
+	// Changes inside .git, such as from git status, are ignored
+	if err := os.MkdirAll(filepath.Join(dir, ".git"), 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	before = treeFingerprint(config, paths)
+	if err := os.WriteFile(filepath.Join(dir, ".git", "index"), []byte("index"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	if got := treeFingerprint(config, paths); got != before {
+		t.Error("fingerprint changed with a change inside .git")
+	}
+
+	// So are changes to files the filters leave out of the output
+	if err := os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("notes"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	if got := treeFingerprint(config, paths); got != before {
+		t.Error("fingerprint changed with a change to a filtered file")
+	}
+
+	if err := os.WriteFile(file, []byte("package main\n\nfunc main() {}\n"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	if got := treeFingerprint(config, paths); got == before {
+		t.Error("fingerprint didn't change when a file was edited")
+	}
+
+	before = treeFingerprint(config, paths)
+	if err := os.Remove(file); err != nil {
+		t.Fatalf("Failed to remove file: %v", err)
+	}
+	if got := treeFingerprint(config, paths); got == before {
+		t.Error("fingerprint didn't change when a file was removed")
+	}
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// ErrObjectStorageFailed is returned when no storage CLI could upload the output
+var ErrObjectStorageFailed = errors.New("object storage upload failed")
+
+// isObjectStorageOutput reports whether an -output value targets S3 or Google Cloud Storage
+func isObjectStorageOutput(output string) bool {
+	return strings.HasPrefix(output, "s3://") || strings.HasPrefix(output, "gs://")
+}
+
+// objectStorageCommands returns the candidate commands that upload stdin to the
+// object URL, in order of preference. The provider CLIs are used so credentials
+// come from their standard chains (environment, profiles, instance metadata).
+func objectStorageCommands(url string) ([][]string, error) {
+	scheme, path, _ := strings.Cut(url, "://")
+	bucket, key, _ := strings.Cut(path, "/")
+	if bucket == "" || key == "" || strings.HasSuffix(key, "/") {
+		return nil, fmt.Errorf("invalid object storage URL %q: want %s://bucket/key", url, scheme)
+	}
+
+	switch scheme {
+	case "s3":
+		return [][]string{
+			{"aws", "s3", "cp", "-", url, "--content-type", "text/plain; charset=utf-8"},
+		}, nil
+	case "gs":
+		return [][]string{
+			{"gcloud", "storage", "cp", "-", url},
+			{"gsutil", "-h", "Content-Type:text/plain; charset=utf-8", "cp", "-", url},
+		}, nil
+	}
+	return nil, fmt.Errorf("unsupported object storage scheme %q", scheme)
+}
+
+// uploadObject uploads content to an s3:// or gs:// URL using the provider's CLI.
+func uploadObject(url, content string) error {
+	commands, err := objectStorageCommands(url)
+	if err != nil {
+		return err
+	}
+
+	var failures []string
+	for _, args := range commands {
+		if _, err := exec.LookPath(args[0]); err != nil {
+			failures = append(failures, args[0]+" not found")
+			continue
+		}
+
+		cmd := exec.Command(args[0], args[1:]...)
+		cmd.Stdin = strings.NewReader(content)
+		output, err := cmd.CombinedOutput()
+		if err == nil {
+			return nil // Success
+		}
+		failures = append(failures, fmt.Sprintf("%s failed: %v: %s", args[0], err, strings.TrimSpace(string(output))))
+	}
+
+	return fmt.Errorf("%w: %s", ErrObjectStorageFailed, strings.Join(failures, "; "))
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// TestObjectStorageCommands tests URL validation and CLI selection for object storage
+func TestObjectStorageCommands(t *testing.T) {
+	commands, err := objectStorageCommands("s3://snapshots/repos/handoff.md")
+	if err != nil {
+		t.Fatalf("objectStorageCommands failed: %v", err)
+	}
+	if len(commands) != 1 || commands[0][0] != "aws" {
+		t.Errorf("s3 commands = %v, want aws", commands)
+	}
+
+	commands, err = objectStorageCommands("gs://snapshots/handoff.md")
+	if err != nil {
+		t.Fatalf("objectStorageCommands failed: %v", err)
+	}
+	if len(commands) != 2 || commands[0][0] != "gcloud" || commands[1][0] != "gsutil" {
+		t.Errorf("gs commands = %v, want gcloud then gsutil", commands)
+	}
+
+	for _, url := range []string{"s3://bucket", "s3://bucket/", "gs:///key", "s3://bucket/dir/"} {
+		if _, err := objectStorageCommands(url); err == nil {
+			t.Errorf("objectStorageCommands(%q) succeeded, want error", url)
+		}
+	}
+}
+
+// TestUploadObject tests uploading through a stand-in provider CLI
+func TestUploadObject(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script as a fake aws CLI")
+	}
+
+	cat, err := exec.LookPath("cat")
+	if err != nil {
+		t.Skip("cat not available")
+	}
+
+	dir := t.TempDir()
+	captured := filepath.Join(dir, "captured")
+	script := "#!/bin/sh\n" + cat + " > " + captured + "\necho \"$@\" >> " + captured + "\n"
+	if err := os.WriteFile(filepath.Join(dir, "aws"), []byte(script), 0755); err != nil {
+		t.Fatalf("Failed to write fake aws: %v", err)
+	}
+	t.Setenv("PATH", dir)
+
+	if err := uploadObject("s3://snapshots/handoff.md", "<context>\nhi\n</context>\n"); err != nil {
+		t.Fatalf("uploadObject failed: %v", err)
+	}
+	data, err := os.ReadFile(captured)
+	if err != nil {
+		t.Fatalf("Failed to read captured upload: %v", err)
+	}
+	if !strings.HasPrefix(string(data), "<context>\nhi\n</context>\n") || !strings.Contains(string(data), "s3 cp - s3://snapshots/handoff.md") {
+		t.Errorf("captured upload = %q, want content followed by aws s3 cp arguments", data)
+	}
+
+	// Without gcloud or gsutil on PATH, gs:// uploads fail with a combined error
+	err = uploadObject("gs://snapshots/handoff.md", "content")
+	if !errors.Is(err, ErrObjectStorageFailed) || !strings.Contains(err.Error(), "gsutil not found") {
+		t.Errorf("uploadObject error = %v, want ErrObjectStorageFailed listing missing CLIs", err)
+	}
+}
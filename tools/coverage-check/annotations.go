@@ -0,0 +1,35 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"golang.org/x/tools/cover"
+)
+
+// writeAnnotations prints a file:line:col: message line for each uncovered
+// block, the format compilers and linters use, so editors and code review
+// tools can jump to untested code. Files in the module are given by their
+// module-relative path.
+func writeAnnotations(w io.Writer, profiles []*cover.Profile, module string) error {
+	bw := bufio.NewWriter(w)
+	for _, profile := range profiles {
+		path := profile.FileName
+		if module != "" && strings.HasPrefix(path, module+"/") {
+			path = strings.TrimPrefix(path, module+"/")
+		}
+		for _, b := range profile.Blocks {
+			if b.Count > 0 || b.NumStmt == 0 {
+				continue
+			}
+			statements := "statements"
+			if b.NumStmt == 1 {
+				statements = "statement"
+			}
+			fmt.Fprintf(bw, "%s:%d:%d: %d %s not covered by tests\n", path, b.StartLine, b.StartCol, b.NumStmt, statements)
+		}
+	}
+	return bw.Flush()
+}
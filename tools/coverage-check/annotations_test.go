@@ -0,0 +1,59 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"golang.org/x/tools/cover"
+)
+
+func TestWriteAnnotations(t *testing.T) {
+	profiles := []*cover.Profile{
+		{
+			FileName: "example.com/mod/pkg/a.go",
+			Blocks: []cover.ProfileBlock{
+				{StartLine: 3, StartCol: 20, EndLine: 5, EndCol: 2, NumStmt: 2, Count: 1},
+				{StartLine: 6, StartCol: 12, EndLine: 8, EndCol: 3, NumStmt: 1, Count: 0},
+				{StartLine: 9, StartCol: 2, EndLine: 12, EndCol: 3, NumStmt: 3, Count: 0},
+				{StartLine: 13, StartCol: 1, EndLine: 13, EndCol: 2, NumStmt: 0, Count: 0},
+			},
+		},
+		{
+			FileName: "other.com/lib/b.go",
+			Blocks:   []cover.ProfileBlock{{StartLine: 4, StartCol: 5, EndLine: 4, EndCol: 20, NumStmt: 1, Count: 0}},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		module   string
+		expected string
+	}{
+		{
+			name:   "module relative paths",
+			module: "example.com/mod",
+			expected: "pkg/a.go:6:12: 1 statement not covered by tests\n" +
+				"pkg/a.go:9:2: 3 statements not covered by tests\n" +
+				"other.com/lib/b.go:4:5: 1 statement not covered by tests\n",
+		},
+		{
+			name:   "no module",
+			module: "",
+			expected: "example.com/mod/pkg/a.go:6:12: 1 statement not covered by tests\n" +
+				"example.com/mod/pkg/a.go:9:2: 3 statements not covered by tests\n" +
+				"other.com/lib/b.go:4:5: 1 statement not covered by tests\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := writeAnnotations(&buf, profiles, tt.module); err != nil {
+				t.Fatalf("writeAnnotations failed: %v", err)
+			}
+			if buf.String() != tt.expected {
+				t.Errorf("Expected:\n%s\ngot:\n%s", tt.expected, buf.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"os"
+)
+
+// badgeColors are the shields.io colors for coverage bands, from the lowest
+// percentage each band starts at
+var badgeColors = []struct {
+	min   float64
+	color string
+}{
+	{90, "#4c1"},    // brightgreen
+	{80, "#97ca00"}, // green
+	{70, "#a4a61d"}, // yellowgreen
+	{60, "#dfb317"}, // yellow
+	{50, "#fe7d37"}, // orange
+	{0, "#e05d44"},  // red
+}
+
+// badgeColor returns the color for a coverage percentage
+func badgeColor(coverage float64) string {
+	for _, band := range badgeColors {
+		if coverage >= band.min {
+			return band.color
+		}
+	}
+	return badgeColors[len(badgeColors)-1].color
+}
+
+// badgeTextWidth estimates the width in pixels of text in the badge font
+// (11px Verdana), using the average character width
+func badgeTextWidth(text string) int {
+	return len(text)*7 + 10
+}
+
+// renderBadge returns a shields-style flat SVG badge showing a coverage percentage
+func renderBadge(coverage float64) string {
+	// Round first so the color matches the value shown (89.96% is shown as 90.0%)
+	coverage = math.Round(coverage*10) / 10
+	label := "coverage"
+	value := fmt.Sprintf("%.1f%%", coverage)
+	labelWidth := badgeTextWidth(label)
+	valueWidth := badgeTextWidth(value)
+	width := labelWidth + valueWidth
+
+	return fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" width="%[1]d" height="20" role="img" aria-label="%[2]s: %[3]s">
+<title>%[2]s: %[3]s</title>
+<linearGradient id="s" x2="0" y2="100%%"><stop offset="0" stop-color="#bbb" stop-opacity=".1"/><stop offset="1" stop-opacity=".1"/></linearGradient>
+<clipPath id="r"><rect width="%[1]d" height="20" rx="3" fill="#fff"/></clipPath>
+<g clip-path="url(#r)"><rect width="%[4]d" height="20" fill="#555"/><rect x="%[4]d" width="%[5]d" height="20" fill="%[6]s"/><rect width="%[1]d" height="20" fill="url(#s)"/></g>
+<g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="11">
+<text x="%[7]d" y="15" fill="#010101" fill-opacity=".3">%[2]s</text><text x="%[7]d" y="14">%[2]s</text>
+<text x="%[8]d" y="15" fill="#010101" fill-opacity=".3">%[3]s</text><text x="%[8]d" y="14">%[3]s</text>
+</g>
+</svg>
+`, width, label, value, labelWidth, valueWidth, badgeColor(coverage), labelWidth/2, labelWidth+valueWidth/2)
+}
+
+// WriteBadge writes an SVG coverage badge to a file
+func WriteBadge(filepath string, coverage float64) error {
+	if err := os.WriteFile(filepath, []byte(renderBadge(coverage)), 0644); err != nil {
+		return fmt.Errorf("failed to write badge: %v", err)
+	}
+	return nil
+}
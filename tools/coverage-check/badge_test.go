@@ -0,0 +1,55 @@
+package main
+
+import (
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBadgeColor(t *testing.T) {
+	testCases := []struct {
+		coverage float64
+		expected string
+	}{
+		{100.0, "#4c1"},
+		{90.0, "#4c1"},
+		{85.0, "#97ca00"},
+		{72.5, "#a4a61d"},
+		{60.0, "#dfb317"},
+		{55.0, "#fe7d37"},
+		{49.9, "#e05d44"},
+		{0.0, "#e05d44"},
+	}
+
+	for _, tc := range testCases {
+		if result := badgeColor(tc.coverage); result != tc.expected {
+			t.Errorf("badgeColor(%.1f) = %s, expected %s", tc.coverage, result, tc.expected)
+		}
+	}
+}
+
+func TestWriteBadge(t *testing.T) {
+	badgePath := filepath.Join(t.TempDir(), "coverage.svg")
+	if err := WriteBadge(badgePath, 89.96); err != nil {
+		t.Fatalf("WriteBadge failed: %v", err)
+	}
+
+	data, err := os.ReadFile(badgePath)
+	if err != nil {
+		t.Fatalf("Failed to read badge: %v", err)
+	}
+	badge := string(data)
+
+	if !strings.Contains(badge, ">90.0%<") || !strings.Contains(badge, `fill="#4c1"`) {
+		t.Errorf("Expected a brightgreen 90.0%% badge, got:\n%s", badge)
+	}
+	if err := xml.Unmarshal(data, new(struct{ XMLName xml.Name })); err != nil {
+		t.Errorf("Badge is not well-formed XML: %v", err)
+	}
+
+	if err := WriteBadge(filepath.Join(t.TempDir(), "missing", "coverage.svg"), 50.0); err == nil {
+		t.Error("Expected error writing to a missing directory but got nil")
+	}
+}
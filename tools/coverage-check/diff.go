@@ -0,0 +1,174 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/cover"
+)
+
+// lineRange is an inclusive range of line numbers
+type lineRange struct {
+	start, end int
+}
+
+// ChangedProfiles restricts profiles to the blocks changed relative to base,
+// which is either a coverage profile file or a git ref. Against a profile, a
+// block is changed when the base has no block with the same position and
+// statement count; against a git ref, when it overlaps a line added or
+// modified since the ref.
+func ChangedProfiles(profiles []*cover.Profile, base string) ([]*cover.Profile, error) {
+	if _, err := os.Stat(base); err == nil {
+		baseProfiles, err := ReadProfilesFromFile(base)
+		if err != nil {
+			return nil, err
+		}
+		return newBlocks(profiles, baseProfiles), nil
+	}
+
+	changes, err := changedLines(base)
+	if err != nil {
+		return nil, err
+	}
+	return changedBlocks(profiles, changes, modulePath("go.mod")), nil
+}
+
+// newBlocks returns the blocks of profiles that don't appear in base
+func newBlocks(profiles, base []*cover.Profile) []*cover.Profile {
+	type blockKey struct {
+		file                                 string
+		startLine, startCol, endLine, endCol int
+		numStmt                              int
+	}
+	known := make(map[blockKey]bool)
+	for _, profile := range base {
+		for _, b := range profile.Blocks {
+			known[blockKey{profile.FileName, b.StartLine, b.StartCol, b.EndLine, b.EndCol, b.NumStmt}] = true
+		}
+	}
+
+	return filterBlocks(profiles, func(profile *cover.Profile, b cover.ProfileBlock) bool {
+		return !known[blockKey{profile.FileName, b.StartLine, b.StartCol, b.EndLine, b.EndCol, b.NumStmt}]
+	})
+}
+
+// changedBlocks returns the blocks of profiles overlapping the changed lines.
+// Profile file names are import paths; with the module path they are matched
+// exactly against changes keyed by module-relative path, and otherwise by the
+// longest path suffix.
+func changedBlocks(profiles []*cover.Profile, changes map[string][]lineRange, module string) []*cover.Profile {
+	return filterBlocks(profiles, func(profile *cover.Profile, b cover.ProfileBlock) bool {
+		for _, r := range changesFor(profile.FileName, changes, module) {
+			if b.StartLine <= r.end && b.EndLine >= r.start {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// changesFor finds the changed lines for a profile's file name
+func changesFor(fileName string, changes map[string][]lineRange, module string) []lineRange {
+	if module != "" && strings.HasPrefix(fileName, module+"/") {
+		return changes[strings.TrimPrefix(fileName, module+"/")]
+	}
+
+	var match string
+	for path := range changes {
+		if (fileName == path || strings.HasSuffix(fileName, "/"+path)) && len(path) > len(match) {
+			match = path
+		}
+	}
+	return changes[match]
+}
+
+// filterBlocks returns copies of profiles keeping only the blocks for which
+// keep returns true, dropping profiles left without blocks
+func filterBlocks(profiles []*cover.Profile, keep func(*cover.Profile, cover.ProfileBlock) bool) []*cover.Profile {
+	var filtered []*cover.Profile
+	for _, profile := range profiles {
+		var blocks []cover.ProfileBlock
+		for _, b := range profile.Blocks {
+			if keep(profile, b) {
+				blocks = append(blocks, b)
+			}
+		}
+		if len(blocks) > 0 {
+			filtered = append(filtered, &cover.Profile{FileName: profile.FileName, Mode: profile.Mode, Blocks: blocks})
+		}
+	}
+	return filtered
+}
+
+// changedLines returns the lines added or modified since a git ref, keyed by
+// path relative to the current directory
+func changedLines(ref string) (map[string][]lineRange, error) {
+	out, err := exec.Command("git", "diff", "--unified=0", "--no-color", "--no-ext-diff", "--relative", "--end-of-options", ref, "--").Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && len(exitErr.Stderr) > 0 {
+			return nil, fmt.Errorf("base %s is neither a coverage file nor a git ref: %s", ref, strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return nil, fmt.Errorf("failed to diff against %s: %v", ref, err)
+	}
+	return parseDiffLines(out), nil
+}
+
+// parseDiffLines collects the new-file line ranges from the hunk headers of a
+// unified diff
+func parseDiffLines(diff []byte) map[string][]lineRange {
+	changes := make(map[string][]lineRange)
+	var path string
+	scanner := bufio.NewScanner(bytes.NewReader(diff))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "+++ "):
+			path = strings.TrimPrefix(strings.TrimPrefix(line, "+++ "), "b/")
+			if path == "/dev/null" {
+				path = ""
+			}
+		case strings.HasPrefix(line, "@@ ") && path != "":
+			// @@ -old,count +new,count @@
+			fields := strings.Fields(line)
+			if len(fields) < 3 || !strings.HasPrefix(fields[2], "+") {
+				continue
+			}
+			startText, countText, hasCount := strings.Cut(strings.TrimPrefix(fields[2], "+"), ",")
+			start, err := strconv.Atoi(startText)
+			if err != nil {
+				continue
+			}
+			count := 1
+			if hasCount {
+				if count, err = strconv.Atoi(countText); err != nil {
+					continue
+				}
+			}
+			if count > 0 {
+				changes[path] = append(changes[path], lineRange{start: start, end: start + count - 1})
+			}
+		}
+	}
+	return changes
+}
+
+// modulePath reads the module path from a go.mod file, or returns an empty
+// string if it can't be read
+func modulePath(goMod string) string {
+	data, err := os.ReadFile(goMod)
+	if err != nil {
+		return ""
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if rest, ok := strings.CutPrefix(strings.TrimSpace(line), "module "); ok {
+			return strings.Trim(strings.TrimSpace(rest), `"`)
+		}
+	}
+	return ""
+}
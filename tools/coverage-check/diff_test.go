@@ -0,0 +1,138 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"golang.org/x/tools/cover"
+)
+
+func TestParseDiffLines(t *testing.T) {
+	diff := `diff --git a/pkg/file1.go b/pkg/file1.go
+index 1111111..2222222 100644
+--- a/pkg/file1.go
++++ b/pkg/file1.go
+@@ -10,0 +11,3 @@ func One() {
+@@ -20 +23 @@ func Two() {
+@@ -30,2 +33,0 @@ func Three() {
+diff --git a/old.go b/old.go
+deleted file mode 100644
+--- a/old.go
++++ /dev/null
+@@ -1,5 +0,0 @@
+`
+	expected := map[string][]lineRange{
+		"pkg/file1.go": {{start: 11, end: 13}, {start: 23, end: 23}},
+	}
+
+	if result := parseDiffLines([]byte(diff)); !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+}
+
+func TestChangedBlocks(t *testing.T) {
+	profiles := []*cover.Profile{
+		{
+			FileName: "example.com/mod/pkg/file1.go",
+			Blocks: []cover.ProfileBlock{
+				{StartLine: 5, EndLine: 8, NumStmt: 2, Count: 1},
+				{StartLine: 10, EndLine: 14, NumStmt: 3, Count: 0},
+				{StartLine: 20, EndLine: 22, NumStmt: 1, Count: 1},
+			},
+		},
+		{
+			FileName: "example.com/mod/file1.go",
+			Blocks:   []cover.ProfileBlock{{StartLine: 10, EndLine: 12, NumStmt: 4, Count: 1}},
+		},
+	}
+	changes := map[string][]lineRange{
+		"pkg/file1.go": {{start: 12, end: 13}, {start: 22, end: 22}},
+	}
+
+	for _, module := range []string{"example.com/mod", ""} {
+		result := changedBlocks(profiles, changes, module)
+		if len(result) != 1 || result[0].FileName != "example.com/mod/pkg/file1.go" {
+			t.Fatalf("module %q: expected only pkg/file1.go, got %v", module, result)
+		}
+		if total, covered := countStatements(result); total != 4 || covered != 1 {
+			t.Errorf("module %q: expected 1 of 4 changed statements covered, got %d of %d", module, covered, total)
+		}
+	}
+}
+
+func TestChangedProfilesAgainstBaseProfile(t *testing.T) {
+	tempDir := t.TempDir()
+	baseContent := `mode: set
+example.com/pkg/file1.go:10.20,15.3 3 1
+example.com/pkg/file1.go:20.30,25.3 3 0
+`
+	basePath := filepath.Join(tempDir, "base.out")
+	if err := os.WriteFile(basePath, []byte(baseContent), 0644); err != nil {
+		t.Fatalf("Failed to write base profile: %v", err)
+	}
+
+	profiles := []*cover.Profile{
+		{
+			FileName: "example.com/pkg/file1.go",
+			Mode:     "set",
+			Blocks: []cover.ProfileBlock{
+				{StartLine: 10, StartCol: 20, EndLine: 15, EndCol: 3, NumStmt: 3, Count: 1},
+				{StartLine: 20, StartCol: 30, EndLine: 25, EndCol: 3, NumStmt: 3, Count: 0},
+				{StartLine: 30, StartCol: 2, EndLine: 32, EndCol: 3, NumStmt: 2, Count: 1},
+			},
+		},
+		{
+			FileName: "example.com/pkg/file2.go",
+			Mode:     "set",
+			Blocks:   []cover.ProfileBlock{{StartLine: 1, StartCol: 1, EndLine: 3, EndCol: 2, NumStmt: 2, Count: 0}},
+		},
+	}
+
+	result, err := ChangedProfiles(profiles, basePath)
+	if err != nil {
+		t.Fatalf("ChangedProfiles failed: %v", err)
+	}
+	if total, covered := countStatements(result); total != 4 || covered != 2 {
+		t.Errorf("Expected 2 of 4 new statements covered, got %d of %d", covered, total)
+	}
+	if coverage := calculateCoverage(result); coverage != 50.0 {
+		t.Errorf("Expected diff coverage 50.00%%, got %.2f%%", coverage)
+	}
+}
+
+func TestModulePath(t *testing.T) {
+	goMod := filepath.Join(t.TempDir(), "go.mod")
+	if err := os.WriteFile(goMod, []byte("// comment\nmodule example.com/mod\n\ngo 1.24\n"), 0644); err != nil {
+		t.Fatalf("Failed to write go.mod: %v", err)
+	}
+
+	if result := modulePath(goMod); result != "example.com/mod" {
+		t.Errorf("Expected example.com/mod, got %q", result)
+	}
+	if result := modulePath(filepath.Join(t.TempDir(), "missing")); result != "" {
+		t.Errorf("Expected empty module path for a missing go.mod, got %q", result)
+	}
+}
+
+func TestChangedLinesRejectsOptionRefs(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	dir := t.TempDir()
+	if out, err := exec.Command("git", "init", "-q", dir).CombinedOutput(); err != nil {
+		t.Fatalf("git init failed: %v: %s", err, out)
+	}
+	t.Chdir(dir)
+
+	// A base that looks like an option is a ref, not a flag that writes a file
+	written := filepath.Join(dir, "written")
+	if _, err := changedLines("--output=" + written); err == nil {
+		t.Error("changedLines() succeeded for an option-like ref, want error")
+	}
+	if _, err := os.Stat(written); err == nil {
+		t.Errorf("changedLines() let git write %s", written)
+	}
+}
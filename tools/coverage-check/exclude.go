@@ -0,0 +1,71 @@
+package main
+
+import (
+	"path"
+	"strings"
+
+	"golang.org/x/tools/cover"
+)
+
+// excludeProfiles drops the profiles of files matching any of the glob
+// patterns, so generated and mock code doesn't count toward coverage.
+// Patterns are matched against the profile's import path and, for files in
+// the module, the path relative to the module root.
+func excludeProfiles(profiles []*cover.Profile, patterns []string, module string) []*cover.Profile {
+	if len(patterns) == 0 {
+		return profiles
+	}
+
+	var kept []*cover.Profile
+	for _, profile := range profiles {
+		names := []string{profile.FileName}
+		if module != "" && strings.HasPrefix(profile.FileName, module+"/") {
+			names = append(names, strings.TrimPrefix(profile.FileName, module+"/"))
+		}
+		if !matchesAny(patterns, names) {
+			kept = append(kept, profile)
+		}
+	}
+	return kept
+}
+
+// matchesAny reports whether any pattern matches any of the names
+func matchesAny(patterns, names []string) bool {
+	for _, pattern := range patterns {
+		for _, name := range names {
+			if matchGlob(pattern, name) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// matchGlob reports whether a slash-separated name matches a glob pattern.
+// Each path element is matched with path.Match, and a "**" element matches any
+// number of elements, including none.
+func matchGlob(pattern, name string) bool {
+	return matchElements(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+// matchElements matches pattern elements against name elements
+func matchElements(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for skip := 0; skip <= len(name); skip++ {
+				if matchElements(pattern[1:], name[skip:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(name) == 0 {
+			return false
+		}
+		if matched, err := path.Match(pattern[0], name[0]); err != nil || !matched {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return len(name) == 0
+}
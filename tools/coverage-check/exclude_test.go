@@ -0,0 +1,49 @@
+package main
+
+import (
+	"testing"
+
+	"golang.org/x/tools/cover"
+)
+
+func TestMatchGlob(t *testing.T) {
+	testCases := []struct {
+		pattern  string
+		name     string
+		expected bool
+	}{
+		{"**/*_mock.go", "example.com/pkg/store_mock.go", true},
+		{"**/*_mock.go", "store_mock.go", true},
+		{"**/*_mock.go", "example.com/pkg/store.go", false},
+		{"**/zz_generated*", "example.com/api/v1/zz_generated.deepcopy.go", true},
+		{"pkg/*.go", "pkg/file.go", true},
+		{"pkg/*.go", "pkg/sub/file.go", false},
+		{"pkg/**/*.go", "pkg/sub/deep/file.go", true},
+		{"pkg/**", "pkg/sub/file.go", true},
+		{"[", "file.go", false},
+	}
+
+	for _, tc := range testCases {
+		if result := matchGlob(tc.pattern, tc.name); result != tc.expected {
+			t.Errorf("matchGlob(%q, %q) = %v, expected %v", tc.pattern, tc.name, result, tc.expected)
+		}
+	}
+}
+
+func TestExcludeProfiles(t *testing.T) {
+	profiles := []*cover.Profile{
+		{FileName: "example.com/mod/pkg/store.go"},
+		{FileName: "example.com/mod/pkg/store_mock.go"},
+		{FileName: "example.com/mod/api/zz_generated.deepcopy.go"},
+		{FileName: "example.com/mod/internal/gen/types.go"},
+	}
+
+	result := excludeProfiles(profiles, []string{"**/*_mock.go", "**/zz_generated*", "internal/gen/*"}, "example.com/mod")
+	if len(result) != 1 || result[0].FileName != "example.com/mod/pkg/store.go" {
+		t.Errorf("Expected only store.go to be kept, got %v", result)
+	}
+
+	if result := excludeProfiles(profiles, nil, ""); len(result) != len(profiles) {
+		t.Errorf("Expected no patterns to keep all profiles, got %d", len(result))
+	}
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"golang.org/x/tools/cover"
+)
+
+// FuncCoverage is the coverage of a single function
+type FuncCoverage struct {
+	File       string
+	Line       int
+	Name       string
+	Coverage   float64
+	Statements int
+	Covered    int
+}
+
+// functionCoverage computes the coverage of each function in the profiled
+// files, as go tool cover -func does, by parsing their source. Files whose
+// source can't be found or parsed are skipped.
+func functionCoverage(profiles []*cover.Profile, module string) []FuncCoverage {
+	var funcs []FuncCoverage
+	for _, profile := range profiles {
+		path := sourcePath(profile.FileName, module)
+		if path == "" {
+			continue
+		}
+		fset := token.NewFileSet()
+		file, err := parser.ParseFile(fset, path, nil, 0)
+		if err != nil {
+			continue
+		}
+
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Body == nil {
+				continue
+			}
+			start, end := fset.Position(fn.Pos()), fset.Position(fn.End())
+
+			var total, covered int
+			for _, b := range profile.Blocks {
+				if afterPosition(b.StartLine, b.StartCol, start) && beforePosition(b.EndLine, b.EndCol, end) {
+					total += b.NumStmt
+					if b.Count > 0 {
+						covered += b.NumStmt
+					}
+				}
+			}
+			funcs = append(funcs, FuncCoverage{
+				File:       profile.FileName,
+				Line:       start.Line,
+				Name:       funcName(fn),
+				Coverage:   percentage(covered, total),
+				Statements: total,
+				Covered:    covered,
+			})
+		}
+	}
+	return funcs
+}
+
+// afterPosition reports whether line and col are at or after pos
+func afterPosition(line, col int, pos token.Position) bool {
+	return line > pos.Line || (line == pos.Line && col >= pos.Column)
+}
+
+// beforePosition reports whether line and col are at or before pos
+func beforePosition(line, col int, pos token.Position) bool {
+	return line < pos.Line || (line == pos.Line && col <= pos.Column)
+}
+
+// funcName returns a function's name, qualified by its receiver type for methods
+func funcName(fn *ast.FuncDecl) string {
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return fn.Name.Name
+	}
+	recv := fn.Recv.List[0].Type
+	if star, ok := recv.(*ast.StarExpr); ok {
+		return "(*" + typeName(star.X) + ")." + fn.Name.Name
+	}
+	return typeName(recv) + "." + fn.Name.Name
+}
+
+// typeName returns the name of a receiver type, ignoring type parameters
+func typeName(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		return t.Name
+	case *ast.IndexExpr:
+		return typeName(t.X)
+	case *ast.IndexListExpr:
+		return typeName(t.X)
+	}
+	return "?"
+}
+
+// sourcePath finds the source file for a profile file name, which is an
+// import path such as example.com/mod/pkg/file.go, or returns an empty string.
+// Files in the module are found relative to the current directory.
+func sourcePath(fileName, module string) string {
+	candidates := []string{fileName}
+	if module != "" && strings.HasPrefix(fileName, module+"/") {
+		candidates = append([]string{strings.TrimPrefix(fileName, module+"/")}, candidates...)
+	}
+	for _, candidate := range candidates {
+		if info, err := os.Stat(candidate); err == nil && !info.IsDir() {
+			return candidate
+		}
+	}
+	return ""
+}
+
+// writeUncovered lists the functions and files below a coverage percentage,
+// lowest first
+func writeUncovered(w io.Writer, report Report, funcs []FuncCoverage, limit float64) error {
+	var lowFuncs []FuncCoverage
+	for _, fn := range funcs {
+		if fn.Statements > 0 && fn.Coverage < limit {
+			lowFuncs = append(lowFuncs, fn)
+		}
+	}
+	sort.SliceStable(lowFuncs, func(i, j int) bool { return lowFuncs[i].Coverage < lowFuncs[j].Coverage })
+
+	var lowFiles []Summary
+	for _, file := range report.Files {
+		if file.Statements > 0 && file.Coverage < limit {
+			lowFiles = append(lowFiles, file)
+		}
+	}
+	sort.SliceStable(lowFiles, func(i, j int) bool { return lowFiles[i].Coverage < lowFiles[j].Coverage })
+
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	if len(lowFuncs) > 0 {
+		fmt.Fprintf(tw, "\nFunctions below %.2f%%:\n", limit)
+		for _, fn := range lowFuncs {
+			fmt.Fprintf(tw, "%s:%d:\t%s\t%.1f%%\t(%d/%d statements)\n", fn.File, fn.Line, fn.Name, fn.Coverage, fn.Covered, fn.Statements)
+		}
+	}
+	if len(lowFiles) > 0 {
+		fmt.Fprintf(tw, "\nFiles below %.2f%%:\n", limit)
+		for _, file := range lowFiles {
+			fmt.Fprintf(tw, "%s\t%.1f%%\t(%d/%d statements)\n", file.Name, file.Coverage, file.Covered, file.Statements)
+		}
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/tools/cover"
+)
+
+func TestFunctionCoverage(t *testing.T) {
+	source := `package pkg
+
+func One() int {
+	return 1
+}
+
+type T[K any] struct{}
+
+func (t *T[K]) Two(ok bool) int {
+	if ok {
+		return 2
+	}
+	return 0
+}
+`
+	sourcePath := filepath.Join(t.TempDir(), "file1.go")
+	if err := os.WriteFile(sourcePath, []byte(source), 0644); err != nil {
+		t.Fatalf("Failed to write source file: %v", err)
+	}
+
+	profiles := []*cover.Profile{
+		{
+			FileName: sourcePath,
+			Blocks: []cover.ProfileBlock{
+				{StartLine: 3, StartCol: 18, EndLine: 5, EndCol: 2, NumStmt: 1, Count: 1},
+				{StartLine: 9, StartCol: 33, EndLine: 10, EndCol: 7, NumStmt: 1, Count: 1},
+				{StartLine: 10, StartCol: 7, EndLine: 12, EndCol: 3, NumStmt: 1, Count: 0},
+				{StartLine: 13, StartCol: 2, EndLine: 13, EndCol: 10, NumStmt: 1, Count: 1},
+			},
+		},
+		{
+			FileName: "example.com/missing/file2.go",
+			Blocks:   []cover.ProfileBlock{{StartLine: 1, EndLine: 2, NumStmt: 1, Count: 0}},
+		},
+	}
+
+	funcs := functionCoverage(profiles, "")
+	expected := []FuncCoverage{
+		{File: sourcePath, Line: 3, Name: "One", Coverage: 100.0, Statements: 1, Covered: 1},
+		{File: sourcePath, Line: 9, Name: "(*T).Two", Coverage: 100.0 * 2 / 3, Statements: 3, Covered: 2},
+	}
+	if len(funcs) != len(expected) {
+		t.Fatalf("Expected %d functions, got %+v", len(expected), funcs)
+	}
+	for i := range expected {
+		if funcs[i] != expected[i] {
+			t.Errorf("Expected %+v, got %+v", expected[i], funcs[i])
+		}
+	}
+}
+
+func TestWriteUncovered(t *testing.T) {
+	report := Report{Files: []Summary{
+		{Name: "example.com/pkg/a.go", Coverage: 90.0, Statements: 10, Covered: 9},
+		{Name: "example.com/pkg/b.go", Coverage: 50.0, Statements: 4, Covered: 2},
+		{Name: "example.com/pkg/c.go", Coverage: 25.0, Statements: 4, Covered: 1},
+	}}
+	funcs := []FuncCoverage{
+		{File: "example.com/pkg/b.go", Line: 3, Name: "Half", Coverage: 50.0, Statements: 4, Covered: 2},
+		{File: "example.com/pkg/b.go", Line: 9, Name: "Empty"},
+		{File: "example.com/pkg/c.go", Line: 7, Name: "None", Coverage: 0.0, Statements: 2, Covered: 0},
+		{File: "example.com/pkg/a.go", Line: 1, Name: "Full", Coverage: 100.0, Statements: 10, Covered: 10},
+	}
+
+	var buf bytes.Buffer
+	if err := writeUncovered(&buf, report, funcs, 80.0); err != nil {
+		t.Fatalf("writeUncovered failed: %v", err)
+	}
+	output := buf.String()
+
+	order := []string{"Functions below 80.00%", "None", "Half", "Files below 80.00%", "c.go", "b.go"}
+	last := -1
+	for _, want := range order {
+		index := strings.Index(output[last+1:], want)
+		if index < 0 {
+			t.Fatalf("Expected %q after position %d in output:\n%s", want, last, output)
+		}
+		last += 1 + index
+	}
+	for _, unwanted := range []string{"Full", "Empty", "a.go"} {
+		if strings.Contains(output, unwanted) {
+			t.Errorf("Expected %q to be left out of output:\n%s", unwanted, output)
+		}
+	}
+}
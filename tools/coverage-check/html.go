@@ -0,0 +1,157 @@
+package main
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"strings"
+
+	"golang.org/x/tools/cover"
+)
+
+// Line coverage states in the HTML source listing
+const (
+	lineUntracked = ""
+	lineCovered   = "hit"
+	lineUncovered = "miss"
+)
+
+// htmlLine is a source line in the HTML report
+type htmlLine struct {
+	Number int
+	Text   string
+	State  string
+}
+
+// htmlFile is a file section in the HTML report
+type htmlFile struct {
+	Summary
+	Anchor    string
+	Below     bool
+	Functions []FuncCoverage
+	Lines     []htmlLine
+}
+
+// htmlReport is the data rendered into the HTML report
+type htmlReport struct {
+	Report
+	Label string
+	Files []htmlFile
+}
+
+// WriteHTML writes a self-contained HTML coverage report to a file, with a
+// summary, per-file and per-function coverage, and a source listing colored by
+// coverage. Files and functions below the threshold are highlighted. Source is
+// found as for -show-uncovered; files without it are listed without a listing.
+func WriteHTML(filepath string, report Report, label string, profiles []*cover.Profile, module string) error {
+	file, err := os.Create(filepath)
+	if err != nil {
+		return fmt.Errorf("failed to write HTML report: %v", err)
+	}
+	if err := renderHTML(file, report, label, profiles, module); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write HTML report: %v", err)
+	}
+	return file.Close()
+}
+
+// renderHTML renders the HTML report
+func renderHTML(w io.Writer, report Report, label string, profiles []*cover.Profile, module string) error {
+	funcs := make(map[string][]FuncCoverage)
+	for _, fn := range functionCoverage(profiles, module) {
+		funcs[fn.File] = append(funcs[fn.File], fn)
+	}
+
+	data := htmlReport{Report: report, Label: label}
+	for i, summary := range report.Files {
+		file := htmlFile{
+			Summary:   summary,
+			Anchor:    fmt.Sprintf("file-%d", i),
+			Below:     summary.Coverage < report.Threshold,
+			Functions: funcs[summary.Name],
+		}
+		for _, profile := range profiles {
+			if profile.FileName == summary.Name {
+				file.Lines = sourceLines(profile, module)
+				break
+			}
+		}
+		data.Files = append(data.Files, file)
+	}
+	return htmlTemplate.Execute(w, data)
+}
+
+// sourceLines reads a profiled file's source and marks each line covered,
+// uncovered, or untracked; a line touched by any uncovered block is uncovered.
+// It returns nil when the source can't be read.
+func sourceLines(profile *cover.Profile, module string) []htmlLine {
+	path := sourcePath(profile.FileName, module)
+	if path == "" {
+		return nil
+	}
+	source, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+
+	texts := strings.Split(strings.TrimSuffix(string(source), "\n"), "\n")
+	lines := make([]htmlLine, len(texts))
+	for i, text := range texts {
+		lines[i] = htmlLine{Number: i + 1, Text: text}
+	}
+	for _, b := range profile.Blocks {
+		for n := b.StartLine; n <= b.EndLine && n <= len(lines); n++ {
+			line := &lines[n-1]
+			if b.Count == 0 {
+				line.State = lineUncovered
+			} else if line.State == lineUntracked {
+				line.State = lineCovered
+			}
+		}
+	}
+	return lines
+}
+
+// htmlTemplate is the layout of the HTML report
+var htmlTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"percent": func(coverage float64) string { return fmt.Sprintf("%.1f%%", coverage) },
+	"below":   func(coverage, threshold float64) bool { return coverage < threshold },
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Label}} report</title>
+<style>
+body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", sans-serif; margin: 2em; color: #222; }
+table { border-collapse: collapse; margin: 1em 0; }
+th, td { text-align: left; padding: 0.2em 0.8em; border-bottom: 1px solid #ddd; }
+td.num { text-align: right; font-variant-numeric: tabular-nums; }
+.pass { color: #2a7d2a; }
+.fail, .below { color: #c0392b; font-weight: bold; }
+pre { background: #fafafa; border: 1px solid #ddd; padding: 0.5em 0; overflow-x: auto; }
+pre span { display: block; min-height: 1.2em; padding: 0 0.5em; }
+pre span::before { content: attr(data-line); display: inline-block; width: 4em; color: #999; }
+.hit { background: #e6f4e6; }
+.miss { background: #fbe3e0; }
+</style>
+</head>
+<body>
+<h1>{{.Label}}: {{percent .Coverage}}</h1>
+<p>{{.Covered}} of {{.Statements}} statements covered{{if .Base}}, changed relative to {{.Base}}{{end}}. Threshold {{percent .Threshold}}:
+{{if .Passed}}<span class="pass">PASS</span>{{else}}<span class="fail">FAIL</span>{{end}}</p>
+<table>
+<tr><th>File</th><th>Coverage</th><th>Statements</th></tr>
+{{range .Files}}<tr><td><a href="#{{.Anchor}}">{{.Name}}</a></td><td class="num{{if .Below}} below{{end}}">{{percent .Coverage}}</td><td class="num">{{.Covered}}/{{.Statements}}</td></tr>
+{{end}}</table>
+{{$threshold := .Threshold}}{{range .Files}}
+<h2 id="{{.Anchor}}">{{.Name}} <span class="{{if .Below}}below{{else}}pass{{end}}">{{percent .Coverage}}</span></h2>
+{{if .Functions}}<table>
+<tr><th>Function</th><th>Line</th><th>Coverage</th><th>Statements</th></tr>
+{{range .Functions}}<tr><td>{{.Name}}</td><td class="num">{{.Line}}</td><td class="num{{if and .Statements (below .Coverage $threshold)}} below{{end}}">{{percent .Coverage}}</td><td class="num">{{.Covered}}/{{.Statements}}</td></tr>
+{{end}}</table>
+{{end}}{{if .Lines}}<pre>{{range .Lines}}<span data-line="{{.Number}}"{{if .State}} class="{{.State}}"{{end}}>{{.Text}}</span>{{end}}</pre>
+{{else}}<p>Source not found.</p>
+{{end}}{{end}}</body>
+</html>
+`))
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/tools/cover"
+)
+
+func TestWriteHTML(t *testing.T) {
+	tempDir := t.TempDir()
+	source := `package pkg
+
+func Check(a, b int) bool {
+	if a < b {
+		return true
+	}
+	return false
+}
+`
+	sourcePath := filepath.Join(tempDir, "check.go")
+	if err := os.WriteFile(sourcePath, []byte(source), 0644); err != nil {
+		t.Fatalf("Failed to write source file: %v", err)
+	}
+
+	profiles := []*cover.Profile{
+		{
+			FileName: sourcePath,
+			Blocks: []cover.ProfileBlock{
+				{StartLine: 3, StartCol: 27, EndLine: 4, EndCol: 11, NumStmt: 1, Count: 1},
+				{StartLine: 4, StartCol: 11, EndLine: 6, EndCol: 3, NumStmt: 1, Count: 0},
+				{StartLine: 7, StartCol: 2, EndLine: 7, EndCol: 14, NumStmt: 1, Count: 1},
+			},
+		},
+		{
+			FileName: "example.com/missing/other.go",
+			Blocks:   []cover.ProfileBlock{{StartLine: 1, EndLine: 2, NumStmt: 1, Count: 1}},
+		},
+	}
+	report := NewReport(profiles, 70.0, "")
+
+	reportPath := filepath.Join(tempDir, "report.html")
+	if err := WriteHTML(reportPath, report, "Coverage", profiles, ""); err != nil {
+		t.Fatalf("WriteHTML failed: %v", err)
+	}
+	data, err := os.ReadFile(reportPath)
+	if err != nil {
+		t.Fatalf("Failed to read report: %v", err)
+	}
+	output := string(data)
+
+	expected := []string{
+		"<h1>Coverage: 75.0%</h1>",
+		`<span class="pass">PASS</span>`,
+		`<td class="num below">66.7%</td>`,
+		"<td>Check</td>",
+		`<span data-line="4" class="miss">	if a &lt; b {</span>`,
+		`<span data-line="7" class="hit">	return false</span>`,
+		`<span data-line="1">package pkg</span>`,
+		"Source not found.",
+	}
+	for _, want := range expected {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected report to contain %q", want)
+		}
+	}
+
+	if err := WriteHTML(filepath.Join(tempDir, "missing", "report.html"), report, "Coverage", profiles, ""); err == nil {
+		t.Error("Expected error writing to a missing directory but got nil")
+	}
+}
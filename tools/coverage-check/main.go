@@ -5,6 +5,9 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"strings"
+
+	"golang.org/x/tools/cover"
 )
 
 func main() {
@@ -12,16 +15,39 @@ func main() {
 	thresholdPtr := flag.Float64("threshold", 85.0, "Minimum coverage percentage required")
 	filePtr := flag.String("file", "", "Coverage profile file (default reads from stdin)")
 	verbosePtr := flag.Bool("verbose", false, "Show detailed output")
+	mergePtr := flag.String("merge", "", "Comma-separated coverage profile files to merge, summing counts per block, before checking")
+	excludePtr := flag.String("exclude", "", "Comma-separated glob patterns of files to leave out, e.g. \"**/*_mock.go,**/zz_generated*\"")
+	htmlPtr := flag.String("html", "", "Write a self-contained HTML coverage report with colored source to this file")
+	badgePtr := flag.String("badge", "", "Write an SVG coverage badge to this file")
+	ratchetPtr := flag.String("ratchet", "", "Fail if coverage drops below the baseline stored in this file, and store improvements as the new baseline")
+	tolerancePtr := flag.Float64("ratchet-tolerance", 0.1, "Percentage points coverage may drop below the -ratchet baseline")
+	basePtr := flag.String("base", "", "Check only statements changed relative to this coverage profile file or git ref")
+	formatPtr := flag.String("format", "text", "Output format: text, json, or annotations")
+	uncoveredPtr := flag.Float64("show-uncovered", 0, "List functions and files below this coverage percentage, lowest first (0 disables the list)")
 	flag.Parse()
 
-	var coverage float64
+	switch *formatPtr {
+	case "text", "json", "annotations":
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown format %q (want text, json, or annotations)\n", *formatPtr)
+		os.Exit(2)
+	}
+
+	var profiles []*cover.Profile
 	var err error
 
-	// Parse coverage from file or stdin
-	if *filePtr != "" {
-		coverage, err = ParseCoverageFromFile(*filePtr)
-	} else {
-		coverage, err = ParseCoverageFromStdin()
+	// Parse coverage from the merged files, a file, or stdin
+	switch {
+	case *mergePtr != "":
+		var files []string
+		if *filePtr != "" {
+			files = append(files, *filePtr)
+		}
+		profiles, err = ReadMergedProfiles(append(files, splitList(*mergePtr)...))
+	case *filePtr != "":
+		profiles, err = ReadProfilesFromFile(*filePtr)
+	default:
+		profiles, err = ReadProfilesFromStdin()
 	}
 
 	if err != nil {
@@ -29,25 +55,119 @@ func main() {
 		os.Exit(2)
 	}
 
+	// Leave out generated and mock files
+	profiles = excludeProfiles(profiles, splitList(*excludePtr), modulePath("go.mod"))
+
+	// Restrict the check to changed statements
+	label, metric := "Coverage", MetricTotal
+	if *basePtr != "" {
+		profiles, err = ChangedProfiles(profiles, *basePtr)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error comparing with base: %v\n", err)
+			os.Exit(2)
+		}
+		label, metric = "Diff coverage", MetricDiff
+	}
+
 	// Check coverage against threshold
-	passed := CheckCoverageThreshold(coverage, *thresholdPtr)
+	report := NewReport(profiles, *thresholdPtr, *basePtr)
+
+	// Compare with the last accepted coverage
+	if *ratchetPtr != "" && report.Statements > 0 {
+		result, err := ApplyRatchet(*ratchetPtr, metric, report.Coverage, *tolerancePtr)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error checking baseline: %v\n", err)
+			os.Exit(2)
+		}
+		report.Ratchet = &result
+		report.Passed = report.Passed && result.Passed
+	}
+
+	// Write the badge and HTML report before reporting so they are updated even when the check fails
+	if *badgePtr != "" {
+		if err := WriteBadge(*badgePtr, report.Coverage); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing badge: %v\n", err)
+			os.Exit(2)
+		}
+	}
+
+	if *htmlPtr != "" {
+		if err := WriteHTML(*htmlPtr, report, label, profiles, modulePath("go.mod")); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing HTML report: %v\n", err)
+			os.Exit(2)
+		}
+	}
 
 	// Output results
-	if *verbosePtr {
-		fmt.Printf("Coverage: %.2f%%\n", coverage)
-		fmt.Printf("Threshold: %.2f%%\n", *thresholdPtr)
-		fmt.Printf("Status: %s\n", getStatusText(passed))
+	switch *formatPtr {
+	case "json":
+		if err := report.WriteJSON(os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing report: %v\n", err)
+			os.Exit(2)
+		}
+	case "annotations":
+		if err := writeAnnotations(os.Stdout, profiles, modulePath("go.mod")); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing report: %v\n", err)
+			os.Exit(2)
+		}
+	default:
+		writeText(report, label, *verbosePtr)
+		if *uncoveredPtr > 0 {
+			funcs := functionCoverage(profiles, modulePath("go.mod"))
+			if err := writeUncovered(os.Stdout, report, funcs, *uncoveredPtr); err != nil {
+				fmt.Fprintf(os.Stderr, "Error writing report: %v\n", err)
+				os.Exit(2)
+			}
+		}
+	}
+
+	if !report.Passed {
+		os.Exit(1)
+	}
+}
+
+// writeText prints the result of the check as plain text
+func writeText(report Report, label string, verbose bool) {
+	if report.Base != "" && report.Statements == 0 {
+		fmt.Printf("No statements changed relative to %s\n", report.Base)
+		return
+	}
+
+	if verbose {
+		fmt.Printf("%s: %.2f%%\n", label, report.Coverage)
+		if report.Base != "" {
+			fmt.Printf("Changed statements: %d of %d covered\n", report.Covered, report.Statements)
+		}
+		fmt.Printf("Threshold: %.2f%%\n", report.Threshold)
+		if ratchet := report.Ratchet; ratchet != nil {
+			fmt.Printf("Baseline: %.2f%% (tolerance %.2f points, from %s)\n", ratchet.Baseline, ratchet.Tolerance, ratchet.File)
+		}
+		fmt.Printf("Status: %s\n", getStatusText(report.Passed))
 	} else {
-		if passed {
-			fmt.Printf("Coverage %.2f%% meets threshold of %.2f%%\n", coverage, *thresholdPtr)
+		if CheckCoverageThreshold(report.Coverage, report.Threshold) {
+			fmt.Printf("%s %.2f%% meets threshold of %.2f%%\n", label, report.Coverage, report.Threshold)
 		} else {
-			fmt.Printf("Coverage %.2f%% is below threshold of %.2f%%\n", coverage, *thresholdPtr)
+			fmt.Printf("%s %.2f%% is below threshold of %.2f%%\n", label, report.Coverage, report.Threshold)
+		}
+		if ratchet := report.Ratchet; ratchet != nil && !ratchet.Passed {
+			fmt.Printf("%s %.2f%% dropped more than %.2f points below the baseline of %.2f%% in %s\n",
+				label, report.Coverage, ratchet.Tolerance, ratchet.Baseline, ratchet.File)
 		}
 	}
+	if ratchet := report.Ratchet; ratchet != nil && ratchet.Updated {
+		fmt.Printf("Updated baseline in %s to %.2f%%\n", ratchet.File, report.Coverage)
+	}
+}
 
-	if !passed {
-		os.Exit(1)
+// splitList splits a comma-separated flag value, dropping empty entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
 	}
+	return items
 }
 
 func getStatusText(passed bool) string {
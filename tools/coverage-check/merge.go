@@ -0,0 +1,94 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+
+	"golang.org/x/tools/cover"
+)
+
+// ReadMergedProfiles parses several coverage profile files and merges them
+// with MergeProfiles
+func ReadMergedProfiles(filepaths []string) ([]*cover.Profile, error) {
+	var sets [][]*cover.Profile
+	for _, filepath := range filepaths {
+		profiles, err := ReadProfilesFromFile(filepath)
+		if err != nil {
+			return nil, err
+		}
+		sets = append(sets, profiles)
+	}
+	return MergeProfiles(sets...)
+}
+
+// MergeProfiles combines coverage profiles, such as those from unit and
+// integration runs or different operating systems. Counts of blocks at the
+// same position are summed (in set mode, a block is covered if any profile
+// covered it), and blocks found in only some profiles are kept, so a statement
+// counts as covered when any run covered it.
+func MergeProfiles(sets ...[]*cover.Profile) ([]*cover.Profile, error) {
+	mode := ""
+	files := make(map[string]*cover.Profile)
+	for _, profiles := range sets {
+		for _, profile := range profiles {
+			if mode == "" {
+				mode = profile.Mode
+			} else if profile.Mode != mode && (profile.Mode == "set" || mode == "set") {
+				return nil, fmt.Errorf("cannot merge coverage profiles with modes %s and %s", mode, profile.Mode)
+			}
+
+			merged := files[profile.FileName]
+			if merged == nil {
+				merged = &cover.Profile{FileName: profile.FileName, Mode: profile.Mode}
+				files[profile.FileName] = merged
+			}
+			merged.Blocks = append(merged.Blocks, profile.Blocks...)
+		}
+	}
+
+	result := make([]*cover.Profile, 0, len(files))
+	for _, profile := range files {
+		blocks, err := mergeBlocks(profile.Blocks, mode)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", profile.FileName, err)
+		}
+		profile.Mode = mode
+		profile.Blocks = blocks
+		result = append(result, profile)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].FileName < result[j].FileName })
+	return result, nil
+}
+
+// mergeBlocks sorts blocks by position and combines the counts of blocks at
+// the same position
+func mergeBlocks(blocks []cover.ProfileBlock, mode string) ([]cover.ProfileBlock, error) {
+	sort.SliceStable(blocks, func(i, j int) bool {
+		a, b := blocks[i], blocks[j]
+		if a.StartLine != b.StartLine {
+			return a.StartLine < b.StartLine
+		}
+		return a.StartCol < b.StartCol
+	})
+
+	var merged []cover.ProfileBlock
+	for _, b := range blocks {
+		if n := len(merged); n > 0 {
+			last := &merged[n-1]
+			if b.StartLine == last.StartLine && b.StartCol == last.StartCol &&
+				b.EndLine == last.EndLine && b.EndCol == last.EndCol {
+				if b.NumStmt != last.NumStmt {
+					return nil, fmt.Errorf("inconsistent statement count at line %d: %d and %d", b.StartLine, last.NumStmt, b.NumStmt)
+				}
+				if mode == "set" {
+					last.Count |= b.Count
+				} else {
+					last.Count += b.Count
+				}
+				continue
+			}
+		}
+		merged = append(merged, b)
+	}
+	return merged, nil
+}
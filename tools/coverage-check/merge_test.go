@@ -0,0 +1,103 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/tools/cover"
+)
+
+func TestMergeProfiles(t *testing.T) {
+	unit := []*cover.Profile{
+		{
+			FileName: "example.com/pkg/file1.go",
+			Mode:     "count",
+			Blocks: []cover.ProfileBlock{
+				{StartLine: 10, StartCol: 20, EndLine: 15, EndCol: 3, NumStmt: 3, Count: 2},
+				{StartLine: 20, StartCol: 30, EndLine: 25, EndCol: 3, NumStmt: 3, Count: 0},
+			},
+		},
+	}
+	integration := []*cover.Profile{
+		{
+			FileName: "example.com/pkg/file1.go",
+			Mode:     "count",
+			Blocks: []cover.ProfileBlock{
+				{StartLine: 20, StartCol: 30, EndLine: 25, EndCol: 3, NumStmt: 3, Count: 1},
+				{StartLine: 10, StartCol: 20, EndLine: 15, EndCol: 3, NumStmt: 3, Count: 1},
+			},
+		},
+		{
+			FileName: "example.com/pkg/file2.go",
+			Mode:     "count",
+			Blocks:   []cover.ProfileBlock{{StartLine: 1, StartCol: 1, EndLine: 3, EndCol: 2, NumStmt: 2, Count: 0}},
+		},
+	}
+
+	merged, err := MergeProfiles(unit, integration)
+	if err != nil {
+		t.Fatalf("MergeProfiles failed: %v", err)
+	}
+	if len(merged) != 2 || merged[0].FileName != "example.com/pkg/file1.go" {
+		t.Fatalf("Expected file1.go and file2.go, got %v", merged)
+	}
+	blocks := merged[0].Blocks
+	if len(blocks) != 2 || blocks[0].Count != 3 || blocks[1].Count != 1 {
+		t.Errorf("Expected counts 3 and 1 for file1.go blocks, got %v", blocks)
+	}
+	if coverage := calculateCoverage(merged); coverage != 75.0 {
+		t.Errorf("Expected merged coverage 75.00%%, got %.2f%%", coverage)
+	}
+}
+
+func TestMergeProfilesErrors(t *testing.T) {
+	block := cover.ProfileBlock{StartLine: 1, StartCol: 1, EndLine: 2, EndCol: 2, NumStmt: 1, Count: 1}
+
+	set := []*cover.Profile{{FileName: "a.go", Mode: "set", Blocks: []cover.ProfileBlock{block}}}
+	count := []*cover.Profile{{FileName: "a.go", Mode: "count", Blocks: []cover.ProfileBlock{block}}}
+	if _, err := MergeProfiles(set, count); err == nil {
+		t.Error("Expected error merging set and count profiles but got nil")
+	}
+
+	changed := block
+	changed.NumStmt = 2
+	other := []*cover.Profile{{FileName: "a.go", Mode: "count", Blocks: []cover.ProfileBlock{changed}}}
+	if _, err := MergeProfiles(count, other); err == nil {
+		t.Error("Expected error for inconsistent statement counts but got nil")
+	}
+}
+
+func TestReadMergedProfiles(t *testing.T) {
+	tempDir := t.TempDir()
+	contents := map[string]string{
+		"linux.out": `mode: set
+example.com/pkg/file1.go:10.20,15.3 3 1
+example.com/pkg/file1.go:20.30,25.3 3 0
+`,
+		"windows.out": `mode: set
+example.com/pkg/file1.go:10.20,15.3 3 0
+example.com/pkg/file1.go:20.30,25.3 3 1
+`,
+	}
+	var paths []string
+	for name, content := range contents {
+		path := filepath.Join(tempDir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+		paths = append(paths, path)
+	}
+
+	merged, err := ReadMergedProfiles(paths)
+	if err != nil {
+		t.Fatalf("ReadMergedProfiles failed: %v", err)
+	}
+	if coverage := calculateCoverage(merged); coverage != 100.0 {
+		t.Errorf("Expected merged coverage 100.00%%, got %.2f%%", coverage)
+	}
+
+	if _, err := ReadMergedProfiles(append(paths, filepath.Join(tempDir, "missing.out"))); err == nil {
+		t.Error("Expected error for a missing profile but got nil")
+	}
+}
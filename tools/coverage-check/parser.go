@@ -12,15 +12,9 @@ import (
 
 // ParseCoverageFromFile parses a coverage profile file and calculates the coverage percentage
 func ParseCoverageFromFile(filepath string) (float64, error) {
-	// Check if file exists
-	if _, err := os.Stat(filepath); os.IsNotExist(err) {
-		return 0, fmt.Errorf("coverage file %s does not exist", filepath)
-	}
-
-	// Parse the coverage profile using the cover package
-	profiles, err := cover.ParseProfiles(filepath)
+	profiles, err := ReadProfilesFromFile(filepath)
 	if err != nil {
-		return 0, fmt.Errorf("failed to parse coverage profile: %v", err)
+		return 0, err
 	}
 
 	// Calculate total coverage percentage
@@ -30,10 +24,37 @@ func ParseCoverageFromFile(filepath string) (float64, error) {
 
 // ParseCoverageFromStdin parses coverage profile data from stdin
 func ParseCoverageFromStdin() (float64, error) {
+	profiles, err := ReadProfilesFromStdin()
+	if err != nil {
+		return 0, err
+	}
+
+	// Calculate total coverage percentage
+	coverage := calculateCoverage(profiles)
+	return coverage, nil
+}
+
+// ReadProfilesFromFile parses the blocks of a coverage profile file
+func ReadProfilesFromFile(filepath string) ([]*cover.Profile, error) {
+	// Check if file exists
+	if _, err := os.Stat(filepath); os.IsNotExist(err) {
+		return nil, fmt.Errorf("coverage file %s does not exist", filepath)
+	}
+
+	// Parse the coverage profile using the cover package
+	profiles, err := cover.ParseProfiles(filepath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse coverage profile: %v", err)
+	}
+	return profiles, nil
+}
+
+// ReadProfilesFromStdin parses the blocks of coverage profile data from stdin
+func ReadProfilesFromStdin() ([]*cover.Profile, error) {
 	// Create a temporary file to store the stdin data
 	tempFile, err := os.CreateTemp("", "coverage-*.out")
 	if err != nil {
-		return 0, fmt.Errorf("failed to create temporary file: %v", err)
+		return nil, fmt.Errorf("failed to create temporary file: %v", err)
 	}
 	defer os.Remove(tempFile.Name())
 	defer tempFile.Close()
@@ -45,12 +66,12 @@ func ParseCoverageFromStdin() (float64, error) {
 	for {
 		line, err := reader.ReadString('\n')
 		if err != nil && err != io.EOF {
-			return 0, fmt.Errorf("error reading from stdin: %v", err)
+			return nil, fmt.Errorf("error reading from stdin: %v", err)
 		}
 
 		if line != "" {
 			if _, err := writer.WriteString(line); err != nil {
-				return 0, fmt.Errorf("error writing to temporary file: %v", err)
+				return nil, fmt.Errorf("error writing to temporary file: %v", err)
 			}
 		}
 
@@ -60,12 +81,12 @@ func ParseCoverageFromStdin() (float64, error) {
 	}
 
 	if err := writer.Flush(); err != nil {
-		return 0, fmt.Errorf("error flushing data to temporary file: %v", err)
+		return nil, fmt.Errorf("error flushing data to temporary file: %v", err)
 	}
 
 	// Make sure we're at the beginning of the file for reading
 	if _, err := tempFile.Seek(0, 0); err != nil {
-		return 0, fmt.Errorf("error seeking in temporary file: %v", err)
+		return nil, fmt.Errorf("error seeking in temporary file: %v", err)
 	}
 
 	// Parse the coverage profile
@@ -73,14 +94,11 @@ func ParseCoverageFromStdin() (float64, error) {
 	if err != nil {
 		// Check if the input might be the output of go tool cover -func
 		if isToolCoverOutput(tempFile.Name()) {
-			return 0, fmt.Errorf("input appears to be the output of 'go tool cover -func'. Please provide a coverage profile file instead")
+			return nil, fmt.Errorf("input appears to be the output of 'go tool cover -func'. Please provide a coverage profile file instead")
 		}
-		return 0, fmt.Errorf("failed to parse coverage profile from stdin: %v", err)
+		return nil, fmt.Errorf("failed to parse coverage profile from stdin: %v", err)
 	}
-
-	// Calculate total coverage percentage
-	coverage := calculateCoverage(profiles)
-	return coverage, nil
+	return profiles, nil
 }
 
 // isToolCoverOutput checks if the file contains the output of go tool cover -func
@@ -98,20 +116,19 @@ func isToolCoverOutput(filepath string) bool {
 
 // calculateCoverage computes the coverage percentage from profile data
 func calculateCoverage(profiles []*cover.Profile) float64 {
-	var totalStmts, coveredStmts int
+	totalStmts, coveredStmts := countStatements(profiles)
+	return percentage(coveredStmts, totalStmts)
+}
 
+// countStatements returns the total and covered statement counts in profile data
+func countStatements(profiles []*cover.Profile) (total, covered int) {
 	for _, profile := range profiles {
 		for _, block := range profile.Blocks {
-			totalStmts += block.NumStmt
+			total += block.NumStmt
 			if block.Count > 0 {
-				coveredStmts += block.NumStmt
+				covered += block.NumStmt
 			}
 		}
 	}
-
-	if totalStmts == 0 {
-		return 0.0
-	}
-
-	return float64(coveredStmts) * 100.0 / float64(totalStmts)
+	return total, covered
 }
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Ratchet metrics; each has its own baseline in the -ratchet file, so diff
+// coverage is never compared with total coverage
+const (
+	MetricTotal = "total"
+	MetricDiff  = "diff"
+)
+
+// RatchetResult is the outcome of comparing coverage with a stored baseline
+type RatchetResult struct {
+	// File is the file holding the baseline percentage
+	File string `json:"file"`
+
+	// Metric is the coverage the baseline is for, MetricTotal or MetricDiff
+	Metric string `json:"metric"`
+
+	// Baseline is the last accepted percentage, before any update
+	Baseline float64 `json:"baseline"`
+
+	// Tolerance is how many percentage points coverage may drop below the baseline
+	Tolerance float64 `json:"tolerance"`
+
+	// Passed is set when coverage is within the tolerance of the baseline
+	Passed bool `json:"passed"`
+
+	// Updated is set when coverage improved and was stored as the new baseline
+	Updated bool `json:"updated"`
+}
+
+// ApplyRatchet compares coverage with the metric's baseline stored in a file.
+// The check fails when coverage is more than tolerance percentage points below
+// the baseline; when coverage is higher, it becomes the new baseline. A missing
+// file or metric is created with the current coverage, keeping the baselines
+// of other metrics. Percentages are stored and compared to two decimal places.
+func ApplyRatchet(filepath, metric string, coverage, tolerance float64) (RatchetResult, error) {
+	coverage = math.Round(coverage*100) / 100
+	result := RatchetResult{File: filepath, Metric: metric, Baseline: coverage, Tolerance: tolerance, Passed: true}
+
+	baselines, err := readBaselines(filepath)
+	if err != nil {
+		return result, err
+	}
+	if baseline, ok := baselines[metric]; ok {
+		result.Baseline = baseline
+		result.Passed = coverage >= baseline-tolerance
+		result.Updated = coverage > baseline
+	} else {
+		result.Updated = true
+	}
+
+	if result.Updated {
+		baselines[metric] = coverage
+		if err := writeBaselines(filepath, baselines); err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+// readBaselines reads the baselines stored in a file, one "metric: percentage"
+// line each. A file holding only a percentage, as written before each metric
+// had its own baseline, holds the total baseline; a missing file holds none.
+func readBaselines(filepath string) (map[string]float64, error) {
+	baselines := make(map[string]float64)
+	data, err := os.ReadFile(filepath)
+	if os.IsNotExist(err) {
+		return baselines, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read baseline: %v", err)
+	}
+
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		metric, value, found := strings.Cut(line, ":")
+		if !found {
+			metric, value = MetricTotal, metric
+		}
+		baseline, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			return nil, fmt.Errorf("baseline file %s does not hold a percentage: %v", filepath, err)
+		}
+		baselines[strings.TrimSpace(metric)] = baseline
+	}
+	return baselines, nil
+}
+
+// writeBaselines stores the baselines in a file, one "metric: percentage" line
+// each in sorted order
+func writeBaselines(filepath string, baselines map[string]float64) error {
+	metrics := make([]string, 0, len(baselines))
+	for metric := range baselines {
+		metrics = append(metrics, metric)
+	}
+	sort.Strings(metrics)
+
+	var b strings.Builder
+	for _, metric := range metrics {
+		fmt.Fprintf(&b, "%s: %.2f\n", metric, baselines[metric])
+	}
+	if err := os.WriteFile(filepath, []byte(b.String()), 0644); err != nil {
+		return fmt.Errorf("failed to update baseline: %v", err)
+	}
+	return nil
+}
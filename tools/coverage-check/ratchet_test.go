@@ -0,0 +1,134 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestApplyRatchet(t *testing.T) {
+	testCases := []struct {
+		name            string
+		baseline        string
+		coverage        float64
+		expectedPassed  bool
+		expectedUpdated bool
+		expectedStored  string
+	}{
+		{
+			name:            "Missing baseline is created",
+			coverage:        81.234,
+			expectedPassed:  true,
+			expectedUpdated: true,
+			expectedStored:  "total: 81.23",
+		},
+		{
+			name:            "Improvement raises the baseline",
+			baseline:        "total: 80.00\n",
+			coverage:        82.5,
+			expectedPassed:  true,
+			expectedUpdated: true,
+			expectedStored:  "total: 82.50",
+		},
+		{
+			name:            "Bare percentage is the total baseline",
+			baseline:        "80.00\n",
+			coverage:        82.5,
+			expectedPassed:  true,
+			expectedUpdated: true,
+			expectedStored:  "total: 82.50",
+		},
+		{
+			name:           "Unchanged coverage passes",
+			baseline:       "80.00",
+			coverage:       80.001,
+			expectedPassed: true,
+			expectedStored: "80.00",
+		},
+		{
+			name:           "Drop within tolerance passes",
+			baseline:       "80.00",
+			coverage:       79.9,
+			expectedPassed: true,
+			expectedStored: "80.00",
+		},
+		{
+			name:           "Drop beyond tolerance fails",
+			baseline:       "80.00",
+			coverage:       79.5,
+			expectedPassed: false,
+			expectedStored: "80.00",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			baselinePath := filepath.Join(t.TempDir(), ".coverage-baseline")
+			if tc.baseline != "" {
+				if err := os.WriteFile(baselinePath, []byte(tc.baseline), 0644); err != nil {
+					t.Fatalf("Failed to write baseline: %v", err)
+				}
+			}
+
+			result, err := ApplyRatchet(baselinePath, MetricTotal, tc.coverage, 0.1)
+			if err != nil {
+				t.Fatalf("ApplyRatchet failed: %v", err)
+			}
+			if result.Passed != tc.expectedPassed || result.Updated != tc.expectedUpdated {
+				t.Errorf("Expected passed=%v updated=%v, got %+v", tc.expectedPassed, tc.expectedUpdated, result)
+			}
+
+			data, err := os.ReadFile(baselinePath)
+			if err != nil {
+				t.Fatalf("Failed to read baseline: %v", err)
+			}
+			if stored := strings.TrimSpace(string(data)); stored != tc.expectedStored {
+				t.Errorf("Expected stored baseline %s, got %s", tc.expectedStored, stored)
+			}
+		})
+	}
+}
+
+func TestApplyRatchetInvalidBaseline(t *testing.T) {
+	baselinePath := filepath.Join(t.TempDir(), ".coverage-baseline")
+	if err := os.WriteFile(baselinePath, []byte("not a number"), 0644); err != nil {
+		t.Fatalf("Failed to write baseline: %v", err)
+	}
+
+	if _, err := ApplyRatchet(baselinePath, MetricTotal, 80.0, 0.1); err == nil {
+		t.Error("Expected error for an invalid baseline but got nil")
+	}
+}
+
+func TestApplyRatchetMetrics(t *testing.T) {
+	baselinePath := filepath.Join(t.TempDir(), ".coverage-baseline")
+	if err := os.WriteFile(baselinePath, []byte("total: 80.00\n"), 0644); err != nil {
+		t.Fatalf("Failed to write baseline: %v", err)
+	}
+
+	// Diff coverage far below the total baseline starts its own baseline
+	result, err := ApplyRatchet(baselinePath, MetricDiff, 50.0, 0.1)
+	if err != nil {
+		t.Fatalf("ApplyRatchet failed: %v", err)
+	}
+	if !result.Passed || !result.Updated || result.Baseline != 50.0 {
+		t.Errorf("Expected a new passing diff baseline of 50, got %+v", result)
+	}
+
+	// Each metric keeps its own baseline
+	if result, err = ApplyRatchet(baselinePath, MetricDiff, 40.0, 0.1); err != nil || result.Passed {
+		t.Errorf("Expected a drop in diff coverage to fail, got %+v, %v", result, err)
+	}
+	if result, err = ApplyRatchet(baselinePath, MetricTotal, 80.0, 0.1); err != nil || !result.Passed || result.Updated {
+		t.Errorf("Expected unchanged total coverage to pass, got %+v, %v", result, err)
+	}
+
+	data, err := os.ReadFile(baselinePath)
+	if err != nil {
+		t.Fatalf("Failed to read baseline: %v", err)
+	}
+	if stored := string(data); stored != "diff: 50.00\ntotal: 80.00\n" {
+		t.Errorf("Expected both baselines stored, got %q", stored)
+	}
+}
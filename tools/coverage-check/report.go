@@ -0,0 +1,98 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"path"
+	"sort"
+
+	"golang.org/x/tools/cover"
+)
+
+// Report is the result of a coverage check, as written by -format json
+type Report struct {
+	// Coverage is the percentage of statements covered
+	Coverage float64 `json:"coverage"`
+
+	// Threshold is the minimum coverage percentage required
+	Threshold float64 `json:"threshold"`
+
+	// Passed is set when coverage meets the threshold
+	Passed bool `json:"passed"`
+
+	// Statements and Covered count all statements and the covered ones
+	Statements int `json:"statements"`
+	Covered    int `json:"covered"`
+
+	// Base is the profile or git ref the statements were restricted to, if any
+	Base string `json:"base,omitempty"`
+
+	// Ratchet is the comparison with the stored baseline, if one was requested
+	Ratchet *RatchetResult `json:"ratchet,omitempty"`
+
+	// Packages and Files break coverage down, sorted by name
+	Packages []Summary `json:"packages"`
+	Files    []Summary `json:"files"`
+}
+
+// Summary is the coverage of a package or file
+type Summary struct {
+	Name       string  `json:"name"`
+	Coverage   float64 `json:"coverage"`
+	Statements int     `json:"statements"`
+	Covered    int     `json:"covered"`
+}
+
+// NewReport checks profiles against a threshold and breaks the result down by
+// package and file. With a base, profiles hold only the changed statements,
+// and the check passes when none changed.
+func NewReport(profiles []*cover.Profile, threshold float64, base string) Report {
+	report := Report{Threshold: threshold, Base: base}
+	report.Statements, report.Covered = countStatements(profiles)
+	report.Coverage = calculateCoverage(profiles)
+	report.Passed = CheckCoverageThreshold(report.Coverage, threshold) || (base != "" && report.Statements == 0)
+
+	packages := make(map[string]*Summary)
+	for _, profile := range profiles {
+		file := newSummary(profile.FileName, []*cover.Profile{profile})
+		report.Files = append(report.Files, file)
+
+		name := path.Dir(profile.FileName)
+		pkg := packages[name]
+		if pkg == nil {
+			pkg = &Summary{Name: name}
+			packages[name] = pkg
+		}
+		pkg.Statements += file.Statements
+		pkg.Covered += file.Covered
+	}
+	for _, pkg := range packages {
+		pkg.Coverage = percentage(pkg.Covered, pkg.Statements)
+		report.Packages = append(report.Packages, *pkg)
+	}
+
+	sort.Slice(report.Files, func(i, j int) bool { return report.Files[i].Name < report.Files[j].Name })
+	sort.Slice(report.Packages, func(i, j int) bool { return report.Packages[i].Name < report.Packages[j].Name })
+	return report
+}
+
+// newSummary summarizes the coverage of profiles under a name
+func newSummary(name string, profiles []*cover.Profile) Summary {
+	total, covered := countStatements(profiles)
+	return Summary{Name: name, Coverage: percentage(covered, total), Statements: total, Covered: covered}
+}
+
+// percentage returns covered as a percentage of total, or 0 when total is 0
+func percentage(covered, total int) float64 {
+	if total == 0 {
+		return 0.0
+	}
+	return float64(covered) * 100.0 / float64(total)
+}
+
+// WriteJSON writes the report as indented JSON
+func (r Report) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"golang.org/x/tools/cover"
+)
+
+func TestNewReport(t *testing.T) {
+	profiles := []*cover.Profile{
+		{
+			FileName: "example.com/pkg/b.go",
+			Blocks: []cover.ProfileBlock{
+				{StartLine: 10, EndLine: 15, NumStmt: 3, Count: 1},
+				{StartLine: 20, EndLine: 25, NumStmt: 1, Count: 0},
+			},
+		},
+		{
+			FileName: "example.com/pkg/a.go",
+			Blocks:   []cover.ProfileBlock{{StartLine: 1, EndLine: 5, NumStmt: 4, Count: 0}},
+		},
+		{
+			FileName: "example.com/other/c.go",
+			Blocks:   []cover.ProfileBlock{{StartLine: 1, EndLine: 5, NumStmt: 2, Count: 1}},
+		},
+	}
+
+	report := NewReport(profiles, 50.0, "")
+	if report.Statements != 10 || report.Covered != 5 || report.Coverage != 50.0 || !report.Passed {
+		t.Errorf("Expected 5 of 10 statements covered (50%%, passing), got %+v", report)
+	}
+
+	expectedFiles := []Summary{
+		{Name: "example.com/other/c.go", Coverage: 100.0, Statements: 2, Covered: 2},
+		{Name: "example.com/pkg/a.go", Coverage: 0.0, Statements: 4, Covered: 0},
+		{Name: "example.com/pkg/b.go", Coverage: 75.0, Statements: 4, Covered: 3},
+	}
+	for i, expected := range expectedFiles {
+		if i >= len(report.Files) || report.Files[i] != expected {
+			t.Errorf("Expected file %d to be %+v, got %+v", i, expected, report.Files)
+			break
+		}
+	}
+
+	expectedPackages := []Summary{
+		{Name: "example.com/other", Coverage: 100.0, Statements: 2, Covered: 2},
+		{Name: "example.com/pkg", Coverage: 37.5, Statements: 8, Covered: 3},
+	}
+	for i, expected := range expectedPackages {
+		if i >= len(report.Packages) || report.Packages[i] != expected {
+			t.Errorf("Expected package %d to be %+v, got %+v", i, expected, report.Packages)
+			break
+		}
+	}
+}
+
+func TestNewReportWithoutStatements(t *testing.T) {
+	if report := NewReport(nil, 85.0, ""); report.Passed {
+		t.Error("Expected an empty profile to fail the threshold")
+	}
+	if report := NewReport(nil, 85.0, "main"); !report.Passed {
+		t.Error("Expected no changed statements to pass the threshold")
+	}
+}
+
+func TestReportWriteJSON(t *testing.T) {
+	profiles := []*cover.Profile{
+		{
+			FileName: "example.com/pkg/a.go",
+			Blocks:   []cover.ProfileBlock{{StartLine: 1, EndLine: 5, NumStmt: 4, Count: 0}},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := NewReport(profiles, 85.0, "").WriteJSON(&buf); err != nil {
+		t.Fatalf("WriteJSON failed: %v", err)
+	}
+
+	var decoded map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("Failed to decode JSON output: %v", err)
+	}
+	if decoded["passed"] != false || decoded["threshold"] != 85.0 || decoded["statements"] != 4.0 {
+		t.Errorf("Unexpected summary fields in %s", buf.String())
+	}
+	if _, ok := decoded["base"]; ok {
+		t.Errorf("Expected base to be omitted, got %s", buf.String())
+	}
+	if files, ok := decoded["files"].([]any); !ok || len(files) != 1 {
+		t.Errorf("Expected one file entry, got %s", buf.String())
+	}
+}
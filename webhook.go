@@ -0,0 +1,71 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	handoff "github.com/phrazzld/handoff/lib"
+)
+
+// isWebhookOutput reports whether an -output value targets an HTTP webhook
+func isWebhookOutput(output string) bool {
+	return strings.HasPrefix(output, "http://") || strings.HasPrefix(output, "https://")
+}
+
+// webhookPayload is the JSON body POSTed to webhook targets
+type webhookPayload struct {
+	Content string        `json:"content"`
+	Stats   handoff.Stats `json:"stats"`
+}
+
+// parseOutputHeaders converts "Name: value" strings into an http.Header,
+// expanding environment variables in values so secrets stay out of shell history
+func parseOutputHeaders(headers []string) (http.Header, error) {
+	parsed := http.Header{}
+	for _, header := range headers {
+		name, value, ok := strings.Cut(header, ":")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid header %q: want \"Name: value\"", header)
+		}
+		parsed.Add(name, os.ExpandEnv(strings.TrimSpace(value)))
+	}
+	return parsed, nil
+}
+
+// postWebhook POSTs the content and its statistics as JSON to url.
+// Any non-2xx response is reported as an error.
+func postWebhook(url, content string, stats handoff.Stats, headers http.Header) error {
+	payload, err := json.Marshal(webhookPayload{Content: content, Stats: stats})
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook payload: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	for name, values := range headers {
+		req.Header[name] = values
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post to webhook: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("webhook returned %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	handoff "github.com/phrazzld/handoff/lib"
+)
+
+// TestParseOutputHeaders tests parsing and environment expansion of webhook headers
+func TestParseOutputHeaders(t *testing.T) {
+	t.Setenv("HANDOFF_TEST_TOKEN", "s3cret")
+
+	headers, err := parseOutputHeaders([]string{"Authorization: Bearer $HANDOFF_TEST_TOKEN", "X-Team:  platform "})
+	if err != nil {
+		t.Fatalf("parseOutputHeaders failed: %v", err)
+	}
+	if got := headers.Get("Authorization"); got != "Bearer s3cret" {
+		t.Errorf("Authorization = %q, want %q", got, "Bearer s3cret")
+	}
+	if got := headers.Get("X-Team"); got != "platform" {
+		t.Errorf("X-Team = %q, want %q", got, "platform")
+	}
+
+	if _, err := parseOutputHeaders([]string{"no-colon"}); err == nil {
+		t.Error("parseOutputHeaders accepted a header without a colon")
+	}
+}
+
+// TestPostWebhook tests POSTing content and stats to a webhook
+func TestPostWebhook(t *testing.T) {
+	var received webhookPayload
+	var auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/fail" {
+			http.Error(w, "quota exceeded", http.StatusTooManyRequests)
+			return
+		}
+		auth = r.Header.Get("Authorization")
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	stats := handoff.Stats{FilesProcessed: 2, FilesTotal: 3, Tokens: 42,
+		Files: []handoff.FileStat{{Path: "main.go", Lines: 3, Chars: 20, Tokens: 4, LineEndings: handoff.LineEndingLF}}}
+	headers := http.Header{"Authorization": []string{"Bearer abc"}}
+	if err := postWebhook(server.URL+"/ingest", "<context>\nhi\n</context>", stats, headers); err != nil {
+		t.Fatalf("postWebhook failed: %v", err)
+	}
+	if received.Content != "<context>\nhi\n</context>" || !reflect.DeepEqual(received.Stats, stats) {
+		t.Errorf("received %+v, want content and stats", received)
+	}
+	if auth != "Bearer abc" {
+		t.Errorf("Authorization = %q, want %q", auth, "Bearer abc")
+	}
+
+	err := postWebhook(server.URL+"/fail", "content", stats, nil)
+	if err == nil || !strings.Contains(err.Error(), "quota exceeded") {
+		t.Errorf("postWebhook error = %v, want failure including response body", err)
+	}
+}